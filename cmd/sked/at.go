@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/dateparse"
+	"github.com/Daniel-42-z/sked/internal/output"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	atJSON  bool
+	atStdin bool
+)
+
+var atCmd = &cobra.Command{
+	Use:   "at TIME",
+	Short: "Show the task scheduled at a time",
+	Long: `Show the task that was (or will be) in progress at a time, resolved with
+the overrides, rules and events of its date. TIME is RFC 3339 or a date and a
+clock time, "2025-03-03 14:30", where the date is any form the other commands
+accept ("yesterday 09:15", "last friday 16:00") and defaults to today.
+
+With --stdin, times are read one per line and each answer is printed as a
+line of JSON with the input, the time and the task (null if none), or an
+error for lines that aren't a time, which make the command fail at the end.
+Times are answered in batches, each date's schedule resolved once, so long
+logs are annotated in one run.`,
+	Example: `  sked at "2025-03-03 14:30"
+  sked at "yesterday 09:15" --json
+  cut -f1 work.log | sked at --stdin`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if atStdin {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runAt,
+}
+
+// atAnswer is the JSON form of the task at a time; Input and Error are set
+// by --stdin only.
+type atAnswer struct {
+	Input string           `json:"input,omitempty"`
+	Time  string           `json:"time,omitempty"`
+	Task  *output.JSONTask `json:"task"`
+	Error string           `json:"error,omitempty"`
+}
+
+// atBatch is how many --stdin lines are answered with one GetTasksAt call.
+const atBatch = 4096
+
+func init() {
+	atCmd.Flags().BoolVarP(&atJSON, "json", "j", false, "output in JSON format")
+	atCmd.Flags().BoolVar(&atStdin, "stdin", false, "read one time per line from stdin and print a JSON answer per line")
+	addTagFlag(atCmd)
+	rootCmd.AddCommand(atCmd)
+}
+
+func runAt(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	var at time.Time
+	if !atStdin {
+		var err error
+		if at, err = parseInstant(args[0], now); err != nil {
+			return err
+		}
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	sched := newScheduler(cfg)
+	if atStdin {
+		cmd.SilenceUsage = true
+		return answerLines(sched, os.Stdin, os.Stdout, now)
+	}
+
+	task, err := sched.GetCurrentTask(at)
+	if err != nil {
+		return err
+	}
+	if atJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(atAnswer{Time: at.Format(time.RFC3339), Task: output.NewJSONTask(task)})
+	}
+	when := at.Format("2006-01-02") + " " + displayClock(cfg).Format(at)
+	if task == nil {
+		fmt.Printf("%s: no task\n", when)
+		return nil
+	}
+	fmt.Printf("%s: %s\n", when, describeTask(task, displayClock(cfg)))
+	return nil
+}
+
+// answerLines writes an atAnswer line for each line of r, blank lines
+// included (without a task) so answers stay aligned with their input, and
+// returns an error at the end if any other line wasn't a time.
+func answerLines(sched *scheduler.Scheduler, r io.Reader, w io.Writer, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	sc := bufio.NewScanner(r)
+	var answers []atAnswer
+	var times []time.Time
+	var slots []int // index into answers of each of times
+	failed := 0
+
+	flush := func() error {
+		tasks, err := sched.GetTasksAt(times)
+		if err != nil {
+			return err
+		}
+		for i, t := range tasks {
+			answers[slots[i]].Task = output.NewJSONTask(t)
+		}
+		for _, a := range answers {
+			if err := enc.Encode(a); err != nil {
+				return err
+			}
+		}
+		answers, times, slots = answers[:0], times[:0], slots[:0]
+		return bw.Flush()
+	}
+
+	for sc.Scan() {
+		line := sc.Text()
+		a := atAnswer{Input: line}
+		switch t, err := parseInstant(line, now); {
+		case strings.TrimSpace(line) == "":
+		case err != nil:
+			a.Error = err.Error()
+			failed++
+		default:
+			a.Time = t.Format(time.RFC3339)
+			times = append(times, t)
+			slots = append(slots, len(answers))
+		}
+		answers = append(answers, a)
+		if len(answers) == atBatch {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("failed to read stdin: %w", err)
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d line(s) were not a time", failed)
+	}
+	return nil
+}
+
+// parseInstant parses an RFC 3339 time, or a clock time (HH:MM or
+// HH:MM:SS) preceded by a date in any form dateparse reads, "today" if
+// there is none, in now's location. The date and time may also be joined
+// by a T, as in "2025-03-03T14:30".
+func parseInstant(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	invalid := fmt.Errorf("invalid time '%s' (expected RFC 3339, or HH:MM after a date such as 2025-03-03, yesterday or last friday)", s)
+
+	datePart, clockPart := "today", s
+	if i := strings.LastIndexAny(s, " T"); i >= 0 {
+		datePart, clockPart = s[:i], s[i+1:]
+	}
+	var clock time.Time
+	var err error
+	if clock, err = time.Parse("15:04", clockPart); err != nil {
+		if clock, err = time.Parse("15:04:05", clockPart); err != nil {
+			return time.Time{}, invalid
+		}
+	}
+	date, err := dateparse.Parse(datePart, now)
+	if err != nil {
+		return time.Time{}, invalid
+	}
+	y, m, d := date.Date()
+	return time.Date(y, m, d, clock.Hour(), clock.Minute(), clock.Second(), 0, now.Location()), nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func TestParseInstant(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2024, 1, 3, 18, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in, want string
+	}{
+		{in: "2025-03-03 14:30", want: "2025-03-03T14:30:00Z"},
+		{in: "2025-03-03T14:30", want: "2025-03-03T14:30:00Z"},
+		{in: "2025-03-03 14:30:15", want: "2025-03-03T14:30:15Z"},
+		{in: "2025-03-03T14:30:00+01:00", want: "2025-03-03T14:30:00+01:00"},
+		{in: "09:15", want: "2024-01-03T09:15:00Z"},
+		{in: "yesterday 09:15", want: "2024-01-02T09:15:00Z"},
+		{in: "last friday 16:00", want: "2023-12-29T16:00:00Z"},
+		{in: " Tuesday 8:05 ", want: "2024-01-09T08:05:00Z"},
+	}
+	for _, tt := range tests {
+		got, err := parseInstant(tt.in, now)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.in, err)
+			continue
+		}
+		if got.Format(time.RFC3339) != tt.want {
+			t.Errorf("%q: expected %s, got %s", tt.in, tt.want, got.Format(time.RFC3339))
+		}
+	}
+	for _, in := range []string{"", "2025-03-03", "14:30 tomorrow", "soon 14:30", "25:00"} {
+		if _, err := parseInstant(in, now); err == nil {
+			t.Errorf("%q: expected error, got nil", in)
+		}
+	}
+}
+
+func TestAnswerLines(t *testing.T) {
+	sched := scheduler.New(&config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Math", Start: "09:00", End: "10:00"},
+		}}},
+	})
+	in := "2024-01-01 09:30\n\nnonsense\n2024-01-01 10:30\n2024-01-08T09:00:00Z\n"
+	var out bytes.Buffer
+	err := answerLines(sched, strings.NewReader(in), &out, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC))
+	if err == nil || !strings.Contains(err.Error(), "1 line") {
+		t.Errorf("Expected an error for 1 line, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	want := []string{"Math", "", "", "", "Math"}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d answers, got %d:\n%s", len(want), len(lines), out.String())
+	}
+	for i, line := range lines {
+		var a atAnswer
+		if err := json.Unmarshal([]byte(line), &a); err != nil {
+			t.Fatalf("Line %d is not JSON: %v", i+1, err)
+		}
+		name := ""
+		if a.Task != nil {
+			name = a.Task.Name
+		}
+		if name != want[i] {
+			t.Errorf("Line %d: expected task %q, got %q", i+1, want[i], name)
+		}
+		if (a.Error != "") != (i == 2) {
+			t.Errorf("Line %d: unexpected error field %q", i+1, a.Error)
+		}
+	}
+}
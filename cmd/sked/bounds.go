@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/dateparse"
+	"github.com/Daniel-42-z/sked/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var boundsJSON bool
+
+var boundsCmd = &cobra.Command{
+	Use:   "bounds [date]",
+	Short: "Show when the day's first task starts and its last task ends",
+	Long: `Show when the first task of a date starts and when its last task ends,
+ignoring empty slots. The date (default today) is YYYY-MM-DD, today,
+tomorrow, yesterday, a weekday for its next occurrence, next/last <weekday>,
+next/last week or +N/-N days.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBounds,
+}
+
+// boundsOutput is the JSON form of a day's bounds.
+type boundsOutput struct {
+	Date  string           `json:"date"`
+	IsOff bool             `json:"is_off"`
+	First *output.JSONTask `json:"first"`
+	Last  *output.JSONTask `json:"last"`
+}
+
+func init() {
+	boundsCmd.Flags().BoolVarP(&boundsJSON, "json", "j", false, "output in JSON format")
+	addTagFlag(boundsCmd)
+	rootCmd.AddCommand(boundsCmd)
+}
+
+func runBounds(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if len(args) == 1 {
+		var err error
+		if date, err = dateparse.Parse(args[0], date); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	sched := newScheduler(cfg)
+	info, err := sched.GetDayInfo(date)
+	if err != nil {
+		return err
+	}
+	first, last, err := sched.GetDayBounds(date)
+	if err != nil {
+		return err
+	}
+
+	if boundsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(boundsOutput{
+			Date:  date.Format("2006-01-02"),
+			IsOff: info.IsOff,
+			First: output.NewJSONTask(first),
+			Last:  output.NewJSONTask(last),
+		})
+	}
+
+	switch {
+	case first != nil:
+		clock := displayClock(cfg)
+		fmt.Printf("%s - %s\n", clock.Format(first.StartTime), clock.Format(last.EndTime))
+	case info.IsOff:
+		text := cfg.OffDayText
+		if text == "" {
+			text = output.DefaultOffDayText
+		}
+		fmt.Println(text)
+	default:
+		fmt.Printf("No tasks on %s.\n", date.Format("2006-01-02"))
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the compiled config cache used by --cache",
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached config snapshots",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := config.DefaultCacheDir()
+		if err != nil {
+			return err
+		}
+		if err := config.ClearCache(dir); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Cleared %s\n", dir)
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// loadCachedConfig loads path through the snapshot cache, falling back to a
+// plain load when the cache directory can't be determined.
+func loadCachedConfig(path string) (*config.Config, error) {
+	dir, err := config.DefaultCacheDir()
+	if err != nil {
+		return config.Load(path)
+	}
+	return config.LoadCached(path, dir)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/calendar"
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+// Calendar events are overlaid from a month back to a year ahead.
+const (
+	calendarPast  = 31 * 24 * time.Hour
+	calendarAhead = 366 * 24 * time.Hour
+)
+
+// calendarFetchTimeout bounds a single feed download; the first download
+// of a one-shot command gets the shorter calendarFirstFetchTimeout.
+const (
+	calendarFetchTimeout      = 30 * time.Second
+	calendarFirstFetchTimeout = 10 * time.Second
+)
+
+var calendarClient = &http.Client{Timeout: calendarFetchTimeout}
+
+// calendarCachePath returns where the feed of cal is cached, or "" if
+// there is no cache directory.
+func calendarCachePath(cal config.Calendar) string {
+	dir, err := calendar.DefaultCacheDir()
+	if err != nil {
+		slog.Warn("calendar cache unavailable", "calendar", cal.Label(), "err", err)
+		return ""
+	}
+	return calendar.CachePath(dir, cal.FeedURL())
+}
+
+// applyCalendars overlays the events of cfg's calendars from their cached
+// copies. A calendar that was never downloaded is fetched once. Failures
+// are logged and leave the rest of the schedule as it is.
+func applyCalendars(cfg *config.Config, now time.Time) {
+	for _, cal := range cfg.Calendars {
+		path := calendarCachePath(cal)
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			ctx, cancel := context.WithTimeout(context.Background(), calendarFirstFetchTimeout)
+			fetchCalendar(ctx, cal, path)
+			cancel()
+		}
+		events, err := calendar.Load(path)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				slog.Warn("failed to read cached calendar", "calendar", cal.Label(), "err", err)
+			}
+			continue
+		}
+		tasks, err := calendar.Tasks(cal, events, now.Add(-calendarPast), now.Add(calendarAhead), now.Location())
+		if err != nil {
+			slog.Warn("failed to apply calendar", "calendar", cal.Label(), "err", err)
+			continue
+		}
+		slog.Debug("calendar applied", "calendar", cal.Label(), "events", len(tasks))
+		cfg.Events = append(cfg.Events, tasks...)
+	}
+}
+
+// fetchCalendar downloads cal into its cache and reports whether the feed
+// changed. Failures are logged; the last good copy stays in place.
+func fetchCalendar(ctx context.Context, cal config.Calendar, path string) bool {
+	changed, err := calendar.Fetch(ctx, calendarClient, cal.FeedURL(), path)
+	if err != nil {
+		// Shutting down isn't a failure
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			slog.Warn("calendar refresh failed", "calendar", cal.Label(), "err", err)
+		}
+		return false
+	}
+	slog.Debug("calendar refreshed", "calendar", cal.Label(), "changed", changed)
+	return changed
+}
+
+// refreshCalendars fetches each of cals on its refresh interval, starting
+// now, until ctx is done. onChange runs after a feed changed, typically to
+// reload the schedule.
+func refreshCalendars(ctx context.Context, cals []config.Calendar, onChange func()) {
+	for _, cal := range cals {
+		path := calendarCachePath(cal)
+		if path == "" {
+			continue
+		}
+		go func() {
+			ticker := time.NewTicker(cal.RefreshInterval())
+			defer ticker.Stop()
+			for {
+				if fetchCalendar(ctx, cal, path) {
+					onChange()
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+func TestConfigPath_Create(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name     string
+		terminal bool
+		flag     *bool // --no-create, if given
+		created  bool
+	}{
+		{name: "interactive", terminal: true, created: true},
+		{name: "scripted", terminal: false, created: false},
+		{name: "no_create", terminal: true, flag: &yes, created: false},
+		{name: "scripted_opt_in", terminal: false, flag: &no, created: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Setenv(config.ConfigDirEnv, dir)
+			t.Setenv(config.ConfigEnv, "")
+			t.Chdir(t.TempDir())
+			cfgFile = ""
+
+			oldTerminal, oldSet, oldNoCreate := stdoutIsTerminal, noCreateSet, noCreate
+			t.Cleanup(func() { stdoutIsTerminal, noCreateSet, noCreate = oldTerminal, oldSet, oldNoCreate })
+			stdoutIsTerminal = func() bool { return tt.terminal }
+			noCreateSet = func() bool { return tt.flag != nil }
+			if tt.flag != nil {
+				noCreate = *tt.flag
+			}
+
+			want := filepath.Join(dir, "config.toml")
+			path, err := configPath(true)
+			_, statErr := os.Stat(want)
+			if tt.created {
+				if err != nil || path != want || statErr != nil {
+					t.Errorf("Expected %s to be created, got %q, %v (stat: %v)", want, path, err, statErr)
+				}
+				return
+			}
+			var notFound *config.NotFoundError
+			if !errors.As(err, &notFound) || notFound.Path != want {
+				t.Errorf("Expected a NotFoundError for %s, got %v", want, err)
+			}
+			if statErr == nil {
+				t.Errorf("Expected %s not to be created", want)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/Daniel-42-z/sked/internal/conflicts"
+
+	"github.com/spf13/cobra"
+)
+
+var conflictsJSON bool
+
+var conflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "Check every day of the cycle for conflicting tasks",
+	Long: `Check every day ID of the cycle for overlapping tasks, tasks that don't end
+after they start and duplicated entries, and every override for borrowed days
+(use_day_id) without tasks.
+
+Exits nonzero if any error is found; duplicates are only warnings.`,
+	Args: cobra.NoArgs,
+	RunE: runConflicts,
+}
+
+func init() {
+	conflictsCmd.Flags().BoolVarP(&conflictsJSON, "json", "j", false, "output in JSON format")
+	rootCmd.AddCommand(conflictsCmd)
+}
+
+func runConflicts(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	report := conflicts.Check(cfg)
+	if conflictsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		conflicts.Write(os.Stdout, report)
+	}
+
+	if report.Errors > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d conflict(s) found", report.Errors)
+	}
+	return nil
+}
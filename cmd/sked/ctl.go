@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/dateparse"
+
+	"github.com/spf13/cobra"
+)
+
+var ctlSocket string
+
+var ctlCmd = &cobra.Command{
+	Use:   "ctl COMMAND [DATE]",
+	Short: "Query or control a running 'sked --watch --control-socket'",
+	Long: `Send a command to the control socket of a running watch process and print
+its JSON answer. Commands:
+
+  current      the current task
+  next         the next task
+  previous     the previous task
+  day [DATE]   the day's schedule (YYYY-MM-DD, today, a weekday, +N, ...)
+  reload       reload the config
+  notify-test  send a test notification through the configured backends`,
+	Args:      cobra.RangeArgs(1, 2),
+	ValidArgs: []string{"current", "next", "previous", "day", "reload", "notify-test"},
+	RunE:      runCtl,
+}
+
+func init() {
+	ctlCmd.Flags().StringVar(&ctlSocket, "socket", defaultControlSocket(), "control socket of the watch process")
+	rootCmd.AddCommand(ctlCmd)
+}
+
+// defaultControlSocket is the socket ctl connects to unless --socket is given.
+func defaultControlSocket() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "sked.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("sked-%d.sock", os.Getuid()))
+}
+
+// listenControlSocket listens on the unix socket at path, replacing a socket
+// left behind by a process that didn't exit cleanly. Only the owner may connect.
+func listenControlSocket(path string) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&fs.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s is in use by another process", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+func runCtl(cmd *cobra.Command, args []string) error {
+	if len(args) == 2 && args[0] != "day" {
+		return fmt.Errorf("'%s' takes no argument", args[0])
+	}
+
+	method, target := http.MethodGet, ""
+	switch args[0] {
+	case "current", "next", "previous":
+		target = "/" + args[0]
+	case "day":
+		target = "/day"
+		if len(args) == 2 {
+			now := time.Now()
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+			date, err := dateparse.Parse(args[1], today)
+			if err != nil {
+				return err
+			}
+			target += "?date=" + date.Format("2006-01-02")
+		}
+	case "reload", "notify-test":
+		method, target = http.MethodPost, "/"+args[0]
+	default:
+		return fmt.Errorf("unknown command '%s' (expected current, next, previous, day, reload or notify-test)", args[0])
+	}
+	cmd.SilenceUsage = true
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", ctlSocket)
+			},
+		},
+	}
+	req, err := http.NewRequest(method, "http://sked"+target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot reach the watch process on %s (is 'sked --watch --control-socket' running?): %w", ctlSocket, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(body, &e) == nil && e.Error != "" {
+			return errors.New(e.Error)
+		}
+		return fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	_, err = os.Stdout.Write(body)
+	return err
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListenControlSocket(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("replaces_stale_socket", func(t *testing.T) {
+		path := filepath.Join(dir, "stale.sock")
+		l, err := net.Listen("unix", path)
+		if err != nil {
+			t.Fatalf("Listen() returned error: %v", err)
+		}
+		// Leave the socket file behind like a killed process would
+		l.(*net.UnixListener).SetUnlinkOnClose(false)
+		l.Close()
+
+		l, err = listenControlSocket(path)
+		if err != nil {
+			t.Fatalf("listenControlSocket() returned error: %v", err)
+		}
+		defer l.Close()
+		fi, err := os.Stat(path)
+		if err != nil || fi.Mode().Perm() != 0600 {
+			t.Errorf("Expected a 0600 socket, got %v, %v", fi, err)
+		}
+	})
+
+	t.Run("in_use", func(t *testing.T) {
+		path := filepath.Join(dir, "busy.sock")
+		l, err := listenControlSocket(path)
+		if err != nil {
+			t.Fatalf("listenControlSocket() returned error: %v", err)
+		}
+		defer l.Close()
+		if _, err := listenControlSocket(path); err == nil || !strings.Contains(err.Error(), "in use") {
+			t.Errorf("Expected an in-use error, got %v", err)
+		}
+	})
+
+	t.Run("not_a_socket", func(t *testing.T) {
+		path := filepath.Join(dir, "config.toml")
+		os.WriteFile(path, []byte("cycle_days = 7\n"), 0600)
+		if _, err := listenControlSocket(path); err == nil {
+			t.Errorf("Expected an error for a regular file")
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected the file to be left alone, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/dateparse"
+	"github.com/Daniel-42-z/sked/internal/diff"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFrom string
+	diffDays int
+	diffJSON bool
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [old] new",
+	Short: "Show how two schedules differ over a date range",
+	Long: `Compare the events two configs produce and print added, removed and
+modified tasks grouped by date. With a single argument, the given file is
+compared against the currently active config.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffFrom, "from", "", "first date to compare, YYYY-MM-DD, tomorrow, a weekday, next week, +N, ... (default today)")
+	diffCmd.Flags().IntVar(&diffDays, "days", 0, "number of days to compare (default the longer cycle of both configs)")
+	diffCmd.Flags().BoolVarP(&diffJSON, "json", "j", false, "output in JSON format")
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	var oldCfg, newCfg *config.Config
+	var err error
+	if len(args) == 1 {
+		oldCfg, err = loadConfig()
+	} else {
+		oldCfg, err = loadConfigFile(args[0])
+	}
+	if err != nil {
+		return err
+	}
+	newCfg, err = loadConfigFile(args[len(args)-1])
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if diffFrom != "" {
+		from, err = dateparse.Parse(diffFrom, now)
+		if err != nil {
+			return fmt.Errorf("invalid --from: %w", err)
+		}
+	}
+	days := diffDays
+	if days == 0 {
+		days = max(oldCfg.CycleDays, newCfg.CycleDays)
+	}
+	if days < 0 {
+		return fmt.Errorf("--days must be positive")
+	}
+
+	changes, err := diff.Schedules(scheduler.New(oldCfg), scheduler.New(newCfg), from, days)
+	if err != nil {
+		return err
+	}
+
+	if diffJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(changes)
+	}
+	diff.Write(os.Stdout, changes)
+	return nil
+}
+
+// loadConfigFile loads and validates the config at path.
+func loadConfigFile(path string) (*config.Config, error) {
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	if err := reportWarnings(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
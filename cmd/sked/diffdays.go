@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/dateparse"
+	"github.com/Daniel-42-z/sked/internal/diff"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+
+	"github.com/spf13/cobra"
+)
+
+var diffDaysJSON bool
+
+var diffDaysCmd = &cobra.Command{
+	Use:   "diff-days a b",
+	Short: "Show how the tasks of two days differ",
+	Long: `Compare the tasks of two days and print added, removed, renamed and
+time-shifted tasks, as in sked diff. Each argument is a date (YYYY-MM-DD,
+today, tomorrow, yesterday, next/last <weekday>, next/last week or +N/-N
+days), resolved with its overrides and rules, or a cycle day as written in
+the config: a weekday name or a day ID.
+
+  sked diff-days 2025-03-06 monday   # what "Thursday follows a Monday schedule" changes`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiffDays,
+}
+
+// diffDaysOutput is the JSON form of a day comparison.
+type diffDaysOutput struct {
+	Before  diffDaysSide  `json:"before"`
+	After   diffDaysSide  `json:"after"`
+	Changes []diff.Change `json:"changes"`
+}
+
+// diffDaysSide describes one compared day: a date, or a cycle day laid out
+// on the date the comparison uses.
+type diffDaysSide struct {
+	Label string `json:"label"`
+	Date  string `json:"date"`
+	DayID *int   `json:"day_id,omitempty"`
+}
+
+func init() {
+	diffDaysCmd.Flags().BoolVarP(&diffDaysJSON, "json", "j", false, "output in JSON format")
+	addTagFlag(diffDaysCmd)
+	rootCmd.AddCommand(diffDaysCmd)
+}
+
+// dayRef is a parsed diff-days argument: a date, or a cycle day if isDay.
+type dayRef struct {
+	date  time.Time
+	dayID int
+	isDay bool
+}
+
+func runDiffDays(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	a, err := parseDayRef(args[0], today)
+	if err != nil {
+		return err
+	}
+	b, err := parseDayRef(args[1], today)
+	if err != nil {
+		return err
+	}
+	// A cycle day is laid out on the other argument's date, if it has one.
+	switch {
+	case a.isDay && !b.isDay:
+		a.date = b.date
+	case b.isDay && !a.isDay:
+		b.date = a.date
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	sched := newScheduler(cfg)
+	before, err := dayTasks(sched, a)
+	if err != nil {
+		return err
+	}
+	after, err := dayTasks(sched, b)
+	if err != nil {
+		return err
+	}
+	changes := diff.Events(diff.FromTasks(before), diff.FromTasks(after))
+
+	if diffDaysJSON {
+		if changes == nil {
+			changes = []diff.Change{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diffDaysOutput{
+			Before:  a.side(sched),
+			After:   b.side(sched),
+			Changes: changes,
+		})
+	}
+
+	fmt.Printf("%s -> %s\n", a.label(sched), b.label(sched))
+	if len(changes) == 0 {
+		fmt.Println("  no differences")
+		return nil
+	}
+	diff.WriteChanges(os.Stdout, changes)
+	return nil
+}
+
+// parseDayRef parses a cycle day in config.ParseDayName's syntax, so a bare
+// weekday is that cycle day rather than a date, or else a date in any form
+// dateparse reads.
+func parseDayRef(s string, today time.Time) (dayRef, error) {
+	s = strings.TrimSpace(s)
+	// "+1" is a date offset, not day ID 1
+	if !strings.HasPrefix(s, "+") {
+		if id, err := config.ParseDayName(s); err == nil && id >= 0 {
+			return dayRef{date: today, dayID: id, isDay: true}, nil
+		}
+	}
+	date, err := dateparse.Parse(s, today)
+	if err != nil {
+		return dayRef{}, fmt.Errorf("invalid day '%s' (expected %s, or a day ID)", s, dateparse.Forms)
+	}
+	return dayRef{date: date}, nil
+}
+
+// dayTasks returns the tasks r refers to.
+func dayTasks(sched *scheduler.Scheduler, r dayRef) ([]scheduler.TaskEvent, error) {
+	if r.isDay {
+		return sched.GetTasksForDay(r.dayID, r.date)
+	}
+	return sched.GetTasksForDate(r.date)
+}
+
+// label names r in the text output.
+func (r dayRef) label(sched *scheduler.Scheduler) string {
+	if r.isDay {
+		return sched.DayName(r.dayID)
+	}
+	return fmt.Sprintf("%s (%s)", r.date.Format("2006-01-02"), r.date.Weekday())
+}
+
+func (r dayRef) side(sched *scheduler.Scheduler) diffDaysSide {
+	s := diffDaysSide{Label: r.label(sched), Date: r.date.Format("2006-01-02")}
+	if r.isDay {
+		id := r.dayID
+		s.DayID = &id
+	}
+	return s
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/doctor"
+
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the config and environment for common problems",
+	Args:  cobra.NoArgs,
+	RunE:  runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	// Don't create a default config; reporting it missing is the point.
+	wd, _ := os.Getwd()
+	path, source, err := config.Resolve(cfgFile, wd)
+	if err != nil {
+		return err
+	}
+
+	env := doctor.SystemEnv()
+	results := append([]doctor.Result{doctor.CheckConfigSource(env, source)}, doctor.Run(env, path)...)
+	doctor.Write(os.Stdout, results)
+
+	if n := doctor.Failures(results); n > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d check(s) failed", n)
+	}
+	return nil
+}
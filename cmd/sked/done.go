@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/dateparse"
+	"github.com/Daniel-42-z/sked/internal/journal"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+	"github.com/Daniel-42-z/sked/internal/wizard"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	logFrom string
+	logTo   string
+	logJSON bool
+)
+
+var doneCmd = &cobra.Command{
+	Use:   "done",
+	Short: "Mark the current task as done",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMark(journal.Done)
+	},
+}
+
+var skipCmd = &cobra.Command{
+	Use:   "skip",
+	Short: "Mark the current task as skipped",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMark(journal.Skipped)
+	},
+}
+
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show recorded done/skipped tasks",
+	Args:  cobra.NoArgs,
+	RunE:  runLog,
+}
+
+func init() {
+	logCmd.Flags().StringVar(&logFrom, "from", "", "first date to show, YYYY-MM-DD, yesterday, a weekday for its latest occurrence, -N days, ... (default 6 days ago)")
+	logCmd.Flags().StringVar(&logTo, "to", "", "last date to show, in the same forms as --from (default today)")
+	logCmd.Flags().BoolVarP(&logJSON, "json", "j", false, "output in JSON format")
+	rootCmd.AddCommand(doneCmd, skipCmd, logCmd)
+}
+
+func openJournal() (*journal.Journal, error) {
+	path, err := journal.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return journal.New(path), nil
+}
+
+// runMark records status for the current task, offering the previous task
+// when nothing is in progress.
+func runMark(status journal.Status) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	sched := scheduler.New(cfg)
+	now := time.Now()
+
+	task, err := sched.GetCurrentTask(now)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		task, err = sched.GetPreviousTask(now)
+		if err != nil {
+			return err
+		}
+		if task == nil {
+			return fmt.Errorf("no current or previous task to mark as %s", status)
+		}
+		p := wizard.NewPrompter(os.Stdin, os.Stdout)
+		ok, err := p.Confirm(fmt.Sprintf("No task in progress. Mark %s as %s?", describeTask(task, displayClock(cfg)), status), true)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+
+	j, err := openJournal()
+	if err != nil {
+		return err
+	}
+	added, err := j.Mark(*task, status, now)
+	if err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if added {
+		fmt.Printf("Marked %s as %s.\n", describeTask(task, displayClock(cfg)), status)
+	} else {
+		fmt.Printf("%s is already marked as %s.\n", describeTask(task, displayClock(cfg)), status)
+	}
+	return nil
+}
+
+func runLog(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	to := now
+	from := now.AddDate(0, 0, -6)
+	var err error
+	if logFrom != "" {
+		if from, err = dateparse.ParsePast(logFrom, now); err != nil {
+			return fmt.Errorf("invalid --from: %w", err)
+		}
+	}
+	if logTo != "" {
+		if to, err = dateparse.ParsePast(logTo, now); err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+	}
+
+	j, err := openJournal()
+	if err != nil {
+		return err
+	}
+	records, err := j.Range(from, to)
+	if err != nil {
+		return err
+	}
+
+	if logJSON {
+		if records == nil {
+			records = []journal.Record{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+	if len(records) == 0 {
+		fmt.Println("No records.")
+		return nil
+	}
+	return writeRecords(os.Stdout, records, displayClock(nil))
+}
+
+// writeRecords prints records as aligned columns with their times on clock.
+func writeRecords(out io.Writer, records []journal.Record, clock config.Clock) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, r := range records {
+		start := r.Start
+		if t, err := time.Parse("15:04", r.Start); err == nil {
+			start = clock.Format(t)
+		}
+		at := r.Timestamp
+		if ts, err := time.Parse(time.RFC3339, r.Timestamp); err == nil {
+			at = clock.Format(ts.Local())
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t(at %s)\n", r.Date, start, statusMark(r.Status), r.Task, at)
+	}
+	return w.Flush()
+}
+
+// taskStatuses returns a lookup of recorded task statuses for JSON output.
+// A journal that can't be read is reported and treated as empty.
+func taskStatuses() func(scheduler.TaskEvent) string {
+	j, err := openJournal()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open journal: %v\n", err)
+		return nil
+	}
+	statuses, err := j.Statuses()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read journal: %v\n", err)
+		return nil
+	}
+	return func(t scheduler.TaskEvent) string {
+		return string(statuses[journal.KeyOf(t)])
+	}
+}
+
+func statusMark(s journal.Status) string {
+	switch s {
+	case journal.Done:
+		return "✓ done"
+	case journal.Skipped:
+		return "✗ skipped"
+	}
+	return string(s)
+}
+
+func describeTask(t *scheduler.TaskEvent, clock config.Clock) string {
+	return fmt.Sprintf("'%s' (%s-%s)", t.Name, clock.Format(t.StartTime), clock.Format(t.EndTime))
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/journal"
+)
+
+func TestWriteRecords_Golden(t *testing.T) {
+	at := func(h, m int) string {
+		return time.Date(2024, 9, 2, h, m, 0, 0, time.Local).Format(time.RFC3339)
+	}
+	records := []journal.Record{
+		{Date: "2024-09-02", Task: "Math", Start: "09:00", Status: journal.Done, Timestamp: at(9, 55)},
+		{Date: "2024-09-02", Task: "Lunch", Start: "12:30", Status: journal.Skipped, Timestamp: at(13, 2)},
+		{Date: "2024-09-02", Task: "Review", Start: "17:00", Status: journal.Done, Timestamp: at(18, 10)},
+	}
+	for _, clock := range []config.Clock{config.Clock24, config.Clock12} {
+		t.Run(string(clock), func(t *testing.T) {
+			var b strings.Builder
+			if err := writeRecords(&b, records, clock); err != nil {
+				t.Fatalf("writeRecords() returned error: %v", err)
+			}
+			got := b.String()
+
+			path := filepath.Join("testdata", "log_"+string(clock)+".golden")
+			if *updateGolden {
+				if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+					t.Fatalf("Failed to write %s: %v", path, err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read %s (run with -update to create it): %v", path, err)
+			}
+			if got != string(want) {
+				t.Errorf("Output no longer matches %s. If the change is intentional, run 'go test ./cmd/sked -run TestWriteRecords_Golden -update'.\n%s", path, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var envShell string
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Print the current and next task as shell variables",
+	Long: `Print export lines for scripts to eval: SKED_IS_OFF, SKED_GENERATED_AT
+and, for the previous, current and next task, SKED_<TASK>_NAME, _RAW_NAME,
+_START, _END, _START_UNIX, _END_UNIX, _DURATION_SECONDS, _COLOR, _ICON,
+_TAGS (comma-separated), _URL, _LOCATION and _STATUS, where <TASK> is
+PREVIOUS, CURRENT or NEXT.
+
+The values are those --json reports at the same instant. Every variable is
+set, to an empty value when there is no such task, so scripts running with
+set -u can use them. Names are quoted, so any task name is safe to eval.`,
+	Example: `  eval "$(sked env)"                 # sh, bash, zsh
+  sked env --shell fish | source
+  sked env --shell powershell | Out-String | Invoke-Expression`,
+	Args: cobra.NoArgs,
+	RunE: runEnv,
+}
+
+func init() {
+	envCmd.Flags().StringVar(&envShell, "shell", output.ShellSh, "syntax of the assignments: sh, fish or powershell")
+	addTagFlag(envCmd)
+	rootCmd.AddCommand(envCmd)
+}
+
+func runEnv(cmd *cobra.Command, args []string) error {
+	if err := output.CheckShell(envShell); err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	sched := newScheduler(cfg)
+	now := time.Now()
+	current, err := sched.GetCurrentTask(now)
+	if err != nil {
+		return err
+	}
+	next, err := nextOrNone(sched, now)
+	if err != nil {
+		return err
+	}
+	previous, err := sched.GetPreviousTask(now)
+	if err != nil {
+		return err
+	}
+	opts := output.Options{Now: now, Status: taskStatuses()}
+	if info, err := sched.GetDayInfo(now); err == nil {
+		opts.OffDay = info.IsOff
+	}
+	return output.WriteEnv(os.Stdout, previous, current, next, opts, envShell)
+}
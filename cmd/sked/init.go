@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+	"github.com/Daniel-42-z/sked/internal/wizard"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	initPath  string
+	initForce bool
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Interactively create a new configuration",
+	Args:  cobra.NoArgs,
+	RunE:  runInit,
+}
+
+func init() {
+	initCmd.Flags().StringVar(&initPath, "path", "", "where to write the config (default is $SKED_CONFIG_DIR/config.toml, else $XDG_CONFIG_HOME/sked/config.toml)")
+	initCmd.Flags().BoolVar(&initForce, "force", false, "overwrite existing files")
+	rootCmd.AddCommand(initCmd)
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	path := initPath
+	if path == "" {
+		var err error
+		path, err = config.DefaultPath()
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := os.Stat(path); err == nil && !initForce {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+	}
+
+	now := time.Now()
+	answers, err := wizard.Interview(wizard.NewPrompter(os.Stdin, os.Stdout), now)
+	if err != nil {
+		return fmt.Errorf("setup aborted: %w", err)
+	}
+
+	files := answers.Files(path)
+	if err := wizard.Write(files, initForce); err != nil {
+		return err
+	}
+	for _, f := range files {
+		fmt.Printf("Wrote %s\n", f.Path)
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	current, err := scheduler.New(cfg).GetCurrentTask(now)
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		fmt.Printf("Current task: %s\n", current.Name)
+	} else {
+		fmt.Println("No task right now.")
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/Daniel-42-z/sked/internal/i18n"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	lang string
+
+	// locale translates the output, notifications and TUI; setupLocale
+	// selects it.
+	locale = i18n.English
+)
+
+// setup runs before every command.
+func setup(cmd *cobra.Command, args []string) error {
+	if err := setupLogging(cmd, args); err != nil {
+		return err
+	}
+	return setupLocale()
+}
+
+// setupLocale selects the locale of --lang, or else of LC_ALL, LC_MESSAGES
+// or LANG. An unknown --lang is an error; an unknown environment locale
+// means English.
+func setupLocale() error {
+	if lang != "" {
+		l, ok := i18n.Lookup(lang)
+		if !ok {
+			return fmt.Errorf("unknown --lang '%s' (available: %s)", lang, strings.Join(i18n.Tags(), ", "))
+		}
+		locale = l
+	} else {
+		locale, _ = i18n.Lookup(i18n.FromEnv())
+	}
+	slog.Debug("locale selected", "locale", locale.Tag)
+	return nil
+}
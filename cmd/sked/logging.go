@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/logging"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	verbosity int
+	logFile   string
+	quiet     bool
+	strict    bool
+
+	// logCloser closes the --log-file, if one is open.
+	logCloser io.Closer
+)
+
+// setupLogging installs the default logger for all commands.
+func setupLogging(cmd *cobra.Command, args []string) error {
+	var file io.Writer
+	if logFile != "" {
+		f, err := logging.OpenFile(logFile)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		file, logCloser = f, f
+	}
+	slog.SetDefault(logging.New(os.Stderr, verbosity, file))
+	return nil
+}
+
+// reportWarnings prints the load warnings of cfg to stderr unless --quiet.
+// With --strict, unknown keys fail the load instead.
+func reportWarnings(cfg *config.Config) error {
+	if strict {
+		if err := cfg.StrictError(); err != nil {
+			return err
+		}
+	}
+	if quiet {
+		return nil
+	}
+	for _, w := range cfg.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	return nil
+}
+
+// reportEmpty prints a prominent warning to stderr, unless --quiet, when
+// the loaded schedule has no tasks at all, which otherwise only shows as the
+// no-task text forever.
+func reportEmpty(cfg *config.Config) {
+	if quiet {
+		return
+	}
+	if msg := cfg.EmptyWarning(); msg != "" {
+		fmt.Fprintf(os.Stderr, "WARNING: %s\n", msg)
+	}
+}
@@ -2,30 +2,81 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
 	"sync"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/hooks"
+	"github.com/Daniel-42-z/sked/internal/metrics"
 	"github.com/Daniel-42-z/sked/internal/notifier"
 	"github.com/Daniel-42-z/sked/internal/output"
 	"github.com/Daniel-42-z/sked/internal/scheduler"
+	"github.com/Daniel-42-z/sked/internal/server"
+	"github.com/Daniel-42-z/sked/internal/watch"
 
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
+const (
+	// errorRetryDelay is how long watch mode waits after a failed iteration.
+	errorRetryDelay = 5 * time.Second
+	// shutdownTimeout bounds how long watch mode waits for its servers to stop.
+	shutdownTimeout = 2 * time.Second
+	// exitNoConfig is the exit status when no config exists and none was
+	// created; EX_CONFIG in sysexits.h.
+	exitNoConfig = 78
+)
+
 var (
-	cfgFile     string
-	tmpFile     string
-	jsonFmt     bool
-	jsonAll     bool
-	showTime    bool
-	nextTask    bool
-	watchMode   bool
-	noTaskText  string
-	lookahead   time.Duration
-	notifyAhead time.Duration
+	cfgFile        string
+	noCreate       bool
+	tmpFile        string
+	twelveHour     bool
+	precise        bool
+	inlineSched    string
+	jsonFmt        bool
+	jsonAll        bool
+	showTime       bool
+	nextTask       bool
+	showPrevious   bool
+	relative       bool
+	watchMode      bool
+	noTaskText     string
+	lookahead      time.Duration
+	lookaheadLabel bool
+	notifyAhead    time.Duration
+	noNotifyState  bool
+	execOnChange   string
+	colorMode      string
+	jsonCompact    bool
+	jsonFields     []string
+	fieldDefault   string
+	jsonFlat       bool
+	outputFormat   string
+	maxWidth       int
+	useCache       bool
+	eventsMode     bool
+	showIcons      bool
+	controlSocket  string
+	alignWakeups   bool
+	watchInterval  time.Duration
+	watchFor       time.Duration
+	watchUntil     string
+	maxUpdates     int
 
 	// Build information
 	version = "dev"
@@ -39,27 +90,74 @@ var rootCmd = &cobra.Command{
 	Long:    `sked reads your timetable configuration and tells you what you should be doing.`,
 	Version: version,
 	RunE:    run,
+
+	PersistentPreRunE: setup,
 }
 
 func init() {
 	rootCmd.SetVersionTemplate(fmt.Sprintf("sked %s\ncommit: %s\nbuilt at: %s\n", version, commit, date))
 
-	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default is $XDG_CONFIG_HOME/sked/config.toml)")
-	rootCmd.PersistentFlags().StringVar(&tmpFile, "tmp", "", "temporary csv config file (only for today's tasks)")
+	rootCmd.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "config file (default is $SKED_CONFIG, a .sked.toml or .sked.csv in the current or a parent directory, or $XDG_CONFIG_HOME/sked/config.toml)")
+	rootCmd.PersistentFlags().BoolVar(&noCreate, "no-create", false, "fail instead of creating a default config when none exists (the default when stdout is not a terminal; --no-create=false creates one anyway)")
+	rootCmd.PersistentFlags().StringVar(&tmpFile, "tmp", "", "temporary csv config file (only for today's tasks), or - to read it from stdin")
+	rootCmd.PersistentFlags().StringVar(&inlineSched, "inline", "", "today's schedule instead of a config, e.g. \"09:00-10:00 Math; 10:05-11:00 History @Room 4\"")
+	rootCmd.PersistentFlags().StringVar(&lang, "lang", "", "language of output, notifications and the TUI, e.g. de (default from LC_ALL, LC_MESSAGES or LANG)")
+	rootCmd.PersistentFlags().BoolVar(&twelveHour, "12h", false, "show times on a 12-hour clock, e.g. 9:00 AM (also clock = \"12h\" in the config)")
+	rootCmd.PersistentFlags().BoolVar(&precise, "precise", false, "show durations to the second instead of rounding to whole minutes")
+	rootCmd.PersistentFlags().BoolVar(&showIcons, "icons", false, "show task icons before names (output, notifications and the TUI)")
+	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "log more detail to stderr (-v for info, -vv for debug)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "don't print warnings about skipped config data to stderr")
+	rootCmd.PersistentFlags().BoolVar(&strict, "strict", false, "treat unknown config keys as errors (like strict = true)")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "append log records as JSON lines to this file")
 	rootCmd.Flags().BoolVarP(&jsonFmt, "json", "j", false, "output in JSON format")
-	rootCmd.Flags().BoolVar(&jsonAll, "all", false, "include all tasks for today in JSON output (only with --json)")
+	rootCmd.Flags().BoolVar(&jsonCompact, "json-compact", false, "output JSON on a single line (implies --json)")
+	rootCmd.Flags().StringArrayVar(&jsonFields, "field", nil, "print only this dotted path of the JSON output, e.g. current.name (repeatable, one value per line; implies --json)")
+	rootCmd.Flags().StringVar(&fieldDefault, "default", "", "text printed by --field for null values")
+	rootCmd.Flags().BoolVar(&jsonFlat, "json-flat", false, "output JSON as a single flat object with keys like current_name (implies --json)")
+	rootCmd.Flags().BoolVar(&jsonAll, "all", false, "include today's full day: its context and tasks in JSON output, or in natural output a list of its tasks with the current one marked")
+	rootCmd.Flags().StringVarP(&outputFormat, "output", "o", output.FormatNatural, "output format: natural, json, tmux, or markdown or org for the day's agenda ('list' shows them all)")
+	rootCmd.Flags().IntVar(&maxWidth, "max-width", output.DefaultMaxWidth, "maximum task name width for tmux output")
 	rootCmd.Flags().BoolVarP(&showTime, "time", "t", false, "show time ranges in output")
+	rootCmd.Flags().BoolVar(&useCache, "cache", false, "reuse a compiled snapshot of the config while its files are unchanged (for fast prompt/status calls)")
 	rootCmd.Flags().BoolVarP(&nextTask, "next", "n", false, "show next task instead of current")
+	rootCmd.Flags().BoolVarP(&showPrevious, "previous", "p", false, "show the task that ended last instead of current")
+	rootCmd.Flags().BoolVar(&relative, "relative", false, "add when the task ends, ended or starts relative to now, e.g. \"ended 12m ago\"")
 	rootCmd.Flags().BoolVarP(&watchMode, "watch", "w", false, "continuous mode (watch for changes)")
-	rootCmd.Flags().StringVar(&noTaskText, "no-task-text", "No task currently.", "text to display when no task is found")
-	rootCmd.Flags().DurationVarP(&lookahead, "lookahead", "l", 0, "lookahead duration for watch mode (affects output time)")
+	rootCmd.Flags().StringVar(&colorMode, "color", "auto", "colorize output: auto, always or never")
+	rootCmd.Flags().StringVar(&noTaskText, "no-task-text", "", "text to display when no task is found (default \"No task currently.\", translated with --lang)")
+	rootCmd.Flags().DurationVarP(&lookahead, "lookahead", "l", 0, "in watch mode, show the tasks this much later, or earlier if negative (e.g. -10m); notifications follow the real clock")
+	rootCmd.Flags().BoolVar(&lookaheadLabel, "lookahead-label", false, "in watch mode, end natural output with \"(in 5m)\" or \"(5m ago)\" while --lookahead shifts it")
 	rootCmd.Flags().DurationVar(&notifyAhead, "notify-ahead", 0, "enable notifications with this lookahead duration (use 0s for immediate)")
+	rootCmd.Flags().StringVar(&execOnChange, "exec-on-change", "", "command to run in watch mode whenever the current task changes")
+	rootCmd.Flags().StringVar(&metricsAddr, "metrics", "", "serve Prometheus metrics on this address (only with --watch)")
+	rootCmd.Flags().BoolVar(&servePublic, "public", false, "allow --metrics on addresses other than localhost")
+	rootCmd.Flags().StringVar(&serveToken, "token", "", "require this bearer token for --metrics (default $SKED_TOKEN)")
+	rootCmd.Flags().BoolVar(&notifyPlan, "notify-plan", false, "list the notifications of the next 24 hours and exit (with --watch --notify-ahead)")
+	rootCmd.Flags().BoolVar(&eventsMode, "events", false, "in watch mode, print one JSON event per line on each state change instead of snapshots")
+	rootCmd.Flags().BoolVar(&alignWakeups, "align", false, "in watch mode, wake up on whole minutes instead of exact boundaries")
+	rootCmd.Flags().DurationVar(&watchInterval, "interval", 0, "in watch mode, refresh at least this often (e.g. 30s)")
+	rootCmd.Flags().DurationVar(&watchFor, "for", 0, "in watch mode, exit after this long (e.g. 2h)")
+	rootCmd.Flags().StringVar(&watchUntil, "until", "", "in watch mode, exit at this local time today (HH:MM)")
+	rootCmd.Flags().IntVar(&maxUpdates, "max-updates", 0, "in watch mode, exit after printing this many updates")
+	rootCmd.Flags().StringVar(&controlSocket, "control-socket", "", "in watch mode, answer 'sked ctl' queries on this unix socket")
+	rootCmd.Flags().BoolVar(&noNotifyState, "no-notify-state", false, "keep notification history in memory only (do not persist across restarts)")
 
-	rootCmd.MarkFlagsMutuallyExclusive("config", "tmp")
+	addTagFlag(rootCmd)
+
+	rootCmd.MarkFlagsMutuallyExclusive("config", "tmp", "inline")
+	noCreateSet = func() bool { return rootCmd.PersistentFlags().Changed("no-create") }
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	if logCloser != nil {
+		logCloser.Close()
+	}
+	var notFound *config.NotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		os.Exit(exitNoConfig)
+	case err != nil:
 		os.Exit(1)
 	}
 }
@@ -70,47 +168,141 @@ func run(cmd *cobra.Command, args []string) error {
 	if notifyEnabled && !watchMode {
 		return fmt.Errorf("--notify-ahead can only be used with --watch (-w)")
 	}
-
-	var cfg *config.Config
-	var err error
-
-	if tmpFile != "" {
-		cfg, err = config.LoadTmpCSV(tmpFile)
-		if err != nil {
-			return fmt.Errorf("failed to load temporary config: %w", err)
+	if showPrevious && nextTask {
+		return fmt.Errorf("--previous (-p) and --next (-n) cannot be combined")
+	}
+	if jsonAll && (nextTask || showPrevious) {
+		return fmt.Errorf("--all lists the whole day and cannot be combined with --next (-n) or --previous (-p)")
+	}
+	if notifyPlan && !notifyEnabled {
+		return fmt.Errorf("--notify-plan requires --watch (-w) and --notify-ahead")
+	}
+	if cmd.Flags().Changed("default") && len(jsonFields) == 0 {
+		return fmt.Errorf("--default can only be used with --field")
+	}
+	for _, f := range jsonFields {
+		if err := output.CheckField(f); err != nil {
+			return fmt.Errorf("invalid --field: %w", err)
 		}
-	} else {
-		// 1. Resolve config file path
-		if cfgFile == "" {
-			cfgFile, err = config.FindOrCreateDefault()
-			if err != nil {
-				return err
-			}
+		if f == "day" || strings.HasPrefix(f, "day.") {
+			jsonAll = true
+		}
+	}
+	if len(jsonFields) > 0 && jsonFlat {
+		return fmt.Errorf("--field and --json-flat cannot be combined")
+	}
+	if jsonCompact || jsonFlat || len(jsonFields) > 0 {
+		jsonFmt = true
+	}
+	if outputFormat == output.FormatList {
+		return listFormats(os.Stdout)
+	}
+	switch outputFormat {
+	case output.FormatNatural:
+	case output.FormatJSON:
+		jsonFmt = true
+	case output.FormatTmux:
+		if jsonFmt {
+			return fmt.Errorf("--output tmux cannot be combined with --json")
 		}
+		if jsonAll {
+			return fmt.Errorf("--output tmux shows a single task and cannot be combined with --all")
+		}
+		if watchMode {
+			return fmt.Errorf("--output tmux is meant for polling and cannot be used with --watch (-w)")
+		}
+	default:
+		f, ok := output.LookupFormat(outputFormat)
+		if !ok {
+			return fmt.Errorf("invalid --output value '%s' (expected %s, or list)", outputFormat, strings.Join(formatNames(), ", "))
+		}
+		if jsonFmt {
+			return fmt.Errorf("--output %s cannot be combined with --json", outputFormat)
+		}
+		if f.Day && watchMode {
+			return fmt.Errorf("--output %s prints the day once and cannot be used with --watch (-w)", outputFormat)
+		}
+	}
+	if jsonFmt {
+		outputFormat = output.FormatJSON
+	}
 
-		// 2. Load Config
-		cfg, err = config.Load(cfgFile)
-		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
+	if eventsMode {
+		if !watchMode {
+			return fmt.Errorf("--events can only be used with --watch (-w)")
+		}
+		if jsonFmt || jsonAll {
+			return fmt.Errorf("--events replaces the snapshot output and cannot be combined with --json or --all")
 		}
 	}
 
-	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid config: %w", err)
+	switch colorMode {
+	case "auto", "always", "never":
+	default:
+		return fmt.Errorf("invalid --color value '%s' (expected auto, always or never)", colorMode)
+	}
+	if (alignWakeups || cmd.Flags().Changed("interval")) && !watchMode {
+		return fmt.Errorf("--align and --interval can only be used with --watch (-w)")
+	}
+	if watchInterval < 0 || (cmd.Flags().Changed("interval") && watchInterval < time.Second) {
+		return fmt.Errorf("--interval must be at least 1s")
+	}
+	if lookaheadLabel && !watchMode {
+		return fmt.Errorf("--lookahead-label can only be used with --watch (-w)")
+	}
+	if controlSocket != "" && !watchMode {
+		return fmt.Errorf("--control-socket can only be used with --watch (-w)")
+	}
+	if (cmd.Flags().Changed("for") || watchUntil != "" || cmd.Flags().Changed("max-updates")) && !watchMode {
+		return fmt.Errorf("--for, --until and --max-updates can only be used with --watch (-w)")
+	}
+	if cmd.Flags().Changed("for") && watchFor <= 0 {
+		return fmt.Errorf("--for must be positive")
+	}
+	if cmd.Flags().Changed("max-updates") && maxUpdates < 1 {
+		return fmt.Errorf("--max-updates must be at least 1")
+	}
+	stopAt, err := watchStopTime(time.Now())
+	if err != nil {
+		return err
+	}
+	if metricsAddr != "" && !watchMode {
+		return fmt.Errorf("--metrics can only be used with --watch (-w) or serve")
+	}
+	if err := checkMetricsAddr(); err != nil {
+		return err
+	}
+
+	// 1. Load Config
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
 	}
 
-	// 3. Initialize Scheduler
-	sched := scheduler.New(cfg)
+	// 2. Initialize Scheduler
+	sched := newScheduler(cfg)
 
-	// 4. Handle Watch Mode
+	// 3. Handle Watch Mode
+	if notifyPlan {
+		return runNotifyPlan(sched, cfg)
+	}
 	if watchMode {
-		return runWatch(sched, notifyEnabled)
+		// SIGINT and SIGTERM end watch mode cleanly with exit code 0, and
+		// so do --for and --until
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if !stopAt.IsZero() {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, stopAt)
+			defer cancel()
+		}
+		return runWatch(ctx, sched, cfg, notifyEnabled)
 	}
 
-	// 5. Output
+	// 4. Output
 	now := time.Now()
 	var currentTask, nextTaskEvent, previousTask *scheduler.TaskEvent
-	var dayTasks []scheduler.TaskEvent
+	var day *output.Day
 
 	// If JSON, we want both
 	if jsonFmt {
@@ -126,7 +318,7 @@ func run(cmd *cobra.Command, args []string) error {
 
 		go func() {
 			defer wg.Done()
-			nextTaskEvent, errNext = sched.GetNextTask(now)
+			nextTaskEvent, errNext = nextOrNone(sched, now)
 		}()
 
 		go func() {
@@ -138,7 +330,7 @@ func run(cmd *cobra.Command, args []string) error {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				dayTasks, errDayTasks = sched.GetTasksForDate(now)
+				day, errDayTasks = output.LoadDay(sched, now)
 			}()
 		}
 
@@ -156,202 +348,694 @@ func run(cmd *cobra.Command, args []string) error {
 		if errDayTasks != nil {
 			return errDayTasks
 		}
-	} else {
-		// Natural language mode: depends on flag
-		if nextTask {
-			// If user asked for next, we treat it as the "primary" task to print
-			currentTask, err = sched.GetNextTask(now)
-		} else {
-			currentTask, err = sched.GetCurrentTask(now)
+	} else if dayFormat() {
+		day, err = output.LoadDay(sched, now)
+		if err != nil {
+			return err
 		}
+	} else if outputFormat == output.FormatTmux {
+		// Tmux mode shows the current task, falling back to the next one
+		currentTask, err = sched.GetCurrentTask(now)
+		if err != nil {
+			return err
+		}
+		if currentTask == nil {
+			nextTaskEvent, err = nextOrNone(sched, now)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		// Natural language mode: the flags pick the task to print, and the
+		// previous and next tasks the text printed without one
+		dayCtx, err := sched.GetDayContext(now)
 		if err != nil {
 			return err
 		}
+		previousTask, nextTaskEvent = dayCtx.Previous, dayCtx.Next
+		if jsonAll {
+			if day, err = output.LoadDay(sched, now); err != nil {
+				return err
+			}
+		}
+		switch {
+		case nextTask:
+			// If user asked for next, we treat it as the "primary" task to print
+			currentTask = dayCtx.Next
+		case showPrevious:
+			currentTask = dayCtx.Previous
+		default:
+			currentTask = dayCtx.Current
+		}
 	}
 
-	return output.Print(previousTask, currentTask, nextTaskEvent, dayTasks, jsonFmt, showTime, noTaskText)
+	return output.Print(previousTask, currentTask, nextTaskEvent, day, outputOptions(sched, cfg, now))
 }
 
-func runWatch(sched *scheduler.Scheduler, notifyEnabled bool) error {
-	var notif *notifier.Notifier
+// runWatch runs watch mode until ctx is cancelled, then shuts down cleanly.
+func runWatch(ctx context.Context, sched *scheduler.Scheduler, cfg *config.Config, notifyEnabled bool) error {
+	var notif notifier.Fanout
+	var notifyState *notifier.State
+	var notifyOpts notifier.SendOptions
 	if notifyEnabled {
-		notif = notifier.New()
+		var err error
+		notif, err = notifyBackends(cfg)
+		if err != nil {
+			return err
+		}
+		notifyState = loadNotifyState()
+		notifyOpts = notifySendOptions(cfg)
 	}
 
-	// Keep track of the last task we notified about to avoid spamming
-	// We use a signature "Name|StartTime"
-	var lastNotifiedSig string
-
-	for {
-		now := time.Now()
-		effectiveNow := now.Add(lookahead)
+	var hookRunner *hooks.Runner
+	if execOnChange != "" || cfg.OnTaskStart != "" || cfg.OnTaskEnd != "" {
+		hookRunner = hooks.NewRunner()
+	}
 
-		var realCurrent, realNext, realPrevious *scheduler.TaskEvent
-		var dayTasks []scheduler.TaskEvent
-		var errCurrent, errNext, errPrevious, errDayTasks error
+	var metricsReg *metrics.Registry
+	var metricsSrv *http.Server
+	if metricsAddr != "" {
+		metricsReg = metrics.New()
+		metricsSrv = startMetrics(metricsReg, accessToken())
+	}
 
-		// Parallelize task fetching
-		var wg sync.WaitGroup
+	settings := watch.Settings{
+		Lookahead:     lookahead,
+		Notify:        notifyEnabled,
+		NotifyAhead:   notifyAhead,
+		NotifyOptions: notifyOpts,
+		Icons:         showIcons,
+		Locale:        locale,
+		Clock:         displayClock(cfg),
+		Durations:     displayDurations(cfg),
+		Align:         alignWakeups,
+		Interval:      watchInterval,
+	}
+	state := watch.State{}
+	if notifyState != nil {
+		state.Notified = notifyState
+	}
+	// Each event is written to stdout, which is unbuffered, as a single line.
+	events := json.NewEncoder(os.Stdout)
 
-		// Always fetch current and next
-		wg.Add(2)
+	sleep := sleeper{stop: ctx.Done()}
+	systemd := newServiceNotifier(&sleep)
 
+	// With a control socket or calendars, reloads swap the scheduler in
+	// holder, shared with the control socket, and wake the loop.
+	holder := scheduler.NewHolder(sched)
+	var controlSrv *http.Server
+	var wake chan struct{}
+	reload := func(source string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			slog.Warn("config reload failed", "source", source, "err", err)
+			return err
+		}
+		holder.Store(newScheduler(cfg))
+		if metricsReg != nil {
+			metricsReg.IncConfigReloads()
+		}
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+		slog.Info("config reloaded", "source", source)
+		return nil
+	}
+	if controlSocket != "" || len(cfg.Calendars) > 0 {
+		wake = make(chan struct{}, 1)
+		sleep.wake = wake
+	}
+	if len(cfg.Calendars) > 0 {
+		refreshCalendars(ctx, cfg.Calendars, func() { _ = reload("calendar") })
+	}
+	if controlSocket != "" {
+		l, err := listenControlSocket(controlSocket)
+		if err != nil {
+			return fmt.Errorf("control socket: %w", err)
+		}
+		handler := server.NewShared(holder).ControlHandler(server.Control{
+			Reload: func() error {
+				return reload("control socket")
+			},
+			NotifyTest: func() error {
+				return sendTestNotification(holder.Load().Config(), "sked", "This is a test notification.")
+			},
+		})
+		controlSrv = &http.Server{Handler: handler}
 		go func() {
-			defer wg.Done()
-			realCurrent, errCurrent = sched.GetCurrentTask(effectiveNow)
+			if err := controlSrv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("control socket stopped", "err", err)
+			}
 		}()
+	}
 
-		go func() {
-			defer wg.Done()
-			realNext, errNext = sched.GetNextTask(effectiveNow)
-		}()
+	// Reminders for the same task replace each other where the desktop
+	// supports it.
+	replacements := notifier.NewReplacements()
+	send := func(n notifier.Notification) {
+		if err := replacements.Send(notif, n, time.Now()); err != nil {
+			slog.Warn("failed to send notification", "title", n.Title, "err", err)
+			return
+		}
+		if metricsReg != nil {
+			metricsReg.IncNotifications()
+		}
+	}
+	var gate *notifyGate
+	if notifyEnabled {
+		gate = newNotifyGate(cfg, send)
+	}
 
-		if jsonFmt {
+	// prevEmpty logs the empty-schedule warning once, not every iteration.
+	prevEmpty := false
+	// listed is set once natural output with --all has printed a day, so
+	// the following ones are set apart by a blank line.
+	listed := false
+	// updates counts the snapshots, or iterations with events, printed for
+	// --max-updates.
+	updates := 0
+	for ctx.Err() == nil {
+		now := time.Now()
+		sched = holder.Load()
+		cfg = sched.Config()
+		settings.Countdown = !jsonFmt && !eventsMode && countsDown(cfg)
+
+		d, nextState, err := watch.Step(sched, now, state, settings)
+		if err != nil {
+			slog.Error("watch iteration failed", "err", err)
+			sleep.sleepUntil(time.Now().Add(errorRetryDelay))
+			continue
+		}
+		effectiveNow := d.EffectiveNow
+
+		var day *output.Day
+		var metricsTasks []scheduler.TaskEvent
+		var errDayTasks, errMetricsTasks error
+
+		// Parallelize fetching of what the output and metrics need beyond
+		// the tasks of the decision
+		var wg sync.WaitGroup
+
+		if jsonAll {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
-				realPrevious, errPrevious = sched.GetPreviousTask(effectiveNow)
+				day, errDayTasks = output.LoadDay(sched, effectiveNow)
+			}()
+		}
+
+		if metricsReg != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				metricsTasks, errMetricsTasks = sched.GetTasksForDate(effectiveNow)
 			}()
-			if jsonAll {
-				wg.Add(1)
-				go func() {
-					defer wg.Done()
-					dayTasks, errDayTasks = sched.GetTasksForDate(effectiveNow)
-				}()
-			}
 		}
 
 		wg.Wait()
 
-		if errCurrent != nil {
-			fmt.Fprintf(os.Stderr, "Error getting current task: %v\n", errCurrent)
-			time.Sleep(5 * time.Second)
+		if errDayTasks != nil {
+			slog.Error("getting day tasks", "err", errDayTasks)
+			sleep.sleepUntil(time.Now().Add(errorRetryDelay))
 			continue
 		}
-		if errNext != nil {
-			fmt.Fprintf(os.Stderr, "Error getting next task: %v\n", errNext)
-			time.Sleep(5 * time.Second)
-			continue
+		prevState := state
+		state = nextState
+		systemd.update(d)
+
+		// --- Metrics ---
+		if metricsReg != nil {
+			if errMetricsTasks != nil {
+				slog.Error("getting day tasks for metrics", "err", errMetricsTasks)
+			}
+			metricsReg.Update(effectiveNow, d.Current, d.Next, metricsTasks)
 		}
-		if jsonFmt {
-			if errPrevious != nil {
-				fmt.Fprintf(os.Stderr, "Error getting previous task: %v\n", errPrevious)
-				time.Sleep(5 * time.Second)
-				continue
+
+		if d.Empty && !prevEmpty {
+			slog.Warn("the schedule has no tasks; no task will start until the config changes", "sources", cfg.Sources)
+		}
+		prevEmpty = d.Empty
+
+		if d.Transition != nil {
+			slog.Info("task changed", "previous", taskName(d.Transition.Previous), "current", taskName(d.Current))
+		} else if d.PreviousChanged {
+			slog.Info("previous task changed", "previous", taskName(d.Previous))
+		}
+		slog.Debug("wake-up scheduled", "deadline", d.Deadline, "in", d.Deadline.Sub(now).Round(time.Millisecond).String(),
+			"current", taskName(d.Current), "next", taskName(d.Next))
+
+		// --- Hook Logic ---
+		if hookRunner != nil && d.Transition != nil {
+			tr := *d.Transition
+			// Hooks are queued in order, so A's end hook always runs before B's start hook.
+			if tr.Previous != nil {
+				hookRunner.Run(cfg.OnTaskEnd, tr.Previous, tr)
 			}
-			if errDayTasks != nil {
-				fmt.Fprintf(os.Stderr, "Error getting day tasks: %v\n", errDayTasks)
-				time.Sleep(5 * time.Second)
-				continue
+			if tr.Current != nil {
+				hookRunner.Run(cfg.OnTaskStart, tr.Current, tr)
 			}
+			hookRunner.Run(execOnChange, tr.Current, tr)
 		}
 
 		// --- Notification Logic ---
-		if notifyEnabled && notif != nil && realNext != nil {
-			// Check if we should notify about the next task
-			// We notify if:
-			// 1. We haven't notified about this specific task instance yet
-			// 2. We are within the notify-ahead window relative to the *actual* start time (not lookahead time)
-
-			// So we use `now` to check against `realNext.StartTime`.
-			// `realNext` is the next task relative to `effectiveNow`. If `lookahead` is 0, it's the next task relative to now.
-
-			triggerTime := realNext.StartTime.Add(-notifyAhead)
-			sig := fmt.Sprintf("%s|%s", realNext.Name, realNext.StartTime.Format(time.RFC3339))
-
-			if sig != lastNotifiedSig {
-				// If we are past the trigger time, send notification
-				if !now.Before(triggerTime) {
-					// Send notification asynchronously
-					msg := fmt.Sprintf("Starts at %s", realNext.StartTime.Format("15:04"))
-					if notifyAhead > 0 {
-						msg += fmt.Sprintf(" (in %s)", notifyAhead)
-					}
-
-					go func(name, message string) {
-						if err := notif.Send(name, message); err != nil {
-							fmt.Fprintf(os.Stderr, "Failed to send notification: %v\n", err)
-						}
-					}(realNext.Name, msg)
-
-					lastNotifiedSig = sig
+		for _, notice := range d.Notices() {
+			if !notice.Stale {
+				// Send asynchronously so a slow backend or presence check
+				// doesn't delay the output
+				if gate != nil {
+					go gate.deliver(ctx, notice.Notification)
+				} else {
+					go send(notice.Notification)
 				}
 			}
+
+			notifyState.Mark(notice.Signature, now)
+			notifyState.Prune(now)
+			if err := notifyState.Save(); err != nil {
+				slog.Warn("failed to save notification state", "err", err)
+			}
 		}
 
 		// --- Output Logic ---
-		var outCurrent, outNext, outPrevious *scheduler.TaskEvent
+		if eventsMode {
+			evs := watch.Events(prevState, d, now)
+			for _, e := range evs {
+				if err := events.Encode(e); err != nil {
+					return err
+				}
+			}
+			if len(evs) > 0 {
+				updates++
+			}
+			if maxUpdates > 0 && updates >= maxUpdates {
+				break
+			}
+			state.SuspendedAt = sleep.sleepUntil(d.Deadline)
+			continue
+		}
 
-		if jsonFmt {
-			outCurrent = realCurrent
-			outNext = realNext
-			outPrevious = realPrevious
-		} else {
-			if nextTask {
-				outCurrent = realNext
-			} else {
-				outCurrent = realCurrent
+		// Natural output only prints outCurrent, but picks its idle text
+		// from the previous and next tasks.
+		outCurrent, outNext, outPrevious := d.Current, d.Next, d.Previous
+		if !jsonFmt {
+			switch {
+			case nextTask:
+				outCurrent = d.Next
+			case showPrevious:
+				outCurrent = d.Previous
 			}
 		}
 
-		output.Print(outPrevious, outCurrent, outNext, dayTasks, jsonFmt, showTime, noTaskText)
+		opts := outputOptions(sched, cfg, effectiveNow)
+		if lookaheadLabel {
+			opts.Shift = lookahead
+		}
+		if day != nil && !jsonFmt {
+			if listed {
+				fmt.Println()
+			}
+			listed = true
+		}
+		output.Print(outPrevious, outCurrent, outNext, day, opts)
+		updates++
+		if maxUpdates > 0 && updates >= maxUpdates {
+			break
+		}
 
-		// --- Sleep Calculation ---
-		// We need to wake up for:
-		// 1. Current task ending (status update)
-		// 2. Next task starting (status update)
-		// 3. Notification trigger time (if enabled)
+		// Sleep in short chunks so a system suspend doesn't leave us showing stale state
+		state.SuspendedAt = sleep.sleepUntil(d.Deadline)
+	}
 
-		targetTimes := []time.Time{}
+	// --- Shutdown ---
+	// The loop only stops between iterations, so no output is cut short.
+	systemd.stopping()
+	if eventsMode {
+		if err := events.Encode(watch.Stopped(state, time.Now())); err != nil {
+			slog.Warn("failed to write stop event", "err", err)
+		}
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	for _, s := range []*http.Server{controlSrv, metricsSrv} {
+		if s != nil {
+			if err := s.Shutdown(shutdownCtx); err != nil {
+				slog.Warn("failed to stop server", "err", err)
+			}
+		}
+	}
+	if notifyState != nil {
+		notifyState.Prune(time.Now())
+		if err := notifyState.Save(); err != nil {
+			slog.Warn("failed to save notification state", "err", err)
+		}
+	}
+	return nil
+}
 
-		if realCurrent != nil {
-			targetTimes = append(targetTimes, realCurrent.EndTime.Add(-lookahead))
+// watchStopTime returns when watch mode should stop for --for and --until,
+// the earlier of the two, or the zero time without either. --until must
+// still be ahead today.
+func watchStopTime(now time.Time) (time.Time, error) {
+	var stopAt time.Time
+	if watchFor > 0 {
+		stopAt = now.Add(watchFor)
+	}
+	if watchUntil != "" {
+		t, err := time.Parse("15:04", watchUntil)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --until value '%s' (expected HH:MM)", watchUntil)
 		}
+		until := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !until.After(now) {
+			return time.Time{}, fmt.Errorf("--until %s has already passed today", watchUntil)
+		}
+		if stopAt.IsZero() || until.Before(stopAt) {
+			stopAt = until
+		}
+	}
+	return stopAt, nil
+}
 
-		if realNext != nil {
-			// Wake up when next task starts (status update)
-			targetTimes = append(targetTimes, realNext.StartTime.Add(-lookahead))
+// countsDown reports whether an idle text of natural output shows
+// {next_in}, which watch mode has to refresh every minute.
+func countsDown(cfg *config.Config) bool {
+	for _, text := range []string{noTaskText, cfg.NoTaskBefore, cfg.NoTaskBetween, cfg.NoTaskAfter, cfg.OffDayText} {
+		if strings.Contains(text, "{next_in}") {
+			return true
+		}
+	}
+	return false
+}
 
-			// Wake up for notification
-			if notifyEnabled && notif != nil {
-				// We want to wake up exactly at triggerTime
-				triggerTime := realNext.StartTime.Add(-notifyAhead)
-				// Only if it's in the future
-				if triggerTime.After(now) {
-					targetTimes = append(targetTimes, triggerTime)
-				}
-			}
+// taskName returns the name of t for log records, or "" if there is none.
+func taskName(t *scheduler.TaskEvent) string {
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
+
+// outputOptions collects the output settings from flags and config.
+func outputOptions(sched *scheduler.Scheduler, cfg *config.Config, now time.Time) output.Options {
+	opts := output.Options{
+		Format:        outputFormat,
+		MaxWidth:      maxWidth,
+		Compact:       jsonCompact,
+		Fields:        jsonFields,
+		Flat:          jsonFlat,
+		FieldDefault:  fieldDefault,
+		ShowTime:      showTime,
+		Relative:      relative,
+		Clock:         displayClock(cfg),
+		Durations:     displayDurations(cfg),
+		NoTaskText:    noTaskText,
+		NoTaskBefore:  cfg.NoTaskBefore,
+		NoTaskBetween: cfg.NoTaskBetween,
+		NoTaskAfter:   cfg.NoTaskAfter,
+		Color:         output.ColorEnabled(colorMode),
+		Colors:        cfg.Colors,
+		OffDayText:    cfg.OffDayText,
+		Icons:         showIcons,
+		Locale:        locale,
+		Now:           now,
+	}
+	if info, err := sched.GetDayInfo(now); err == nil {
+		opts.OffDay = info.IsOff
+		opts.DayNote = info.Note
+	}
+	if outputFormat == output.FormatJSON || dayFormat() {
+		opts.Status = taskStatuses()
+	}
+	if conflicts, err := sched.GetConflicts(now); err == nil && len(conflicts) > 0 {
+		opts.Conflicts = func(t scheduler.TaskEvent) []string {
+			return scheduler.ConflictsWith(conflicts, t)
 		}
+	}
+	return opts
+}
 
-		// Find the earliest target time that is in the future
-		var earliestTarget time.Time
-		for _, t := range targetTimes {
-			if t.After(now) {
-				if earliestTarget.IsZero() || t.Before(earliestTarget) {
-					earliestTarget = t
-				}
-			}
+// dayFormat reports whether the --output format renders the whole day.
+func dayFormat() bool {
+	f, _ := output.LookupFormat(outputFormat)
+	return f.Day
+}
+
+// formatNames returns the names of the registered output formats.
+func formatNames() []string {
+	var names []string
+	for _, f := range output.Formats() {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// listFormats prints the registered output formats for '--output list'.
+func listFormats(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, f := range output.Formats() {
+		fmt.Fprintf(w, "%s\t%s\n", f.Name, f.Description)
+	}
+	return w.Flush()
+}
+
+// displayClock returns the clock that times are shown on: 12-hour with
+// --12h, else the clock of cfg, which is nil for commands that don't load
+// the config.
+func displayClock(cfg *config.Config) config.Clock {
+	if twelveHour {
+		return config.Clock12
+	}
+	if cfg == nil {
+		return config.Clock24
+	}
+	return cfg.Clock
+}
+
+// displayDurations returns how durations are shown: in the duration_style
+// of cfg, rounded to minutes unless --precise.
+func displayDurations(cfg *config.Config) config.Durations {
+	return config.Durations{Style: cfg.DurationStyle, Precise: precise}
+}
+
+// configPath returns the config file selected by --config, $SKED_CONFIG, a
+// project file or the default location, in that order, and logs the choice.
+// With create, a missing default config is created if mayCreate allows it;
+// otherwise a *config.NotFoundError is returned.
+func configPath(create bool) (string, error) {
+	wd, _ := os.Getwd()
+	path, source, err := config.Resolve(cfgFile, wd)
+	if err != nil {
+		return "", err
+	}
+	slog.Info("config selected", "path", path, "source", source)
+	if source != config.SourceDefault || !create {
+		return path, nil
+	}
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if !mayCreate() {
+		return "", &config.NotFoundError{Path: path}
+	}
+	return config.FindOrCreateDefault()
+}
+
+// mayCreate reports whether a missing default config may be created: only
+// on interactive runs, where stdout is a terminal, unless --no-create says
+// otherwise either way.
+func mayCreate() bool {
+	if noCreateSet() {
+		return !noCreate
+	}
+	return stdoutIsTerminal()
+}
+
+// noCreateSet reports whether --no-create was given. init sets it, as
+// referring to rootCmd here would make its initialization depend on itself.
+var noCreateSet func() bool
+
+// stdoutIsTerminal reports whether stdout is a terminal; tests replace it.
+// Unlike a character-device check, it isn't fooled by /dev/null.
+var stdoutIsTerminal = func() bool {
+	fd := os.Stdout.Fd()
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
+// loadConfig loads and validates the configuration selected by --inline,
+// --tmp or configPath.
+func loadConfig() (*config.Config, error) {
+	var cfg *config.Config
+	var err error
+
+	if temporary() {
+		cfg, err = loadTemporary()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// 1. Resolve config file path
+		path, err := configPath(true)
+		if err != nil {
+			return nil, err
 		}
 
-		var waitDuration time.Duration
-		if earliestTarget.IsZero() {
-			// No known future events. Check back in a minute.
-			waitDuration = 1 * time.Minute
+		// 2. Load Config, from the compiled snapshot if requested
+		if useCache {
+			cfg, err = loadCachedConfig(path)
 		} else {
-			waitDuration = earliestTarget.Sub(now)
+			cfg, err = config.Load(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
 		}
+	}
+
+	if err := reportWarnings(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	applyCalendars(cfg, time.Now())
+	reportEmpty(cfg)
+	return cfg, nil
+}
+
+// nextOrNone is GetNextTask for callers to which an empty schedule,
+// already reported by loadConfig, just means there is no next task.
+func nextOrNone(sched *scheduler.Scheduler, now time.Time) (*scheduler.TaskEvent, error) {
+	next, err := sched.GetNextTask(now)
+	if errors.Is(err, scheduler.ErrEmptySchedule) {
+		return nil, nil
+	}
+	return next, err
+}
 
-		// Add a small buffer to ensure we land in the next state
-		if waitDuration < 0 {
-			waitDuration = 0
+// temporary reports whether --inline or --tmp replaces the config.
+func temporary() bool {
+	return inlineSched != "" || tmpFile != ""
+}
+
+// loadTemporary loads the one-day schedule of --inline or --tmp, with the
+// configured schedule, if it loads, as its base. With "--tmp -", stdin is
+// read once and reused by every reload.
+func loadTemporary() (*config.Config, error) {
+	if inlineSched != "" {
+		cfg, err := config.ParseInline(inlineSched)
+		if err != nil {
+			return nil, err
+		}
+		return withBase(cfg, "--inline"), nil
+	}
+	if tmpFile == "-" {
+		data, err := readStdin()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read temporary config from stdin: %w", err)
+		}
+		cfg, err := config.ReadTmpCSV("<stdin>", bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load temporary config: %w", err)
 		}
+		return withBase(cfg, "<stdin>"), nil
+	}
+	cfg, err := config.LoadTmpCSV(tmpFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load temporary config: %w", err)
+	}
+	return withBase(cfg, tmpFile), nil
+}
 
-		// Sleep
-		if waitDuration > 0 {
-			time.Sleep(waitDuration + 50*time.Millisecond)
-		} else {
-			// If we are already past target, just yield briefly to avoid tight loop in weird cases
-			time.Sleep(50 * time.Millisecond)
+// withBase makes the configured schedule the base of the temporary cfg
+// loaded from label, and warns about the tasks of cfg overlapping it today.
+// Without a config, or one that doesn't load, cfg stands alone.
+func withBase(cfg *config.Config, label string) *config.Config {
+	path, err := configPath(false)
+	if err != nil {
+		return cfg
+	}
+	base, err := config.Load(path)
+	if err == nil {
+		err = base.Validate()
+	}
+	if err != nil {
+		slog.Debug("no base schedule for conflicts", "path", path, "err", err)
+		return cfg
+	}
+	setBase(cfg, base, label)
+	return cfg
+}
+
+// setBase makes base the base of the temporary cfg loaded from label and
+// adds a warning for each task of cfg that overlaps a base task today.
+func setBase(cfg, base *config.Config, label string) {
+	cfg.Base = base
+	conflicts, err := scheduler.New(cfg).GetConflicts(time.Now())
+	if err != nil {
+		return
+	}
+	for _, c := range conflicts {
+		cfg.Warnings = append(cfg.Warnings, config.Warning{File: label, Message: c.String()})
+	}
+}
+
+// readStdin returns all of stdin, read on the first call.
+var readStdin = sync.OnceValues(func() ([]byte, error) {
+	return io.ReadAll(os.Stdin)
+})
+
+// notifyBackends returns the notification backends configured in cfg:
+// always the desktop (or notify_command), plus a webhook if one is configured.
+func notifyBackends(cfg *config.Config) (notifier.Fanout, error) {
+	desktop := notifier.New()
+	desktop.Command = cfg.NotifyCommand
+	backends := notifier.Fanout{desktop}
+
+	if wh := cfg.Notify.Webhook; wh != nil {
+		webhook, err := notifier.NewWebhook(wh.URL, wh.Method, wh.Format, wh.Body, wh.Headers)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notify.webhook: %w", err)
 		}
+		backends = append(backends, webhook)
+	}
+	return backends, nil
+}
+
+// notifySendOptions builds the notification appearance from the config.
+// The config has already been validated, so parse errors can't occur here.
+func notifySendOptions(cfg *config.Config) notifier.SendOptions {
+	opts := notifier.SendOptions{
+		Urgency:  cfg.NotifyUrgency,
+		IconName: cfg.NotifyIcon,
+	}
+	if opts.Urgency == "" {
+		opts.Urgency = notifier.UrgencyNormal
+	}
+	if cfg.NotifyTimeout != "" {
+		timeout, _ := time.ParseDuration(cfg.NotifyTimeout)
+		opts.ExpireMs = int(timeout.Milliseconds())
+	}
+	return opts
+}
+
+// loadNotifyState returns the notification history used to avoid duplicate
+// notifications. Persistence failures are reported but never fatal.
+func loadNotifyState() *notifier.State {
+	if noNotifyState {
+		return notifier.NewMemoryState()
+	}
+
+	path, err := notifier.DefaultStatePath()
+	if err != nil {
+		slog.Warn("notification state disabled", "err", err)
+		return notifier.NewMemoryState()
+	}
+
+	state, err := notifier.LoadState(path)
+	if err != nil {
+		slog.Warn("failed to load notification state", "err", err)
 	}
+	state.Prune(time.Now())
+	return state
 }
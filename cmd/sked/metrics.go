@@ -0,0 +1,51 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/Daniel-42-z/sked/internal/metrics"
+	"github.com/Daniel-42-z/sked/internal/server"
+)
+
+var metricsAddr string
+
+// checkMetricsAddr applies serve's rule for --listen to --metrics: only
+// loopback addresses, unless --public is given.
+func checkMetricsAddr() error {
+	if metricsAddr == "" {
+		return nil
+	}
+	return checkListenAddr("--metrics", metricsAddr, servePublic)
+}
+
+// metricsHandler serves reg at /metrics, requiring token if it is set.
+func metricsHandler(reg *metrics.Registry, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", reg.Handler())
+	if token != "" {
+		return server.RequireToken(token, mux)
+	}
+	return mux
+}
+
+// startMetrics serves reg on metricsAddr in the background and returns the
+// server so it can be shut down. Listener errors are reported but do not stop
+// the main command.
+func startMetrics(reg *metrics.Registry, token string) *http.Server {
+	srv := &http.Server{Addr: metricsAddr, Handler: metricsHandler(reg, token)}
+	if servePublic && token == "" {
+		slog.Warn("serving metrics on a public address without --token; anyone on the network can read the current task", "metrics", metricsAddr)
+	}
+
+	go func() {
+		fmt.Fprintf(os.Stderr, "Serving metrics on %s/metrics\n", metricsAddr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("metrics server stopped", "err", err)
+		}
+	}()
+	return srv
+}
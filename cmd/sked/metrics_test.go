@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Daniel-42-z/sked/internal/metrics"
+)
+
+func TestMetricsHandler_Token(t *testing.T) {
+	h := metricsHandler(metrics.New(), "secret")
+	tests := []struct {
+		name   string
+		auth   string
+		status int
+	}{
+		{name: "missing", status: http.StatusUnauthorized},
+		{name: "wrong", auth: "Bearer nope", status: http.StatusUnauthorized},
+		{name: "valid", auth: "Bearer secret", status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	metricsHandler(metrics.New(), "").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected metrics without a token to be open, got %d", rec.Code)
+	}
+}
+
+func TestCheckMetricsAddr(t *testing.T) {
+	defer func(addr string, public bool) { metricsAddr, servePublic = addr, public }(metricsAddr, servePublic)
+
+	metricsAddr, servePublic = ":9374", false
+	if err := checkMetricsAddr(); err == nil {
+		t.Error("Expected an error for metrics on all interfaces without --public")
+	}
+	servePublic = true
+	if err := checkMetricsAddr(); err != nil {
+		t.Errorf("Expected --public to allow it, got %v", err)
+	}
+	metricsAddr, servePublic = "127.0.0.1:9374", false
+	if err := checkMetricsAddr(); err != nil {
+		t.Errorf("Expected a loopback address to be allowed, got %v", err)
+	}
+}
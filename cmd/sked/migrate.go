@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Rewrite the TOML config in the newest syntax",
+	Long: `Rewrite the TOML config in the syntax of the newest config_version,
+after copying it to a timestamped .bak file next to it. Comments and layout
+are kept. Older configs keep working without migrating; sked upgrades them
+in memory when loading.`,
+	Args: cobra.NoArgs,
+	RunE: runMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	if temporary() {
+		return fmt.Errorf("temporary configs have no config_version to migrate")
+	}
+	path, err := configPath(true)
+	if err != nil {
+		return err
+	}
+	if strings.ToLower(filepath.Ext(path)) != ".toml" {
+		return fmt.Errorf("only TOML configs have a config_version; %s is not one", path)
+	}
+
+	res, err := config.Migrate(path)
+	if err != nil {
+		return err
+	}
+	if res.From == res.To {
+		fmt.Printf("%s is already at config_version %d.\n", path, res.To)
+		return nil
+	}
+	if len(res.Changes) == 0 {
+		fmt.Printf("Migrated %s from config_version %d to %d; only config_version changed.\n", path, res.From, res.To)
+	} else {
+		fmt.Printf("Migrated %s from config_version %d to %d:\n", path, res.From, res.To)
+	}
+	for _, c := range res.Changes {
+		fmt.Printf("  - %s\n", c)
+	}
+	if res.Backup != "" {
+		fmt.Printf("Backup: %s\n", res.Backup)
+	}
+	return nil
+}
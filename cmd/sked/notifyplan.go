@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/notifier"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+	"github.com/Daniel-42-z/sked/internal/watch"
+)
+
+// notifyPlan lists upcoming notifications instead of running watch mode.
+var notifyPlan bool
+
+// notifyPlanWindow is how far ahead --notify-plan looks.
+const notifyPlanWindow = 24 * time.Hour
+
+// plannedNotification is the JSON form of a planned notification.
+type plannedNotification struct {
+	Trigger       string   `json:"trigger"`
+	TriggerUnix   int64    `json:"trigger_unix"`
+	Task          string   `json:"task"`
+	Start         string   `json:"start"`
+	OffsetSeconds int64    `json:"offset_seconds"`
+	Message       string   `json:"message"`
+	Urgency       string   `json:"urgency,omitempty"`
+	Backends      []string `json:"backends"`
+}
+
+// runNotifyPlan prints every notification watch mode would send in the next
+// 24 hours, without sleeping or sending anything.
+func runNotifyPlan(sched *scheduler.Scheduler, cfg *config.Config) error {
+	backends, err := notifyBackends(cfg)
+	if err != nil {
+		return err
+	}
+	names := make([]string, len(backends))
+	for i, b := range backends {
+		names[i] = b.Name()
+	}
+
+	settings := watch.Settings{
+		Lookahead:     lookahead,
+		Notify:        true,
+		NotifyAhead:   notifyAhead,
+		NotifyOptions: notifySendOptions(cfg),
+		Icons:         showIcons,
+		Locale:        locale,
+		Clock:         displayClock(cfg),
+		Durations:     displayDurations(cfg),
+	}
+	state := watch.State{}
+	if !noNotifyState {
+		// Skip what a running watch process already sent, but never write the history.
+		if path, err := notifier.DefaultStatePath(); err == nil {
+			if st, err := notifier.LoadState(path); err == nil {
+				state.Notified = st
+			}
+		}
+	}
+
+	now := time.Now()
+	plan, err := watch.Plan(sched, now, now.Add(notifyPlanWindow), state, settings)
+	if err != nil {
+		return err
+	}
+
+	if jsonFmt {
+		out := make([]plannedNotification, len(plan))
+		for i, p := range plan {
+			n := p.Notification
+			out[i] = plannedNotification{
+				Trigger:       p.Trigger.Format(time.RFC3339),
+				TriggerUnix:   p.Trigger.Unix(),
+				Task:          n.TaskName,
+				Start:         n.TaskStart.Format(time.RFC3339),
+				OffsetSeconds: int64(p.Offset.Seconds()),
+				Message:       n.Message,
+				Urgency:       n.Options.Urgency,
+				Backends:      names,
+			}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		if !jsonCompact {
+			enc.SetIndent("", "  ")
+		}
+		return enc.Encode(out)
+	}
+
+	if len(plan) == 0 {
+		fmt.Println("No notifications in the next 24 hours.")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TRIGGER\tTASK\tSTART\tOFFSET\tBACKENDS")
+	for _, p := range plan {
+		n := p.Notification
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			p.Trigger.Format("Mon 15:04:05"), n.TaskName, displayClock(cfg).Format(n.TaskStart), displayDurations(cfg).Format(p.Offset), strings.Join(names, ", "))
+	}
+	return w.Flush()
+}
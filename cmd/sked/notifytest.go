@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/notifier"
+
+	"github.com/spf13/cobra"
+)
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "notify-test [title] [message]",
+	Short: "Send a test notification through the configured backends",
+	Args:  cobra.MaximumNArgs(2),
+	RunE:  runNotifyTest,
+}
+
+func init() {
+	rootCmd.AddCommand(notifyTestCmd)
+}
+
+func runNotifyTest(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	title, message := "sked", "This is a test notification."
+	if len(args) > 0 {
+		title = args[0]
+	}
+	if len(args) > 1 {
+		message = args[1]
+	}
+	return sendTestNotification(cfg, title, message)
+}
+
+// sendTestNotification sends a notification through the backends of cfg.
+func sendTestNotification(cfg *config.Config, title, message string) error {
+	backends, err := notifyBackends(cfg)
+	if err != nil {
+		return err
+	}
+	n := notifier.Notification{
+		Title:   title,
+		Message: message,
+		Options: notifySendOptions(cfg),
+	}
+	if err := backends.Notify(n); err != nil {
+		return fmt.Errorf("failed to send test notification: %w", err)
+	}
+	return nil
+}
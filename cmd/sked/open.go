@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/opener"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+
+	"github.com/spf13/cobra"
+)
+
+var openCmd = &cobra.Command{
+	Use:   "open",
+	Short: "Open the current task's URL",
+	Long: `Open the url of the task in progress with the platform's default handler
+(xdg-open, open or the Windows URL handler).`,
+	Args: cobra.NoArgs,
+	RunE: runOpen,
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	task, err := scheduler.New(cfg).GetCurrentTask(time.Now())
+	if err != nil {
+		return err
+	}
+	if err := openTaskURL(task); err != nil {
+		return err
+	}
+	fmt.Printf("Opened %s\n", task.URL)
+	return nil
+}
+
+// openTaskURL opens the url of task, failing if there is no task or it has no url.
+func openTaskURL(task *scheduler.TaskEvent) error {
+	if task == nil || task.RawName == "/" {
+		return fmt.Errorf("no task in progress")
+	}
+	if task.URL == "" {
+		return fmt.Errorf("task '%s' has no url", task.Name)
+	}
+	return opener.Open(task.URL)
+}
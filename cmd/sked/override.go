@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var overrideCmd = &cobra.Command{
+	Use:   "override",
+	Short: "Inspect date overrides",
+}
+
+var overrideListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List overrides and the winner on dates where several apply",
+	Long: `List the overrides of the config in file order, followed by every date
+matched by more than one of them and the override that takes effect there.
+Single-date overrides beat ranges; among the same kind, the later entry wins.`,
+	Args: cobra.NoArgs,
+	RunE: runOverrideList,
+}
+
+func init() {
+	overrideCmd.AddCommand(overrideListCmd)
+	rootCmd.AddCommand(overrideCmd)
+}
+
+func runOverrideList(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if len(cfg.Overrides) == 0 {
+		fmt.Println("No overrides.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tOVERRIDE")
+	for i, o := range cfg.Overrides {
+		fmt.Fprintf(w, "%d\t%s\n", i+1, o.Describe())
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	conflicts := cfg.OverrideConflicts()
+	if len(conflicts) == 0 {
+		return nil
+	}
+	fmt.Println()
+	fmt.Println("Dates matched by several overrides:")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tEFFECTIVE\tSHADOWED")
+	for _, c := range conflicts {
+		shadowed := ""
+		for j, idx := range c.Overrides[1:] {
+			if j > 0 {
+				shadowed += ", "
+			}
+			shadowed += fmt.Sprintf("#%d", idx+1)
+		}
+		fmt.Fprintf(w, "%s\t#%d %s\t%s\n", c.Date.Format("2006-01-02"), c.Overrides[0]+1, cfg.Overrides[c.Overrides[0]].Describe(), shadowed)
+	}
+	return w.Flush()
+}
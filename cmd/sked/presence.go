@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/notifier"
+)
+
+// idleRecheckInterval is how often notifications held back while the
+// session is idle are re-evaluated.
+const idleRecheckInterval = 30 * time.Second
+
+// notifyGate holds back watch-mode notifications while the user is away
+// (suppress_when_idle) or has Do-Not-Disturb on (suppress_when_dnd).
+type notifyGate struct {
+	gate notifier.Gate
+	// resend holds notifications suppressed for idleness and sends them
+	// once the user returns, if their task hasn't started yet.
+	resend bool
+	send   func(notifier.Notification)
+
+	mu       sync.Mutex
+	held     []notifier.Notification
+	checking bool
+}
+
+// newNotifyGate returns a gate for the config's presence settings, or nil
+// if there are none. The config has already been validated.
+func newNotifyGate(cfg *config.Config, send func(notifier.Notification)) *notifyGate {
+	if cfg.SuppressWhenIdle == "" && !cfg.SuppressWhenDND {
+		return nil
+	}
+	idle, _ := time.ParseDuration(cfg.SuppressWhenIdle)
+	return &notifyGate{
+		gate: notifier.Gate{
+			Presence:  notifier.NewDesktopPresence(),
+			IdleAfter: idle,
+			DND:       cfg.SuppressWhenDND,
+		},
+		resend: cfg.ResendAfterIdle,
+		send:   send,
+	}
+}
+
+// deliver sends n unless the user is away. Querying the desktop may take a
+// moment, so deliver is meant to run in its own goroutine.
+func (g *notifyGate) deliver(ctx context.Context, n notifier.Notification) {
+	reason := g.gate.Check()
+	if reason == "" {
+		g.send(n)
+		return
+	}
+	slog.Info("notification suppressed", "title", n.Title, "reason", reason)
+	if reason != notifier.SuppressedIdle || !g.resend {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.held = append(g.held, n)
+	if !g.checking {
+		g.checking = true
+		go g.recheckLoop(ctx)
+	}
+}
+
+// recheckLoop re-evaluates held notifications until they're released or
+// dropped.
+func (g *notifyGate) recheckLoop(ctx context.Context) {
+	ticker := time.NewTicker(idleRecheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if g.recheck(time.Now()) {
+			return
+		}
+	}
+}
+
+// recheck handles the held notifications at now and reports whether none
+// are left. While the session is still idle, notifications whose task has
+// started are dropped. Once the user is back, the rest get a single second
+// chance: they're sent if the gate lets them through, dropped otherwise.
+func (g *notifyGate) recheck(now time.Time) bool {
+	reason := g.gate.Check()
+
+	g.mu.Lock()
+	var keep, release, started, drop []notifier.Notification
+	for _, n := range g.held {
+		switch {
+		case !n.TaskStart.After(now):
+			started = append(started, n)
+		case reason == notifier.SuppressedIdle:
+			keep = append(keep, n)
+		case reason == "":
+			release = append(release, n)
+		default:
+			drop = append(drop, n)
+		}
+	}
+	g.held = keep
+	done := len(keep) == 0
+	if done {
+		g.checking = false
+	}
+	g.mu.Unlock()
+
+	for _, n := range started {
+		slog.Info("held notification dropped", "title", n.Title, "reason", "task started")
+	}
+	for _, n := range drop {
+		slog.Info("held notification dropped", "title", n.Title, "reason", reason)
+	}
+	for _, n := range release {
+		slog.Info("sending held notification", "title", n.Title)
+		g.send(n)
+	}
+	return done
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/notifier"
+)
+
+type stubPresence struct {
+	idle time.Duration
+	dnd  bool
+}
+
+func (s *stubPresence) IdleTime() (time.Duration, error) { return s.idle, nil }
+func (s *stubPresence) DoNotDisturb() (bool, error)      { return s.dnd, nil }
+
+func TestNotifyGate_ResendAfterIdle(t *testing.T) {
+	now := time.Date(2025, 3, 3, 9, 50, 0, 0, time.UTC)
+	presence := &stubPresence{idle: 20 * time.Minute}
+	var sent []string
+	g := &notifyGate{
+		gate:   notifier.Gate{Presence: presence, IdleAfter: 10 * time.Minute, DND: true},
+		resend: true,
+		send:   func(n notifier.Notification) { sent = append(sent, n.Title) },
+		// Keep deliver from starting the background re-check.
+		checking: true,
+	}
+
+	g.deliver(t.Context(), notifier.Notification{Title: "Math", TaskStart: now.Add(10 * time.Minute)})
+	g.deliver(t.Context(), notifier.Notification{Title: "Art", TaskStart: now.Add(2 * time.Minute)})
+	if len(sent) != 0 {
+		t.Fatalf("Expected nothing sent while idle, got %v", sent)
+	}
+
+	// Still idle after Art started: Art is dropped, Math still held.
+	if g.recheck(now.Add(5 * time.Minute)) {
+		t.Fatal("Expected Math to stay held")
+	}
+	if len(sent) != 0 || len(g.held) != 1 {
+		t.Fatalf("Expected only Math held, got sent %v, held %d", sent, len(g.held))
+	}
+
+	presence.idle = 0
+	if !g.recheck(now.Add(6 * time.Minute)) {
+		t.Fatal("Expected nothing held after the user returned")
+	}
+	if len(sent) != 1 || sent[0] != "Math" {
+		t.Errorf("Expected Math sent on return, got %v", sent)
+	}
+}
+
+func TestNotifyGate_DNDNotResent(t *testing.T) {
+	now := time.Date(2025, 3, 3, 9, 50, 0, 0, time.UTC)
+	var sent []string
+	g := &notifyGate{
+		gate:     notifier.Gate{Presence: &stubPresence{dnd: true}, DND: true},
+		resend:   true,
+		send:     func(n notifier.Notification) { sent = append(sent, n.Title) },
+		checking: true,
+	}
+
+	g.deliver(t.Context(), notifier.Notification{Title: "Math", TaskStart: now.Add(10 * time.Minute)})
+	if len(sent) != 0 || len(g.held) != 0 {
+		t.Errorf("Expected notification suppressed and not held, got sent %v, held %d", sent, len(g.held))
+	}
+}
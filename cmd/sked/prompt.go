@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	promptMaxWidth int
+	promptHideFree bool
+	promptColor    bool
+	promptIcon     string
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print a shell prompt segment for the current task",
+	Long: `Print a short segment for shell prompts such as starship or powerlevel10k:
+the current task and the time it has left ("Math 12m"), or the next task while
+nothing is in progress ("→ Art in 1h05m").
+
+The segment never ends in a newline. It is empty, with exit status 0, on off
+days, when nothing more is scheduled today and, with --hide-when-free, while
+no task is in progress, so prompts can hide it when the output is empty. The
+config is read through the compiled snapshot cache (like --cache), and
+colors are only written with --color.`,
+	Example: `  sked prompt --icon $'\uf017' --hide-when-free   # a clock glyph, then "Math 12m"`,
+	Args:    cobra.NoArgs,
+	RunE:    runPrompt,
+}
+
+func init() {
+	promptCmd.Flags().IntVar(&promptMaxWidth, "max-width", output.DefaultMaxWidth, "maximum task name width")
+	promptCmd.Flags().BoolVar(&promptHideFree, "hide-when-free", false, "print nothing while no task is in progress")
+	promptCmd.Flags().BoolVar(&promptColor, "color", false, "color the segment with ANSI codes")
+	promptCmd.Flags().StringVar(&promptIcon, "icon", "", "text before the segment, such as a nerd font glyph")
+	addTagFlag(promptCmd)
+	rootCmd.AddCommand(promptCmd)
+}
+
+func runPrompt(cmd *cobra.Command, args []string) error {
+	// Prompts run on every redraw, so skip parsing the config whenever the
+	// snapshot is current
+	useCache = true
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	sched := newScheduler(cfg)
+	now := time.Now()
+	if off, err := sched.IsOffDay(now); err != nil || off {
+		return err
+	}
+	current, err := sched.GetCurrentTask(now)
+	if err != nil {
+		return err
+	}
+	next, err := nextOrNone(sched, now)
+	if err != nil {
+		return err
+	}
+	return output.WritePrompt(os.Stdout, current, next, output.Options{
+		MaxWidth:     promptMaxWidth,
+		HideWhenFree: promptHideFree,
+		PromptIcon:   promptIcon,
+		Color:        promptColor,
+		Colors:       cfg.Colors,
+		Icons:        showIcons,
+		Locale:       locale,
+		Durations:    displayDurations(cfg),
+		Now:          now,
+	})
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/i18n"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+
+	"github.com/spf13/cobra"
+)
+
+var remainingJSON bool
+
+var remainingCmd = &cobra.Command{
+	Use:   "remaining",
+	Short: "Show how many of today's tasks are left and how long they take",
+	Long: `Show how many of today's tasks haven't ended yet and the time they still
+cover, e.g. "3 tasks left (2h40m)". Only the rest of a task in progress
+counts, and overlapping tasks count once. Empty slots are ignored. Off days
+have nothing left.`,
+	Args: cobra.NoArgs,
+	RunE: runRemaining,
+}
+
+// remainingOutput is the JSON form of what is left of today, named like
+// the fields of the JSON day object.
+type remainingOutput struct {
+	Date                  string `json:"date"`
+	IsOff                 bool   `json:"is_off"`
+	RemainingToday        int    `json:"remaining_today"`
+	RemainingMinutesToday int    `json:"remaining_minutes_today"`
+}
+
+func init() {
+	remainingCmd.Flags().BoolVarP(&remainingJSON, "json", "j", false, "output in JSON format")
+	addTagFlag(remainingCmd)
+	rootCmd.AddCommand(remainingCmd)
+}
+
+func runRemaining(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	sched := newScheduler(cfg)
+	now := time.Now()
+	info, err := sched.GetDayInfo(now)
+	if err != nil {
+		return err
+	}
+	r, err := sched.GetRemaining(now)
+	if err != nil {
+		return err
+	}
+
+	if remainingJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(remainingOutput{
+			Date:                  now.Format("2006-01-02"),
+			IsOff:                 info.IsOff,
+			RemainingToday:        r.Tasks,
+			RemainingMinutesToday: int(r.Time.Minutes()),
+		})
+	}
+	fmt.Println(remainingText(r, info.IsOff, displayDurations(cfg).Format, locale))
+	return nil
+}
+
+// remainingText is the line 'sked remaining' prints in l, formatting the
+// time left with format.
+func remainingText(r scheduler.Remaining, isOff bool, format func(time.Duration) string, l *i18n.Locale) string {
+	switch {
+	case isOff:
+		return l.T("none_left_off")
+	case r.Tasks == 0:
+		return l.T("none_left")
+	case r.Tasks == 1:
+		return l.T("task_left", r.Tasks, format(r.Time))
+	}
+	return l.T("tasks_left", r.Tasks, format(r.Time))
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/i18n"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func TestRemainingText(t *testing.T) {
+	format := config.Durations{}.Format
+	de, _ := i18n.Lookup("de")
+	tests := []struct {
+		r      scheduler.Remaining
+		isOff  bool
+		locale *i18n.Locale
+		want   string
+	}{
+		{r: scheduler.Remaining{Tasks: 3, Time: 160 * time.Minute}, want: "3 tasks left (2h40m)"},
+		{r: scheduler.Remaining{Tasks: 1, Time: 50 * time.Minute}, want: "1 task left (50m)"},
+		{want: "0 tasks left"},
+		{isOff: true, want: "0 tasks left (off day)"},
+		{r: scheduler.Remaining{Tasks: 3, Time: 160 * time.Minute}, locale: de, want: "3 Aufgaben übrig (2h40m)"},
+		{r: scheduler.Remaining{Tasks: 1, Time: 50 * time.Minute}, locale: de, want: "1 Aufgabe übrig (50m)"},
+		{isOff: true, locale: de, want: "0 Aufgaben übrig (freier Tag)"},
+	}
+	for _, tt := range tests {
+		if got := remainingText(tt.r, tt.isOff, format, tt.locale); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/metrics"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+	"github.com/Daniel-42-z/sked/internal/server"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	listenAddr  string
+	servePublic bool
+	serveToken  string
+	serveCert   string
+	serveKey    string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the schedule as JSON over HTTP",
+	Long: `serve exposes the schedule over HTTP with the endpoints
+/current, /next, /previous, /day?date=YYYY-MM-DD, /range?from=...&to=... and /healthz.
+Send SIGHUP to reload the configuration without restarting. Calendar feeds
+are refreshed in the background and reload the schedule when they change.
+
+serve listens on localhost only unless --public is given. With --token (or
+SKED_TOKEN), every endpoint except /healthz requires the header
+"Authorization: Bearer <token>" or a ?token= query parameter. The --metrics
+listener follows the same rules. --cert and --key enable TLS.`,
+	RunE: runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&listenAddr, "listen", "127.0.0.1:8374", "address to listen on")
+	serveCmd.Flags().BoolVar(&servePublic, "public", false, "allow listening on addresses other than localhost")
+	serveCmd.Flags().StringVar(&serveToken, "token", "", "require this bearer token (default $SKED_TOKEN)")
+	serveCmd.Flags().StringVar(&serveCert, "cert", "", "TLS certificate file (requires --key)")
+	serveCmd.Flags().StringVar(&serveKey, "key", "", "TLS key file (requires --cert)")
+	serveCmd.Flags().StringVar(&metricsAddr, "metrics", "", "serve Prometheus metrics on this address")
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	if err := checkListenAddr("--listen", listenAddr, servePublic); err != nil {
+		return err
+	}
+	if err := checkMetricsAddr(); err != nil {
+		return err
+	}
+	if (serveCert == "") != (serveKey == "") {
+		return fmt.Errorf("--cert and --key must be given together")
+	}
+	token := accessToken()
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	srv := server.New(scheduler.New(cfg))
+	srv.Token = token
+	if servePublic && token == "" {
+		slog.Warn("serving on a public address without --token; anyone on the network can read the schedule", "listen", listenAddr)
+	}
+
+	var metricsReg *metrics.Registry
+	if metricsAddr != "" {
+		metricsReg = metrics.New()
+		// Serve mode has no loop of its own, so refresh the gauges on each scrape
+		metricsReg.OnScrape = func() {
+			if err := refreshMetrics(metricsReg, srv.Scheduler(), time.Now()); err != nil {
+				slog.Warn("failed to refresh metrics", "err", err)
+			}
+		}
+		startMetrics(metricsReg, token)
+	}
+
+	// Reload the config on SIGHUP or a calendar change, keeping the old
+	// schedule if the new one is invalid
+	reload := func(source string) {
+		cfg, err := loadConfig()
+		if err != nil {
+			slog.Warn("config reload failed", "source", source, "err", err)
+			return
+		}
+		srv.SetScheduler(scheduler.New(cfg))
+		if metricsReg != nil {
+			metricsReg.IncConfigReloads()
+		}
+		slog.Info("config reloaded", "source", source)
+	}
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			reload("SIGHUP")
+		}
+	}()
+	refreshCalendars(context.Background(), cfg.Calendars, func() { reload("calendar") })
+
+	httpSrv := &http.Server{Addr: listenAddr, Handler: server.LogRequests(srv.Handler())}
+	if serveCert != "" {
+		fmt.Fprintf(os.Stderr, "Serving schedule on https://%s\n", listenAddr)
+		return httpSrv.ListenAndServeTLS(serveCert, serveKey)
+	}
+	fmt.Fprintf(os.Stderr, "Serving schedule on http://%s\n", listenAddr)
+	return httpSrv.ListenAndServe()
+}
+
+// accessToken returns the --token flag, or SKED_TOKEN if it is unset.
+func accessToken() string {
+	if serveToken != "" {
+		return serveToken
+	}
+	return os.Getenv("SKED_TOKEN")
+}
+
+// checkListenAddr refuses addresses other than loopback ones for the flag
+// named flag unless public is set. An empty host listens on all interfaces.
+func checkListenAddr(flag, addr string, public bool) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid %s address '%s': %w", flag, addr, err)
+	}
+	if public || host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("refusing to listen on '%s', which is reachable from other machines; pass --public to allow it", addr)
+}
+
+// refreshMetrics updates reg with the schedule state at now.
+func refreshMetrics(reg *metrics.Registry, sched *scheduler.Scheduler, now time.Time) error {
+	current, err := sched.GetCurrentTask(now)
+	if err != nil {
+		return err
+	}
+	next, err := nextOrNone(sched, now)
+	if err != nil {
+		return err
+	}
+	dayTasks, err := sched.GetTasksForDate(now)
+	if err != nil {
+		return err
+	}
+	reg.Update(now, current, next, dayTasks)
+	return nil
+}
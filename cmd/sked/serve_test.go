@@ -0,0 +1,29 @@
+package main
+
+import "testing"
+
+func TestCheckListenAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		public  bool
+		wantErr bool
+	}{
+		{addr: "127.0.0.1:8374"},
+		{addr: "localhost:8374"},
+		{addr: "[::1]:8374"},
+		{addr: ":8374", wantErr: true},
+		{addr: "0.0.0.0:8374", wantErr: true},
+		{addr: "192.168.1.5:8374", wantErr: true},
+		{addr: ":8374", public: true},
+		{addr: "8374", public: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			err := checkListenAddr("--listen", tt.addr, tt.public)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkListenAddr(%q, %v) error = %v, wantErr %v", tt.addr, tt.public, err, tt.wantErr)
+			}
+		})
+	}
+}
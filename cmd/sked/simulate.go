@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/dateparse"
+	"github.com/Daniel-42-z/sked/internal/watch"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	simDate        string
+	simSpeed       float64
+	simLookahead   time.Duration
+	simNotifyAhead time.Duration
+)
+
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Replay watch mode for a full day against a fast simulated clock",
+	Long: `Run the watch-mode logic over a whole day with a simulated clock, printing
+every task transition and every notification that would be sent.`,
+	Args: cobra.NoArgs,
+	RunE: runSimulate,
+}
+
+func init() {
+	simulateCmd.Flags().StringVar(&simDate, "date", "", "day to simulate, e.g. 2025-03-04, monday or +1 (default today)")
+	addTagFlag(simulateCmd)
+	simulateCmd.Flags().Float64Var(&simSpeed, "speed", 600, "clock speed multiplier (0 replays instantly)")
+	simulateCmd.Flags().DurationVarP(&simLookahead, "lookahead", "l", 0, "lookahead duration, as in watch mode")
+	simulateCmd.Flags().DurationVar(&simNotifyAhead, "notify-ahead", 0, "simulate notifications with this lookahead duration")
+	rootCmd.AddCommand(simulateCmd)
+}
+
+func runSimulate(cmd *cobra.Command, args []string) error {
+	if simSpeed < 0 {
+		return fmt.Errorf("--speed cannot be negative")
+	}
+
+	date := time.Now()
+	if simDate != "" {
+		var err error
+		if date, err = dateparse.Parse(simDate, date); err != nil {
+			return fmt.Errorf("invalid --date: %w", err)
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	settings := watch.Settings{
+		Lookahead:     simLookahead,
+		Notify:        cmd.Flags().Changed("notify-ahead"),
+		NotifyAhead:   simNotifyAhead,
+		NotifyOptions: notifySendOptions(cfg),
+		Icons:         showIcons,
+		Locale:        locale,
+		Clock:         displayClock(cfg),
+		Durations:     displayDurations(cfg),
+	}
+
+	wait := func(d time.Duration) {
+		if simSpeed > 0 {
+			time.Sleep(time.Duration(float64(d) / simSpeed))
+		}
+	}
+	return watch.Simulate(newScheduler(cfg), date, settings, os.Stdout, wait)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+)
+
+const (
+	// maxSleepChunk is the longest watch mode sleeps without re-checking the clock.
+	maxSleepChunk = time.Minute
+	// clockJumpThreshold is how far the wall clock may run ahead of the
+	// monotonic clock before we assume the system was suspended.
+	clockJumpThreshold = 5 * time.Second
+)
+
+// clockJumped reports whether the wall clock advanced noticeably more than the
+// monotonic clock over the same interval. The monotonic clock stops while the
+// system is suspended, so a large gap means we just resumed.
+func clockJumped(wallElapsed, monoElapsed time.Duration) bool {
+	return wallElapsed-monoElapsed > clockJumpThreshold
+}
+
+// shouldRecompute decides whether watch mode must re-evaluate the schedule now,
+// given the previous and current wall times, the monotonic time elapsed between
+// them and the wake-up deadline. The deadline is compared on the wall clock so
+// time spent suspended counts towards it.
+func shouldRecompute(prevWall, curWall time.Time, monoElapsed time.Duration, deadline time.Time) bool {
+	if !curWall.Before(deadline) {
+		return true
+	}
+	return clockJumped(curWall.Sub(prevWall), monoElapsed)
+}
+
+// sleeper blocks the watch loop between iterations.
+type sleeper struct {
+	// wake ends a sleep early, e.g. after a config reload. It may be nil.
+	wake <-chan struct{}
+	// stop ends a sleep early when watch mode shuts down. It may be nil.
+	stop <-chan struct{}
+	// keepalive, if set, is called at least every keepaliveEvery while
+	// sleeping, so a watchdog can tell the loop from a hung process.
+	keepalive      func()
+	keepaliveEvery time.Duration
+}
+
+// sleepUntil blocks until deadline or a receive on wake or stop, waking at least
+// every maxSleepChunk to detect suspend/resume. If a resume was detected it
+// returns the wall time at which the process went to sleep; otherwise it
+// returns the zero time.
+func (s sleeper) sleepUntil(deadline time.Time) time.Time {
+	chunk := maxSleepChunk
+	if s.keepalive != nil && s.keepaliveEvery < chunk {
+		chunk = s.keepaliveEvery
+	}
+	deadline = deadline.Round(0)
+	prev := time.Now()
+	for {
+		remaining := deadline.Sub(prev.Round(0))
+		if remaining > chunk {
+			remaining = chunk
+		}
+		if remaining > 0 {
+			timer := time.NewTimer(remaining)
+			select {
+			case <-timer.C:
+			case <-s.wake:
+				timer.Stop()
+				return time.Time{}
+			case <-s.stop:
+				timer.Stop()
+				return time.Time{}
+			}
+		}
+		if s.keepalive != nil {
+			s.keepalive()
+		}
+
+		cur := time.Now()
+		prevWall, curWall := prev.Round(0), cur.Round(0)
+		// Sub on readings that carry a monotonic component uses the monotonic clock
+		monoElapsed := cur.Sub(prev)
+		if clockJumped(curWall.Sub(prevWall), monoElapsed) {
+			slog.Info("resume detected", "suspended_at", prevWall)
+			return prevWall
+		}
+		if shouldRecompute(prevWall, curWall, monoElapsed, deadline) {
+			return time.Time{}
+		}
+		prev = cur
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShouldRecompute(t *testing.T) {
+	base := time.Date(2024, 1, 1, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		prevWall    time.Time
+		curWall     time.Time
+		monoElapsed time.Duration
+		deadline    time.Time
+		want        bool
+	}{
+		{
+			name:        "normal_tick_before_deadline",
+			prevWall:    base,
+			curWall:     base.Add(time.Minute),
+			monoElapsed: time.Minute,
+			deadline:    base.Add(time.Hour),
+			want:        false,
+		},
+		{
+			name:        "deadline_reached",
+			prevWall:    base,
+			curWall:     base.Add(time.Minute),
+			monoElapsed: time.Minute,
+			deadline:    base.Add(time.Minute),
+			want:        true,
+		},
+		{
+			name:        "resumed_from_suspend",
+			prevWall:    base,
+			curWall:     base.Add(30 * time.Minute),
+			monoElapsed: time.Minute,
+			deadline:    base.Add(10 * time.Hour),
+			want:        true,
+		},
+		{
+			name:        "small_drift_ignored",
+			prevWall:    base,
+			curWall:     base.Add(time.Minute + time.Second),
+			monoElapsed: time.Minute,
+			deadline:    base.Add(time.Hour),
+			want:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := shouldRecompute(tt.prevWall, tt.curWall, tt.monoElapsed, tt.deadline)
+			if got != tt.want {
+				t.Errorf("shouldRecompute() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSleepUntil_Stop(t *testing.T) {
+	stop := make(chan struct{})
+	close(stop)
+	start := time.Now()
+	if got := (sleeper{stop: stop}).sleepUntil(start.Add(time.Hour)); !got.IsZero() {
+		t.Errorf("Expected zero time, got %v", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected stop to end the sleep at once, took %v", elapsed)
+	}
+}
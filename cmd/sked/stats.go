@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/adherence"
+	"github.com/Daniel-42-z/sked/internal/dateparse"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsAdherence   bool
+	statsUtilization bool
+	statsFrom        string
+	statsDays        int
+	statsJSON        bool
+	statsBy          string
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics about the schedule",
+	Long: `Show statistics about the schedule.
+
+--adherence compares the tasks scheduled over a date range with the records
+written by 'sked done' and 'sked skip', per task and per day. Tasks without a
+record count as missed once they have ended; off days are not counted.
+--by tag groups it per tag instead of per task.
+
+--utilization shows how much of each day is scheduled, counting overlapping
+tasks once, and which share of day_window that is if one is configured.`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().BoolVar(&statsAdherence, "adherence", false, "report done/skipped/missed tasks against the schedule")
+	statsCmd.Flags().BoolVar(&statsUtilization, "utilization", false, "report scheduled time per day and its share of day_window")
+	statsCmd.Flags().StringVar(&statsFrom, "from", "", "first date, YYYY-MM-DD, yesterday, a weekday for its latest occurrence, last <weekday>, last week or -N days (default 6 days ago)")
+	statsCmd.Flags().IntVar(&statsDays, "days", 7, "number of days to include")
+	statsCmd.Flags().BoolVarP(&statsJSON, "json", "j", false, "output in JSON format")
+	statsCmd.Flags().StringVar(&statsBy, "by", "task", "group the adherence table by task or tag")
+	addTagFlag(statsCmd)
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	if !statsAdherence && !statsUtilization {
+		return fmt.Errorf("no report selected (use --adherence or --utilization)")
+	}
+	if statsAdherence && statsUtilization && statsJSON {
+		return fmt.Errorf("--json prints a single report; choose --adherence or --utilization")
+	}
+	switch statsBy {
+	case "task", "tag":
+	default:
+		return fmt.Errorf("invalid --by value '%s' (expected task or tag)", statsBy)
+	}
+	if statsBy == "tag" && !statsAdherence {
+		return fmt.Errorf("--by tag groups the --adherence report")
+	}
+	if statsDays <= 0 {
+		return fmt.Errorf("--days must be positive")
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := today.AddDate(0, 0, -(statsDays - 1))
+	if statsFrom != "" {
+		var err error
+		if from, err = dateparse.ParsePast(statsFrom, today); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	sched := newScheduler(cfg)
+
+	if statsAdherence {
+		if err := printAdherence(sched, from, now); err != nil {
+			return err
+		}
+		if statsUtilization {
+			fmt.Println()
+		}
+	}
+	if statsUtilization {
+		return printUtilization(sched, from)
+	}
+	return nil
+}
+
+func printAdherence(sched *scheduler.Scheduler, from, now time.Time) error {
+	j, err := openJournal()
+	if err != nil {
+		return err
+	}
+	records, err := j.Range(from, from.AddDate(0, 0, statsDays-1))
+	if err != nil {
+		return err
+	}
+
+	report, err := adherence.Compute(sched, records, from, statsDays, now)
+	if err != nil {
+		return err
+	}
+	if statsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return adherence.Write(os.Stdout, report, statsBy == "tag")
+}
+
+// utilizationDay is a row of the utilization report.
+type utilizationDay struct {
+	Date             string   `json:"date,omitempty"`
+	IsOff            bool     `json:"is_off"`
+	ScheduledMinutes int      `json:"scheduled_minutes"`
+	WindowMinutes    int      `json:"window_minutes"`
+	Utilization      *float64 `json:"utilization"` // null without day_window
+}
+
+// utilizationReport is the JSON form of the utilization report.
+type utilizationReport struct {
+	From  string           `json:"from"`
+	To    string           `json:"to"`
+	Total utilizationDay   `json:"total"`
+	Days  []utilizationDay `json:"days"`
+}
+
+func newUtilizationDay(date string, off bool, u scheduler.Usage) utilizationDay {
+	d := utilizationDay{
+		Date:             date,
+		IsOff:            off,
+		ScheduledMinutes: int(u.Scheduled.Minutes()),
+		WindowMinutes:    int(u.Window.Minutes()),
+	}
+	if u.Window > 0 {
+		v := math.Round(u.Utilization()*1000) / 1000
+		d.Utilization = &v
+	}
+	return d
+}
+
+func printUtilization(sched *scheduler.Scheduler, from time.Time) error {
+	report := utilizationReport{
+		From: from.Format("2006-01-02"),
+		To:   from.AddDate(0, 0, statsDays-1).Format("2006-01-02"),
+	}
+	var total scheduler.Usage
+	for i := 0; i < statsDays; i++ {
+		date := from.AddDate(0, 0, i)
+		info, err := sched.GetDayInfo(date)
+		if err != nil {
+			return err
+		}
+		u, err := sched.GetUsage(date)
+		if err != nil {
+			return err
+		}
+		if !info.IsOff {
+			total.Scheduled += u.Scheduled
+			total.Window += u.Window
+		}
+		report.Days = append(report.Days, newUtilizationDay(date.Format("2006-01-02"), info.IsOff, u))
+	}
+	report.Total = newUtilizationDay("", false, total)
+
+	if statsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+
+	fmt.Printf("Utilization %s to %s\n\n", report.From, report.To)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tSCHEDULED\tWINDOW\tUTILIZATION")
+	durations := displayDurations(sched.Config())
+	minutes := func(n int) string { return durations.Format(time.Duration(n) * time.Minute) }
+	row := func(label string, d utilizationDay) {
+		window, pct := "-", "-"
+		if d.Utilization != nil {
+			window = minutes(d.WindowMinutes)
+			pct = fmt.Sprintf("%.0f%%", *d.Utilization*100)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", label, minutes(d.ScheduledMinutes), window, pct)
+	}
+	for _, d := range report.Days {
+		date, _ := time.Parse("2006-01-02", d.Date)
+		label := fmt.Sprintf("%s %s", d.Date, date.Weekday().String()[:3])
+		if d.IsOff {
+			fmt.Fprintf(w, "%s\toff\t\t\n", label)
+			continue
+		}
+		row(label, d)
+	}
+	row("Total", report.Total)
+	return w.Flush()
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+	"github.com/Daniel-42-z/sked/internal/sdnotify"
+	"github.com/Daniel-42-z/sked/internal/watch"
+)
+
+// serviceNotifier reports watch-mode progress to systemd when sked runs as a
+// Type=notify service. A nil serviceNotifier does nothing.
+type serviceNotifier struct {
+	n        *sdnotify.Notifier
+	watchdog bool
+	ready    bool
+	status   string
+}
+
+// newServiceNotifier connects to systemd if NOTIFY_SOCKET is set and
+// configures sleep to send watchdog pings if WatchdogSec is set.
+func newServiceNotifier(sleep *sleeper) *serviceNotifier {
+	n, err := sdnotify.New(os.Getenv)
+	if err != nil {
+		slog.Warn("systemd notification disabled", "err", err)
+		return nil
+	}
+	if n == nil {
+		return nil
+	}
+	s := &serviceNotifier{n: n}
+	if every := sdnotify.WatchdogInterval(os.Getenv, os.Getpid()); every > 0 {
+		s.watchdog = true
+		sleep.keepalive = s.ping
+		sleep.keepaliveEvery = every
+	}
+	return s
+}
+
+// update reports readiness after the first computed decision and the
+// current task whenever it changes, and pings the watchdog.
+func (s *serviceNotifier) update(d watch.Decision) {
+	if s == nil {
+		return
+	}
+	status := serviceStatus(d)
+	var err error
+	switch {
+	case !s.ready:
+		err = s.n.Ready(status)
+		s.ready = true
+	case status != s.status:
+		err = s.n.Status(status)
+	}
+	if err != nil {
+		slog.Warn("failed to notify systemd", "err", err)
+	}
+	s.status = status
+	s.ping()
+}
+
+// stopping reports that watch mode is shutting down.
+func (s *serviceNotifier) stopping() {
+	if s == nil {
+		return
+	}
+	if err := s.n.Stopping(); err != nil {
+		slog.Warn("failed to notify systemd", "err", err)
+	}
+	s.n.Close()
+}
+
+func (s *serviceNotifier) ping() {
+	if !s.watchdog {
+		return
+	}
+	if err := s.n.Watchdog(); err != nil {
+		slog.Warn("failed to notify systemd", "err", err)
+	}
+}
+
+// serviceStatus describes the decision for systemctl status.
+func serviceStatus(d watch.Decision) string {
+	switch {
+	case d.OffDay:
+		return "Day off"
+	case d.Current != nil:
+		status := fmt.Sprintf("Current: %s until %s", d.Current.Name, d.Current.EndTime.Format("15:04"))
+		if d.Next != nil {
+			status += "; next: " + describeStart(d.Next, d.EffectiveNow)
+		}
+		return status
+	case d.Next != nil:
+		return "No task; next: " + describeStart(d.Next, d.EffectiveNow)
+	}
+	return "No task"
+}
+
+// describeStart names a task and its start, with the weekday if it isn't today.
+func describeStart(t *scheduler.TaskEvent, now time.Time) string {
+	if isSameDay(t.StartTime, now) {
+		return fmt.Sprintf("%s at %s", t.Name, t.StartTime.Format("15:04"))
+	}
+	return fmt.Sprintf("%s at %s", t.Name, t.StartTime.Format("Mon 15:04"))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+	"github.com/Daniel-42-z/sked/internal/sdnotify"
+	"github.com/Daniel-42-z/sked/internal/watch"
+)
+
+func TestServiceNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("ListenUnixgram() returned error: %v", err)
+	}
+	defer conn.Close()
+	n, err := sdnotify.New(func(string) string { return path })
+	if err != nil {
+		t.Fatalf("sdnotify.New() returned error: %v", err)
+	}
+	s := &serviceNotifier{n: n, watchdog: true}
+
+	day := func(h, m int) time.Time { return time.Date(2024, 1, 1, h, m, 0, 0, time.UTC) }
+	math := &scheduler.TaskEvent{Name: "Math", StartTime: day(9, 0), EndTime: day(10, 0)}
+	art := &scheduler.TaskEvent{Name: "Art", StartTime: day(13, 0), EndTime: day(14, 0)}
+
+	s.update(watch.Decision{EffectiveNow: day(9, 30), Current: math, Next: art})
+	s.update(watch.Decision{EffectiveNow: day(9, 45), Current: math, Next: art})
+	s.update(watch.Decision{EffectiveNow: day(10, 0), Next: art})
+
+	buf := make([]byte, 4096)
+	for _, want := range []string{
+		"READY=1\nSTATUS=Current: Math until 10:00; next: Art at 13:00",
+		"WATCHDOG=1",
+		"WATCHDOG=1", // unchanged status is not repeated
+		"STATUS=No task; next: Art at 13:00",
+		"WATCHDOG=1",
+	} {
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		k, err := conn.Read(buf)
+		if err != nil {
+			t.Fatalf("Read() returned error: %v", err)
+		}
+		if got := string(buf[:k]); got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	}
+
+	// Without NOTIFY_SOCKET the notifier is nil and ignores updates
+	var none *serviceNotifier
+	none.update(watch.Decision{})
+}
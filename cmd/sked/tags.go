@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+
+	"github.com/spf13/cobra"
+)
+
+// tagFilter holds the --tag values of whichever command is running.
+var tagFilter []string
+
+// addTagFlag registers --tag on cmd.
+func addTagFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&tagFilter, "tag", nil, "only consider tasks with this tag; prefix with - to exclude, 'untagged' matches tasks without tags (repeatable)")
+}
+
+// newScheduler creates the scheduler for cfg, limited to the tasks passing --tag.
+func newScheduler(cfg *config.Config) *scheduler.Scheduler {
+	return scheduler.New(cfg).Filtered(scheduler.ParseTagFilter(tagFilter))
+}
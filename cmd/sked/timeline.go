@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/dateparse"
+	"github.com/Daniel-42-z/sked/internal/output"
+	"github.com/Daniel-42-z/sked/internal/timeline"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+)
+
+var (
+	timelineVertical bool
+	timelineWidth    int
+	timelineColor    string
+)
+
+var timelineCmd = &cobra.Command{
+	Use:   "timeline [date]",
+	Short: "Draw a day as a proportional bar of its tasks",
+	Long: `Draw the tasks of a day (default today) as a horizontal bar spanning
+the whole day, or day_window if set, where each task is as wide as it is
+long. Gaps are dotted and a caret marks the current time. With --vertical,
+each hour gets a line of its own. The date is YYYY-MM-DD, today, tomorrow,
+yesterday, a weekday for its next occurrence, next/last <weekday>,
+next/last week or +N/-N days.
+
+Tasks are drawn as colored blocks on terminals with truecolor support, and
+as ASCII blocks like [Math    ] elsewhere.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTimeline,
+}
+
+func init() {
+	timelineCmd.Flags().BoolVar(&timelineVertical, "vertical", false, "draw one line per hour")
+	timelineCmd.Flags().IntVar(&timelineWidth, "width", 0, "width in columns (default: the terminal width)")
+	timelineCmd.Flags().StringVar(&timelineColor, "color", "auto", "colorize output: auto, always or never")
+	addTagFlag(timelineCmd)
+	rootCmd.AddCommand(timelineCmd)
+}
+
+func runTimeline(cmd *cobra.Command, args []string) error {
+	switch timelineColor {
+	case "auto", "always", "never":
+	default:
+		return fmt.Errorf("invalid --color value '%s' (expected auto, always or never)", timelineColor)
+	}
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if len(args) == 1 {
+		var err error
+		if date, err = dateparse.Parse(args[0], date); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	sched := newScheduler(cfg)
+	info, err := sched.GetDayInfo(date)
+	if err != nil {
+		return err
+	}
+	tasks, err := sched.GetTasksForDate(date)
+	if err != nil {
+		return err
+	}
+
+	opts := timeline.Options{
+		Width: terminalWidth(),
+		Color: timelineColor == "always" || (output.ColorEnabled(timelineColor) && termenv.EnvColorProfile() == termenv.TrueColor),
+		From:  date,
+		To:    date.AddDate(0, 0, 1),
+		Clock: displayClock(cfg),
+	}
+	if timelineWidth > 0 {
+		opts.Width = timelineWidth
+	}
+	if from, to, ok := cfg.DayWindowOn(date); ok {
+		opts.From, opts.To = from, to
+	}
+	if date.Equal(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())) {
+		opts.Now = now
+	}
+
+	header := fmt.Sprintf("%s (%s)", date.Format("2006-01-02"), date.Weekday())
+	if info.IsOff {
+		header += " OFF"
+	}
+	if info.Note != "" {
+		header += " - " + info.Note
+	}
+	fmt.Println(header)
+	if timelineVertical {
+		return timeline.Vertical(os.Stdout, tasks, opts)
+	}
+	return timeline.Horizontal(os.Stdout, tasks, opts)
+}
+
+// terminalWidth returns the width of the terminal on stdout, $COLUMNS when
+// stdout isn't one, or 80.
+func terminalWidth() int {
+	if w, _, err := term.GetSize(os.Stdout.Fd()); err == nil && w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return 80
+}
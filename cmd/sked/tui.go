@@ -2,9 +2,12 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/i18n"
+	"github.com/Daniel-42-z/sked/internal/journal"
 	"github.com/Daniel-42-z/sked/internal/scheduler"
 
 	"github.com/charmbracelet/bubbles/viewport"
@@ -13,13 +16,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
-const (
-	dateDisplayColor          = lipgloss.Color("40")
-	taskHighlightBackground   = lipgloss.Color("22")
-	borderHighlightBackground = lipgloss.Color("40")
-	taskHighlightForeground   = lipgloss.Color("7")
-	borderColor               = lipgloss.Color("240")
-)
+// doubleClickInterval is the longest gap between two clicks on a row that
+// still counts as a double click.
+const doubleClickInterval = 400 * time.Millisecond
+
+var noMouse bool
 
 var tuiCmd = &cobra.Command{
 	Use:   "show",
@@ -28,55 +29,98 @@ var tuiCmd = &cobra.Command{
 }
 
 func init() {
+	addTagFlag(tuiCmd)
+	tuiCmd.Flags().BoolVar(&noMouse, "no-mouse", false, "disable mouse support (also no_mouse in the config)")
 	rootCmd.AddCommand(tuiCmd)
 }
 
 func runTUI(cmd *cobra.Command, args []string) error {
-	var cfg *config.Config
-	var err error
+	// Check for "tmp" mode argument
+	tmp := temporary() || (len(args) > 0 && args[0] == "tmp")
+	cfg, err := loadTUIConfig(tmp)
+	if err != nil {
+		return err
+	}
+	mouse := !noMouse && !cfg.NoMouse
 
-	if tmpFile != "" {
-		cfg, err = config.LoadTmpCSV(tmpFile)
+	// 2. Initialize Scheduler
+	sched := newScheduler(cfg)
+
+	// 3. Start Bubble Tea program
+	m := initialModel(sched, cfg)
+	m.tmp = tmp
+	m.mouse = mouse
+	m.load = loadTUIConfig
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if mouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	if tmpFile == "-" {
+		// stdin holds the schedule, so read keys from the terminal
+		opts = append(opts, tea.WithInputTTY())
+	}
+	p := tea.NewProgram(m, opts...)
+	if _, err := p.Run(); err != nil {
+		return fmt.Errorf("error running TUI: %w", err)
+	}
+	return nil
+}
+
+// loadTUIConfig loads and validates the schedule shown by the TUI: the
+// --inline or --tmp schedule if given, otherwise the config or, if tmp is set, the
+// temporary CSV it configures. A configured temporary schedule keeps the
+// config's TmpCSVPath, so the TUI can switch back and forth.
+func loadTUIConfig(tmp bool) (*config.Config, error) {
+	if temporary() {
+		cfg, err := loadTemporary()
 		if err != nil {
-			return fmt.Errorf("failed to load temporary config: %w", err)
-		}
-	} else {
-		// 1. Load Config (Reusing logic from run)
-		if cfgFile == "" {
-			cfgFile, err = config.FindOrCreateDefault()
-			if err != nil {
-				return err
-			}
+			return nil, err
 		}
+		return cfg, validateTUIConfig(cfg)
+	}
+
+	// 1. Load Config (Reusing logic from run)
+	path, err := configPath(true)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
 
-		cfg, err = config.Load(cfgFile)
+	if tmp {
+		if cfg.TmpCSVPath == "" {
+			return nil, fmt.Errorf("no 'tmp_csv_path' configured in %s", path)
+		}
+		tmpCfg, err := config.LoadTmpCSV(cfg.TmpCSVPath)
 		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
+			return nil, fmt.Errorf("failed to load configured temporary config from %s: %w", cfg.TmpCSVPath, err)
 		}
-
-		// Check for "tmp" mode argument
-		if len(args) > 0 && args[0] == "tmp" {
-			if cfg.TmpCSVPath == "" {
-				return fmt.Errorf("no 'tmp_csv_path' configured in %s", cfgFile)
-			}
-			cfg, err = config.LoadTmpCSV(cfg.TmpCSVPath)
-			if err != nil {
-				return fmt.Errorf("failed to load configured temporary config from %s: %w", cfg.TmpCSVPath, err)
-			}
+		// Keep the config's own TUI settings
+		tmpCfg.TmpCSVPath = cfg.TmpCSVPath
+		tmpCfg.NoMouse = cfg.NoMouse
+		tmpCfg.TUI = cfg.TUI
+		if cfg.Validate() == nil {
+			setBase(tmpCfg, cfg, cfg.TmpCSVPath)
 		}
+		return tmpCfg, validateTUIConfig(tmpCfg)
+	}
+	if err := validateTUIConfig(cfg); err != nil {
+		return nil, err
 	}
+	applyCalendars(cfg, time.Now())
+	return cfg, nil
+}
 
-	if err := cfg.Validate(); err != nil {
+// validateTUIConfig reports the load warnings of cfg and validates it.
+func validateTUIConfig(cfg *config.Config) error {
+	if err := reportWarnings(cfg); err != nil {
 		return fmt.Errorf("invalid config: %w", err)
 	}
-
-	// 2. Initialize Scheduler
-	sched := scheduler.New(cfg)
-
-	// 3. Start Bubble Tea program
-	p := tea.NewProgram(initialModel(sched, cfg), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		return fmt.Errorf("error running TUI: %w", err)
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
 	}
 	return nil
 }
@@ -85,12 +129,59 @@ func runTUI(cmd *cobra.Command, args []string) error {
 
 type model struct {
 	sched       *scheduler.Scheduler
+	journal     *journal.Journal
 	viewport    viewport.Model
 	currentDate time.Time
 	err         error
 	width       int
 	height      int
 	dateFormat  string
+	clock       config.Clock
+	durations   config.Durations
+	offDayText  string
+	theme       config.Theme // resolved colors
+	icons       bool
+	locale      *i18n.Locale
+	tmp         bool   // showing a temporary CSV schedule
+	tmpPath     string // the configured temporary schedule, which m toggles
+	mouse       bool   // mouse support is on
+	status      string // result of the last action, shown in the footer
+
+	// load reloads the schedule from disk, and sources is the state of its
+	// files when it was last loaded. changed is set once they differ.
+	load    func(tmp bool) (*config.Config, error)
+	sources config.SourceState
+	changed bool
+	// statusUntil is when a transient status is cleared; zero keeps it.
+	statusUntil time.Time
+	// The export path prompt opened by Y
+	prompting bool
+	prompt    string
+
+	// The displayed day. rowLines holds the first content line of each row
+	// in tasks, followed by the line after the last row.
+	info     scheduler.DayInfo
+	tasks    []scheduler.TaskEvent
+	rowLines []int
+	// conflicts pairs tasks of a temporary schedule with the base tasks
+	// they overlap, marked with ⚠
+	conflicts []scheduler.Conflict
+	gapRows   [][2]int // first line and the line after each free-time row
+	showGaps  bool     // show free time between tasks as rows
+	selected  int      // index into tasks
+	detail    bool     // the detail pane of the selected task is open
+	threeDay  bool     // show the days before and after beside the current one
+	follow    bool     // move to the new day at midnight; off once the user navigates
+
+	// refreshAt is the next time the table looks different: a task of the
+	// day starting or ending, or midnight. journalMod is the journal's
+	// modification time when the table was rendered.
+	refreshAt  time.Time
+	journalMod time.Time
+
+	// The last click on a row, to detect double clicks
+	lastClick    time.Time
+	lastClickRow int
 }
 
 type tickMsg time.Time
@@ -98,22 +189,43 @@ type tickMsg time.Time
 func initialModel(sched *scheduler.Scheduler, cfg *config.Config) model {
 	vp := viewport.New(0, 0)
 
-	dateFormat := cfg.DateFormat
-	if dateFormat == "" {
-		dateFormat = "2006-01-02 Mon"
-	}
-
 	m := model{
-		sched:       sched,
+		journal:     tuiJournal(),
 		viewport:    vp,
 		currentDate: time.Now(),
-		dateFormat:  dateFormat,
+		icons:       showIcons,
+		locale:      locale,
+		follow:      true,
+		showGaps:    cfg.TUI.ShowGaps,
 	}
+	m.useConfig(sched, cfg)
 
 	m.refreshTable()
 	return m
 }
 
+// useConfig shows the schedule of sched, built from cfg.
+func (m *model) useConfig(sched *scheduler.Scheduler, cfg *config.Config) {
+	m.sched = sched
+	m.sources = config.StatSources(cfg.Sources)
+	m.tmpPath = cfg.TmpCSVPath
+
+	m.dateFormat = cfg.DateFormat
+	if m.dateFormat == "" {
+		m.dateFormat = "2006-01-02 Mon"
+	}
+	m.clock = displayClock(cfg)
+	m.durations = displayDurations(cfg)
+
+	// Validate has already checked the theme
+	m.theme, _ = cfg.TUI.Theme.Resolve()
+
+	m.offDayText = cfg.OffDayText
+	if m.offDayText == "" {
+		m.offDayText = m.locale.T("day_off")
+	}
+}
+
 func (m model) Init() tea.Cmd {
 	return tickCmd()
 }
@@ -129,73 +241,218 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.prompting {
+			m.promptKey(msg, time.Now())
+			return m, nil
+		}
 		switch msg.String() {
 		case "q", "ctrl+c":
 			return m, tea.Quit
 		case "left", "h":
-			m.currentDate = m.currentDate.AddDate(0, 0, -1)
-			m.refreshTable()
+			m.navigate(m.currentDate.AddDate(0, 0, -1))
 		case "right", "l":
-			m.currentDate = m.currentDate.AddDate(0, 0, 1)
-			m.refreshTable()
-		case "t": // Quick jump to today
-			m.currentDate = time.Now()
-			m.refreshTable()
+			m.navigate(m.currentDate.AddDate(0, 0, 1))
+		case "shift+left", "H":
+			m.navigate(m.currentDate.AddDate(0, 0, -7))
+		case "shift+right", "L":
+			m.navigate(m.currentDate.AddDate(0, 0, 7))
+		case "1", "2", "3", "4", "5", "6", "7":
+			m.navigate(m.jumpDate(int(msg.String()[0] - '0')))
+		case "t": // Quick jump to today, following it from now on
+			m.follow = true
+			m.showDate(time.Now())
+		case "o":
+			m.status, m.statusUntil = m.openSelected(), time.Time{}
+			return m, nil
 		case "up", "k":
-			m.viewport.ScrollUp(1)
+			m.moveSelection(-1)
 			return m, nil
 		case "down", "j":
-			m.viewport.ScrollDown(1)
+			m.moveSelection(1)
+			return m, nil
+		case "r":
+			m.reload(time.Now())
+			return m, nil
+		case "m":
+			m.toggleTmp(time.Now())
+			return m, nil
+		case "y":
+			m.setStatus(m.copyDay(), time.Now())
+			return m, nil
+		case "Y":
+			m.prompting = true
+			m.prompt = "sked-" + m.currentDate.Format("2006-01-02") + ".txt"
+			return m, nil
+		case "f":
+			m.showGaps = !m.showGaps
+			m.refreshTable()
+			m.keepSelectionVisible()
+			return m, nil
+		case "v":
+			m.threeDay = !m.threeDay
+			m.refreshTable()
+			m.keepSelectionVisible()
+			return m, nil
+		case "enter":
+			m.detail = len(m.tasks) > 0
+			return m, nil
+		case "esc":
+			m.detail = false
 			return m, nil
 		}
+	case tea.MouseMsg:
+		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+			m.click(msg.X, msg.Y, time.Now())
+			return m, nil
+		}
+		// The viewport scrolls on the mouse wheel
 	case tickMsg:
-		m.refreshTable()
+		// The header countdown is rendered by View; the table only changes
+		// at task boundaries, at midnight and when the journal is written
+		now := time.Time(msg)
+		if m.follow && !isSameDay(m.currentDate, now) {
+			m.showDate(now)
+		}
+		if now.After(m.refreshAt) || m.journalModTime() != m.journalMod {
+			m.refreshTable()
+		}
+		if !m.statusUntil.IsZero() && now.After(m.statusUntil) {
+			m.status, m.statusUntil = "", time.Time{}
+		}
+		m.changed = m.sources.Changed()
 		return m, tickCmd()
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		m.viewport.Width = msg.Width - 4
-		// Leave space for header and footer (approx 6 lines)
-		m.viewport.Height = msg.Height - 6
+		// Leave space for header and footer (approx 8 lines)
+		m.viewport.Height = msg.Height - 8
 		m.refreshTable()
+		m.keepSelectionVisible()
 	}
 
 	m.viewport, cmd = m.viewport.Update(msg)
 	return m, cmd
 }
 
+// Below these viewport widths, the table first drops the spaces around the
+// dash of time ranges, then stacks the time above the name in one column.
+const (
+	compactTableWidth = 70
+	stackedTableWidth = 45
+)
+
+// tableLayout holds the column widths of the day table.
+type tableLayout struct {
+	timeCol int
+	iconCol int // 0 without an icon column
+	taskCol int
+	compact bool // time ranges as "09:00-09:50"
+	stacked bool // a single column with the time above the name
+}
+
+// newTableLayout fits the table of tasks into totalWidth columns.
+func (m *model) newTableLayout(totalWidth int, tasks []scheduler.TaskEvent) tableLayout {
+	if totalWidth < stackedTableWidth {
+		return tableLayout{taskCol: max(totalWidth-3, 10), compact: true, stacked: true}
+	}
+	// Two times, the dash with or without spaces, and padding
+	layout := tableLayout{timeCol: 2*m.clock.Width() + 5}
+	if totalWidth < compactTableWidth {
+		layout.timeCol, layout.compact = 2*m.clock.Width()+3, true
+	}
+	layout.taskCol = totalWidth - layout.timeCol - 4 // Adjust for borders
+	// The icon column is only as wide as the widest icon of the day. Emoji
+	// take two cells, so measure display width rather than runes or bytes.
+	if m.icons {
+		for _, task := range tasks {
+			layout.iconCol = max(layout.iconCol, lipgloss.Width(task.Icon))
+		}
+		if layout.iconCol > 0 {
+			layout.iconCol += 2 // padding
+			layout.taskCol -= layout.iconCol + 1
+		}
+	}
+	if layout.taskCol < 10 {
+		layout.taskCol = 10
+	}
+	return layout
+}
+
 func (m *model) refreshTable() {
+	info, err := m.sched.GetDayInfo(m.currentDate)
+	if err != nil {
+		m.err = err
+		return
+	}
 	tasks, err := m.sched.GetTasksForDate(m.currentDate)
 	if err != nil {
 		m.err = err
 		return
 	}
 	m.err = nil
+	m.info = info
+	m.tasks = tasks
+	// Without conflicts the rows just go unmarked
+	m.conflicts, _ = m.sched.GetConflicts(m.currentDate)
+	m.rowLines = nil
+	m.gapRows = nil
+	if m.selected >= len(tasks) {
+		m.selected = max(len(tasks)-1, 0)
+	}
 
 	now := time.Now()
 	isToday := isSameDay(now, m.currentDate)
+	y, mo, d := now.Date()
+	m.refreshAt = time.Date(y, mo, d+1, 0, 0, 0, 0, now.Location())
+	m.noteBoundaries(tasks, now)
+
+	var statuses map[journal.Key]journal.Status
+	m.journalMod = m.journalModTime()
+	if m.journal != nil {
+		// A missing or unreadable journal just means no markers
+		statuses, _ = m.journal.Statuses()
+	}
 
 	totalWidth := m.viewport.Width
 	if totalWidth == 0 {
 		totalWidth = 80
 	}
 
-	// Calculate columns width
-	timeColWidth := 15
-	taskColWidth := totalWidth - timeColWidth - 4 // Adjust for borders
-	if taskColWidth < 10 {
-		taskColWidth = 10
+	if m.threeDay {
+		content, err := m.renderThreeDays(totalWidth, now, statuses)
+		if err != nil {
+			m.err = err
+			return
+		}
+		m.viewport.SetContent(content)
+		return
+	}
+
+	if info.IsOff {
+		banner := m.offDayText
+		if info.Note != "" {
+			banner = info.Note + " — " + banner
+		}
+		m.viewport.SetContent(lipgloss.NewStyle().
+			Width(totalWidth).
+			Align(lipgloss.Center).
+			Bold(true).
+			Foreground(lipgloss.Color(m.theme.OffDay)).
+			Padding(1, 0).
+			Render(banner))
+		return
 	}
 
+	layout := m.newTableLayout(totalWidth, tasks)
+
 	// Determine if header bottom border should be highlighted (between header and first task)
-	headerBottomBorderColor := borderColor
+	headerBottomBorderColor := lipgloss.Color(m.theme.Border)
 	if isToday && len(tasks) > 0 && now.Before(tasks[0].StartTime) {
-		headerBottomBorderColor = borderHighlightBackground
+		headerBottomBorderColor = lipgloss.Color(m.theme.Gap)
 	}
 
-	// Base styles
-	baseStyle := lipgloss.NewStyle().Padding(0, 1)
-	headerStyle := baseStyle.Bold(true).Align(lipgloss.Center)
+	headerStyle := lipgloss.NewStyle().Padding(0, 1).Bold(true).Align(lipgloss.Center)
 
 	// Custom borders for table continuity
 	hTimeBorder := lipgloss.NormalBorder()
@@ -217,88 +474,429 @@ func (m *model) refreshTable() {
 
 	// Build Header
 	// Time: Top, Right, Bottom, Left borders
-	// Task: Top, Right, Bottom borders (Left shared)
-	header := lipgloss.JoinHorizontal(lipgloss.Top,
-		headerStyle.Width(timeColWidth).
+	// Icon and Task: Top, Right, Bottom borders (Left shared)
+	var headerCells []string
+	if layout.stacked {
+		// A single cell with all four borders
+		hBorder := lipgloss.NormalBorder()
+		hBorder.BottomLeft = hTimeBorder.BottomLeft
+		hBorder.BottomRight = hTaskBorder.BottomRight
+		headerCells = append(headerCells, headerStyle.Width(layout.taskCol).
+			Border(hBorder).
+			BorderForeground(lipgloss.Color(m.theme.Border)).
+			BorderBottomForeground(headerBottomBorderColor).
+			Render("Task"))
+	} else {
+		headerCells = append(headerCells, headerStyle.Width(layout.timeCol).
 			Border(hTimeBorder, true, true, true, true).
-			BorderForeground(borderColor).
+			BorderForeground(lipgloss.Color(m.theme.Border)).
 			BorderBottomForeground(headerBottomBorderColor).
-			Render("Time"),
-		headerStyle.Width(taskColWidth).
+			Render("Time"))
+		if layout.iconCol > 0 {
+			hIconBorder := hTaskBorder
+			hIconBorder.TopRight = "┬"
+			hIconBorder.BottomRight = "┼"
+			headerCells = append(headerCells, headerStyle.Width(layout.iconCol).
+				Border(hIconBorder, true, true, true, false).
+				BorderForeground(lipgloss.Color(m.theme.Border)).
+				BorderBottomForeground(headerBottomBorderColor).
+				Render(""))
+		}
+		headerCells = append(headerCells, headerStyle.Width(layout.taskCol).
 			Border(hTaskBorder, true, true, true, false).
-			BorderForeground(borderColor).
+			BorderForeground(lipgloss.Color(m.theme.Border)).
 			BorderBottomForeground(headerBottomBorderColor).
-			Render("Task"),
-	)
+			Render("Task"))
+	}
+	header := lipgloss.JoinHorizontal(lipgloss.Top, headerCells...)
 
 	content := header + "\n"
+	line := lipgloss.Height(header)
 
-	// Build Rows
-	for i, task := range tasks {
-		isActive := isToday && now.After(task.StartTime) && now.Before(task.EndTime)
-
-		timeStr := fmt.Sprintf("%s - %s", task.StartTime.Format("15:04"), task.EndTime.Format("15:04"))
-
-		// Check if we need to highlight the bottom border (gap between this and next task, or after last task)
-		bottomBorderColor := borderColor
-		if isToday {
-			if i < len(tasks)-1 {
-				nextTask := tasks[i+1]
-				// Gap detection
-				if now.After(task.EndTime) && now.Before(nextTask.StartTime) {
-					bottomBorderColor = borderHighlightBackground
-				}
-			} else {
-				// After last task
-				if now.After(task.EndTime) {
-					bottomBorderColor = borderHighlightBackground
+	// Free time shown before the task that ends it, within the day window
+	gaps := make(map[int]scheduler.Gap)
+	if m.showGaps {
+		from, to, _ := m.sched.Config().DayWindowOn(m.currentDate)
+		for _, g := range scheduler.ClipGaps(scheduler.FreeGaps(tasks), from, to) {
+			for i, task := range tasks {
+				if task.StartTime.Equal(g.End) {
+					gaps[i] = g
+					break
 				}
 			}
 		}
+	}
+
+	// Build Rows, remembering where each starts so the selection can be
+	// scrolled into view
+	for i, task := range tasks {
+		if g, ok := gaps[i]; ok {
+			row := m.renderGapRow(layout, g, now, isToday)
+			m.gapRows = append(m.gapRows, [2]int{line, line + lipgloss.Height(row)})
+			content += row + "\n"
+			line += lipgloss.Height(row)
+		}
+		row := m.renderRow(layout, i, statuses[journal.KeyOf(task)], now, isToday)
+		m.rowLines = append(m.rowLines, line)
+		content += row + "\n"
+		line += lipgloss.Height(row)
+	}
+	m.rowLines = append(m.rowLines, line)
 
-		rowStyle := baseStyle
-		if isActive {
-			rowStyle = rowStyle.Foreground(taskHighlightForeground).Background(taskHighlightBackground)
+	m.viewport.SetContent(content)
+}
+
+// noteBoundaries moves m.refreshAt forward to the first start or end of
+// tasks after now, if that comes sooner.
+func (m *model) noteBoundaries(tasks []scheduler.TaskEvent, now time.Time) {
+	for _, t := range tasks {
+		for _, b := range []time.Time{t.StartTime, t.EndTime} {
+			if b.After(now) && b.Before(m.refreshAt) {
+				m.refreshAt = b
+			}
 		}
+	}
+}
+
+// journalModTime returns the journal's modification time, or the zero time
+// without a journal.
+func (m *model) journalModTime() time.Time {
+	if m.journal == nil {
+		return time.Time{}
+	}
+	return m.journal.ModTime()
+}
+
+// timeRange formats start and end on the model's clock as "09:00 - 09:50",
+// or "09:00-09:50" if compact.
+func (m *model) timeRange(start, end time.Time, compact bool) string {
+	sep := " - "
+	if compact {
+		sep = "-"
+	}
+	return m.clock.Format(start) + sep + m.clock.Format(end)
+}
+
+// renderRow renders the table row of m.tasks[i], including its bottom border.
+// Long names wrap within their cell, and the other cells grow to match.
+func (m *model) renderRow(layout tableLayout, i int, status journal.Status, now time.Time, isToday bool) string {
+	task := m.tasks[i]
+	isActive := isToday && now.After(task.StartTime) && now.Before(task.EndTime)
 
-		// Determine border style
-		timeBorder := lipgloss.NormalBorder()
-		taskBorder := lipgloss.NormalBorder()
+	timeStr := m.timeRange(task.StartTime, task.EndTime, layout.compact)
 
-		if i == len(tasks)-1 {
-			// Last row
-			timeBorder.BottomRight = "┴"
-			taskBorder.BottomLeft = "─"
-			// BottomLeft/BottomRight already └/┘
+	// Check if we need to highlight the bottom border (gap between this and next task, or after last task)
+	bottomBorderColor := lipgloss.Color(m.theme.Border)
+	if isToday {
+		if i < len(m.tasks)-1 {
+			nextTask := m.tasks[i+1]
+			// Gap detection
+			if now.After(task.EndTime) && now.Before(nextTask.StartTime) {
+				bottomBorderColor = lipgloss.Color(m.theme.Gap)
+			}
 		} else {
-			// Middle row
-			timeBorder.BottomLeft = "├"
-			timeBorder.BottomRight = "┼"
-			taskBorder.BottomLeft = "─"
-			taskBorder.BottomRight = "┤"
-		}
-
-		// Time Cell: Bottom, Right, Left borders
-		tStyle := rowStyle.Width(timeColWidth).
-			Border(timeBorder, false, true, true, true).
-			BorderForeground(borderColor).
-			BorderBottomForeground(bottomBorderColor)
-
-		// Task Cell: Bottom, Right borders
-		tskStyle := rowStyle.Width(taskColWidth).
-			Border(taskBorder, false, true, true, false).
-			BorderForeground(borderColor).
-			BorderBottomForeground(bottomBorderColor)
-
-		row := lipgloss.JoinHorizontal(lipgloss.Top,
-			tStyle.Render(timeStr),
-			tskStyle.Render(task.Name),
-		)
+			// After last task
+			if now.After(task.EndTime) {
+				bottomBorderColor = lipgloss.Color(m.theme.Gap)
+			}
+		}
+	}
 
-		content += row + "\n"
+	rowStyle := lipgloss.NewStyle().Padding(0, 1)
+	tagColor := m.sched.Config().TagColor(task.Tags)
+	if isActive {
+		highlight := lipgloss.Color(m.theme.ActiveBackground)
+		if task.Color != "" {
+			highlight = lipgloss.Color(task.Color)
+		} else if tagColor != "" {
+			highlight = lipgloss.Color(tagColor)
+		}
+		rowStyle = rowStyle.Foreground(lipgloss.Color(m.theme.ActiveForeground)).Background(highlight)
+	} else if tagColor != "" {
+		rowStyle = rowStyle.Foreground(lipgloss.Color(tagColor))
+	}
+	if i == m.selected {
+		// The active row keeps its colors, so the selection must not rely
+		// on a background alone
+		rowStyle = rowStyle.Bold(true)
+		if !isActive {
+			rowStyle = rowStyle.Background(lipgloss.Color(m.theme.Selected))
+		}
 	}
 
-	m.viewport.SetContent(content)
+	// Determine border style
+	timeBorder := lipgloss.NormalBorder()
+	taskBorder := lipgloss.NormalBorder()
+
+	if i == len(m.tasks)-1 {
+		// Last row
+		timeBorder.BottomRight = "┴"
+		taskBorder.BottomLeft = "─"
+		// BottomLeft/BottomRight already └/┘
+	} else {
+		// Middle row
+		timeBorder.BottomLeft = "├"
+		timeBorder.BottomRight = "┼"
+		taskBorder.BottomLeft = "─"
+		taskBorder.BottomRight = "┤"
+	}
+
+	name := task.Name
+	switch status {
+	case journal.Done:
+		name = "✓ " + name
+	case journal.Skipped:
+		name = "✗ " + name
+	}
+	if scheduler.ConflictsWith(m.conflicts, task) != nil {
+		name = "⚠ " + name
+	}
+	if task.URL != "" {
+		name += " 🔗"
+	}
+	if i == m.selected {
+		name = "› " + name
+	}
+
+	if layout.stacked {
+		// One cell with Bottom, Right and Left borders; the icon, if shown,
+		// precedes the name
+		if m.icons && task.Icon != "" {
+			name = task.Icon + " " + name
+		}
+		border := taskBorder
+		border.BottomLeft = timeBorder.BottomLeft
+		return rowStyle.Width(layout.taskCol).
+			Border(border, false, true, true, true).
+			BorderForeground(lipgloss.Color(m.theme.Border)).
+			BorderBottomForeground(bottomBorderColor).
+			Render(timeStr + "\n" + name)
+	}
+
+	// Task Cell: Bottom, Right borders
+	taskCell := rowStyle.Width(layout.taskCol).
+		Border(taskBorder, false, true, true, false).
+		BorderForeground(lipgloss.Color(m.theme.Border)).
+		BorderBottomForeground(bottomBorderColor).
+		Render(name)
+	// Lines of the wrapped name, without the bottom border
+	height := lipgloss.Height(taskCell) - 1
+
+	// Time Cell: Bottom, Right, Left borders, top-aligned
+	cells := []string{rowStyle.Width(layout.timeCol).
+		Height(height).
+		Border(timeBorder, false, true, true, true).
+		BorderForeground(lipgloss.Color(m.theme.Border)).
+		BorderBottomForeground(bottomBorderColor).
+		Render(timeStr)}
+	if layout.iconCol > 0 {
+		// Icon Cell: Bottom, Right borders, joining the task cell
+		iconBorder := taskBorder
+		iconBorder.BottomRight = timeBorder.BottomRight
+		cells = append(cells, rowStyle.Width(layout.iconCol).
+			Height(height).
+			Border(iconBorder, false, true, true, false).
+			BorderForeground(lipgloss.Color(m.theme.Border)).
+			BorderBottomForeground(bottomBorderColor).
+			Render(task.Icon))
+	}
+	cells = append(cells, taskCell)
+	return lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+}
+
+// renderGapRow renders the free time before a task as a dimmed row, or
+// highlighted if it is in progress.
+func (m *model) renderGapRow(layout tableLayout, gap scheduler.Gap, now time.Time, isToday bool) string {
+	style := lipgloss.NewStyle().Padding(0, 1).Faint(true)
+	if isToday && !now.Before(gap.Start) && now.Before(gap.End) {
+		style = lipgloss.NewStyle().Padding(0, 1).Bold(true).Foreground(lipgloss.Color(m.theme.Gap))
+	}
+	timeStr := m.timeRange(gap.Start, gap.End, layout.compact)
+	text := "— " + m.durations.Format(gap.End.Sub(gap.Start)) + " free —"
+
+	// Always a middle row
+	timeBorder := lipgloss.NormalBorder()
+	timeBorder.BottomLeft = "├"
+	timeBorder.BottomRight = "┼"
+	taskBorder := lipgloss.NormalBorder()
+	taskBorder.BottomLeft = "─"
+	taskBorder.BottomRight = "┤"
+	border := func(s lipgloss.Style) lipgloss.Style {
+		return s.BorderForeground(lipgloss.Color(m.theme.Border))
+	}
+
+	if layout.stacked {
+		b := taskBorder
+		b.BottomLeft = timeBorder.BottomLeft
+		return border(style.Width(layout.taskCol).Border(b, false, true, true, true)).
+			Render(timeStr + "\n" + text)
+	}
+
+	taskCell := border(style.Width(layout.taskCol).Align(lipgloss.Center).
+		Border(taskBorder, false, true, true, false)).
+		Render(text)
+	height := lipgloss.Height(taskCell) - 1
+	cells := []string{border(style.Width(layout.timeCol).Height(height).
+		Border(timeBorder, false, true, true, true)).
+		Render(timeStr)}
+	if layout.iconCol > 0 {
+		iconBorder := taskBorder
+		iconBorder.BottomRight = timeBorder.BottomRight
+		cells = append(cells, border(style.Width(layout.iconCol).Height(height).
+			Border(iconBorder, false, true, true, false)).
+			Render(""))
+	}
+	cells = append(cells, taskCell)
+	return lipgloss.JoinHorizontal(lipgloss.Top, cells...)
+}
+
+// showDate switches to date, selecting its first row.
+func (m *model) showDate(date time.Time) {
+	m.currentDate = date
+	m.selected = 0
+	m.refreshTable()
+	m.keepSelectionVisible()
+	if len(m.tasks) == 0 {
+		m.detail = false
+	}
+}
+
+// navigate shows date at the user's request, which stops following today.
+func (m *model) navigate(date time.Time) {
+	m.follow = false
+	m.showDate(date)
+}
+
+// jumpDate returns the date for number key n: in a weekly cycle the
+// weekday n (1 is Monday, 7 is Sunday) of the displayed week, otherwise the
+// next date from the displayed one on cycle day n. It returns the displayed
+// date if there is none.
+func (m *model) jumpDate(n int) time.Time {
+	if m.sched.Config().CycleDays == 7 {
+		monday := m.currentDate.AddDate(0, 0, -(int(m.currentDate.Weekday())+6)%7)
+		return monday.AddDate(0, 0, n-1)
+	}
+	for i := range m.sched.Config().CycleDays {
+		date := m.currentDate.AddDate(0, 0, i)
+		if info, err := m.sched.GetDayInfo(date); err == nil && info.DayID == n {
+			return date
+		}
+	}
+	return m.currentDate
+}
+
+// moveSelection moves the selected row by delta, staying within the day.
+func (m *model) moveSelection(delta int) {
+	if len(m.tasks) == 0 {
+		return
+	}
+	m.selected = min(max(m.selected+delta, 0), len(m.tasks)-1)
+	m.refreshTable()
+	m.keepSelectionVisible()
+}
+
+// keepSelectionVisible scrolls the viewport just enough to show the whole
+// selected row. Selecting the first row scrolls back to the header.
+func (m *model) keepSelectionVisible() {
+	if m.selected+1 >= len(m.rowLines) || m.viewport.Height <= 0 {
+		return
+	}
+	top, bottom := m.rowLines[m.selected], m.rowLines[m.selected+1]
+	if m.selected == 0 {
+		top = 0
+	}
+	switch {
+	case top < m.viewport.YOffset:
+		m.viewport.SetYOffset(top)
+	case bottom > m.viewport.YOffset+m.viewport.Height:
+		m.viewport.SetYOffset(bottom - m.viewport.Height)
+	}
+}
+
+// source describes where the selected day's tasks come from.
+func (m model) source() string {
+	switch {
+	case m.tmp:
+		return "temporary CSV"
+	case m.info.Overridden:
+		return "override"
+	default:
+		return "base schedule"
+	}
+}
+
+// detailView renders every field of the selected task, without truncation.
+func (m model) detailView() string {
+	task := m.tasks[m.selected]
+	labelStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(m.theme.Date)).Width(10)
+	fields := [][2]string{
+		{"Name", task.Name},
+	}
+	if task.RawName != task.Name {
+		fields = append(fields, [2]string{"Raw name", task.RawName})
+	}
+	fields = append(fields,
+		[2]string{"Time", m.timeRange(task.StartTime, task.EndTime, false)},
+		[2]string{"Duration", m.durations.Format(task.EndTime.Sub(task.StartTime))},
+	)
+	if task.Icon != "" {
+		fields = append(fields, [2]string{"Icon", task.Icon})
+	}
+	if len(task.Tags) > 0 {
+		fields = append(fields, [2]string{"Tags", strings.Join(task.Tags, ", ")})
+	}
+	if task.Location != "" {
+		fields = append(fields, [2]string{"Location", task.Location})
+	}
+	if task.URL != "" {
+		fields = append(fields, [2]string{"URL", task.URL})
+	}
+	if task.Pomodoro != "" {
+		fields = append(fields, [2]string{"Pomodoro", task.Pomodoro})
+	}
+	if m.info.Note != "" {
+		fields = append(fields, [2]string{"Note", m.info.Note})
+	}
+	fields = append(fields, [2]string{"Source", m.source()})
+	if task.Source != "" {
+		fields = append(fields, [2]string{"Defined", task.Source})
+	}
+
+	width := m.viewport.Width
+	if width == 0 {
+		width = 80
+	}
+	valueStyle := lipgloss.NewStyle().Width(max(width-labelStyle.GetWidth()-6, 10))
+	var rows []string
+	for _, f := range fields {
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, labelStyle.Render(f[0]), valueStyle.Render(f[1])))
+	}
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(m.theme.Border)).
+		Padding(0, 1).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
+
+// openSelected opens the url of the selected task and describes the outcome.
+func (m model) openSelected() string {
+	if len(m.tasks) == 0 || m.tasks[m.selected].RawName == "/" {
+		return "No task selected"
+	}
+	task := m.tasks[m.selected]
+	if err := openTaskURL(&task); err != nil {
+		return "Error: " + err.Error()
+	}
+	return "Opened " + task.URL
+}
+
+// tuiJournal returns the completion journal, or nil if its location is unknown.
+func tuiJournal() *journal.Journal {
+	j, err := openJournal()
+	if err != nil {
+		return nil
+	}
+	return j
 }
 
 func isSameDay(t1, t2 time.Time) bool {
@@ -307,31 +905,264 @@ func isSameDay(t1, t2 time.Time) bool {
 	return y1 == y2 && m1 == m2 && d1 == d2
 }
 
-func (m model) View() string {
-	if m.err != nil {
-		return fmt.Sprintf("Error: %v", m.err)
+// header renders the date line above the table. With the mouse enabled, the
+// date sits between clickable ‹ and › arrows.
+// Today's header adds a countdown line below the date.
+func (m model) header() string {
+	now := time.Now()
+	dateStr := m.locale.Format(m.currentDate, m.dateFormat)
+	if m.mouse {
+		dateStr = "‹ " + dateStr + " ›"
 	}
-
-	dateStr := m.currentDate.Format(m.dateFormat)
-	if isSameDay(m.currentDate, time.Now()) {
-		dateStr += " (Today)"
+	isToday := isSameDay(m.currentDate, now)
+	if isToday {
+		dateStr += " " + m.locale.T("today")
+	}
+	if m.tmp {
+		dateStr += " [tmp]"
+	}
+	if m.sched.Config().CycleDays == 7 {
+		_, week := m.currentDate.ISOWeek()
+		dateStr += fmt.Sprintf(" · W%02d", week)
+	}
+	if info, err := m.sched.GetDayInfo(m.currentDate); err == nil && info.Note != "" {
+		dateStr += " · " + info.Note
+	}
+	if u, err := m.sched.GetUsage(m.currentDate); err == nil && u.Scheduled > 0 {
+		if u.Window > 0 {
+			dateStr += fmt.Sprintf(" · %s / %s (%.0f%%)", m.durations.Format(u.Scheduled), m.durations.Format(u.Window), u.Utilization()*100)
+		} else {
+			dateStr += " · " + m.locale.T("scheduled", m.durations.Format(u.Scheduled))
+		}
 	}
 
+	// Wrap it, so narrow terminals don't widen the frame
 	header := lipgloss.NewStyle().
+		Width(m.frameWidth()).
 		Bold(true).
-		Foreground(dateDisplayColor).
-		PaddingBottom(1).
+		Foreground(lipgloss.Color(m.theme.Date)).
 		Render(dateStr)
+	if isToday {
+		if c := m.countdown(now); c != "" {
+			header += "\n" + lipgloss.NewStyle().Width(m.frameWidth()).Render(c)
+		}
+	}
+	return lipgloss.NewStyle().PaddingBottom(1).Render(header)
+}
+
+// countdown describes the time left in the task in progress at now, or the
+// time until the next task, e.g. "Math — ends in 23:41".
+func (m model) countdown(now time.Time) string {
+	label := func(t *scheduler.TaskEvent) string {
+		if m.icons {
+			return t.Label()
+		}
+		return t.Name
+	}
+	if current, err := m.sched.GetCurrentTask(now); err == nil && current != nil && current.RawName != "/" {
+		return m.locale.T("ends_in", label(current), formatCountdown(current.EndTime.Sub(now)))
+	}
+	if next, err := m.sched.GetNextTask(now); err == nil && next != nil {
+		return m.locale.T("next", label(next), formatCountdown(next.StartTime.Sub(now)))
+	}
+	return ""
+}
+
+// formatCountdown formats d as mm:ss, or h:mm:ss from an hour up.
+func formatCountdown(d time.Duration) string {
+	s := int(d / time.Second)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%02d:%02d", s/60, s%60)
+}
+
+// statusLine summarizes the displayed day at now: its cycle day, number of
+// tasks, what is left of them if it is today, scheduled time and what,
+// besides the base schedule, applies to it.
+func (m model) statusLine(now time.Time) string {
+	var parts []string
+	if m.info.IsOff {
+		parts = append(parts, m.locale.T("off_day"))
+	} else {
+		day := m.locale.T("cycle_day", m.info.DayID)
+		// Weekly cycles name their days after weekdays
+		if wd := time.Weekday(m.info.DayID % 7); m.sched.DayName(m.info.DayID) == wd.String() {
+			day += " (" + m.locale.Weekday(wd) + ")"
+		}
+		n := 0
+		for _, t := range m.tasks {
+			if t.RawName != "/" {
+				n++
+			}
+		}
+		parts = append(parts, day, m.locale.T("tasks", n))
+		if isSameDay(m.currentDate, now) && n > 0 {
+			r := scheduler.RemainingTasks(m.tasks, now)
+			parts = append(parts, m.locale.T("remaining", r.Tasks, m.durations.Format(r.Time)))
+		}
+		if u, err := m.sched.GetUsage(m.currentDate); err == nil {
+			parts = append(parts, m.locale.T("scheduled", m.durations.Format(u.Scheduled)))
+		}
+	}
+	if m.tmp {
+		parts = append(parts, m.locale.T("tmp_overlay"))
+	}
+	if m.info.Overridden {
+		override := m.locale.T("override")
+		if m.info.Note != "" {
+			override += ": " + m.info.Note
+		}
+		parts = append(parts, override)
+	}
+	if m.info.Muted {
+		parts = append(parts, m.locale.T("muted"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// reload loads the schedule again and shows it. On error, the current
+// schedule stays and the footer shows why.
+func (m *model) reload(now time.Time) {
+	if m.load == nil {
+		return
+	}
+	if err := m.switchSchedule(m.tmp); err != nil {
+		m.status, m.statusUntil = m.locale.T("reload_failed", err), time.Time{}
+		return
+	}
+	m.setStatus(m.locale.T("reloaded"), now)
+}
+
+// toggleTmp switches between the base schedule and the configured
+// temporary one, staying on the displayed date.
+func (m *model) toggleTmp(now time.Time) {
+	if m.load == nil || m.tmpPath == "" {
+		m.setStatus("No tmp_csv_path configured", now)
+		return
+	}
+	if err := m.switchSchedule(!m.tmp); err != nil {
+		m.status, m.statusUntil = fmt.Sprintf("Switching schedules failed: %v", err), time.Time{}
+		return
+	}
+	m.tmp = !m.tmp
+	if m.tmp {
+		m.setStatus("Showing the temporary schedule", now)
+	} else {
+		m.setStatus("Showing the base schedule", now)
+	}
+}
+
+// switchSchedule loads the base or temporary schedule and shows it instead
+// of the current one, keeping the date and, where possible, the selection.
+// On error, nothing changes.
+func (m *model) switchSchedule(tmp bool) error {
+	cfg, err := m.load(tmp)
+	if err != nil {
+		return err
+	}
+	m.useConfig(newScheduler(cfg), cfg)
+	m.changed = false
+	selected := m.selected
+	m.showDate(m.currentDate)
+	m.selected = min(selected, max(len(m.tasks)-1, 0))
+	m.refreshTable()
+	m.keepSelectionVisible()
+	return nil
+}
+
+// frameWidth is the width of the header, table and footer.
+func (m model) frameWidth() int {
+	if m.viewport.Width == 0 {
+		return 80
+	}
+	return m.viewport.Width
+}
+
+// click handles a left click at column x, row y of the screen: the header
+// arrows change the day, and a click on a row selects it, or opens its detail
+// pane if it repeats a click on the same row.
+func (m *model) click(x, y int, now time.Time) {
+	// Skip the frame's border
+	x, y = x-1, y-1
+	if y == 0 {
+		dateWidth := lipgloss.Width(m.currentDate.Format(m.dateFormat))
+		switch {
+		case x >= 0 && x < 2:
+			m.navigate(m.currentDate.AddDate(0, 0, -1))
+		case x >= dateWidth+2 && x < dateWidth+4:
+			m.navigate(m.currentDate.AddDate(0, 0, 1))
+		}
+		return
+	}
+
+	top := lipgloss.Height(m.header())
+	if m.detail || y < top || y >= top+m.viewport.Height || len(m.rowLines) != len(m.tasks)+1 {
+		return
+	}
+	if m.threeDay {
+		// Only the center column is selectable
+		if w := dayColumnWidth(m.frameWidth()); x < w || x >= 2*w {
+			return
+		}
+	}
+	line := y - top + m.viewport.YOffset
+	for _, g := range m.gapRows {
+		if line >= g[0] && line < g[1] {
+			return
+		}
+	}
+	for i := range m.tasks {
+		if line < m.rowLines[i] || line >= m.rowLines[i+1] {
+			continue
+		}
+		if i == m.lastClickRow && now.Sub(m.lastClick) <= doubleClickInterval {
+			m.detail = true
+			m.lastClick = time.Time{}
+		} else {
+			m.lastClick, m.lastClickRow = now, i
+		}
+		m.selected = i
+		m.refreshTable()
+		return
+	}
+}
+
+func (m model) View() string {
+	if m.err != nil {
+		return fmt.Sprintf("Error: %v", m.err)
+	}
+
+	header := m.header()
+	width := m.frameWidth()
 
 	baseStyle := lipgloss.NewStyle().
 		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("240"))
+		BorderForeground(lipgloss.Color(m.theme.Border))
+
+	body := m.viewport.View()
+	help := m.locale.T("help")
+	if m.detail {
+		body = m.detailView()
+		help = m.locale.T("help_detail")
+	}
+	summary := lipgloss.NewStyle().Faint(true).Render(m.statusLine(time.Now()))
+	if m.changed {
+		summary += " • " + m.locale.T("config_changed")
+	}
+	help = summary + "\n" + help
+	if m.prompting {
+		help += "\n" + m.locale.T("export_prompt", m.prompt)
+	} else if m.status != "" {
+		help += "\n" + m.status
+	}
+	footer := lipgloss.NewStyle().Width(width).Padding(1, 0, 0, 2).Render(help)
 
 	return baseStyle.Render(
 		lipgloss.JoinVertical(lipgloss.Left,
 			header,
-			m.viewport.View(),
-			"\n  ←/h: prev day • →/l: next day • ↑/k/u: up • ↓/j/d: down • t: today • q: quit",
+			body,
+			footer,
 		),
 	) + "\n"
 }
@@ -0,0 +1,621 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/i18n"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var updateGolden = flag.Bool("update", false, "regenerate the golden files in testdata")
+
+func TestRefreshTable_IconColumnAligned(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Math", Start: "09:00", End: "10:00", Icon: "📚"},
+			{Name: "Art", Start: "10:00", End: "11:00"},
+			{Name: "Run", Start: "11:00", End: "12:00", Icon: "*"},
+		}}},
+	}
+	for _, icons := range []bool{false, true} {
+		m := model{sched: scheduler.New(cfg), currentDate: time.Date(2024, 1, 1, 8, 0, 0, 0, time.Local), icons: icons}
+		m.viewport.Width, m.viewport.Height = 60, 20
+		m.refreshTable()
+
+		// Every table line must end at the same column, whatever the icon widths
+		lines := strings.Split(strings.TrimSpace(m.viewport.View()), "\n")
+		want := lipgloss.Width(strings.TrimRight(lines[0], " "))
+		for i, line := range lines {
+			if w := lipgloss.Width(strings.TrimRight(line, " ")); w != want {
+				t.Errorf("icons=%v, line %d: Expected width %d, got %d: %q", icons, i, want, w, line)
+			}
+		}
+		if got := strings.Contains(m.viewport.View(), "📚"); got != icons {
+			t.Errorf("icons=%v: Expected icon shown %v, got %v", icons, icons, got)
+		}
+	}
+}
+
+func TestRefreshTable_URLMarker(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Lecture", Start: "09:00", End: "10:00", URL: "https://meet.example.com/abc"},
+			{Name: "Reading", Start: "10:00", End: "11:00"},
+		}}},
+	}
+	m := model{sched: scheduler.New(cfg), currentDate: time.Date(2024, 1, 1, 8, 0, 0, 0, time.Local)}
+	m.viewport.Width, m.viewport.Height = 60, 20
+	m.refreshTable()
+
+	for _, line := range strings.Split(m.viewport.View(), "\n") {
+		want := strings.Contains(line, "Lecture")
+		if got := strings.Contains(line, "🔗"); got != want {
+			t.Errorf("Expected link marker only on the task with a url, got line %q", line)
+		}
+	}
+}
+
+func TestSelection(t *testing.T) {
+	var tasks []config.Task
+	for h := 8; h < 20; h++ {
+		tasks = append(tasks, config.Task{Name: fmt.Sprintf("Task %d", h), Start: fmt.Sprintf("%02d:00", h), End: fmt.Sprintf("%02d:00", h+1)})
+	}
+	cfg := &config.Config{CycleDays: 7, Days: []config.Day{{ID: 1, Tasks: tasks}}}
+	m := model{sched: scheduler.New(cfg), currentDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)}
+	m.viewport.Width, m.viewport.Height = 60, 8
+	m.refreshTable()
+
+	key := func(k string) {
+		t.Helper()
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		}
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+
+	for range 20 {
+		key("j")
+	}
+	if m.selected != len(tasks)-1 {
+		t.Fatalf("Expected selection clamped to the last row, got %d", m.selected)
+	}
+	if !strings.Contains(m.viewport.View(), "› Task 19") {
+		t.Errorf("Expected the selected row to be scrolled into view, got:\n%s", m.viewport.View())
+	}
+
+	key("k")
+	key("enter")
+	if !m.detail {
+		t.Fatal("Expected enter to open the detail pane")
+	}
+	view := m.View()
+	for _, want := range []string{"Task 18", "18:00 - 19:00", "1h", "base schedule"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected detail pane to contain %q, got:\n%s", want, view)
+		}
+	}
+	key("esc")
+	if m.detail {
+		t.Error("Expected esc to close the detail pane")
+	}
+
+	// Day navigation resets the selection to the first row
+	key("l")
+	key("h")
+	if m.selected != 0 || m.viewport.YOffset != 0 {
+		t.Errorf("Expected first row selected and scrolled to the top, got row %d at offset %d", m.selected, m.viewport.YOffset)
+	}
+}
+
+func TestView_Golden(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Standup", Start: "09:00", End: "09:15"},
+			{Name: "Deep work on the quarterly planning document and the budget review", Start: "09:30", End: "12:00", URL: "https://meet.example.com/abc"},
+			{Name: "Lunch", Start: "12:00", End: "13:00"},
+		}}},
+	}
+	for _, tt := range []struct {
+		name     string
+		width    int
+		showGaps bool
+		clock    config.Clock
+	}{
+		{"40", 40, false, config.Clock24},
+		{"60", 60, false, config.Clock24},
+		{"100", 100, false, config.Clock24},
+		{"60_gaps", 60, true, config.Clock24},
+		{"60_12h", 60, true, config.Clock12},
+		{"100_12h", 100, false, config.Clock12},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := model{sched: scheduler.New(cfg), currentDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), dateFormat: "2006-01-02 Mon", showGaps: tt.showGaps, clock: tt.clock}
+			m.viewport.Width, m.viewport.Height = tt.width, 16
+			m.refreshTable()
+			got := m.View()
+
+			path := filepath.Join("testdata", fmt.Sprintf("tui_%s.golden", tt.name))
+			if *updateGolden {
+				if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+					t.Fatalf("Failed to write %s: %v", path, err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read %s (run with -update to create it): %v", path, err)
+			}
+			if got != string(want) {
+				t.Errorf("View() no longer matches %s. If the change is intentional, run 'go test ./cmd/sked -run TestView_Golden -update'.\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestMouse(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Standup", Start: "09:00", End: "09:15"},
+			{Name: "Lunch", Start: "12:00", End: "13:00"},
+		}}},
+	}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	m := model{sched: scheduler.New(cfg), currentDate: day, dateFormat: "2006-01-02", mouse: true}
+	m.viewport.Width, m.viewport.Height = 60, 10
+	m.refreshTable()
+
+	// lineOf returns the screen row showing s
+	lineOf := func(s string) int {
+		t.Helper()
+		for y, line := range strings.Split(m.View(), "\n") {
+			if strings.Contains(line, s) {
+				return y
+			}
+		}
+		t.Fatalf("%q not found in view:\n%s", s, m.View())
+		return 0
+	}
+	click := func(x, y int) {
+		next, _ := m.Update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+		m = next.(model)
+	}
+
+	if !strings.Contains(m.View(), "‹ 2024-01-01 ›") {
+		t.Fatalf("Expected arrows around the date, got:\n%s", m.View())
+	}
+
+	click(10, lineOf("Lunch"))
+	if m.selected != 1 || m.detail {
+		t.Fatalf("Expected a click to select row 1 without details, got row %d, detail %v", m.selected, m.detail)
+	}
+	click(10, lineOf("Lunch"))
+	if !m.detail {
+		t.Fatal("Expected a double click to open the detail pane")
+	}
+	m.detail = false
+
+	// The header reads "‹ 2024-01-01 ›" after the frame's border
+	click(lipgloss.Width("│‹ 2024-01-01 "), 1)
+	if !isSameDay(m.currentDate, day.AddDate(0, 0, 1)) || m.selected != 0 {
+		t.Errorf("Expected › to show the next day with the first row selected, got %v, row %d", m.currentDate, m.selected)
+	}
+	click(1, 1)
+	if !isSameDay(m.currentDate, day) {
+		t.Errorf("Expected ‹ to show the previous day, got %v", m.currentDate)
+	}
+
+	// Free-time rows can't be selected
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	m = next.(model)
+	click(10, lineOf("2h45m free"))
+	if m.selected != 0 {
+		t.Errorf("Expected a click on a free-time row to keep row 0 selected, got row %d", m.selected)
+	}
+	click(10, lineOf("Lunch"))
+	if m.selected != 1 {
+		t.Errorf("Expected a click below the free-time row to select row 1, got row %d", m.selected)
+	}
+}
+
+func TestThreeDayView(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 1, Tasks: []config.Task{
+				{Name: "Standup", Start: "09:00", End: "09:15"},
+				{Name: "Lunch", Start: "12:00", End: "13:00"},
+			}},
+			{ID: 2, Tasks: []config.Task{{Name: "Math", Start: "08:00", End: "09:00"}}},
+		},
+		Overrides: []config.Override{{DateStr: "2023-12-31", IsOff: true}},
+	}
+	if err := cfg.ProcessOverrides(); err != nil {
+		t.Fatalf("ProcessOverrides() returned error: %v", err)
+	}
+	m := model{sched: scheduler.New(cfg), currentDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), offDayText: "Day off."}
+	m.viewport.Width, m.viewport.Height = 90, 12
+	m.refreshTable()
+
+	key := func(k string) {
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		m = next.(model)
+	}
+	key("v")
+	key("j")
+	view := m.viewport.View()
+	for _, want := range []string{"Sun Dec 31", "Day off.", "Mon Jan 1", "09:00 Standup", "› 12:00 Lunch", "Tue Jan 2", "08:00 Math"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected three-day view to contain %q, got:\n%s", want, view)
+		}
+	}
+
+	// Navigation shifts the whole window
+	key("l")
+	view = m.viewport.View()
+	if strings.Contains(view, "Sun Dec 31") || !strings.Contains(view, "Wed Jan 3") || !strings.Contains(view, "› 08:00 Math") {
+		t.Errorf("Expected Jan 1-3 with the first row of Jan 2 selected, got:\n%s", view)
+	}
+
+	key("v")
+	if strings.Contains(m.viewport.View(), "Wed Jan 3") {
+		t.Error("Expected v to switch back to the single-day table")
+	}
+}
+
+func TestCountdown(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Math", Start: "09:00", End: "10:00"},
+			{Name: "History", Start: "11:00", End: "12:00"},
+		}}},
+	}
+	m := model{sched: scheduler.New(cfg)}
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		at   time.Duration
+		want string
+	}{
+		{9*time.Hour + 36*time.Minute + 19*time.Second, "Math — ends in 23:41"},
+		{10*time.Hour + 45*time.Minute + 58*time.Second, "Next: History in 14:02"},
+		{7 * time.Hour, "Next: Math in 2:00:00"},
+	}
+	for _, tt := range tests {
+		if got := m.countdown(day.Add(tt.at)); got != tt.want {
+			t.Errorf("At %v: Expected %q, got %q", tt.at, tt.want, got)
+		}
+	}
+}
+
+func TestStatusLine(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Math", Start: "09:00", End: "10:00"},
+			{Name: "/", Start: "10:00", End: "11:00"},
+			{Name: "History", Start: "11:00", End: "11:30"},
+		}}},
+		Overrides: []config.Override{{DateStr: "2024-01-08", UseDayID: 1, Note: "Swap"}},
+	}
+	if err := cfg.ProcessOverrides(); err != nil {
+		t.Fatalf("ProcessOverrides() returned error: %v", err)
+	}
+
+	now := time.Date(2024, 1, 1, 9, 30, 0, 0, time.Local)
+	tests := []struct {
+		date time.Time
+		tmp  bool
+		lang string
+		want string
+	}{
+		{time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), false, "en", "Day 1 (Monday) · 2 tasks · 2 left (1h) · 1h30m scheduled"},
+		{time.Date(2024, 1, 8, 0, 0, 0, 0, time.Local), true, "en", "Day 1 (Monday) · 2 tasks · 1h30m scheduled · tmp overlay · override: Swap"},
+		{time.Date(2024, 1, 8, 0, 0, 0, 0, time.Local), true, "de", "Tag 1 (Montag) · 2 Aufgaben · 1h30m geplant · temporärer Plan · Ausnahme: Swap"},
+	}
+	for _, tt := range tests {
+		l, _ := i18n.Lookup(tt.lang)
+		m := model{sched: scheduler.New(cfg), currentDate: tt.date, tmp: tt.tmp, locale: l}
+		m.refreshTable()
+		if got := m.statusLine(now); got != tt.want {
+			t.Errorf("%s: Expected %q, got %q", tt.date.Format("2006-01-02"), tt.want, got)
+		}
+	}
+}
+
+func TestTick_RefreshesOnlyWhenDue(t *testing.T) {
+	cfg := &config.Config{CycleDays: 7, Days: []config.Day{{ID: 1, Tasks: []config.Task{{Name: "Math", Start: "09:00", End: "10:00"}}}}}
+	m := model{sched: scheduler.New(cfg), currentDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)}
+	m.refreshTable()
+
+	// A past day changes only at midnight
+	m.tasks = nil
+	next, _ := m.Update(tickMsg(time.Now()))
+	if m = next.(model); m.tasks != nil {
+		t.Error("Expected a tick before refreshAt to leave the table alone")
+	}
+	next, _ = m.Update(tickMsg(m.refreshAt.Add(time.Second)))
+	if m = next.(model); len(m.tasks) != 1 {
+		t.Error("Expected a tick after refreshAt to refresh the table")
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	write := func(content string, mod time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	day := func(name string) string {
+		return fmt.Sprintf("cycle_days = 7\n[[day]]\nid = 1\n[[day.tasks]]\nname = %q\nstart = \"09:00\"\nend = \"10:00\"\n", name)
+	}
+	load := func(bool) (*config.Config, error) {
+		cfg, err := config.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		return cfg, validateTUIConfig(cfg)
+	}
+
+	start := time.Now().Add(-time.Hour)
+	write(day("Math"), start)
+	cfg, err := load(false)
+	if err != nil {
+		t.Fatalf("load() returned error: %v", err)
+	}
+	m := model{currentDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), load: load}
+	m.useConfig(scheduler.New(cfg), cfg)
+	m.viewport.Width, m.viewport.Height = 100, 20
+	m.refreshTable()
+
+	// An edit is noticed on the next tick, but not applied
+	write(day("Art")+"[tui.theme]\npreset = \"light\"\n", start.Add(time.Minute))
+	next, _ := m.Update(tickMsg(time.Now()))
+	m = next.(model)
+	if !m.changed || !strings.Contains(m.View(), "config changed on disk (press r)") {
+		t.Error("Expected the footer to show that the config changed on disk")
+	}
+	if m.tasks[0].Name != "Math" {
+		t.Errorf("Expected the old schedule before reloading, got %q", m.tasks[0].Name)
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = next.(model)
+	if m.tasks[0].Name != "Art" || m.changed || m.status != "Reloaded" {
+		t.Errorf("Expected the new schedule, got %q, changed=%v, status %q", m.tasks[0].Name, m.changed, m.status)
+	}
+	if m.theme.Preset != "light" {
+		t.Errorf("Expected the reload to apply the light theme, got %q", m.theme.Preset)
+	}
+	next, _ = m.Update(tickMsg(time.Now().Add(statusDuration + time.Second)))
+	if m = next.(model); m.status != "" {
+		t.Errorf("Expected the reload message to clear, got %q", m.status)
+	}
+
+	// A broken config keeps the schedule shown
+	write("cycle_days = 0\n", start.Add(2*time.Minute))
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = next.(model)
+	if m.tasks[0].Name != "Art" || !strings.HasPrefix(m.status, "Reload failed: ") {
+		t.Errorf("Expected the old schedule and an error, got %q, status %q", m.tasks[0].Name, m.status)
+	}
+}
+
+func TestWeekNavigation(t *testing.T) {
+	weekly := &config.Config{CycleDays: 7}
+	cycle := &config.Config{CycleDays: 3, AnchorDate: "2024-01-01"}
+	wednesday := time.Date(2024, 1, 3, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name string
+		cfg  *config.Config
+		keys []string
+		want time.Time
+	}{
+		{"next_week", weekly, []string{"L"}, time.Date(2024, 1, 10, 0, 0, 0, 0, time.Local)},
+		{"previous_week", weekly, []string{"shift+left"}, time.Date(2023, 12, 27, 0, 0, 0, 0, time.Local)},
+		{"monday", weekly, []string{"1"}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)},
+		{"sunday", weekly, []string{"7"}, time.Date(2024, 1, 7, 0, 0, 0, 0, time.Local)},
+		{"sunday_then_monday", weekly, []string{"7", "1"}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)},
+		// 2024-01-03 is day 2 of the cycle
+		{"cycle_day_next", cycle, []string{"1"}, time.Date(2024, 1, 5, 0, 0, 0, 0, time.Local)},
+		{"cycle_day_same", cycle, []string{"2"}, wednesday},
+		{"cycle_day_missing", cycle, []string{"5"}, wednesday},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := model{sched: scheduler.New(tt.cfg), currentDate: wednesday, follow: true}
+			for _, k := range tt.keys {
+				msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+				if k == "shift+left" {
+					msg = tea.KeyMsg{Type: tea.KeyShiftLeft}
+				}
+				next, _ := m.Update(msg)
+				m = next.(model)
+			}
+			if !isSameDay(m.currentDate, tt.want) {
+				t.Errorf("Expected %s, got %s", tt.want.Format("2006-01-02"), m.currentDate.Format("2006-01-02"))
+			}
+			if m.follow {
+				t.Error("Expected navigation to stop following today")
+			}
+		})
+	}
+}
+
+func TestFollowToday(t *testing.T) {
+	cfg := &config.Config{CycleDays: 7}
+	today := time.Now()
+	m := model{sched: scheduler.New(cfg), currentDate: today.AddDate(0, 0, -1), follow: true}
+
+	// At midnight the view moves to the new day
+	next, _ := m.Update(tickMsg(today))
+	if m = next.(model); !isSameDay(m.currentDate, today) {
+		t.Errorf("Expected the view to follow today, got %s", m.currentDate.Format("2006-01-02"))
+	}
+
+	// Until the user navigates away
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")})
+	next, _ = next.(model).Update(tickMsg(today))
+	if m = next.(model); isSameDay(m.currentDate, today) {
+		t.Error("Expected a tick not to move the view after navigating")
+	}
+
+	// t resumes following
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if m = next.(model); !m.follow || !isSameDay(m.currentDate, today) {
+		t.Error("Expected t to jump to today and follow it")
+	}
+}
+
+func TestHeader_ISOWeek(t *testing.T) {
+	for _, tt := range []struct {
+		cfg  *config.Config
+		want bool
+	}{
+		{&config.Config{CycleDays: 7}, true},
+		{&config.Config{CycleDays: 3, AnchorDate: "2024-01-01"}, false},
+	} {
+		m := model{sched: scheduler.New(tt.cfg), currentDate: time.Date(2024, 12, 30, 0, 0, 0, 0, time.Local), dateFormat: "2006-01-02"}
+		if got := strings.Contains(m.header(), "W01"); got != tt.want {
+			t.Errorf("cycle_days=%d: Expected week number shown %v, got %v: %q", tt.cfg.CycleDays, tt.want, got, m.header())
+		}
+	}
+}
+
+func TestExportDay(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Math", Start: "09:00", End: "10:00", Icon: "📚"},
+			{Name: "/", Start: "10:00", End: "11:00"},
+			{Name: "Art", Start: "11:00", End: "12:00"},
+		}}},
+		Overrides: []config.Override{{DateStr: "2024-01-08", IsOff: true, Note: "Holiday"}},
+	}
+	if err := cfg.ProcessOverrides(); err != nil {
+		t.Fatalf("ProcessOverrides() returned error: %v", err)
+	}
+	m := model{sched: scheduler.New(cfg), currentDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), dateFormat: "2006-01-02 Mon", offDayText: "Day off.", icons: true}
+	m.refreshTable()
+
+	want := "2024-01-01 Mon\n09:00 - 10:00  📚 Math\n11:00 - 12:00  Art\n"
+	if got := m.dayText(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	key := func(msg tea.KeyMsg) {
+		next, _ := m.Update(msg)
+		m = next.(model)
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	// Y prompts for a path; keys edit it instead of acting on the table
+	key(runes("Y"))
+	if !m.prompting || m.prompt != "sked-2024-01-01.txt" {
+		t.Fatalf("Expected a prompt with the default file name, got %v, %q", m.prompting, m.prompt)
+	}
+	path := filepath.Join(t.TempDir(), "day.txt")
+	key(tea.KeyMsg{Type: tea.KeyCtrlU})
+	key(runes(path + "x"))
+	key(tea.KeyMsg{Type: tea.KeyBackspace})
+	if !isSameDay(m.currentDate, time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)) {
+		t.Error("Expected keys typed into the prompt not to change the day")
+	}
+	key(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.prompting || m.status != "Exported to "+path {
+		t.Errorf("Expected the export to be confirmed, got %v, %q", m.prompting, m.status)
+	}
+	if got, err := os.ReadFile(path); err != nil || string(got) != want {
+		t.Errorf("Expected %q in the file, got %q, %v", want, got, err)
+	}
+
+	// Other users' homes are not expanded into ours
+	if got := m.exportDay("~bob/day.txt"); !strings.HasPrefix(got, "Export failed: ") {
+		t.Errorf("Expected ~bob to be rejected, got %q", got)
+	}
+
+	// Off days export the banner with the note
+	m.showDate(time.Date(2024, 1, 8, 0, 0, 0, 0, time.Local))
+	if got, want := m.dayText(), "2024-01-08 Mon · Holiday\nDay off.\n"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	key(runes("Y"))
+	key(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.prompting {
+		t.Error("Expected esc to cancel the prompt")
+	}
+}
+
+func TestToggleTmp(t *testing.T) {
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "tmp.csv")
+	if err := os.WriteFile(csvPath, []byte("Start,End,Task\n09:00,10:00,Exam\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.toml")
+	content := fmt.Sprintf("cycle_days = 7\ntmp_csv_path = %q\n[[day]]\nid = %d\n[[day.tasks]]\nname = \"Math\"\nstart = \"09:00\"\nend = \"10:00\"\n", csvPath, time.Now().Weekday())
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	oldCfg, oldTmp := cfgFile, tmpFile
+	cfgFile, tmpFile = path, ""
+	t.Cleanup(func() { cfgFile, tmpFile = oldCfg, oldTmp })
+
+	cfg, err := loadTUIConfig(false)
+	if err != nil {
+		t.Fatalf("loadTUIConfig() returned error: %v", err)
+	}
+	m := model{currentDate: time.Now(), dateFormat: "2006-01-02", load: loadTUIConfig}
+	m.useConfig(scheduler.New(cfg), cfg)
+	m.refreshTable()
+
+	toggle := func() {
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+		m = next.(model)
+	}
+	toggle()
+	if !m.tmp || len(m.tasks) != 1 || m.tasks[0].Name != "Exam" || !strings.Contains(m.header(), "[tmp]") {
+		t.Fatalf("Expected the temporary schedule with a [tmp] badge, got tmp=%v, %v, header %q", m.tmp, m.tasks, m.header())
+	}
+	toggle()
+	if m.tmp || len(m.tasks) != 1 || m.tasks[0].Name != "Math" || strings.Contains(m.header(), "[tmp]") {
+		t.Fatalf("Expected the base schedule again, got tmp=%v, %v", m.tmp, m.tasks)
+	}
+
+	// With --tmp there is no base schedule to switch to
+	tmpFile = csvPath
+	cfg, err = loadTUIConfig(true)
+	if err != nil {
+		t.Fatalf("loadTUIConfig() returned error: %v", err)
+	}
+	m = model{currentDate: time.Now(), tmp: true, load: loadTUIConfig}
+	m.useConfig(scheduler.New(cfg), cfg)
+	toggle()
+	if !m.tmp || m.status != "No tmp_csv_path configured" {
+		t.Errorf("Expected the toggle to be refused, got tmp=%v, status %q", m.tmp, m.status)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/clipboard"
+	"github.com/Daniel-42-z/sked/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// statusDuration is how long the footer shows a confirmation.
+const statusDuration = 3 * time.Second
+
+// dayText renders the displayed day as plain text: the date, with the
+// override note if any, and one "09:00 - 10:00  Name" line per task. Empty
+// slots are left out, and the tag filter already applies to m.tasks.
+func (m *model) dayText() string {
+	var b strings.Builder
+	b.WriteString(m.locale.Format(m.currentDate, m.dateFormat))
+	if m.info.Note != "" {
+		b.WriteString(" · " + m.info.Note)
+	}
+	b.WriteString("\n")
+	if m.info.IsOff {
+		b.WriteString(m.offDayText + "\n")
+		return b.String()
+	}
+	for _, t := range m.tasks {
+		if t.RawName == "/" {
+			continue
+		}
+		name := t.Name
+		if m.icons {
+			name = t.Label()
+		}
+		fmt.Fprintf(&b, "%s  %s\n", m.timeRange(t.StartTime, t.EndTime, false), name)
+	}
+	return b.String()
+}
+
+// copyDay copies the displayed day to the clipboard and returns the
+// footer message.
+func (m *model) copyDay() string {
+	if err := clipboard.Copy(os.Stdout, m.dayText()); err != nil {
+		return m.locale.T("copy_failed", err)
+	}
+	return m.locale.T("copied", m.currentDate.Format("2006-01-02"))
+}
+
+// exportDay writes the displayed day to path and returns the footer message.
+func (m *model) exportDay(path string) string {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return m.locale.T("export_canceled")
+	}
+	path, err := config.ExpandTilde(path)
+	if err != nil {
+		return m.locale.T("export_failed", err)
+	}
+	if err := os.WriteFile(path, []byte(m.dayText()), 0644); err != nil {
+		return m.locale.T("export_failed", err)
+	}
+	return m.locale.T("exported", path)
+}
+
+// promptKey edits the export path prompt: enter writes the file, esc
+// cancels.
+func (m *model) promptKey(msg tea.KeyMsg, now time.Time) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.prompting = false
+		m.setStatus(m.exportDay(m.prompt), now)
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.prompting = false
+	case tea.KeyBackspace:
+		if r := []rune(m.prompt); len(r) > 0 {
+			m.prompt = string(r[:len(r)-1])
+		}
+	case tea.KeyCtrlU:
+		m.prompt = ""
+	case tea.KeyRunes, tea.KeySpace:
+		m.prompt += string(msg.Runes)
+	}
+}
+
+// setStatus shows msg in the footer until statusDuration has passed.
+func (m *model) setStatus(msg string, now time.Time) {
+	m.status, m.statusUntil = msg, now.Add(statusDuration)
+}
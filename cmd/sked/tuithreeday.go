@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/journal"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// minDayColumnWidth is the narrowest a column of the three-day view gets,
+// borders included, even if that widens the frame.
+const minDayColumnWidth = 20
+
+// renderThreeDays renders the days before and after m.currentDate beside it,
+// one "HH:MM Name" line per task. The center column is framed and carries
+// the selection; the side columns are dimmed. It records the center rows in
+// m.rowLines.
+func (m *model) renderThreeDays(totalWidth int, now time.Time, statuses map[journal.Key]journal.Status) (string, error) {
+	colWidth := dayColumnWidth(totalWidth)
+	inner := colWidth - 4 // border and padding
+
+	var height int
+	var bodies [3][]string
+	for c := range 3 {
+		date := m.currentDate.AddDate(0, 0, c-1)
+		info, err := m.sched.GetDayInfo(date)
+		if err != nil {
+			return "", err
+		}
+		tasks, err := m.sched.GetTasksForDate(date)
+		if err != nil {
+			return "", err
+		}
+		m.noteBoundaries(tasks, now)
+
+		title := m.locale.Format(date, "Mon Jan 2")
+		if isSameDay(date, now) {
+			title += " " + m.locale.T("today")
+		}
+		lines := []string{
+			lipgloss.NewStyle().Bold(true).Render(truncate(title, inner)),
+			strings.Repeat("─", inner),
+		}
+		switch {
+		case info.IsOff:
+			lines = append(lines, "", lipgloss.PlaceHorizontal(inner, lipgloss.Center, m.offDayText))
+		case len(tasks) == 0:
+			lines = append(lines, "", lipgloss.PlaceHorizontal(inner, lipgloss.Center, m.locale.T("no_tasks")))
+		}
+		for i, task := range tasks {
+			lines = append(lines, m.dayColumnLine(c == 1, i, task, inner, now, statuses[journal.KeyOf(task)]))
+		}
+		bodies[c] = lines
+		height = max(height, len(lines))
+	}
+
+	var columns []string
+	for c, lines := range bodies {
+		style := lipgloss.NewStyle().
+			Width(colWidth-2).
+			Height(height).
+			Padding(0, 1).
+			Border(lipgloss.NormalBorder()).
+			BorderForeground(lipgloss.Color(m.theme.Border))
+		if c != 1 {
+			style = style.Faint(true).Border(lipgloss.HiddenBorder())
+		}
+		columns = append(columns, style.Render(strings.Join(lines, "\n")))
+	}
+
+	// Center tasks start below the top border, the title and its rule
+	if n := len(m.tasks); n > 0 {
+		m.rowLines = make([]int, n+1)
+		for i := range m.rowLines {
+			m.rowLines[i] = 3 + i
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, columns...), nil
+}
+
+// dayColumnWidth is the width of each three-day column, borders included.
+func dayColumnWidth(totalWidth int) int {
+	return max((totalWidth-1)/3, minDayColumnWidth)
+}
+
+// dayColumnLine renders task, the i-th task of its day, as one line of a
+// three-day column. Only center lines show the selection.
+func (m *model) dayColumnLine(center bool, i int, task scheduler.TaskEvent, width int, now time.Time, status journal.Status) string {
+	name := task.Name
+	if m.icons && task.Icon != "" {
+		name = task.Icon + " " + name
+	}
+	switch status {
+	case journal.Done:
+		name = "✓ " + name
+	case journal.Skipped:
+		name = "✗ " + name
+	}
+	if center && scheduler.ConflictsWith(m.conflicts, task) != nil {
+		name = "⚠ " + name
+	}
+	// Right-align the time, so names line up on a 12-hour clock too
+	text := fmt.Sprintf("%*s %s", m.clock.Width(), m.clock.Format(task.StartTime), name)
+	selected := center && i == m.selected
+	if selected {
+		text = "› " + text
+	}
+	// Cut the name rather than the link marker
+	marker := ""
+	if task.URL != "" {
+		marker = " 🔗"
+	}
+	text = truncate(text, width-lipgloss.Width(marker)) + marker
+
+	style := lipgloss.NewStyle().Width(width)
+	isActive := !now.Before(task.StartTime) && now.Before(task.EndTime)
+	if isActive {
+		highlight := lipgloss.Color(m.theme.ActiveBackground)
+		if task.Color != "" {
+			highlight = lipgloss.Color(task.Color)
+		} else if c := m.sched.Config().TagColor(task.Tags); c != "" {
+			highlight = lipgloss.Color(c)
+		}
+		style = style.Foreground(lipgloss.Color(m.theme.ActiveForeground)).Background(highlight)
+	}
+	if selected {
+		style = style.Bold(true)
+		if !isActive {
+			style = style.Background(lipgloss.Color(m.theme.Selected))
+		}
+	}
+	return style.Render(text)
+}
+
+// truncate shortens s to at most width display cells, ending it with "…" if
+// anything was cut.
+func truncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	untilEvent   string
+	untilHuman   bool
+	untilHorizon time.Duration
+)
+
+var untilCmd = &cobra.Command{
+	Use:   "until",
+	Short: "Print the seconds until the current task ends or the next one starts",
+	Long: `Print the number of seconds until the next task boundary: the end of the
+task in progress or the start of the next task, whichever comes first.
+--event end only considers the end of the current task and --event start only
+the start of the next one. Exits with status 1 if there is no such boundary
+within --horizon.`,
+	Example: `  sked until                 # 720
+  sked until --human         # 12m
+  sked until --event start   # seconds until the next task starts`,
+	Args: cobra.NoArgs,
+	RunE: runUntil,
+}
+
+func init() {
+	untilCmd.Flags().StringVar(&untilEvent, "event", "next", "boundary to count down to: next, start or end")
+	untilCmd.Flags().BoolVar(&untilHuman, "human", false, "print a duration such as 12m instead of seconds (in the configured duration_style)")
+	untilCmd.Flags().DurationVar(&untilHorizon, "horizon", 24*time.Hour, "fail if the boundary is further away than this")
+	addTagFlag(untilCmd)
+	rootCmd.AddCommand(untilCmd)
+}
+
+func runUntil(cmd *cobra.Command, args []string) error {
+	switch untilEvent {
+	case "next", "start", "end":
+	default:
+		return fmt.Errorf("invalid --event value '%s' (expected next, start or end)", untilEvent)
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	sched := newScheduler(cfg)
+	now := time.Now()
+
+	var b scheduler.Boundary
+	var ok bool
+	switch untilEvent {
+	case "next":
+		b, ok, err = sched.GetNextBoundary(now)
+	case "end":
+		var current *scheduler.TaskEvent
+		current, err = sched.GetCurrentTask(now)
+		b, ok = scheduler.NextBoundary(current, nil, now)
+	case "start":
+		var next *scheduler.TaskEvent
+		next, err = sched.GetNextTask(now)
+		b, ok = scheduler.NextBoundary(nil, next, now)
+	}
+	if err != nil {
+		return err
+	}
+	if !ok || b.At.Sub(now) > untilHorizon {
+		cmd.SilenceUsage = true
+		kind := untilEvent
+		if kind == "next" {
+			kind = "boundary"
+		}
+		return fmt.Errorf("no task %s within %s", kind, untilHorizon)
+	}
+
+	left := b.At.Sub(now)
+	if untilHuman {
+		fmt.Println(displayDurations(cfg).Left(left))
+		return nil
+	}
+	fmt.Println(int64(math.Ceil(left.Seconds())))
+	return nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/dateparse"
+
+	"github.com/spf13/cobra"
+)
+
+var vacationNote string
+
+var vacationCmd = &cobra.Command{
+	Use:   "vacation FROM..TO",
+	Short: "Mark a date range off",
+	Long: `Mark a date range (FROM..TO, or a single date) off by appending an
+is_off override to the TOML config. Vacations overlapping or adjoining the
+range are merged into it. Dates are YYYY-MM-DD, today, tomorrow, a weekday
+for its next occurrence, next/last <weekday>, next/last week or +N/-N days,
+e.g. "monday..friday" or "+1..+7".`,
+	Args: cobra.ExactArgs(1),
+	RunE: runVacationAdd,
+}
+
+var vacationListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List vacations",
+	Args:  cobra.NoArgs,
+	RunE:  runVacationList,
+}
+
+var vacationRmCmd = &cobra.Command{
+	Use:   "rm <id-or-date>",
+	Short: "Remove a vacation by ID or by a date it covers",
+	Long: `Remove a vacation by the ID 'sked vacation list' shows, or by a date it
+covers in any form 'sked vacation' accepts, e.g. "tomorrow" or "+3".`,
+	Args: cobra.ExactArgs(1),
+	RunE: runVacationRm,
+}
+
+func init() {
+	vacationCmd.Flags().StringVar(&vacationNote, "note", "", "label shown for the covered dates, e.g. \"PTO\"")
+	vacationCmd.AddCommand(vacationListCmd, vacationRmCmd)
+	rootCmd.AddCommand(vacationCmd)
+}
+
+// vacationConfigPath returns the TOML config that vacations are written to.
+func vacationConfigPath() (string, error) {
+	if temporary() {
+		return "", fmt.Errorf("vacations can't be stored in a temporary config")
+	}
+	path, err := configPath(true)
+	if err != nil {
+		return "", err
+	}
+	if strings.ToLower(filepath.Ext(path)) != ".toml" {
+		return "", fmt.Errorf("vacations are stored as overrides in a TOML config; %s is not one", path)
+	}
+	return path, nil
+}
+
+func runVacationAdd(cmd *cobra.Command, args []string) error {
+	fromStr, toStr, isRange := strings.Cut(args[0], "..")
+	if !isRange {
+		toStr = fromStr
+	}
+	// Overrides are plain dates, kept at UTC midnight
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from, err := dateparse.Parse(fromStr, today)
+	if err != nil {
+		return fmt.Errorf("invalid start date: %w", err)
+	}
+	to, err := dateparse.Parse(toStr, today)
+	if err != nil {
+		return fmt.Errorf("invalid end date: %w", err)
+	}
+
+	path, err := vacationConfigPath()
+	if err != nil {
+		return err
+	}
+	v, err := config.AddVacation(path, from, to, vacationNote)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added vacation %d: %s\n", v.ID, describeVacation(v))
+	return nil
+}
+
+func runVacationList(cmd *cobra.Command, args []string) error {
+	path, err := vacationConfigPath()
+	if err != nil {
+		return err
+	}
+	vacations, err := config.Vacations(path)
+	if err != nil {
+		return err
+	}
+	if len(vacations) == 0 {
+		fmt.Println("No vacations.")
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tFROM\tTO\tDAYS\tNOTE")
+	for _, v := range vacations {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\n", v.ID, v.From.Format("2006-01-02"), v.To.Format("2006-01-02"), v.Days(), v.Note)
+	}
+	return w.Flush()
+}
+
+func runVacationRm(cmd *cobra.Command, args []string) error {
+	path, err := vacationConfigPath()
+	if err != nil {
+		return err
+	}
+	var v config.Vacation
+	ref := args[0]
+	// Signed numbers are day offsets, not IDs
+	if id, err := strconv.Atoi(ref); err == nil && ref[0] != '+' && ref[0] != '-' {
+		v, err = config.RemoveVacation(path, id)
+		if err != nil {
+			return err
+		}
+	} else {
+		now := time.Now()
+		date, err := dateparse.Parse(ref, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			return fmt.Errorf("invalid vacation '%s' (expected an ID or %s)", ref, dateparse.Forms)
+		}
+		if v, err = config.RemoveVacationOn(path, date); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Removed vacation %d: %s\n", v.ID, describeVacation(v))
+	return nil
+}
+
+func describeVacation(v config.Vacation) string {
+	s := v.From.Format("2006-01-02")
+	if !v.To.Equal(v.From) {
+		s += ".." + v.To.Format("2006-01-02")
+	}
+	if n := v.Days(); n == 1 {
+		s += " (1 day)"
+	} else {
+		s += fmt.Sprintf(" (%d days)", n)
+	}
+	if v.Note != "" {
+		s += " " + v.Note
+	}
+	return s
+}
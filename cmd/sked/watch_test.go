@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatchStopTime(t *testing.T) {
+	now := time.Date(2025, 3, 3, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		forDur  time.Duration
+		until   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "unbounded"},
+		{name: "for", forDur: 2 * time.Hour, want: now.Add(2 * time.Hour)},
+		{name: "until", until: "17:00", want: time.Date(2025, 3, 3, 17, 0, 0, 0, time.UTC)},
+		{name: "earlier_wins", forDur: time.Hour, until: "17:00", want: now.Add(time.Hour)},
+		{name: "until_passed", until: "15:30", wantErr: true},
+		{name: "until_invalid", until: "5pm", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watchFor, watchUntil = tt.forDur, tt.until
+			defer func() { watchFor, watchUntil = 0, "" }()
+			got, err := watchStopTime(now)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
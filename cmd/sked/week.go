@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/dateparse"
+	"github.com/Daniel-42-z/sked/internal/grid"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	weekStart    string
+	weekCycle    bool
+	weekMarkdown bool
+	weekJSON     bool
+)
+
+var weekCmd = &cobra.Command{
+	Use:   "week [date]",
+	Short: "Print the week as a grid of days and time slots",
+	Long: `Print the 7 days of the week containing a date (default today) as a grid
+with a column per day and a row per time slot, resolved through overrides:
+off days are marked OFF and left blank. Slots that exist on some days only
+get their own rows. With --cycle, the grid covers the full cycle instead,
+from its day 0. The date is YYYY-MM-DD, today, tomorrow, yesterday, a
+weekday for its next occurrence, next/last <weekday>, next/last week or
++N/-N days.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWeek,
+}
+
+func init() {
+	weekCmd.Flags().StringVar(&weekStart, "start", "monday", "weekday the week starts on")
+	weekCmd.Flags().BoolVar(&weekCycle, "cycle", false, "show the full cycle containing the date instead of its week")
+	weekCmd.Flags().BoolVar(&weekMarkdown, "markdown", false, "output a GitHub-flavored Markdown table")
+	weekCmd.Flags().BoolVarP(&weekJSON, "json", "j", false, "output in JSON format, as an array of days")
+	addTagFlag(weekCmd)
+	rootCmd.AddCommand(weekCmd)
+}
+
+func runWeek(cmd *cobra.Command, args []string) error {
+	if weekMarkdown && weekJSON {
+		return fmt.Errorf("--markdown and --json are mutually exclusive")
+	}
+	now := time.Now()
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if len(args) == 1 {
+		var err error
+		if date, err = dateparse.Parse(args[0], date); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	sched := newScheduler(cfg)
+
+	var start time.Time
+	count := 7
+	if weekCycle {
+		if start, err = sched.CycleStart(date); err != nil {
+			return err
+		}
+		count = cfg.CycleDays
+	} else {
+		first, err := parseWeekday(weekStart)
+		if err != nil {
+			return err
+		}
+		start = date.AddDate(0, 0, -((int(date.Weekday()) - int(first) + 7) % 7))
+	}
+
+	g, err := grid.Build(sched, start, count)
+	if err != nil {
+		return err
+	}
+	switch {
+	case weekJSON:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(grid.JSON(g))
+	case weekMarkdown:
+		return grid.WriteMarkdown(os.Stdout, g, displayClock(cfg))
+	default:
+		return grid.WriteText(os.Stdout, g, displayClock(cfg))
+	}
+}
+
+// parseWeekday parses a weekday name, full or abbreviated to at least three
+// letters.
+func parseWeekday(s string) (time.Weekday, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if len(name) >= 3 {
+		for wd := time.Sunday; wd <= time.Saturday; wd++ {
+			if strings.HasPrefix(strings.ToLower(wd.String()), name) {
+				return wd, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday '%s' (expected a name like monday or mon)", s)
+}
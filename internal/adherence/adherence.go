@@ -0,0 +1,234 @@
+// Package adherence compares the completion journal against the schedule.
+package adherence
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/journal"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// Counts tallies scheduled task instances by outcome. Scheduled includes
+// pending instances, which have not ended yet and have no record.
+type Counts struct {
+	Scheduled int `json:"scheduled"`
+	Done      int `json:"done"`
+	Skipped   int `json:"skipped"`
+	Missed    int `json:"missed"`
+	Pending   int `json:"pending"`
+}
+
+// Percent returns the share of elapsed or recorded instances that were done,
+// or -1 if there are none.
+func (c Counts) Percent() float64 {
+	n := c.Scheduled - c.Pending
+	if n == 0 {
+		return -1
+	}
+	return float64(c.Done) * 100 / float64(n)
+}
+
+func (c *Counts) add(o Counts) {
+	c.Scheduled += o.Scheduled
+	c.Done += o.Done
+	c.Skipped += o.Skipped
+	c.Missed += o.Missed
+	c.Pending += o.Pending
+}
+
+// Task holds the counts for one task name, or for one tag.
+type Task struct {
+	Name string `json:"name"`
+	Counts
+}
+
+// Day holds the counts for one date. Off days have no scheduled tasks.
+type Day struct {
+	Date string `json:"date"`
+	Off  bool   `json:"off"`
+	Counts
+}
+
+// Report is the adherence over a date range.
+type Report struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Total Counts `json:"total"`
+	Tasks []Task `json:"tasks"`
+	// Tags counts each instance once per tag; untagged instances are listed
+	// under scheduler.Untagged.
+	Tags []Task `json:"tags"`
+	Days []Day  `json:"days"`
+}
+
+// Compute joins the events sched produces for days consecutive dates starting
+// at from with records. Instances without a record count as missed once they
+// have ended at now, and as pending before that.
+//
+// Records match an event by date, name and start time. Records left over are
+// then matched by date and name, so marks survive a task being moved.
+func Compute(sched *scheduler.Scheduler, records []journal.Record, from time.Time, days int, now time.Time) (*Report, error) {
+	byDate := make(map[string][]journal.Record)
+	for _, r := range records {
+		byDate[r.Date] = append(byDate[r.Date], r)
+	}
+
+	report := &Report{
+		From:  from.Format("2006-01-02"),
+		To:    from.AddDate(0, 0, days-1).Format("2006-01-02"),
+		Tasks: []Task{},
+		Tags:  []Task{},
+		Days:  []Day{},
+	}
+	tasks := make(map[string]*Counts)
+	var names []string
+	tags := make(map[string]*Counts)
+	var tagNames []string
+
+	for i := 0; i < days; i++ {
+		date := from.AddDate(0, 0, i)
+		info, err := sched.GetDayInfo(date)
+		if err != nil {
+			return nil, err
+		}
+		day := Day{Date: date.Format("2006-01-02"), Off: info.IsOff}
+		if !info.IsOff {
+			events, err := sched.GetTasksForDate(date)
+			if err != nil {
+				return nil, err
+			}
+			statuses := match(events, byDate[day.Date])
+			for j, e := range events {
+				if e.RawName == "/" {
+					continue
+				}
+				var c Counts
+				c.Scheduled = 1
+				switch {
+				case statuses[j] == journal.Done:
+					c.Done = 1
+				case statuses[j] == journal.Skipped:
+					c.Skipped = 1
+				case e.EndTime.After(now):
+					c.Pending = 1
+				default:
+					c.Missed = 1
+				}
+				day.add(c)
+				if tasks[e.Name] == nil {
+					tasks[e.Name] = &Counts{}
+					names = append(names, e.Name)
+				}
+				tasks[e.Name].add(c)
+
+				eventTags := e.Tags
+				if len(eventTags) == 0 {
+					eventTags = []string{scheduler.Untagged}
+				}
+				for _, tag := range eventTags {
+					if tags[tag] == nil {
+						tags[tag] = &Counts{}
+						tagNames = append(tagNames, tag)
+					}
+					tags[tag].add(c)
+				}
+			}
+		}
+		report.Total.add(day.Counts)
+		report.Days = append(report.Days, day)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		report.Tasks = append(report.Tasks, Task{Name: name, Counts: *tasks[name]})
+	}
+	sort.Strings(tagNames)
+	for _, tag := range tagNames {
+		report.Tags = append(report.Tags, Task{Name: tag, Counts: *tags[tag]})
+	}
+	return report, nil
+}
+
+// match returns the recorded status of each event, given the records of that
+// date. The latest record of an instance wins.
+func match(events []scheduler.TaskEvent, records []journal.Record) []journal.Status {
+	type instance struct {
+		name, start string
+		status      journal.Status
+		used        bool
+	}
+	var instances []*instance
+	seen := make(map[[2]string]*instance)
+	for _, r := range records {
+		k := [2]string{r.Task, r.Start}
+		if in, ok := seen[k]; ok {
+			in.status = r.Status
+			continue
+		}
+		in := &instance{name: r.Task, start: r.Start, status: r.Status}
+		seen[k] = in
+		instances = append(instances, in)
+	}
+
+	statuses := make([]journal.Status, len(events))
+	for i, e := range events {
+		if in, ok := seen[[2]string{e.RawName, e.StartTime.Format("15:04")}]; ok && !in.used {
+			statuses[i] = in.status
+			in.used = true
+		}
+	}
+	for i, e := range events {
+		if statuses[i] != "" {
+			continue
+		}
+		for _, in := range instances {
+			if !in.used && in.name == e.RawName {
+				statuses[i] = in.status
+				in.used = true
+				break
+			}
+		}
+	}
+	return statuses
+}
+
+// Write prints the per-task table, or the per-tag table if byTag is set,
+// followed by the daily breakdown.
+func Write(w io.Writer, r *Report, byTag bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Adherence %s to %s\n\n", r.From, r.To)
+	rows, heading := r.Tasks, "TASK"
+	if byTag {
+		rows, heading = r.Tags, "TAG"
+	}
+	fmt.Fprintf(tw, "%s\tSCHEDULED\tDONE\tSKIPPED\tMISSED\tPENDING\tADHERENCE\n", heading)
+	for _, t := range rows {
+		writeRow(tw, t.Name, t.Counts)
+	}
+	writeRow(tw, "Total", r.Total)
+	fmt.Fprintln(tw)
+
+	fmt.Fprintln(tw, "DATE\tSCHEDULED\tDONE\tSKIPPED\tMISSED\tPENDING\tADHERENCE")
+	for _, d := range r.Days {
+		date, _ := time.Parse("2006-01-02", d.Date)
+		label := fmt.Sprintf("%s %s", d.Date, date.Weekday().String()[:3])
+		if d.Off {
+			fmt.Fprintf(tw, "%s\toff\t\t\t\t\t\n", label)
+			continue
+		}
+		writeRow(tw, label, d.Counts)
+	}
+	return tw.Flush()
+}
+
+func writeRow(w io.Writer, label string, c Counts) {
+	pct := "-"
+	if p := c.Percent(); p >= 0 {
+		pct = fmt.Sprintf("%.0f%%", p)
+	}
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%s\n", label, c.Scheduled, c.Done, c.Skipped, c.Missed, c.Pending, pct)
+}
@@ -0,0 +1,160 @@
+package adherence
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/journal"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func TestCompute(t *testing.T) {
+	tasks := []config.Task{
+		{Name: "Math", Start: "09:00", End: "10:00"},
+		{Name: "/", Start: "10:00", End: "11:00"},
+		{Name: "Art", Start: "11:00", End: "12:00"},
+	}
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days:      []config.Day{{ID: 1, Tasks: tasks}, {ID: 2, Tasks: tasks}, {ID: 3, Tasks: tasks}},
+		Overrides: []config.Override{{
+			// Tuesday Jan 2, 2024 is off
+			DateStr: "2024-01-02",
+			IsOff:   true,
+			Date:    time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			EndDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+	records := []journal.Record{
+		{Date: "2024-01-01", Task: "Math", Start: "09:00", Status: journal.Skipped},
+		{Date: "2024-01-01", Task: "Math", Start: "09:00", Status: journal.Done},
+		// Art was marked at its old time before the schedule moved it
+		{Date: "2024-01-01", Task: "Art", Start: "10:30", Status: journal.Skipped},
+		// Off days don't count, even with a stray record
+		{Date: "2024-01-02", Task: "Math", Start: "09:00", Status: journal.Done},
+		{Date: "2024-01-03", Task: "Math", Start: "09:00", Status: journal.Done},
+	}
+	// Wednesday 11:30: Art is still in progress
+	now := time.Date(2024, 1, 3, 11, 30, 0, 0, time.UTC)
+
+	// Monday Jan 1 to Thursday Jan 4, 2024
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	report, err := Compute(scheduler.New(cfg), records, from, 4, now)
+	if err != nil {
+		t.Fatalf("Compute() returned error: %v", err)
+	}
+
+	wantTotal := Counts{Scheduled: 4, Done: 2, Skipped: 1, Pending: 1}
+	if report.Total != wantTotal {
+		t.Errorf("Expected total %+v, got %+v", wantTotal, report.Total)
+	}
+	wantTasks := []Task{
+		{Name: "Art", Counts: Counts{Scheduled: 2, Skipped: 1, Pending: 1}},
+		{Name: "Math", Counts: Counts{Scheduled: 2, Done: 2}},
+	}
+	if len(report.Tasks) != len(wantTasks) {
+		t.Fatalf("Expected %d tasks, got %+v", len(wantTasks), report.Tasks)
+	}
+	for i, want := range wantTasks {
+		if report.Tasks[i] != want {
+			t.Errorf("Task %d: expected %+v, got %+v", i, want, report.Tasks[i])
+		}
+	}
+
+	if len(report.Days) != 4 {
+		t.Fatalf("Expected 4 days, got %d", len(report.Days))
+	}
+	if !report.Days[1].Off || report.Days[1].Scheduled != 0 {
+		t.Errorf("Expected Jan 2 to be an uncounted off day, got %+v", report.Days[1])
+	}
+	if report.Days[3].Scheduled != 0 || report.Days[3].Off {
+		t.Errorf("Expected Jan 4 to have nothing scheduled, got %+v", report.Days[3])
+	}
+}
+
+func TestCompute_Missed(t *testing.T) {
+	cfg := &config.Config{CycleDays: 7, Days: []config.Day{
+		{ID: 1, Tasks: []config.Task{{Name: "Math", Start: "09:00", End: "10:00"}, {Name: "Math", Start: "14:00", End: "15:00"}}},
+	}}
+	// One record for a Math instance that no longer exists: it covers one of
+	// the two Math instances, the other is missed.
+	records := []journal.Record{{Date: "2024-01-01", Task: "Math", Start: "08:00", Status: journal.Done}}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	report, err := Compute(scheduler.New(cfg), records, from, 1, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Compute() returned error: %v", err)
+	}
+	want := Counts{Scheduled: 2, Done: 1, Missed: 1}
+	if report.Total != want {
+		t.Errorf("Expected %+v, got %+v", want, report.Total)
+	}
+	if p := report.Total.Percent(); p != 50 {
+		t.Errorf("Expected 50%%, got %v", p)
+	}
+}
+
+func TestCompute_ByTag(t *testing.T) {
+	cfg := &config.Config{CycleDays: 7, Days: []config.Day{
+		{ID: 1, Tasks: []config.Task{
+			{Name: "Report", Start: "09:00", End: "10:00", Tags: []string{"work"}},
+			{Name: "Gym", Start: "12:00", End: "13:00", Tags: []string{"health", "personal"}},
+			{Name: "Lunch", Start: "13:00", End: "14:00"},
+		}},
+	}}
+	records := []journal.Record{{Date: "2024-01-01", Task: "Gym", Start: "12:00", Status: journal.Done}}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	report, err := Compute(scheduler.New(cfg), records, from, 1, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Compute() returned error: %v", err)
+	}
+	want := []Task{
+		{Name: "health", Counts: Counts{Scheduled: 1, Done: 1}},
+		{Name: "personal", Counts: Counts{Scheduled: 1, Done: 1}},
+		{Name: "untagged", Counts: Counts{Scheduled: 1, Missed: 1}},
+		{Name: "work", Counts: Counts{Scheduled: 1, Missed: 1}},
+	}
+	if len(report.Tags) != len(want) {
+		t.Fatalf("Expected %d tags, got %+v", len(want), report.Tags)
+	}
+	for i := range want {
+		if report.Tags[i] != want[i] {
+			t.Errorf("Tag %d: expected %+v, got %+v", i, want[i], report.Tags[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, report, true); err != nil {
+		t.Fatalf("Write() returned error: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "TAG ") || strings.Contains(out, "Report") {
+		t.Errorf("Expected a per-tag table, got:\n%s", out)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	report := &Report{
+		From:  "2024-01-01",
+		To:    "2024-01-02",
+		Total: Counts{Scheduled: 2, Done: 1, Missed: 1},
+		Tasks: []Task{{Name: "Math", Counts: Counts{Scheduled: 2, Done: 1, Missed: 1}}},
+		Days: []Day{
+			{Date: "2024-01-01", Counts: Counts{Scheduled: 2, Done: 1, Missed: 1}},
+			{Date: "2024-01-02", Off: true},
+		},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, report, false); err != nil {
+		t.Fatalf("Write() returned error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Math", "50%", "2024-01-01 Mon", "2024-01-02 Tue  off"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package calendar
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Instance is one occurrence of an event.
+type Instance struct {
+	UID        string
+	Summary    string
+	URL        string
+	Start, End time.Time
+}
+
+// maxSteps bounds the expansion of a single recurring event, e.g. to about
+// 270 years of a daily event.
+const maxSteps = 100000
+
+// Instances returns the occurrences of events overlapping [from, to),
+// sorted by start. Events are deduplicated by UID (and RECURRENCE-ID),
+// keeping the highest SEQUENCE or else the last one in the feed. Cancelled
+// events and occurrences, EXDATEs and all-day events are left out.
+// Recurrence rules are expanded for FREQ=DAILY, WEEKLY (with BYDAY),
+// MONTHLY and YEARLY with INTERVAL, COUNT and UNTIL; an event with any
+// other rule only occurs at its DTSTART.
+func Instances(events []Event, from, to time.Time) []Instance {
+	masters := make(map[string]Event)
+	exceptions := make(map[string]map[int64]Event)
+	for _, e := range events {
+		if e.RecurrenceID.IsZero() {
+			if old, ok := masters[e.UID]; !ok || e.Sequence >= old.Sequence {
+				masters[e.UID] = e
+			}
+			continue
+		}
+		if exceptions[e.UID] == nil {
+			exceptions[e.UID] = make(map[int64]Event)
+		}
+		key := e.RecurrenceID.Unix()
+		if old, ok := exceptions[e.UID][key]; !ok || e.Sequence >= old.Sequence {
+			exceptions[e.UID][key] = e
+		}
+	}
+
+	var out []Instance
+	add := func(e Event, start, end time.Time) {
+		if e.AllDay || !end.After(start) || !end.After(from) || !start.Before(to) {
+			return
+		}
+		out = append(out, Instance{UID: e.UID, Summary: e.Summary, URL: e.URL, Start: start, End: end})
+	}
+	for uid, m := range masters {
+		if m.Cancelled() {
+			delete(exceptions, uid)
+			continue
+		}
+		skip := make(map[int64]bool)
+		for _, x := range m.ExDates {
+			skip[x.Unix()] = true
+		}
+		for key := range exceptions[uid] {
+			skip[key] = true
+		}
+		length := m.End.Sub(m.Start)
+		for _, start := range occurrences(m, from.Add(-length), to) {
+			if !skip[start.Unix()] {
+				add(m, start, start.Add(length))
+			}
+		}
+	}
+	for _, byID := range exceptions {
+		for _, x := range byID {
+			if !x.Cancelled() {
+				add(x, x.Start, x.End)
+			}
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Start.Equal(out[j].Start) {
+			return out[i].Start.Before(out[j].Start)
+		}
+		return out[i].UID < out[j].UID
+	})
+	return out
+}
+
+// rule is a parsed RRULE.
+type rule struct {
+	freq     string
+	interval int
+	count    int
+	until    time.Time
+	byDay    []time.Weekday
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+func parseRule(s string, loc *time.Location) (rule, error) {
+	r := rule{interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		var err error
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			r.freq = strings.ToUpper(v)
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(v)
+		case "COUNT":
+			r.count, err = strconv.Atoi(v)
+		case "UNTIL":
+			r.until, _, err = parseDateTime(v, map[string]string{})
+			if err == nil && len(v) == len("20060102") {
+				// A date UNTIL includes the whole day
+				r.until = time.Date(r.until.Year(), r.until.Month(), r.until.Day(), 23, 59, 59, 0, loc)
+			}
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				wd, ok := weekdays[strings.ToUpper(d)]
+				if !ok {
+					return rule{}, fmt.Errorf("unsupported BYDAY '%s'", d)
+				}
+				r.byDay = append(r.byDay, wd)
+			}
+		case "WKST":
+		default:
+			return rule{}, fmt.Errorf("unsupported rule part '%s'", k)
+		}
+		if err != nil {
+			return rule{}, fmt.Errorf("invalid %s '%s'", k, v)
+		}
+	}
+	switch r.freq {
+	case "DAILY", "WEEKLY":
+	case "MONTHLY", "YEARLY":
+		if len(r.byDay) > 0 {
+			return rule{}, fmt.Errorf("unsupported BYDAY with FREQ=%s", r.freq)
+		}
+	default:
+		return rule{}, fmt.Errorf("unsupported FREQ '%s'", r.freq)
+	}
+	if r.interval < 1 {
+		return rule{}, fmt.Errorf("invalid INTERVAL %d", r.interval)
+	}
+	return r, nil
+}
+
+// occurrences returns the start times of e after from and before to,
+// keeping the wall clock time of DTSTART across DST changes.
+func occurrences(e Event, from, to time.Time) []time.Time {
+	if e.RRule == "" {
+		return []time.Time{e.Start}
+	}
+	loc := e.Start.Location()
+	r, err := parseRule(e.RRule, loc)
+	if err != nil {
+		return []time.Time{e.Start}
+	}
+	y, mo, d := e.Start.Date()
+	h, mi, s := e.Start.Clock()
+	at := func(days, months, years int) time.Time {
+		return time.Date(y+years, mo+time.Month(months), d+days, h, mi, s, 0, loc)
+	}
+
+	var starts []time.Time
+	seen := 0
+	emit := func(t time.Time) bool {
+		if t.Before(e.Start) {
+			return true
+		}
+		if (!r.until.IsZero() && t.After(r.until)) || !t.Before(to) || (r.count > 0 && seen >= r.count) {
+			return false
+		}
+		seen++
+		if t.After(from) {
+			starts = append(starts, t)
+		}
+		return true
+	}
+	for i := 0; i < maxSteps; i++ {
+		n := i * r.interval
+		switch r.freq {
+		case "DAILY":
+			t := at(n, 0, 0)
+			if len(r.byDay) > 0 && !hasWeekday(r.byDay, t.Weekday()) {
+				if !t.Before(to) {
+					return starts
+				}
+				continue
+			}
+			if !emit(t) {
+				return starts
+			}
+		case "WEEKLY":
+			days := r.byDay
+			if len(days) == 0 {
+				days = []time.Weekday{e.Start.Weekday()}
+			}
+			// Weeks start on Monday
+			monday := 7*n - (int(e.Start.Weekday())+6)%7
+			for off := 0; off < 7; off++ {
+				t := at(monday+off, 0, 0)
+				if hasWeekday(days, t.Weekday()) && !emit(t) {
+					return starts
+				}
+			}
+		case "MONTHLY", "YEARLY":
+			t := at(0, n, 0)
+			if r.freq == "YEARLY" {
+				t = at(0, 0, n)
+			}
+			// Months without the day of DTSTART are skipped
+			if !t.Before(to) || (t.Day() == d && !emit(t)) {
+				return starts
+			}
+		}
+	}
+	return starts
+}
+
+func hasWeekday(days []time.Weekday, wd time.Weekday) bool {
+	for _, d := range days {
+		if d == wd {
+			return true
+		}
+	}
+	return false
+}
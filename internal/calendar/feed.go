@@ -0,0 +1,139 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+// maxFeedSize bounds the size of a downloaded feed.
+const maxFeedSize = 32 << 20
+
+// DefaultCacheDir returns the directory holding the cached feeds.
+func DefaultCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("could not find user cache directory: %w", err)
+	}
+	return filepath.Join(cacheDir, "sked", "calendars"), nil
+}
+
+// CachePath returns the file caching the feed at feedURL in dir. The name is a
+// hash, so secret URLs don't end up in file names.
+func CachePath(dir, feedURL string) string {
+	sum := sha256.Sum256([]byte(feedURL))
+	return filepath.Join(dir, hex.EncodeToString(sum[:8])+".ics")
+}
+
+// Load reads the events of the cached feed at path.
+func Load(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Fetch downloads the feed at feedURL and, if it parses, replaces the cached
+// copy at path. It reports whether the content changed. A failed download
+// or an invalid feed leaves the cached copy untouched.
+func Fetch(ctx context.Context, client *http.Client, feedURL, path string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// The error text includes the URL, which may be secret
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return false, fmt.Errorf("request failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize+1))
+	if err != nil {
+		return false, fmt.Errorf("reading the feed: %w", err)
+	}
+	if len(data) > maxFeedSize {
+		return false, fmt.Errorf("feed is larger than %d MiB", maxFeedSize>>20)
+	}
+	if _, err := Parse(bytes.NewReader(data)); err != nil {
+		return false, fmt.Errorf("invalid feed: %w", err)
+	}
+
+	if old, err := os.ReadFile(path); err == nil && bytes.Equal(old, data) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return false, err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Tasks turns the occurrences of events overlapping [from, to) into dated
+// tasks for cal: only summaries matching its filter are kept, each task
+// gets its tag, and occurrences crossing midnight are split per day.
+// Times are converted to loc.
+func Tasks(cal config.Calendar, events []Event, from, to time.Time, loc *time.Location) ([]config.Event, error) {
+	filter, err := regexp.Compile(cal.Filter)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	if cal.Tag != "" {
+		tags = []string{cal.Tag}
+	}
+	source := config.Origin{File: "calendar '" + cal.Label() + "'"}
+	var out []config.Event
+	for _, in := range Instances(events, from, to) {
+		if !filter.MatchString(in.Summary) {
+			continue
+		}
+		link := in.URL
+		if u, err := url.Parse(link); err != nil || u.Scheme == "" {
+			link = ""
+		}
+		start, end := in.Start.In(loc), in.End.In(loc)
+		for start.Before(end) {
+			y, m, d := start.Date()
+			date := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+			dayEnd := time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+			stop, endClock := end, end.Format("15:04")
+			if !end.Before(dayEnd) {
+				stop, endClock = dayEnd, "23:59"
+			}
+			if endClock != start.Format("15:04") {
+				out = append(out, config.Event{
+					DateStr: date.Format("2006-01-02"),
+					Date:    date,
+					Task:    config.Task{Name: in.Summary, Start: start.Format("15:04"), End: endClock, Tags: tags, URL: link, Source: source},
+				})
+			}
+			start = stop
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,90 @@
+package calendar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+func TestFetch(t *testing.T) {
+	body, status := feed, http.StatusOK
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer ts.Close()
+	path := CachePath(t.TempDir(), ts.URL)
+
+	steps := []struct {
+		name        string
+		body        string
+		status      int
+		wantChanged bool
+		wantErr     bool
+	}{
+		{name: "first", body: feed, status: http.StatusOK, wantChanged: true},
+		{name: "unchanged", body: feed, status: http.StatusOK},
+		{name: "server_error", body: "", status: http.StatusInternalServerError, wantErr: true},
+		{name: "invalid_feed", body: "<html>login</html>", status: http.StatusOK, wantErr: true},
+	}
+	for _, s := range steps {
+		body, status = s.body, s.status
+		changed, err := Fetch(context.Background(), ts.Client(), ts.URL, path)
+		if (err != nil) != s.wantErr || changed != s.wantChanged {
+			t.Errorf("%s: Expected changed=%v err=%v, got changed=%v err=%v", s.name, s.wantChanged, s.wantErr, changed, err)
+		}
+		// Failures keep the last good copy
+		if data, err := os.ReadFile(path); err != nil || string(data) != feed {
+			t.Errorf("%s: Expected the cached feed to be kept, got %q (%v)", s.name, data, err)
+		}
+	}
+}
+
+func TestCachePath_HidesURL(t *testing.T) {
+	path := CachePath("/cache", "https://calendar.example.com/private-secret/basic.ics")
+	if strings.Contains(path, "secret") || filepath.Dir(path) != "/cache" {
+		t.Errorf("Expected a hashed file name in /cache, got %s", path)
+	}
+}
+
+func TestTasks(t *testing.T) {
+	at := func(d, h, m int) time.Time { return time.Date(2024, 1, d, h, m, 0, 0, time.UTC) }
+	events := []Event{
+		{UID: "a", Summary: "Standup", Start: at(1, 9, 0), End: at(1, 9, 15), URL: "https://meet.example.com/a"},
+		{UID: "b", Summary: "Lunch", Start: at(1, 12, 0), End: at(1, 13, 0)},
+		{UID: "c", Summary: "Deploy", Start: at(2, 22, 0), End: at(3, 1, 0), URL: "not a url"},
+	}
+	cal := config.Calendar{Filter: "^(Standup|Deploy)$", Tag: "work"}
+	tasks, err := Tasks(cal, events, at(1, 0, 0), at(31, 0, 0), time.UTC)
+	if err != nil {
+		t.Fatalf("Tasks() returned error: %v", err)
+	}
+
+	want := []string{
+		"2024-01-01 09:00-09:15 Standup https://meet.example.com/a",
+		"2024-01-02 22:00-23:59 Deploy",
+		"2024-01-03 00:00-01:00 Deploy",
+	}
+	if len(tasks) != len(want) {
+		t.Fatalf("Expected %d tasks, got %+v", len(want), tasks)
+	}
+	for i, e := range tasks {
+		s := e.DateStr + " " + e.Start + "-" + e.End + " " + e.Name
+		if e.URL != "" {
+			s += " " + e.URL
+		}
+		if s != want[i] {
+			t.Errorf("Task %d: Expected %q, got %q", i, want[i], s)
+		}
+		if len(e.Tags) != 1 || e.Tags[0] != "work" || !e.Date.Equal(time.Date(2024, 1, 1+i, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("Task %d: Expected the work tag and a parsed date, got %+v", i, e)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Package calendar reads iCalendar (ICS) feeds, such as a Google Calendar
+// secret address, and turns their events into dated sked tasks. Feeds are
+// cached on disk so schedule queries never wait on the network.
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a VEVENT of a feed. An event with a RecurrenceID replaces one
+// occurrence of the recurring event with the same UID.
+type Event struct {
+	UID          string
+	Summary      string
+	URL          string
+	Status       string // e.g. "CANCELLED"
+	Sequence     int
+	Start, End   time.Time
+	AllDay       bool
+	RecurrenceID time.Time
+	RRule        string
+	ExDates      []time.Time
+
+	duration time.Duration // DURATION, applied when there is no DTEND
+}
+
+// Cancelled reports whether the event was cancelled.
+func (e Event) Cancelled() bool {
+	return strings.EqualFold(e.Status, "CANCELLED")
+}
+
+// property is a content line: NAME;PARAM=VALUE:value.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Parse reads the events of an iCalendar stream. Events without a UID or
+// start are skipped; floating times are read in time.Local.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	var events []Event
+	var ev *Event
+	calendar := false
+	for n, line := range lines {
+		if line == "" {
+			continue
+		}
+		p, err := parseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+		switch {
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VCALENDAR"):
+			calendar = true
+		case p.name == "BEGIN" && strings.EqualFold(p.value, "VEVENT"):
+			ev = &Event{}
+		case p.name == "END" && strings.EqualFold(p.value, "VEVENT"):
+			if ev != nil && ev.UID != "" && !ev.Start.IsZero() {
+				if ev.End.IsZero() {
+					ev.End = ev.Start.Add(ev.duration)
+				}
+				events = append(events, *ev)
+			}
+			ev = nil
+		case ev != nil:
+			if err := ev.set(p); err != nil {
+				return nil, fmt.Errorf("line %d: %s: %w", n+1, p.name, err)
+			}
+		}
+	}
+	if !calendar {
+		return nil, fmt.Errorf("not an iCalendar file (no BEGIN:VCALENDAR)")
+	}
+	return events, nil
+}
+
+// unfold joins continuation lines, which start with a space or tab.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+func parseProperty(line string) (property, error) {
+	// The value starts at the first colon outside a quoted parameter
+	quoted := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			quoted = !quoted
+		} else if c == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return property{}, fmt.Errorf("malformed content line %q", line)
+	}
+	parts := strings.Split(line[:colon], ";")
+	p := property{name: strings.ToUpper(parts[0]), params: make(map[string]string), value: line[colon+1:]}
+	for _, param := range parts[1:] {
+		k, v, _ := strings.Cut(param, "=")
+		p.params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return p, nil
+}
+
+func (e *Event) set(p property) error {
+	var err error
+	switch p.name {
+	case "UID":
+		e.UID = p.value
+	case "SUMMARY":
+		e.Summary = unescapeText(p.value)
+	case "URL":
+		e.URL = p.value
+	case "STATUS":
+		e.Status = strings.ToUpper(p.value)
+	case "SEQUENCE":
+		e.Sequence, err = strconv.Atoi(p.value)
+	case "DTSTART":
+		e.Start, e.AllDay, err = parseDateTime(p.value, p.params)
+	case "DTEND":
+		e.End, _, err = parseDateTime(p.value, p.params)
+	case "DURATION":
+		e.duration, err = parseDuration(p.value)
+	case "RECURRENCE-ID":
+		e.RecurrenceID, _, err = parseDateTime(p.value, p.params)
+	case "RRULE":
+		e.RRule = p.value
+	case "EXDATE":
+		for _, v := range strings.Split(p.value, ",") {
+			t, _, perr := parseDateTime(v, p.params)
+			if perr != nil {
+				return perr
+			}
+			e.ExDates = append(e.ExDates, t)
+		}
+	}
+	return err
+}
+
+var textUnescaper = strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+func unescapeText(s string) string {
+	return textUnescaper.Replace(s)
+}
+
+// parseDateTime parses a DATE or DATE-TIME value: UTC with a Z suffix, in
+// the TZID parameter's zone, or floating in time.Local. Unknown zones, such
+// as Windows zone names, fall back to time.Local.
+func parseDateTime(v string, params map[string]string) (time.Time, bool, error) {
+	loc := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	if params["VALUE"] == "DATE" || len(v) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", v, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(v, "Z") {
+		t, err := time.Parse("20060102T150405Z", v)
+		return t, false, err
+	}
+	t, err := time.ParseInLocation("20060102T150405", v, loc)
+	return t, false, err
+}
+
+// parseDuration parses a DURATION value such as "PT1H30M" or "P1D".
+func parseDuration(v string) (time.Duration, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(v, "+"), "P")
+	if s == v || s == "" {
+		return 0, fmt.Errorf("invalid duration '%s'", v)
+	}
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+		case c == 'T':
+			inTime = true
+		default:
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration '%s'", v)
+			}
+			unit := map[rune]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour}
+			if inTime {
+				unit = map[rune]time.Duration{'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+			}
+			u, ok := unit[c]
+			if !ok {
+				return 0, fmt.Errorf("invalid duration '%s'", v)
+			}
+			d += time.Duration(n) * u
+			num = ""
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("invalid duration '%s'", v)
+	}
+	return d, nil
+}
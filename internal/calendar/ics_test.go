@@ -0,0 +1,131 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const feed = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	// A weekly meeting with one moved and one cancelled occurrence
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup@example.com\r\n" +
+	"SUMMARY:Standup\\, team\r\n" +
+	"DTSTART;TZID=UTC:20240101T090000\r\n" +
+	"DTEND;TZID=UTC:20240101T091500\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO,WE;COUNT=6\r\n" +
+	"EXDATE;TZID=UTC:20240103T090000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup@example.com\r\n" +
+	"RECURRENCE-ID;TZID=UTC:20240108T090000\r\n" +
+	"SUMMARY:Standup (moved)\r\n" +
+	"DTSTART:20240108T100000Z\r\n" +
+	"DURATION:PT30M\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup@example.com\r\n" +
+	"RECURRENCE-ID;TZID=UTC:20240110T090000\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"DTSTART:20240110T090000Z\r\n" +
+	"END:VEVENT\r\n" +
+	// An updated event appears twice; the higher SEQUENCE wins
+	"BEGIN:VEVENT\r\n" +
+	"UID:review@example.com\r\n" +
+	"SEQUENCE:2\r\n" +
+	"SUMMARY:Design review\r\n" +
+	"DTSTART:20240102T140000Z\r\n" +
+	"DTEND:20240102T150000Z\r\n" +
+	"URL:https://meet.example.com/\r\n" +
+	" review\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:review@example.com\r\n" +
+	"SEQUENCE:1\r\n" +
+	"SUMMARY:Old review\r\n" +
+	"DTSTART:20240102T130000Z\r\n" +
+	"DTEND:20240102T140000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:party@example.com\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"SUMMARY:Party\r\n" +
+	"DTSTART:20240104T180000Z\r\n" +
+	"DTEND:20240104T200000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:holiday@example.com\r\n" +
+	"SUMMARY:Holiday\r\n" +
+	"DTSTART;VALUE=DATE:20240105\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestInstances(t *testing.T) {
+	events, err := Parse(strings.NewReader(feed))
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	got := Instances(events, from, from.AddDate(0, 1, 0))
+
+	want := []string{
+		"2024-01-01 09:00-09:15 Standup, team",
+		"2024-01-02 14:00-15:00 Design review https://meet.example.com/review",
+		"2024-01-08 10:00-10:30 Standup (moved)",
+		"2024-01-15 09:00-09:15 Standup, team",
+		"2024-01-17 09:00-09:15 Standup, team",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d instances, got %+v", len(want), got)
+	}
+	for i, in := range got {
+		s := in.Start.UTC().Format("2006-01-02 15:04") + "-" + in.End.UTC().Format("15:04") + " " + in.Summary
+		if in.URL != "" {
+			s += " " + in.URL
+		}
+		if s != want[i] {
+			t.Errorf("Instance %d: Expected %q, got %q", i, want[i], s)
+		}
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	start := time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		rule string
+		want []string
+	}{
+		{"FREQ=DAILY;INTERVAL=2;UNTIL=20240206", []string{"01-31", "02-02", "02-04", "02-06"}},
+		{"FREQ=DAILY;BYDAY=SA,SU;COUNT=3", []string{"02-03", "02-04", "02-10"}},
+		// Months without a 31st are skipped
+		{"FREQ=MONTHLY;COUNT=3", []string{"01-31", "03-31", "05-31"}},
+		{"FREQ=WEEKLY;INTERVAL=2;COUNT=3", []string{"01-31", "02-14", "02-28"}},
+		// Unsupported rules leave the first occurrence
+		{"FREQ=MONTHLY;BYSETPOS=-1", []string{"01-31"}},
+	}
+	for _, tt := range tests {
+		e := Event{Start: start, End: start.Add(time.Hour), RRule: tt.rule}
+		var got []string
+		for _, o := range occurrences(e, time.Time{}, start.AddDate(1, 0, 0)) {
+			got = append(got, o.Format("01-02"))
+		}
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("%s: Expected %v, got %v", tt.rule, tt.want, got)
+		}
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name, input string
+	}{
+		{"not_a_calendar", "<html></html>\n"},
+		{"bad_date", "BEGIN:VCALENDAR\nBEGIN:VEVENT\nUID:x\nDTSTART:2024-01-01\nEND:VEVENT\nEND:VCALENDAR\n"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(strings.NewReader(tt.input)); err == nil {
+			t.Errorf("%s: Expected an error, got nil", tt.name)
+		}
+	}
+}
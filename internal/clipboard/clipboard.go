@@ -0,0 +1,67 @@
+// Package clipboard copies text to the system clipboard, through the
+// terminal's OSC 52 escape sequence and a local clipboard tool if there is
+// one.
+package clipboard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Sequence returns the OSC 52 escape sequence that sets the clipboard to
+// text. Inside tmux, it is wrapped in a passthrough sequence so it reaches
+// the outer terminal.
+func Sequence(text string, tmux bool) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07"
+	if tmux {
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	return seq
+}
+
+// Command returns the argv of the local clipboard tool for goos and the
+// environment read through getenv: wl-copy on Wayland, xclip on X11 and
+// pbcopy on macOS. It returns nil if there is none.
+func Command(goos string, getenv func(string) string) []string {
+	switch {
+	case goos == "darwin":
+		return []string{"pbcopy"}
+	case getenv("WAYLAND_DISPLAY") != "":
+		return []string{"wl-copy"}
+	case getenv("DISPLAY") != "":
+		return []string{"xclip", "-selection", "clipboard"}
+	default:
+		return nil
+	}
+}
+
+// Copy writes the OSC 52 sequence for text to the terminal w. Outside SSH
+// sessions, where the clipboard is the local one, text is also handed to
+// the local clipboard tool, for terminals that ignore OSC 52.
+func Copy(w io.Writer, text string) error {
+	if _, err := io.WriteString(w, Sequence(text, os.Getenv("TMUX") != "")); err != nil {
+		return err
+	}
+	if os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != "" {
+		return nil
+	}
+	argv := Command(runtime.GOOS, os.Getenv)
+	if argv == nil {
+		return nil
+	}
+	if _, err := exec.LookPath(argv[0]); err != nil {
+		// OSC 52 is all there is
+		return nil
+	}
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", argv[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
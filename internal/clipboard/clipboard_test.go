@@ -0,0 +1,46 @@
+package clipboard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSequence(t *testing.T) {
+	tests := []struct {
+		name string
+		tmux bool
+		want string
+	}{
+		{name: "plain", want: "\x1b]52;c;aGk=\x07"},
+		{name: "tmux", tmux: true, want: "\x1bPtmux;\x1b\x1b]52;c;aGk=\x07\x1b\\"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sequence("hi", tt.tmux); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		env  map[string]string
+		want []string
+	}{
+		{name: "darwin", goos: "darwin", want: []string{"pbcopy"}},
+		{name: "wayland", goos: "linux", env: map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, want: []string{"wl-copy"}},
+		{name: "x11", goos: "linux", env: map[string]string{"DISPLAY": ":0"}, want: []string{"xclip", "-selection", "clipboard"}},
+		{name: "console", goos: "linux"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Command(tt.goos, func(k string) string { return tt.env[k] })
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+// DisplayName returns the name to show for the raw task name: its exact
+// alias, else the alias of the most specific matching glob pattern (the
+// longest, ties broken alphabetically), else the raw name itself.
+func (c *Config) DisplayName(raw string) string {
+	if alias, ok := lookupName(c.Aliases, raw); ok {
+		return alias
+	}
+	return raw
+}
+
+// Icon returns the icon of t: its own icon field, else the [icons] entry
+// matching its raw name, else the one matching its display name. Entries are
+// matched like aliases.
+func (c *Config) Icon(t Task) string {
+	if t.Icon != "" {
+		return t.Icon
+	}
+	if icon, ok := lookupName(c.Icons, t.Name); ok {
+		return icon
+	}
+	icon, _ := lookupName(c.Icons, c.DisplayName(t.Name))
+	return icon
+}
+
+// lookupName finds name in table by exact key, then by the most specific
+// glob pattern.
+func lookupName(table map[string]string, name string) (string, bool) {
+	if len(table) == 0 {
+		return "", false
+	}
+	if v, ok := table[name]; ok {
+		return v, true
+	}
+	for _, pattern := range namePatterns(table) {
+		if ok, _ := path.Match(pattern, name); ok {
+			return table[pattern], true
+		}
+	}
+	return "", false
+}
+
+// namePatterns returns the keys of table in the order lookupName tries them.
+func namePatterns(table map[string]string) []string {
+	patterns := make([]string, 0, len(table))
+	for p := range table {
+		patterns = append(patterns, p)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	return patterns
+}
+
+// validateNames reports malformed glob patterns and empty values in the
+// [aliases] or [icons] table named section.
+func validateNames(section string, table map[string]string) error {
+	for _, p := range namePatterns(table) {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid %s pattern '%s': %w", section, p, err)
+		}
+		if table[p] == "" {
+			return fmt.Errorf("%s entry '%s' is empty", section, p)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import "testing"
+
+func TestDisplayName(t *testing.T) {
+	cfg := &Config{Aliases: map[string]string{
+		"MATH101": "Calculus",
+		"MATH*":   "Maths",
+		"M*":      "Misc",
+		"HIST?B":  "History",
+	}}
+
+	tests := []struct {
+		raw, want string
+	}{
+		{"MATH101", "Calculus"}, // exact match beats the patterns
+		{"MATH202", "Maths"},    // longer pattern beats "M*"
+		{"MUSIC", "Misc"},
+		{"HIST2B", "History"},
+		{"HIST22B", "HIST22B"},
+		{"Art", "Art"},
+	}
+	for _, tt := range tests {
+		if got := cfg.DisplayName(tt.raw); got != tt.want {
+			t.Errorf("DisplayName(%q): Expected %q, got %q", tt.raw, tt.want, got)
+		}
+	}
+}
+
+func TestValidate_Aliases(t *testing.T) {
+	tests := []struct {
+		aliases map[string]string
+		wantErr bool
+	}{
+		{map[string]string{"MATH*": "Maths"}, false},
+		{map[string]string{"MATH[": "Maths"}, true},
+		{map[string]string{"MATH101": ""}, true},
+	}
+	for _, tt := range tests {
+		cfg := &Config{CycleDays: 7, Aliases: tt.aliases}
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%v: Expected error %v, got %v", tt.aliases, tt.wantErr, err)
+		}
+	}
+}
+
+func TestIcon(t *testing.T) {
+	cfg := &Config{
+		Aliases: map[string]string{"MATH*": "Maths"},
+		Icons:   map[string]string{"MATH101": "📐", "Maths": "📚", "HIST*": "🏛"},
+	}
+
+	tests := []struct {
+		task Task
+		want string
+	}{
+		{Task{Name: "MATH101", Icon: "✏"}, "✏"}, // the task's own icon wins
+		{Task{Name: "MATH101"}, "📐"},            // raw name
+		{Task{Name: "MATH202"}, "📚"},            // display name
+		{Task{Name: "HIST2B"}, "🏛"},             // pattern
+		{Task{Name: "Art"}, ""},
+	}
+	for _, tt := range tests {
+		if got := cfg.Icon(tt.task); got != tt.want {
+			t.Errorf("Icon(%q): Expected %q, got %q", tt.task.Name, tt.want, got)
+		}
+	}
+}
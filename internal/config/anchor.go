@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ResolveAnchor splits the anchor shorthand "2025-01-20 = 3" into
+// anchor_date and anchor_day_id. The day may also be a day name.
+func (c *Config) ResolveAnchor() error {
+	if c.Anchor == "" {
+		return nil
+	}
+	if c.AnchorDate != "" {
+		return fmt.Errorf("anchor and anchor_date are mutually exclusive")
+	}
+	date, day, ok := strings.Cut(c.Anchor, "=")
+	date, day = strings.TrimSpace(date), strings.TrimSpace(day)
+	if !ok || date == "" || day == "" {
+		return fmt.Errorf("invalid anchor '%s' (expected \"YYYY-MM-DD = day\")", c.Anchor)
+	}
+	var id DayID
+	if err := id.UnmarshalText([]byte(day)); err != nil {
+		return fmt.Errorf("invalid anchor '%s': %w", c.Anchor, err)
+	}
+	c.AnchorDate, c.AnchorDayID = date, id
+	return nil
+}
+
+// AnchorWarning describes a 7-day cycle whose anchor puts day IDs out of
+// step with weekdays, so a day block with id 1 ("Monday") would not fall on
+// Mondays. It returns "" if the anchor agrees or there is none.
+func (c *Config) AnchorWarning() string {
+	if c.CycleDays != 7 || c.AnchorDate == "" || len(c.Days) == 0 {
+		return ""
+	}
+	anchor, err := time.Parse("2006-01-02", c.AnchorDate)
+	if err != nil || int(anchor.Weekday()) == int(c.AnchorDayID) {
+		return ""
+	}
+	return fmt.Sprintf("anchor_date %s is a %s but day %d of the cycle, so day IDs don't match weekdays (set anchor_day_id = %d, or remove anchor_date to follow weekdays)",
+		c.AnchorDate, anchor.Weekday(), c.AnchorDayID, anchor.Weekday())
+}
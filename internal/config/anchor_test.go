@@ -0,0 +1,109 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveAnchor(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		date    string
+		id      DayID
+		err     string
+	}{
+		{"shorthand", "cycle_days = 6\nanchor = '2025-01-20 = 3'\n", "2025-01-20", 3, ""},
+		{"no_spaces", "cycle_days = 6\nanchor = '2025-01-20=3'\n", "2025-01-20", 3, ""},
+		{"day_name", "anchor = '2025-01-20 = Mon'\n", "2025-01-20", 1, ""},
+		{"separate_keys", "cycle_days = 6\nanchor_date = '2025-01-20'\nanchor_day_id = 2\n", "2025-01-20", 2, ""},
+		{"both", "cycle_days = 6\nanchor = '2025-01-20 = 3'\nanchor_date = '2025-01-20'\n", "", 0, "mutually exclusive"},
+		{"no_day", "cycle_days = 6\nanchor = '2025-01-20'\n", "", 0, "invalid anchor '2025-01-20'"},
+		{"bad_day", "cycle_days = 6\nanchor = '2025-01-20 = someday'\n", "", 0, "invalid day name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadTOMLString(t, tt.content)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("Expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cfg.AnchorDate != tt.date || cfg.AnchorDayID != tt.id {
+				t.Errorf("Expected %s = %d, got %s = %d", tt.date, tt.id, cfg.AnchorDate, cfg.AnchorDayID)
+			}
+			if err := cfg.Validate(); err != nil {
+				t.Errorf("Unexpected validation error: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateAnchorDayID(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		err  string
+	}{
+		{"in_range", Config{CycleDays: 6, AnchorDate: "2025-01-20", AnchorDayID: 5}, ""},
+		{"too_large", Config{CycleDays: 6, AnchorDate: "2025-01-20", AnchorDayID: 6}, "anchor_day_id 6 is out of range (expected 0 to 5)"},
+		{"negative", Config{CycleDays: 6, AnchorDate: "2025-01-20", AnchorDayID: -1}, "out of range"},
+		{"no_date", Config{CycleDays: 7, AnchorDayID: 2}, "anchor_day_id requires anchor_date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.err == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Expected error containing %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestAnchorWarning(t *testing.T) {
+	days := []Day{{ID: 1}}
+	tests := []struct {
+		name string
+		cfg  Config
+		warn bool
+	}{
+		{"weekdays", Config{CycleDays: 7, Days: days}, false},
+		// 2025-01-19 is a Sunday
+		{"sunday_day_0", Config{CycleDays: 7, AnchorDate: "2025-01-19", Days: days}, false},
+		{"monday_day_0", Config{CycleDays: 7, AnchorDate: "2025-01-20", Days: days}, true},
+		{"monday_day_1", Config{CycleDays: 7, AnchorDate: "2025-01-20", AnchorDayID: 1, Days: days}, false},
+		{"custom_cycle", Config{CycleDays: 6, AnchorDate: "2025-01-20", Days: days}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.AnchorWarning(); (got != "") != tt.warn {
+				t.Errorf("Expected warning %v, got %q", tt.warn, got)
+			}
+		})
+	}
+}
+
+func TestLoadTOML_AnchorWarning(t *testing.T) {
+	// 2025-01-20 is a Monday, but the anchor makes it day 0
+	path := writeTemp(t, "config.toml", "cycle_days = 7\n\nanchor_date = \"2025-01-20\"\n\n[[day]]\nid = 1\ntasks = []\n")
+	cfg, err := LoadTOML(path)
+	if err != nil {
+		t.Fatalf("LoadTOML() returned error: %v", err)
+	}
+	if len(cfg.Warnings) != 1 {
+		t.Fatalf("Expected 1 warning, got %v", cfg.Warnings)
+	}
+	if w := cfg.Warnings[0]; w.File != path || w.Line != 3 || !strings.Contains(w.Message, "anchor_day_id = 1") {
+		t.Errorf("Expected the anchor warning at %s:3, got %+v", path, w)
+	}
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// snapshotVersion is bumped whenever the Config layout changes, invalidating old snapshots.
+const snapshotVersion = 35
+
+// snapshot is a compiled configuration together with the state of its source files.
+type snapshot struct {
+	Version int
+	Sources []sourceStamp
+	Config  *Config
+}
+
+// sourceStamp records a source file's modification time and size.
+// Missing files are recorded with Exists set to false.
+type sourceStamp struct {
+	Path    string
+	Exists  bool
+	ModTime time.Time
+	Size    int64
+}
+
+// DefaultCacheDir returns the directory holding compiled config snapshots.
+func DefaultCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("could not find user cache directory: %w", err)
+	}
+	return filepath.Join(cacheDir, "sked", "snapshots"), nil
+}
+
+// LoadCached behaves like Load but reuses a compiled snapshot stored in cacheDir
+// when none of the source files changed since it was written. Failures to read
+// or write the snapshot fall back to a normal load.
+func LoadCached(path, cacheDir string) (*Config, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	snapPath := filepath.Join(cacheDir, snapshotName(absPath))
+
+	if cfg, ok := readSnapshot(snapPath); ok {
+		slog.Debug("config loaded from cache", "path", absPath, "snapshot", snapPath)
+		return cfg, nil
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeSnapshot(snapPath, cfg); err != nil {
+		slog.Warn("failed to write config cache", "path", snapPath, "err", err)
+	}
+	return cfg, nil
+}
+
+// ClearCache removes all compiled snapshots from cacheDir.
+func ClearCache(cacheDir string) error {
+	if err := os.RemoveAll(cacheDir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+func snapshotName(absPath string) string {
+	sum := sha256.Sum256([]byte(absPath))
+	return hex.EncodeToString(sum[:8]) + ".gob"
+}
+
+// SourceState is the modification time and size of a configuration's source
+// files at one point in time, to notice later edits.
+type SourceState []sourceStamp
+
+// StatSources records the current state of paths, typically Config.Sources.
+func StatSources(paths []string) SourceState {
+	return stampSources(paths)
+}
+
+// Changed reports whether any file was created, removed or modified since s
+// was recorded.
+func (s SourceState) Changed() bool {
+	paths := make([]string, len(s))
+	for i, st := range s {
+		paths[i] = st.Path
+	}
+	current := stampSources(paths)
+	for i, st := range s {
+		c := current[i]
+		if c.Exists != st.Exists || c.Size != st.Size || !c.ModTime.Equal(st.ModTime) {
+			return true
+		}
+	}
+	return false
+}
+
+func stampSources(paths []string) []sourceStamp {
+	stamps := make([]sourceStamp, len(paths))
+	for i, p := range paths {
+		stamps[i] = sourceStamp{Path: p}
+		if info, err := os.Stat(p); err == nil {
+			stamps[i].Exists = true
+			stamps[i].ModTime = info.ModTime()
+			stamps[i].Size = info.Size()
+		}
+	}
+	return stamps
+}
+
+func readSnapshot(snapPath string) (*Config, bool) {
+	f, err := os.Open(snapPath)
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	var snap snapshot
+	if err := gob.NewDecoder(f).Decode(&snap); err != nil {
+		return nil, false
+	}
+	if snap.Version != snapshotVersion || snap.Config == nil {
+		return nil, false
+	}
+
+	if SourceState(snap.Sources).Changed() {
+		return nil, false
+	}
+	// The override index isn't part of the snapshot
+	snap.Config.indexOverrides()
+	return snap.Config, true
+}
+
+func writeSnapshot(snapPath string, cfg *Config) error {
+	dir := filepath.Dir(snapPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	snap := snapshot{
+		Version: snapshotVersion,
+		Sources: stampSources(cfg.Sources),
+		Config:  cfg,
+	}
+	if err := gob.NewEncoder(tmp).Encode(snap); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, snapPath); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t testing.TB, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestLoadCached_Invalidation(t *testing.T) {
+	dir := t.TempDir()
+	cacheDir := filepath.Join(dir, "cache")
+	tomlPath := filepath.Join(dir, "config.toml")
+	csvPath := filepath.Join(dir, "week.csv")
+
+	writeFile(t, tomlPath, `csv_path = "week.csv"`)
+	writeFile(t, csvPath, "Start,End,Mon\n09:00,10:00,Math\n")
+
+	cfg, err := LoadCached(tomlPath, cacheDir)
+	if err != nil {
+		t.Fatalf("LoadCached() returned error: %v", err)
+	}
+	if cfg.Days[0].Tasks[0].Name != "Math" {
+		t.Fatalf("Unexpected task %q", cfg.Days[0].Tasks[0].Name)
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Expected one snapshot in cache dir, got %v (err %v)", entries, err)
+	}
+
+	// Changing the referenced CSV must invalidate the snapshot
+	writeFile(t, csvPath, "Start,End,Mon\n09:00,10:00,Physics\n")
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(csvPath, future, future); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+
+	cfg, err = LoadCached(tomlPath, cacheDir)
+	if err != nil {
+		t.Fatalf("LoadCached() returned error: %v", err)
+	}
+	if cfg.Days[0].Tasks[0].Name != "Physics" {
+		t.Errorf("Expected snapshot to be invalidated, got task %q", cfg.Days[0].Tasks[0].Name)
+	}
+
+	if err := ClearCache(cacheDir); err != nil {
+		t.Fatalf("ClearCache() returned error: %v", err)
+	}
+	if _, err := os.Stat(cacheDir); !os.IsNotExist(err) {
+		t.Errorf("Expected cache dir to be removed")
+	}
+}
+
+// writeLargeConfig writes a TOML config with 500 tasks spread over a week.
+func writeLargeConfig(b testing.TB) string {
+	var sb strings.Builder
+	for day := 0; day < 7; day++ {
+		fmt.Fprintf(&sb, "[[day]]\nid = %d\ntasks = [\n", day)
+		for i := 0; i < 72; i++ {
+			start := time.Date(0, 1, 1, 0, i*20, 0, 0, time.UTC)
+			end := start.Add(20 * time.Minute)
+			fmt.Fprintf(&sb, "  { name = \"Task %d-%d\", start = \"%s\", end = \"%s\" },\n",
+				day, i, start.Format("15:04"), end.Format("15:04"))
+		}
+		sb.WriteString("]\n")
+	}
+	path := filepath.Join(b.TempDir(), "large.toml")
+	writeFile(b, path, sb.String())
+	return path
+}
+
+func BenchmarkLoad(b *testing.B) {
+	path := writeLargeConfig(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Load(path); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoadCached(b *testing.B) {
+	path := writeLargeConfig(b)
+	cacheDir := filepath.Join(b.TempDir(), "cache")
+	if _, err := LoadCached(path, cacheDir); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := LoadCached(path, cacheDir); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultCalendarRefresh is how often a calendar feed is fetched when its
+// refresh is unset.
+const DefaultCalendarRefresh = 30 * time.Minute
+
+// Calendar is a remote ICS feed, e.g. a Google Calendar secret address,
+// whose events are overlaid read-only onto the schedule.
+type Calendar struct {
+	Name    string `toml:"name"`    // label for logs, default the URL's host
+	URL     string `toml:"url"`     // http(s) or webcal address
+	Refresh string `toml:"refresh"` // fetch interval in watch and serve mode, e.g. "15m"
+	Filter  string `toml:"filter"`  // regular expression event summaries must match
+	Tag     string `toml:"tag"`     // tag given to the calendar's tasks
+}
+
+// Label names the calendar without revealing its URL, which often
+// contains a secret token.
+func (c Calendar) Label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	if u, err := url.Parse(c.URL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "calendar"
+}
+
+// FeedURL returns the URL to download, with webcal:// turned into https://.
+func (c Calendar) FeedURL() string {
+	if rest, ok := strings.CutPrefix(c.URL, "webcal://"); ok {
+		return "https://" + rest
+	}
+	return c.URL
+}
+
+// RefreshInterval returns the parsed refresh, or DefaultCalendarRefresh.
+// The value has been checked by Validate.
+func (c Calendar) RefreshInterval() time.Duration {
+	d, err := time.ParseDuration(c.Refresh)
+	if err != nil || d <= 0 {
+		return DefaultCalendarRefresh
+	}
+	return d
+}
+
+// validate checks the calendar's fields.
+func (c Calendar) validate() error {
+	u, err := url.Parse(c.FeedURL())
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("calendar '%s': url must be an http(s) or webcal address", c.Label())
+	}
+	if c.Refresh != "" {
+		d, err := time.ParseDuration(c.Refresh)
+		if err != nil {
+			return fmt.Errorf("calendar '%s': invalid refresh '%s': %w", c.Label(), c.Refresh, err)
+		}
+		if d < time.Minute {
+			return fmt.Errorf("calendar '%s': refresh must be at least 1m", c.Label())
+		}
+	}
+	if _, err := regexp.Compile(c.Filter); err != nil {
+		return fmt.Errorf("calendar '%s': invalid filter: %w", c.Label(), err)
+	}
+	if strings.HasPrefix(c.Tag, "-") {
+		return fmt.Errorf("calendar '%s': invalid tag '%s' (must not start with '-')", c.Label(), c.Tag)
+	}
+	return nil
+}
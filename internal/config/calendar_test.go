@@ -0,0 +1,47 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendar_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cal     Calendar
+		wantErr bool
+	}{
+		{"valid", Calendar{URL: "https://example.com/basic.ics", Refresh: "15m", Filter: "^Standup", Tag: "work"}, false},
+		{"webcal", Calendar{URL: "webcal://example.com/basic.ics"}, false},
+		{"no_url", Calendar{Name: "work"}, true},
+		{"file_url", Calendar{URL: "file:///tmp/basic.ics"}, true},
+		{"short_refresh", Calendar{URL: "https://example.com/a.ics", Refresh: "10s"}, true},
+		{"bad_filter", Calendar{URL: "https://example.com/a.ics", Filter: "("}, true},
+		{"bad_tag", Calendar{URL: "https://example.com/a.ics", Tag: "-work"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{CycleDays: 7, Calendars: []Calendar{tt.cal}}
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Expected error: %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCalendar_LabelAndRefresh(t *testing.T) {
+	cal := Calendar{URL: "webcal://calendar.example.com/private-abc/basic.ics"}
+	if cal.Label() != "calendar.example.com" {
+		t.Errorf("Expected the host as label, got %s", cal.Label())
+	}
+	if cal.FeedURL() != "https://calendar.example.com/private-abc/basic.ics" {
+		t.Errorf("Expected an https URL, got %s", cal.FeedURL())
+	}
+	if cal.RefreshInterval() != DefaultCalendarRefresh {
+		t.Errorf("Expected the default refresh, got %v", cal.RefreshInterval())
+	}
+	cal.Refresh = "5m"
+	if cal.RefreshInterval() != 5*time.Minute {
+		t.Errorf("Expected 5m, got %v", cal.RefreshInterval())
+	}
+}
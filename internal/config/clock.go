@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock selects how times are displayed: Clock24 ("09:00", the default) or
+// Clock12 ("9:00 AM"). Times in the config are always written as HH:MM.
+type Clock string
+
+const (
+	Clock24 Clock = "24h"
+	Clock12 Clock = "12h"
+)
+
+// Layout returns the time.Format layout of times on c.
+func (c Clock) Layout() string {
+	if c == Clock12 {
+		return "3:04 PM"
+	}
+	return "15:04"
+}
+
+// Format formats the time of day of t on c.
+func (c Clock) Format(t time.Time) string {
+	return t.Format(c.Layout())
+}
+
+// Width is the number of columns of the widest time on c, such as
+// "12:00 PM".
+func (c Clock) Width() int {
+	return len(time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC).Format(c.Layout()))
+}
+
+func (c Clock) validate() error {
+	switch c {
+	case "", Clock24, Clock12:
+		return nil
+	}
+	return fmt.Errorf("invalid clock '%s' (expected 12h or 24h)", c)
+}
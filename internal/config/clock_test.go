@@ -0,0 +1,41 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	tests := []struct {
+		clock Clock
+		times [3]string // 00:05, 09:30, 13:00
+		width int
+	}{
+		{"", [3]string{"00:05", "09:30", "13:00"}, 5},
+		{Clock24, [3]string{"00:05", "09:30", "13:00"}, 5},
+		{Clock12, [3]string{"12:05 AM", "9:30 AM", "1:00 PM"}, 8},
+	}
+	for _, tt := range tests {
+		for i, hm := range [][2]int{{0, 5}, {9, 30}, {13, 0}} {
+			got := tt.clock.Format(time.Date(2024, 1, 1, hm[0], hm[1], 0, 0, time.UTC))
+			if got != tt.times[i] {
+				t.Errorf("%q: Expected %q, got %q", tt.clock, tt.times[i], got)
+			}
+		}
+		if got := tt.clock.Width(); got != tt.width {
+			t.Errorf("%q: Expected width %d, got %d", tt.clock, tt.width, got)
+		}
+	}
+}
+
+func TestValidate_Clock(t *testing.T) {
+	cfg := &Config{CycleDays: 7, Clock: "12"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "invalid clock '12'") {
+		t.Errorf("Expected an invalid clock error, got %v", err)
+	}
+	cfg.Clock = Clock12
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected 12h to be valid, got %v", err)
+	}
+}
@@ -2,25 +2,138 @@
 package config
 
 import (
+	"bufio"
 	"encoding/csv"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Daniel-42-z/sked/internal/pomodoro"
+
 	"github.com/pelletier/go-toml/v2"
 )
 
 // Config represents the top-level configuration structure.
 type Config struct {
-	CycleDays  int        `toml:"cycle_days"`
-	AnchorDate string     `toml:"anchor_date"`
-	CSVPath    string     `toml:"csv_path"`
-	TmpCSVPath string     `toml:"tmp_csv_path"`
-	DateFormat string     `toml:"date_format"`
-	Days       []Day      `toml:"day"`
-	Overrides  []Override `toml:"override"`
+	// ConfigVersion is the syntax version of the file; see CurrentVersion.
+	ConfigVersion int `toml:"config_version"`
+
+	CycleDays   int        `toml:"cycle_days"`
+	AnchorDate  string     `toml:"anchor_date"`
+	AnchorDayID DayID      `toml:"anchor_day_id"` // cycle day of anchor_date, default 0
+	Anchor      string     `toml:"anchor"`        // shorthand such as "2025-01-20 = 3"; see ResolveAnchor
+	CSVPath     string     `toml:"csv_path"`
+	TmpCSVPath  string     `toml:"tmp_csv_path"`
+	OrgPath     string     `toml:"org_path"`
+	Sheet       string     `toml:"sheet"` // worksheet of an .xlsx csv_path, default the first
+	DateFormat  string     `toml:"date_format"`
+	Days        []Day      `toml:"day"`
+	Overrides   []Override `toml:"override"`
+	Events      []Event    `toml:"event"`
+	Rules       []Rule     `toml:"rule"`
+	// Strict makes unknown keys errors instead of warnings, like --strict.
+	Strict bool `toml:"strict"`
+	// Backups is how many previous versions commands that edit this file
+	// keep (config.toml.bak, .bak.1, ...); default DefaultBackups.
+	Backups *int `toml:"backups"`
+
+	// Commands run in watch mode when a task starts or ends.
+	OnTaskStart string `toml:"on_task_start"`
+	OnTaskEnd   string `toml:"on_task_end"`
+
+	// Desktop notification appearance.
+	NotifyIcon    string `toml:"notify_icon"`
+	NotifyUrgency string `toml:"notify_urgency"`
+	NotifyTimeout string `toml:"notify_timeout"`
+	// Custom notification command (argv, no shell) replacing the platform backend.
+	NotifyCommand []string `toml:"notify_command"`
+	// Presence checks before sending: skip notifications while the session
+	// has been idle longer than SuppressWhenIdle or Do-Not-Disturb is on, and
+	// with ResendAfterIdle send them once on return if the task hasn't started.
+	SuppressWhenIdle string `toml:"suppress_when_idle"`
+	SuppressWhenDND  bool   `toml:"suppress_when_dnd"`
+	ResendAfterIdle  bool   `toml:"resend_after_idle"`
+
+	// Additional notification backends.
+	Notify NotifyConfig `toml:"notify"`
+
+	// Terminal colors for natural output.
+	Colors Colors `toml:"colors"`
+	// OffDayText replaces the no-task text in natural output on off days.
+	OffDayText string `toml:"off_day_text"`
+	// NoTaskBefore, NoTaskBetween and NoTaskAfter replace the no-task text
+	// in natural output before the day's first task, between two tasks and
+	// after the last one. They may contain {next_name} and {next_in}.
+	NoTaskBefore  string `toml:"no_task_before"`
+	NoTaskBetween string `toml:"no_task_between"`
+	NoTaskAfter   string `toml:"no_task_after"`
+	// Clock is "12h" to display times as "9:00 AM" instead of "09:00".
+	Clock Clock `toml:"clock"`
+	// DurationStyle is how durations are displayed: compact, clock or words.
+	DurationStyle DurationStyle `toml:"duration_style"`
+	// DayWindow is the part of the day gaps and utilization are measured
+	// against, e.g. "08:00-18:00", optionally per weekday.
+	DayWindow DayWindows `toml:"day_window"`
+	// MergeAdjacent merges consecutive tasks of a day with the same name
+	// into one task spanning them, when the gap between them is at most
+	// MergeTolerance (e.g. "5m", none if unset).
+	MergeAdjacent  bool   `toml:"merge_adjacent"`
+	MergeTolerance string `toml:"merge_tolerance"`
+	// Aliases maps raw task names or glob patterns ("MATH*") to display names.
+	Aliases map[string]string `toml:"aliases"`
+	// Icons maps raw or display task names, or glob patterns, to icons for
+	// tasks without their own.
+	Icons map[string]string `toml:"icons"`
+	// TagColors maps tags to colors for the rows of tagged tasks in the TUI.
+	TagColors map[string]string `toml:"tag_colors"`
+	// NoMouse turns off mouse support in the TUI, like --no-mouse.
+	NoMouse bool `toml:"no_mouse"`
+	// TUI configures 'sked show'.
+	TUI TUIConfig `toml:"tui"`
+	// Calendars are remote ICS feeds overlaid onto the schedule.
+	Calendars []Calendar `toml:"calendar"`
+
+	// Sources lists the files this configuration was loaded from.
+	Sources []string `toml:"-"`
+	// Warnings lists data the loaders skipped or ignored.
+	Warnings []Warning `toml:"-"`
+	// Base is the configuration a temporary schedule stands in for, to
+	// tell where the two overlap; nil otherwise.
+	Base *Config `toml:"-"`
+	// TmpDate is the only date a temporary schedule (tmp CSV or inline)
+	// has tasks on, the day it was loaded; zero for other configs.
+	TmpDate time.Time `toml:"-"`
+
+	// overrides indexes Overrides by date; built by ProcessOverrides.
+	overrides *overrideIndex
+}
+
+// Colors configures terminal colors. Values are ANSI color numbers ("2") or hex ("#00ff00").
+type Colors struct {
+	Current string `toml:"current"` // current task name, default green
+	Soon    string `toml:"soon"`    // next task starting within 5 minutes, default yellow
+	Time    string `toml:"time"`    // time ranges, default dim
+}
+
+// NotifyConfig configures notification backends beyond the desktop.
+type NotifyConfig struct {
+	Webhook *WebhookConfig `toml:"webhook"`
+}
+
+// WebhookConfig configures an HTTP webhook notification backend.
+type WebhookConfig struct {
+	URL     string            `toml:"url"`
+	Method  string            `toml:"method"`
+	Headers map[string]string `toml:"headers"`
+	Format  string            `toml:"format"` // generic (default), slack or discord
+	Body    string            `toml:"body"`   // optional text/template overriding the format
 }
 
 func closeFile(f *os.File, err *error) {
@@ -34,7 +147,7 @@ func closeFile(f *os.File, err *error) {
 type DayID int
 
 func (d *DayID) UnmarshalText(text []byte) error {
-	id, err := parseDayName(string(text))
+	id, err := ParseDayName(string(text))
 	if err != nil {
 		return err
 	}
@@ -60,16 +173,38 @@ type Override struct {
 	EndDateStr string `toml:"end_date"`
 	IsOff      bool   `toml:"is_off"`
 	UseDayID   DayID  `toml:"use_day_id"`
+	Note       string `toml:"note"` // optional label, e.g. "PTO"
+	// MuteNotifications suppresses watch-mode notifications on the covered
+	// dates, e.g. during a conference that still follows a cycle day.
+	MuteNotifications bool `toml:"mute_notifications"`
+	// UntilFurtherNotice makes the override apply from date onward, e.g. for
+	// a permanent schedule change mid-term. It leaves EndDate zero and
+	// excludes end_date.
+	UntilFurtherNotice bool `toml:"until_further_notice"`
 
 	// Internal fields populated during validation
 	Date    time.Time `toml:"-"`
 	EndDate time.Time `toml:"-"`
 }
 
+// Event is a task on a single date, added to the tasks of the cycle day
+// the date falls on. Off days drop their events too.
+type Event struct {
+	DateStr string `toml:"date"`
+	Task
+
+	// Internal field populated during validation
+	Date time.Time `toml:"-"`
+}
+
 // Day represents a single day's schedule in the cycle.
 type Day struct {
-	ID    int    `toml:"id"`
-	Tasks []Task `toml:"tasks"`
+	ID int `toml:"id"`
+	// IDs defines several days with the same tasks; see ExpandDayIDs.
+	IDs   []DayID `toml:"ids"`
+	Tasks []Task  `toml:"tasks"`
+	// Extends starts the day from another day's tasks; see ResolveExtends.
+	Extends *DayID `toml:"extends"`
 }
 
 // Task represents a specific activity.
@@ -77,71 +212,185 @@ type Task struct {
 	Name  string `toml:"name"`
 	Start string `toml:"start"`
 	End   string `toml:"end"`
+	Color string `toml:"color"` // optional; overrides the default output color
+	// Pomodoro optionally splits the task into focus/break phases, e.g. "25m/5m".
+	Pomodoro string `toml:"pomodoro"`
+	// Icon is shown before the name with --icons, e.g. "📚".
+	Icon string `toml:"icon"`
+	// Tags categorize the task for --tag filters, e.g. ["work"].
+	Tags []string `toml:"tags"`
+	// URL is opened by 'sked open' and the TUI, e.g. a meeting link.
+	URL string `toml:"url"`
+	// Location says where the task takes place, e.g. "Room 4".
+	Location string `toml:"location"`
+
+	// RepeatAt or Every/From/Until replace start and end with several
+	// instances lasting Duration each; see ExpandRepeats.
+	RepeatAt []string `toml:"repeat_at"`
+	Every    string   `toml:"every"`
+	From     string   `toml:"from"`
+	Until    string   `toml:"until"`
+	Duration string   `toml:"duration"`
+	// Rule describes the repeat rule an expanded instance came from.
+	Rule string `toml:"-"`
+	// Source is where the task was defined.
+	Source Origin `toml:"-"`
 }
 
 // Load reads the configuration from the specified path.
-// It detects the format based on the file extension (.toml or .csv).
+// It detects the format based on the file extension (.toml, .csv or .xlsx).
 func Load(path string) (*Config, error) {
+	var cfg *Config
+	var err error
 	ext := strings.ToLower(filepath.Ext(path))
 	switch ext {
 	case ".toml":
-		return LoadTOML(path)
+		cfg, err = LoadTOML(path)
 	case ".csv":
-		return LoadCSV(path, "")
+		cfg, err = LoadCSV(path, "")
+	case ".xlsx":
+		cfg, err = LoadXLSX(path, "", "")
 	default:
 		return nil, fmt.Errorf("unsupported file extension: %s", ext)
 	}
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("config loaded", "path", path, "days", len(cfg.Days), "tasks", cfg.taskCount(), "overrides", len(cfg.Overrides))
+	return cfg, nil
 }
 
-// LoadTOML reads a TOML configuration file.
+// taskCount returns the number of tasks over all days.
+func (c *Config) taskCount() int {
+	n := 0
+	for _, d := range c.Days {
+		n += len(d.Tasks)
+	}
+	return n
+}
+
+// LoadTOML reads a TOML configuration file, migrating older config_version
+// syntax in memory.
 func LoadTOML(path string) (*Config, error) {
-	f, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	defer closeFile(f, &err)
 
 	var cfg Config
-	// Set defaults
-	cfg.CycleDays = 7
+	if err := decodeTOML(path, data, &cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Strict {
+		if err := cfg.StrictError(); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg.Sources = []string{path}
+	cfg.setTOMLSources(path, data)
 
-	dec := toml.NewDecoder(f)
-	dec.DisallowUnknownFields()
-	if err := dec.Decode(&cfg); err != nil {
+	if err := cfg.ResolveAnchor(); err != nil {
+		return nil, err
+	}
+	if w := cfg.AnchorWarning(); w != "" {
+		cfg.warnf(path, keyLine(data, "anchor_date", "anchor"), 0, "%s", w)
+	}
+	if err := cfg.ExpandRepeats(); err != nil {
+		return nil, err
+	}
+	if err := cfg.ExpandDayIDs(); err != nil {
+		return nil, err
+	}
+	if err := cfg.ResolveExtends(); err != nil {
 		return nil, err
 	}
 
 	// Resolve TmpCSVPath relative to config file
 	if cfg.TmpCSVPath != "" {
-		tmpCsvPath, err := expandTilde(cfg.TmpCSVPath)
+		tmpCsvPath, err := resolvePath(path, cfg.TmpCSVPath)
 		if err != nil {
 			return nil, err
 		}
-		if !filepath.IsAbs(tmpCsvPath) {
-			tmpCsvPath = filepath.Join(filepath.Dir(path), tmpCsvPath)
-		}
 		cfg.TmpCSVPath = tmpCsvPath
+		cfg.Sources = append(cfg.Sources, tmpCsvPath)
+	}
+
+	if cfg.OrgPath != "" {
+		if cfg.CSVPath != "" {
+			return nil, fmt.Errorf("csv_path and org_path are mutually exclusive")
+		}
+		if cfg.CycleDays != 7 || cfg.AnchorDate != "" {
+			return nil, fmt.Errorf("org_path requires a standard 7-day cycle without anchor_date")
+		}
+		orgPath, err := resolvePath(path, cfg.OrgPath)
+		if err != nil {
+			return nil, err
+		}
+		org, err := LoadOrg(orgPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range org.Warnings {
+			cfg.warnf(orgPath, w.Line, 0, "entry '%s' skipped: %s", w.Heading, w.Reason)
+		}
+		cfg.OrgPath = orgPath
+		cfg.Sources = append(cfg.Sources, orgPath)
+		cfg.merge(org)
 	}
 
 	// Check for CSV redirection
 	if cfg.CSVPath != "" {
-		csvPath, err := expandTilde(cfg.CSVPath)
+		csvPath, err := resolvePath(path, cfg.CSVPath)
 		if err != nil {
 			return nil, err
 		}
 
-		// If path is relative, resolve it relative to the TOML file
-		if !filepath.IsAbs(csvPath) {
-			csvPath = filepath.Join(filepath.Dir(path), csvPath)
+		var csvCfg *Config
+		if strings.EqualFold(filepath.Ext(csvPath), ".xlsx") {
+			csvCfg, err = LoadXLSX(csvPath, cfg.Sheet, cfg.DateFormat)
+		} else {
+			csvCfg, err = LoadCSV(csvPath, cfg.DateFormat)
 		}
-
-		csvCfg, err := LoadCSV(csvPath, cfg.DateFormat)
 		if err != nil {
 			return nil, err
 		}
 		// Preserve settings from TOML
+		csvCfg.Sources = append(cfg.Sources, csvCfg.Sources...)
+		csvCfg.Warnings = append(cfg.Warnings, csvCfg.Warnings...)
 		csvCfg.TmpCSVPath = cfg.TmpCSVPath
+		csvCfg.Sheet = cfg.Sheet
+		csvCfg.Strict = cfg.Strict
+		csvCfg.Backups = cfg.Backups
 		csvCfg.Overrides = cfg.Overrides
+		csvCfg.Events = cfg.Events
+		csvCfg.Rules = cfg.Rules
+		csvCfg.OnTaskStart = cfg.OnTaskStart
+		csvCfg.OnTaskEnd = cfg.OnTaskEnd
+		csvCfg.NotifyIcon = cfg.NotifyIcon
+		csvCfg.NotifyUrgency = cfg.NotifyUrgency
+		csvCfg.NotifyTimeout = cfg.NotifyTimeout
+		csvCfg.SuppressWhenIdle = cfg.SuppressWhenIdle
+		csvCfg.SuppressWhenDND = cfg.SuppressWhenDND
+		csvCfg.ResendAfterIdle = cfg.ResendAfterIdle
+		csvCfg.NotifyCommand = cfg.NotifyCommand
+		csvCfg.Notify = cfg.Notify
+		csvCfg.Colors = cfg.Colors
+		csvCfg.OffDayText = cfg.OffDayText
+		csvCfg.NoTaskBefore = cfg.NoTaskBefore
+		csvCfg.NoTaskBetween = cfg.NoTaskBetween
+		csvCfg.NoTaskAfter = cfg.NoTaskAfter
+		csvCfg.Clock = cfg.Clock
+		csvCfg.DurationStyle = cfg.DurationStyle
+		csvCfg.DayWindow = cfg.DayWindow
+		csvCfg.MergeAdjacent = cfg.MergeAdjacent
+		csvCfg.MergeTolerance = cfg.MergeTolerance
+		csvCfg.Aliases = cfg.Aliases
+		csvCfg.Icons = cfg.Icons
+		csvCfg.TagColors = cfg.TagColors
+		csvCfg.NoMouse = cfg.NoMouse
+		csvCfg.TUI = cfg.TUI
+		csvCfg.Calendars = cfg.Calendars
 
 		if err := csvCfg.ProcessOverrides(); err != nil {
 			return nil, err
@@ -159,172 +408,442 @@ func LoadTOML(path string) (*Config, error) {
 // CSV format assumes a standard 7-day cycle.
 // Header: Start,End,Mon,Tue,Wed,Thu,Fri,Sat,Sun (flexible day column order)
 func LoadCSV(path string, dateFormat string) (*Config, error) {
-	f, err := os.Open(path)
+	var load *tableLoader
+	err := readCSV(path, func(row tableRow, left int64) error {
+		if load == nil {
+			cols, err := parseTableHeader(row)
+			if err != nil {
+				return err
+			}
+			load = newTableLoader(path, cols, dateFormat)
+			return nil
+		}
+		load.add(row, left)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer closeFile(f, &err)
+	if load == nil {
+		return nil, fmt.Errorf("csv file is empty")
+	}
+	return load.config(), nil
+}
 
-	reader := csv.NewReader(f)
-	reader.Comment = '#'
-	records, err := reader.ReadAll()
+// tableRow is a record of a CSV file or worksheet with its 1-based line or
+// row number.
+type tableRow struct {
+	line   int
+	fields []string
+}
+
+// readCSV streams the records of a CSV file to fn; see readCSVFrom.
+func readCSV(path string, fn func(row tableRow, left int64) error) (err error) {
+	f, err := os.Open(path)
 	if err != nil {
-		return nil, err
+		return err
 	}
+	defer closeFile(f, &err)
+	return readCSVFrom(f, fn)
+}
 
-	if len(records) < 1 {
-		return nil, fmt.Errorf("csv file is empty")
+// readCSVFrom streams the records of CSV data to fn, skipping blank and '#'
+// comment lines, along with the number of bytes left after each, or -1 if
+// r isn't a regular file. Records may have different numbers of fields.
+// The fields slice is reused between calls, so fn must not keep it.
+func readCSVFrom(r io.Reader, fn func(row tableRow, left int64) error) error {
+	size := int64(-1)
+	if f, ok := r.(*os.File); ok {
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size = info.Size()
+		}
+	}
+
+	reader := csv.NewReader(bufio.NewReaderSize(r, 64*1024))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := reader.FieldPos(0)
+		left := int64(-1)
+		if size >= 0 {
+			left = size - reader.InputOffset()
+		}
+		if err := fn(tableRow{line: line, fields: record}, left); err != nil {
+			return err
+		}
 	}
+}
+
+// maxRowsHint caps how many rows a size estimate may reserve room for.
+const maxRowsHint = 1 << 16
+
+// interner shares one copy of the strings repeated across a table, such as
+// task names and times, instead of keeping every row's copy alive.
+type interner map[string]string
 
-	header := records[0]
-	if len(header) < 3 {
-		return nil, fmt.Errorf("header must have at least Start, End and one Day column")
+func (in interner) get(s string) string {
+	if v, ok := in[s]; ok {
+		return v
 	}
+	s = strings.Clone(s)
+	in[s] = s
+	return s
+}
 
-	// Map column index to day ID
+// tableColumns locates the columns of the weekly table format shared by
+// CSV and XLSX files.
+type tableColumns struct {
+	start, end, tags int
+	days             []dayColumn // in column order
+	header           tableRow
+	unknown          []int // indexes of header columns that were not recognized
+}
 
-	colToDay := make(map[int]int)
-	startCol := -1
-	endCol := -1
+// dayColumn is a day column of a weekly table.
+type dayColumn struct {
+	index, id int
+}
 
-	for i, col := range header {
+// parseTableHeader reads the Start, End, Tags and day columns of a header row.
+func parseTableHeader(header tableRow) (tableColumns, error) {
+	if len(header.fields) < 3 {
+		return tableColumns{}, fmt.Errorf("header must have at least Start, End and one Day column")
+	}
+	cols := tableColumns{start: -1, end: -1, tags: -1, header: header}
+
+	for i, col := range header.fields {
 		col = strings.ToLower(strings.TrimSpace(col))
 		if col == "start" || col == "time-start" {
-			startCol = i
+			cols.start = i
 		} else if col == "end" || col == "time-end" {
-			endCol = i
-		} else {
+			cols.end = i
+		} else if col == "tags" {
+			cols.tags = i
+		} else if col != "" {
 			// Try to parse as day
-			dayID, err := parseDayName(col)
+			dayID, err := ParseDayName(col)
 			if err == nil {
-				colToDay[i] = dayID
+				cols.days = append(cols.days, dayColumn{i, dayID})
+			} else {
+				cols.unknown = append(cols.unknown, i)
 			}
 		}
 	}
 
-	if startCol == -1 || endCol == -1 {
-		return nil, fmt.Errorf("header must contain 'Start' and 'End' columns")
+	if cols.start == -1 || cols.end == -1 {
+		return tableColumns{}, fmt.Errorf("header must contain 'Start' and 'End' columns")
 	}
+	return cols, nil
+}
 
-	cfg := &Config{
-		CycleDays:  7,
-		Days:       make([]Day, 0),
-		DateFormat: dateFormat,
+// tableLoader builds a 7-day config from the rows below a table header,
+// one row at a time. Ignored header columns and skipped rows are reported
+// in Config.Warnings.
+type tableLoader struct {
+	path string
+	cols tableColumns
+	cfg  *Config
+	days map[int][]Task
+	strs interner
+	tags map[string][]string // parsed Tags cells, shared by equal cells
+	hint sizeHint
+}
+
+func newTableLoader(path string, cols tableColumns, dateFormat string) *tableLoader {
+	l := &tableLoader{
+		path: path,
+		cols: cols,
+		cfg: &Config{
+			CycleDays:  7,
+			Days:       make([]Day, 0, len(cols.days)),
+			DateFormat: dateFormat,
+			Sources:    []string{path},
+		},
+		days: make(map[int][]Task, len(cols.days)),
+		strs: make(interner),
+		tags: make(map[string][]string),
 	}
+	for _, i := range cols.unknown {
+		header := strings.TrimSpace(cols.header.fields[i])
+		l.cfg.warnf(path, cols.header.line, i+1, "column '%s' is not Start, End, Tags or a day name; ignored", header)
+		l.cfg.Warnings[len(l.cfg.Warnings)-1].Header = header
+	}
+	return l
+}
 
-	dayMap := make(map[int][]Task)
+// loadTable builds a 7-day config from rows already in memory.
+func loadTable(path string, cols tableColumns, rows []tableRow, dateFormat string) *Config {
+	l := newTableLoader(path, cols, dateFormat)
+	for _, row := range rows {
+		l.add(row, -1)
+	}
+	return l.config()
+}
 
-	for _, record := range records[1:] {
-		if len(record) <= startCol || len(record) <= endCol {
-			continue // Skip invalid rows
-		}
+// add adds the tasks of a row. left is the number of bytes of the file after
+// the row, or -1 if unknown; see sizeHint.
+func (l *tableLoader) add(row tableRow, left int64) {
+	record := row.fields
+	if blank(record) {
+		return
+	}
+	cols := l.cols
+	if len(record) <= cols.start || len(record) <= cols.end {
+		l.cfg.warnf(l.path, row.line, 0, "row has %d field(s), fewer than the %d needed for the Start and End columns; skipped", len(record), max(cols.start, cols.end)+1)
+		return
+	}
 
-		start := strings.TrimSpace(record[startCol])
-		end := strings.TrimSpace(record[endCol])
+	start := l.strs.get(strings.TrimSpace(record[cols.start]))
+	end := l.strs.get(strings.TrimSpace(record[cols.end]))
 
-		if start == "" {
-			continue // Skip rows without start time
+	if start == "" {
+		l.cfg.warnf(l.path, row.line, cols.start+1, "row has no start time; skipped")
+		return
+	}
+	var tags []string
+	if cols.tags >= 0 && cols.tags < len(record) {
+		cell := record[cols.tags]
+		var ok bool
+		if tags, ok = l.tags[cell]; !ok {
+			tags = parseTags(cell)
+			for i := range tags {
+				tags[i] = l.strs.get(tags[i])
+			}
+			l.tags[strings.Clone(cell)] = tags
+		}
+	}
+	if n := l.hint.next(left); n > 0 {
+		for _, day := range cols.days {
+			l.days[day.id] = slices.Grow(l.days[day.id], n)
 		}
+	}
 
-		for colIdx, dayID := range colToDay {
-			if colIdx >= len(record) {
-				continue
+	for _, day := range cols.days {
+		colIdx := day.index
+		if colIdx >= len(record) {
+			continue
+		}
+		name := strings.TrimSpace(record[colIdx])
+		if name == "" {
+			continue
+		}
+		taskStart, taskEnd := start, end
+		if strings.Contains(name, "@") {
+			cellName, cellStart, cellEnd, err := parseCellTimes(name)
+			if err != nil {
+				l.cfg.warnf(l.path, row.line, colIdx+1, "cell '%s': %v; using the row's times", name, err)
+			} else if cellStart != "" {
+				taskStart, taskEnd = l.strs.get(cellStart), l.strs.get(cellEnd)
 			}
-			name := strings.TrimSpace(record[colIdx])
-			if name != "" {
-				task := Task{
-					Name:  name,
-					Start: start,
-					End:   end,
-				}
-				dayMap[dayID] = append(dayMap[dayID], task)
+			if cellName == "" {
+				l.cfg.warnf(l.path, row.line, colIdx+1, "cell '%s' has no task name before '@'; skipped", name)
+				continue
 			}
+			name = cellName
+		}
+		task := Task{
+			Name:   l.strs.get(name),
+			Start:  taskStart,
+			End:    taskEnd,
+			Tags:   tags,
+			Source: Origin{File: l.path, Line: row.line, Column: colIdx + 1},
+		}
+		l.days[day.id] = append(l.days[day.id], task)
+	}
+}
+
+// parseCellTimes splits a day cell with custom times, such as
+// "History@10:15-11:05" or "History @ 10:15 - 11:05", into the name and the
+// times the task runs at on that day. A cell without an "@" followed by a
+// digit is all name, with no times. A suffix that isn't a valid
+// HH:MM-HH:MM range is an error; the name is still split off.
+func parseCellTimes(cell string) (name, start, end string, err error) {
+	i := strings.LastIndex(cell, "@")
+	suffix := strings.TrimSpace(cell[i+1:])
+	if i < 0 || suffix == "" || suffix[0] < '0' || suffix[0] > '9' {
+		return cell, "", "", nil
+	}
+	name = strings.TrimSpace(cell[:i])
+	from, to, ok := strings.Cut(suffix, "-")
+	if !ok {
+		return name, "", "", fmt.Errorf("invalid time range '%s' (expected HH:MM-HH:MM)", suffix)
+	}
+	for _, t := range []*string{&from, &to} {
+		hm, err := time.Parse("15:04", strings.TrimSpace(*t))
+		if err != nil {
+			return name, "", "", fmt.Errorf("invalid time range '%s' (expected HH:MM-HH:MM)", suffix)
 		}
+		*t = hm.Format("15:04")
 	}
+	return name, from, to, nil
+}
+
+// sizeHint estimates how many rows of a file are left from the bytes its
+// second data row takes, so slices can be sized once rather than regrown
+// many times over on a large file.
+type sizeHint struct {
+	rows int
+	left int64
+}
 
-	// Convert map to slice
-	for id, tasks := range dayMap {
-		cfg.Days = append(cfg.Days, Day{
+// next counts a row followed by left bytes (-1 if unknown). At the second
+// row it returns the estimate, and 0 otherwise.
+func (h *sizeHint) next(left int64) int {
+	h.rows++
+	prev := h.left
+	h.left = left
+	if left < 0 || h.rows != 2 || prev <= left {
+		return 0
+	}
+	return int(min(left/(prev-left), maxRowsHint)) + 1
+}
+
+// config returns the loaded config, its days with tasks in ID order.
+func (l *tableLoader) config() *Config {
+	ids := make([]int, 0, len(l.days))
+	for id, tasks := range l.days {
+		if len(tasks) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		l.cfg.Days = append(l.cfg.Days, Day{
 			ID:    id,
-			Tasks: tasks,
+			Tasks: slices.Clip(l.days[id]),
 		})
 	}
+	return l.cfg
+}
 
-	return cfg, nil
+// parseTags splits a semicolon-separated CSV tags cell, e.g. "work;deep".
+func parseTags(s string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, ";") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// startOfToday returns the start of the current day in the local time zone.
+func startOfToday() time.Time {
+	y, m, d := time.Now().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
 }
 
 // LoadTmpCSV reads a temporary CSV configuration file.
 // It expects "Start", "End", and "Task" columns.
 // Tasks are assigned to the current day (as of when this function is called).
-func LoadTmpCSV(path string) (*Config, error) {
+// Skipped rows are reported in Config.Warnings.
+func LoadTmpCSV(path string) (cfg *Config, err error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
-
-	reader := csv.NewReader(f)
-	reader.Comment = '#'
-	records, err := reader.ReadAll()
-	if err != nil {
+	defer closeFile(f, &err)
+	if cfg, err = ReadTmpCSV(path, f); err != nil {
 		return nil, err
 	}
+	cfg.Sources = []string{path}
+	return cfg, nil
+}
 
-	if len(records) < 1 {
-		return nil, fmt.Errorf("csv file is empty")
+// ReadTmpCSV reads a temporary CSV configuration from r, such as stdin,
+// like LoadTmpCSV. label names the input in its warnings. The config has
+// no Sources. Its tasks are today's only, on TmpDate.
+func ReadTmpCSV(label string, r io.Reader) (*Config, error) {
+	today := startOfToday()
+	cfg := &Config{
+		CycleDays: 7,
+		Days:      make([]Day, 0, 1),
+		TmpDate:   today,
 	}
 
-	header := records[0]
-	if len(header) < 3 {
-		return nil, fmt.Errorf("header must have at least Start, End and Task columns")
-	}
+	// Determine current day ID (0-6)
+	currentDayID := int(today.Weekday())
+	var tasks []Task
+	var hint sizeHint
+	strs := make(interner)
 
+	header := true
 	startCol := -1
 	endCol := -1
 	taskCol := -1
 
-	for i, col := range header {
-		col = strings.ToLower(strings.TrimSpace(col))
-		if col == "start" || col == "time-start" {
-			startCol = i
-		} else if col == "end" || col == "time-end" {
-			endCol = i
-		} else if col == "task" {
-			taskCol = i
+	err := readCSVFrom(r, func(row tableRow, left int64) error {
+		record := row.fields
+		if header {
+			header = false
+			if len(record) < 3 {
+				return fmt.Errorf("header must have at least Start, End and Task columns")
+			}
+			for i, col := range record {
+				col = strings.ToLower(strings.TrimSpace(col))
+				if col == "start" || col == "time-start" {
+					startCol = i
+				} else if col == "end" || col == "time-end" {
+					endCol = i
+				} else if col == "task" {
+					taskCol = i
+				}
+			}
+			if startCol == -1 || endCol == -1 || taskCol == -1 {
+				return fmt.Errorf("header must contain 'Start', 'End' and 'Task' columns")
+			}
+			return nil
 		}
-	}
 
-	if startCol == -1 || endCol == -1 || taskCol == -1 {
-		return nil, fmt.Errorf("header must contain 'Start', 'End' and 'Task' columns")
-	}
-
-	cfg := &Config{
-		CycleDays: 7,
-		Days:      make([]Day, 0),
-	}
-
-	// Determine current day ID (0-6)
-	currentDayID := int(time.Now().Weekday())
-	var tasks []Task
-
-	for _, record := range records[1:] {
+		if blank(record) {
+			return nil
+		}
 		if len(record) <= startCol || len(record) <= endCol || len(record) <= taskCol {
-			continue // Skip invalid rows
+			cfg.warnf(label, row.line, 0, "row has %d field(s), fewer than the %d needed for the Start, End and Task columns; skipped", len(record), max(startCol, endCol, taskCol)+1)
+			return nil
 		}
 
 		start := strings.TrimSpace(record[startCol])
 		end := strings.TrimSpace(record[endCol])
 		name := strings.TrimSpace(record[taskCol])
 
-		if start == "" || name == "" {
-			continue
+		if start == "" {
+			cfg.warnf(label, row.line, startCol+1, "row has no start time; skipped")
+			return nil
+		}
+		if name == "" {
+			cfg.warnf(label, row.line, taskCol+1, "row has no task name; skipped")
+			return nil
 		}
 
+		if n := hint.next(left); n > 0 {
+			tasks = slices.Grow(tasks, n)
+		}
 		tasks = append(tasks, Task{
-			Name:  name,
-			Start: start,
-			End:   end,
+			Name:   strs.get(name),
+			Start:  strs.get(start),
+			End:    strs.get(end),
+			Source: Origin{File: label, Line: row.line, Column: taskCol + 1},
 		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if header {
+		return nil, fmt.Errorf("csv file is empty")
 	}
 
 	cfg.Days = append(cfg.Days, Day{
@@ -335,8 +854,17 @@ func LoadTmpCSV(path string) (*Config, error) {
 	return cfg, nil
 }
 
-// ProcessOverrides parses raw override data into usable structs.
+// ProcessOverrides parses raw override and event data into usable structs.
 func (c *Config) ProcessOverrides() error {
+	for i := range c.Events {
+		e := &c.Events[i]
+		t, err := time.Parse("2006-01-02", e.DateStr)
+		if err != nil {
+			return fmt.Errorf("invalid event date '%s' for '%s': %w", e.DateStr, e.Name, err)
+		}
+		e.Date = t
+	}
+
 	for i := range c.Overrides {
 		o := &c.Overrides[i]
 
@@ -351,7 +879,12 @@ func (c *Config) ProcessOverrides() error {
 		o.Date = t
 
 		// Parse EndDate
-		if o.EndDateStr != "" {
+		if o.UntilFurtherNotice {
+			if o.EndDateStr != "" {
+				return fmt.Errorf("override '%s' cannot have both end_date and until_further_notice", o.DateStr)
+			}
+			o.EndDate = time.Time{}
+		} else if o.EndDateStr != "" {
 			et, err := time.Parse("2006-01-02", o.EndDateStr)
 			if err != nil {
 				return fmt.Errorf("invalid override end_date '%s': %w", o.EndDateStr, err)
@@ -368,58 +901,77 @@ func (c *Config) ProcessOverrides() error {
 		// because it defaults to 0 (Sunday). If we want to require it, we'd need a more
 		// complex check or a pointer in the struct.
 	}
+	c.indexOverrides()
 	return nil
 }
 
-// expandTilde expands the '~' prefix in a path to the user's home directory.
-func expandTilde(path string) (string, error) {
-	if !strings.HasPrefix(path, "~") {
-		return path, nil
-	}
-
-	home, err := os.UserHomeDir()
+// ReferencedFiles returns the resolved csv_path and tmp_csv_path of a TOML
+// config (empty when unset) without loading the schedule itself, so the
+// paths are available even when the rest of the config is broken.
+func ReferencedFiles(path string) (csvPath, tmpCSVPath string, err error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return "", fmt.Errorf("could not get user home directory: %w", err)
+		return "", "", err
 	}
-
-	return filepath.Join(home, path[1:]), nil
-}
-
-// parseDayName converts a day name (e.g., "Monday") or a numeric string to a cycle ID (0-6).
-// Assumes 0=Sunday, 1=Monday, ..., 6=Saturday to match time.Weekday().
-func parseDayName(name string) (int, error) {
-	name = strings.ToLower(strings.TrimSpace(name))
-
-	// Try to parse as integer first (for "5" in a string field)
-	var id int
-	if _, err := fmt.Sscanf(name, "%d", &id); err == nil {
-		return id, nil
+	var refs struct {
+		CSVPath    string `toml:"csv_path"`
+		TmpCSVPath string `toml:"tmp_csv_path"`
 	}
-
-	// Prefix matching to allow "mon", "tue", etc.
-	if strings.HasPrefix(name, "sun") {
-		return 0, nil
+	if err := toml.Unmarshal(data, &refs); err != nil {
+		return "", "", err
+	}
+	if refs.CSVPath != "" {
+		if csvPath, err = resolvePath(path, refs.CSVPath); err != nil {
+			return "", "", err
+		}
 	}
-	if strings.HasPrefix(name, "mon") {
-		return 1, nil
+	if refs.TmpCSVPath != "" {
+		if tmpCSVPath, err = resolvePath(path, refs.TmpCSVPath); err != nil {
+			return "", "", err
+		}
 	}
-	if strings.HasPrefix(name, "tue") {
-		return 2, nil
+	return csvPath, tmpCSVPath, nil
+}
+
+// resolvePath expands '~' in p and resolves it relative to the directory of configPath.
+func resolvePath(configPath, p string) (string, error) {
+	p, err := ExpandTilde(p)
+	if err != nil {
+		return "", err
 	}
-	if strings.HasPrefix(name, "wed") {
-		return 3, nil
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(filepath.Dir(configPath), p)
 	}
-	if strings.HasPrefix(name, "thu") {
-		return 4, nil
+	return p, nil
+}
+
+// ExpandTilde expands a leading "~" or "~/" (also "~\" on Windows) to the
+// user's home directory. Other users' homes ("~bob/...") are not supported.
+func ExpandTilde(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok {
+		return path, nil
 	}
-	if strings.HasPrefix(name, "fri") {
-		return 5, nil
+	if rest != "" && rest[0] != '/' && !os.IsPathSeparator(rest[0]) {
+		return "", fmt.Errorf("cannot expand '%s': only '~' for your own home directory is supported; use an absolute path", path)
 	}
-	if strings.HasPrefix(name, "sat") {
-		return 6, nil
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get user home directory: %w", err)
 	}
 
-	return -1, fmt.Errorf("invalid day name: %s", name)
+	return filepath.Join(home, filepath.FromSlash(rest)), nil
+}
+
+// MergeGap returns the longest gap, in minutes, across which merge_adjacent
+// merges two tasks, or -1 if merging is off.
+func (c *Config) MergeGap() int {
+	if !c.MergeAdjacent {
+		return -1
+	}
+	d, _ := time.ParseDuration(c.MergeTolerance)
+	return int(max(d, 0) / time.Minute)
 }
 
 // Validate checks if the configuration is valid.
@@ -436,20 +988,167 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("invalid anchor_date format (expected YYYY-MM-DD): %w", err)
 		}
 	}
+	if c.Backups != nil && *c.Backups < 0 {
+		return fmt.Errorf("backups must not be negative")
+	}
+	if c.AnchorDayID != 0 && c.AnchorDate == "" {
+		return fmt.Errorf("anchor_day_id requires anchor_date")
+	}
+	if c.AnchorDayID < 0 || int(c.AnchorDayID) >= c.CycleDays {
+		return fmt.Errorf("anchor_day_id %d is out of range (expected 0 to %d)", c.AnchorDayID, c.CycleDays-1)
+	}
+	switch c.NotifyUrgency {
+	case "", "low", "normal", "critical":
+	default:
+		return fmt.Errorf("invalid notify_urgency '%s' (expected low, normal or critical)", c.NotifyUrgency)
+	}
+	if c.NotifyTimeout != "" {
+		if _, err := time.ParseDuration(c.NotifyTimeout); err != nil {
+			return fmt.Errorf("invalid notify_timeout '%s': %w", c.NotifyTimeout, err)
+		}
+	}
+	if c.SuppressWhenIdle != "" {
+		if d, err := time.ParseDuration(c.SuppressWhenIdle); err != nil {
+			return fmt.Errorf("invalid suppress_when_idle '%s': %w", c.SuppressWhenIdle, err)
+		} else if d <= 0 {
+			return fmt.Errorf("invalid suppress_when_idle '%s': must be positive", c.SuppressWhenIdle)
+		}
+	}
+	if wh := c.Notify.Webhook; wh != nil {
+		if wh.URL == "" {
+			return fmt.Errorf("notify.webhook requires a url")
+		}
+		switch wh.Format {
+		case "", "generic", "slack", "discord":
+		default:
+			return fmt.Errorf("invalid notify.webhook format '%s' (expected generic, slack or discord)", wh.Format)
+		}
+	}
+	if err := c.Clock.validate(); err != nil {
+		return err
+	}
+	if err := c.DurationStyle.validate(); err != nil {
+		return err
+	}
+	if err := c.DayWindow.validate(); err != nil {
+		return err
+	}
+	if c.MergeTolerance != "" {
+		if d, err := time.ParseDuration(c.MergeTolerance); err != nil || d < 0 || d%time.Minute != 0 {
+			return fmt.Errorf("invalid merge_tolerance '%s' (expected whole minutes, e.g. 5m)", c.MergeTolerance)
+		}
+	}
+	if _, err := c.TUI.Theme.Resolve(); err != nil {
+		return err
+	}
+	for _, cal := range c.Calendars {
+		if err := cal.validate(); err != nil {
+			return err
+		}
+	}
+	if err := validateNames("aliases", c.Aliases); err != nil {
+		return err
+	}
+	if err := validateNames("icons", c.Icons); err != nil {
+		return err
+	}
+	var tasks []Task
+	for _, d := range c.Days {
+		tasks = append(tasks, d.Tasks...)
+	}
+	for _, e := range c.Events {
+		tasks = append(tasks, e.Task)
+	}
+	for i, r := range c.Rules {
+		if err := r.validate(c.CycleDays); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		if r.AddTask != nil {
+			tasks = append(tasks, *r.AddTask)
+		}
+	}
+	for _, t := range tasks {
+		for _, tag := range t.Tags {
+			if tag == "" || strings.HasPrefix(tag, "-") {
+				return fmt.Errorf("%s: invalid tag '%s' (must be non-empty and not start with '-')", t.Describe(), tag)
+			}
+		}
+		if t.URL != "" {
+			if u, err := url.Parse(t.URL); err != nil || u.Scheme == "" {
+				return fmt.Errorf("%s: invalid url '%s' (expected an absolute URL such as https://...)", t.Describe(), t.URL)
+			}
+		}
+		if t.Pomodoro == "" {
+			continue
+		}
+		if _, err := pomodoro.Parse(t.Pomodoro); err != nil {
+			return fmt.Errorf("%s: %w", t.Describe(), err)
+		}
+	}
 	// TODO: Validate time formats (HH:MM)
 	return nil
 }
 
-// FindOrCreateDefault finds the default config file, creating it if it doesn't exist.
-// It returns the path to the config file.
-func FindOrCreateDefault() (string, error) {
+// TagColor returns the [tag_colors] color of the first of tags that has one,
+// or "" if none does.
+func (c *Config) TagColor(tags []string) string {
+	for _, t := range tags {
+		if color := c.TagColors[t]; color != "" {
+			return color
+		}
+	}
+	return ""
+}
+
+// ConfigDirEnv names the environment variable that overrides the directory
+// of the default config, e.g. for a portable setup.
+const ConfigDirEnv = "SKED_CONFIG_DIR"
+
+// DefaultPath returns the location of the default config file, whether or not it exists:
+// config.toml in $SKED_CONFIG_DIR if set, otherwise in the sked directory of
+// the user config directory.
+func DefaultPath() (string, error) {
+	if dir := os.Getenv(ConfigDirEnv); dir != "" {
+		dir, err := ExpandTilde(dir)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", ConfigDirEnv, err)
+		}
+		dir, err = filepath.Abs(dir)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "config.toml"), nil
+	}
 	cfgDir, err := os.UserConfigDir()
 	if err != nil {
 		return "", fmt.Errorf("could not find user config directory: %w", err)
 	}
+	return filepath.Join(cfgDir, "sked", "config.toml"), nil
+}
 
-	skedCfgDir := filepath.Join(cfgDir, "sked")
-	configPath := filepath.Join(skedCfgDir, "config.toml")
+// homeRelative returns path as "~/..." if it lies in the home directory, so
+// a config written on one machine reads the same on another, and path
+// unchanged otherwise.
+func homeRelative(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(home, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return path
+	}
+	return "~/" + filepath.ToSlash(rel)
+}
+
+// FindOrCreateDefault finds the default config file, creating it if it doesn't exist.
+// It returns the path to the config file.
+func FindOrCreateDefault() (string, error) {
+	configPath, err := DefaultPath()
+	if err != nil {
+		return "", err
+	}
+	skedCfgDir := filepath.Dir(configPath)
 
 	// Check if the config file already exists
 	if _, err := os.Stat(configPath); err == nil {
@@ -464,24 +1163,31 @@ func FindOrCreateDefault() (string, error) {
 		return "", fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	// Create the default, self-documenting config.toml
+	// Create the default, self-documenting config.toml. csv_path names
+	// sample.csv by its full path, so the file still finds it when copied
+	// elsewhere and passed with -c.
+	csvPath := filepath.Join(skedCfgDir, "sample.csv")
 	tomlContent := `# Welcome to Sked! This is your main configuration file.
 #
 # Sked can read your schedule in two ways:
 #  1. From a simple CSV file (e.g., for a standard weekly schedule).
 #  2. Directly from this TOML file (e.g., for complex, multi-day cycles).
 
+# The syntax version of this file. 'sked migrate' updates older files.
+config_version = 2
+
 # --- Option 1: Using a CSV file (default for new setups) ---
 #
-# Point to a CSV file. The path can be absolute (/path/to/your/file.csv)
-# or relative to this config file's directory.
-# A sample.csv file has been created for you in this directory.
+# Point to a CSV file. The path can be absolute (/path/to/your/file.csv),
+# start with ~/ for your home directory, or be relative to this config
+# file's directory.
+# A sample.csv file has been created for you next to this file.
 #
 # The CSV file should have a header like:
 # Start,End,Mon,Tue,Wed,Thu,Fri,Sat,Sun
 #
 # Tasks named "/" will be ignored and treated as empty time slots.
-csv_path = "sample.csv"
+csv_path = ` + strconv.Quote(homeRelative(csvPath)) + `
 
 # Optional: Configure a temporary/override CSV file.
 # This file is used when running 'sked show tmp'.
@@ -501,12 +1207,15 @@ csv_path = "sample.csv"
 # Then, you can define your schedule cycle and days below.
 #
 # cycle_days: The number of days in your repeating schedule cycle (e.g., 7 for a week, or 6 for a 6-day school cycle).
-# anchor_date: A specific date (YYYY-MM-DD) that corresponds to day 1 of your cycle.
+# anchor_date: A specific date (YYYY-MM-DD) that corresponds to day 0 of your cycle.
 #              This is required for cycles that are not 7 days.
+# anchor_day_id: The cycle day anchor_date was, if not day 0.
 #
 # Example for a 2-day cycle:
 # cycle_days = 2
-# anchor_date = "2025-01-20" # A day that is "Day 1"
+# anchor_date = "2025-01-20" # A day that was "Day 1"
+# anchor_day_id = 1
+# or, as a shorthand for both: anchor = "2025-01-20 = 1"
 
 # "[[day]]" represents a single day in your cycle.
 # "id" is the day number in the cycle (from 1 to cycle_days).
@@ -544,19 +1253,18 @@ csv_path = "sample.csv"
 # end_date = "2025-01-24"
 # is_off = true
 `
-	if err := os.WriteFile(configPath, []byte(tomlContent), 0o644); err != nil {
+	if err := WriteFile(configPath, []byte(tomlContent)); err != nil {
 		return "", fmt.Errorf("failed to write default config.toml: %w", err)
 	}
 
 	// Create the default sample.csv
-	csvPath := filepath.Join(skedCfgDir, "sample.csv")
 	csvContent := `Start,End,Mon,Tue,Wed,Thu,Fri,Sat,Sun
 09:00,09:50,Math,History,Math,History,Math,,
 10:04,11:00,History,Math,History,Math,History,,
 12:00,13:00,Lunch,Lunch,Lunch,Lunch,Lunch,,
 `
 	if _, err := os.Stat(csvPath); os.IsNotExist(err) {
-		if err := os.WriteFile(csvPath, []byte(csvContent), 0o644); err != nil {
+		if err := WriteFile(csvPath, []byte(csvContent)); err != nil {
 			return "", fmt.Errorf("failed to write default sample.csv: %w", err)
 		}
 	}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"testing"
@@ -154,3 +157,97 @@ func TestLoadTmpCSV_EmptyContent(t *testing.T) {
 		t.Errorf("Expected 0 tasks, got %d", len(cfg.Days[0].Tasks))
 	}
 }
+
+func TestValidate_NotifyOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "defaults", cfg: Config{CycleDays: 7}},
+		{name: "valid", cfg: Config{CycleDays: 7, NotifyUrgency: "critical", NotifyTimeout: "10s"}},
+		{name: "bad_urgency", cfg: Config{CycleDays: 7, NotifyUrgency: "urgent"}, wantErr: true},
+		{name: "bad_timeout", cfg: Config{CycleDays: 7, NotifyTimeout: "ten seconds"}, wantErr: true},
+		{name: "suppress_when_idle", cfg: Config{CycleDays: 7, SuppressWhenIdle: "10m", SuppressWhenDND: true, ResendAfterIdle: true}},
+		{name: "bad_suppress_when_idle", cfg: Config{CycleDays: 7, SuppressWhenIdle: "10"}, wantErr: true},
+		{name: "zero_suppress_when_idle", cfg: Config{CycleDays: 7, SuppressWhenIdle: "0s"}, wantErr: true},
+		{name: "pomodoro", cfg: Config{CycleDays: 7, Days: []Day{{ID: 1, Tasks: []Task{{Name: "Deep work", Pomodoro: "25m/5m"}}}}}},
+		{name: "bad_pomodoro", cfg: Config{CycleDays: 7, Days: []Day{{ID: 1, Tasks: []Task{{Name: "Deep work", Pomodoro: "25m"}}}}}, wantErr: true},
+		{name: "day_window", cfg: Config{CycleDays: 7, DayWindow: DayWindows{"default": "08:00-18:00"}}},
+		{name: "bad_day_window", cfg: Config{CycleDays: 7, DayWindow: DayWindows{"default": "8-18"}}, wantErr: true},
+		{name: "inverted_day_window", cfg: Config{CycleDays: 7, DayWindow: DayWindows{"default": "18:00-08:00"}}, wantErr: true},
+		{name: "weekday_day_window", cfg: Config{CycleDays: 7, DayWindow: DayWindows{"sat": "10:00-18:00", "Sonntag": "12:00-16:00"}}},
+		{name: "bad_weekday_day_window", cfg: Config{CycleDays: 7, DayWindow: DayWindows{"sat": "18:00"}}, wantErr: true},
+		{name: "unknown_day_window_key", cfg: Config{CycleDays: 7, DayWindow: DayWindows{"weekend": "10:00-18:00"}}, wantErr: true},
+		{name: "merge_tolerance", cfg: Config{CycleDays: 7, MergeAdjacent: true, MergeTolerance: "5m"}},
+		{name: "bad_merge_tolerance", cfg: Config{CycleDays: 7, MergeAdjacent: true, MergeTolerance: "5"}, wantErr: true},
+		{name: "negative_merge_tolerance", cfg: Config{CycleDays: 7, MergeAdjacent: true, MergeTolerance: "-5m"}, wantErr: true},
+		{name: "seconds_merge_tolerance", cfg: Config{CycleDays: 7, MergeAdjacent: true, MergeTolerance: "90s"}, wantErr: true},
+		{name: "url", cfg: Config{CycleDays: 7, Days: []Day{{ID: 1, Tasks: []Task{{Name: "Lecture", URL: "https://meet.example.com/abc"}}}}}},
+		{name: "relative_url", cfg: Config{CycleDays: 7, Days: []Day{{ID: 1, Tasks: []Task{{Name: "Lecture", URL: "meet.example.com/abc"}}}}}, wantErr: true},
+		{name: "option_url", cfg: Config{CycleDays: 7, Days: []Day{{ID: 1, Tasks: []Task{{Name: "Lecture", URL: "--help"}}}}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_LogsSummary(t *testing.T) {
+	var buf bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "[[day]]\nid = 1\ntasks = [{ name = \"Math\", start = \"09:00\", end = \"10:00\" }, { name = \"Art\", start = \"10:00\", end = \"11:00\" }]\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := Load(path); err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	var rec struct {
+		Msg   string `json:"msg"`
+		Path  string `json:"path"`
+		Days  int    `json:"days"`
+		Tasks int    `json:"tasks"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("Expected one JSON log record, got %q: %v", buf.String(), err)
+	}
+	if rec.Msg != "config loaded" || rec.Path != path || rec.Days != 1 || rec.Tasks != 2 {
+		t.Errorf("Unexpected log record: %+v", rec)
+	}
+}
+
+func TestLoadCSV_Tags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedule.csv")
+	content := "Start,End,Mon,Tags\n09:00,10:00,Report,work; deep\n12:00,13:00,Lunch,\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write CSV: %v", err)
+	}
+	cfg, err := LoadCSV(path, "")
+	if err != nil {
+		t.Fatalf("LoadCSV() returned error: %v", err)
+	}
+	if len(cfg.Days) != 1 || len(cfg.Days[0].Tasks) != 2 {
+		t.Fatalf("Expected one day with 2 tasks (Tags is not a day), got %+v", cfg.Days)
+	}
+	if tags := cfg.Days[0].Tasks[0].Tags; len(tags) != 2 || tags[0] != "work" || tags[1] != "deep" {
+		t.Errorf("Expected [work deep], got %q", tags)
+	}
+	if tags := cfg.Days[0].Tasks[1].Tags; tags != nil {
+		t.Errorf("Expected no tags, got %q", tags)
+	}
+
+	cfg.Days[0].Tasks[1].Tags = []string{"-work"}
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("Expected a tag starting with '-' to be rejected")
+	}
+}
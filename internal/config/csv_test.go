@@ -0,0 +1,194 @@
+package config
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readAllRows reads a CSV file into memory the way LoadCSV did before it
+// streamed, for comparing the two.
+func readAllRows(t *testing.T, path string) []tableRow {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	reader := csv.NewReader(f)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	var rows []tableRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, tableRow{line: line, fields: record})
+	}
+}
+
+func TestLoadCSV_MatchesInMemory(t *testing.T) {
+	content := "# weekly plan\nStart,End,Tags,Mon,Tue,Notes\n" +
+		"09:00,10:00,work; deep,Math,Art,\n\n" +
+		"10:00,11:00,work,Math,,x\n" +
+		",11:00,,Gym,,\n" +
+		"11:00\n" +
+		"12:00,13:00,,Lunch,Lunch,\n"
+	path := writeTemp(t, "w.csv", content)
+
+	got, err := LoadCSV(path, "")
+	if err != nil {
+		t.Fatalf("LoadCSV() returned error: %v", err)
+	}
+	rows := readAllRows(t, path)
+	cols, err := parseTableHeader(rows[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := loadTable(path, cols, rows[1:], "")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if len(got.Days) != 2 || got.Days[0].ID != 1 || len(got.Days[0].Tasks) != 3 || got.Days[1].ID != 2 || len(got.Days[1].Tasks) != 2 {
+		t.Errorf("Expected Monday with 3 tasks and Tuesday with 2, got %+v", got.Days)
+	}
+	if len(got.Warnings) != 3 {
+		t.Errorf("Expected 3 warnings, got %q", warningStrings(got.Warnings))
+	}
+	if want := path + ":3:5"; len(got.Days) == 2 && got.Days[1].Tasks[0].Source.String() != want {
+		t.Errorf("Expected Tuesday's first task from %s, got %s", want, got.Days[1].Tasks[0].Source)
+	}
+}
+
+func TestLoadCSV_CellTimes(t *testing.T) {
+	content := "Start,End,Mon,Tue,Wed,Thu\n" +
+		"09:00,09:50,History,History@10:15-11:05,History @ 9:30 - 10:20,History@10\n" +
+		"11:00,12:00,/@11:30-12:00,/,Coffee @ Joe's,Lab@ 11:00-12:30\n"
+	cfg, err := LoadCSV(writeTemp(t, "w.csv", content), "")
+	if err != nil {
+		t.Fatalf("LoadCSV() returned error: %v", err)
+	}
+	got := map[int][]string{}
+	for _, d := range cfg.Days {
+		for _, task := range d.Tasks {
+			got[d.ID] = append(got[d.ID], task.Name+" "+task.Start+"-"+task.End)
+		}
+	}
+	want := map[int][]string{
+		// The custom time applies to its day only, and to empty slots too
+		1: {"History 09:00-09:50", "/ 11:30-12:00"},
+		2: {"History 10:15-11:05", "/ 11:00-12:00"},
+		// Spaces around the times are allowed; an "@" not followed by a
+		// time is part of the name
+		3: {"History 09:30-10:20", "Coffee @ Joe's 11:00-12:00"},
+		// A bad suffix falls back to the row's times
+		4: {"History 09:00-09:50", "Lab 11:00-12:30"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if w := warningStrings(cfg.Warnings); len(w) != 1 || !strings.HasPrefix(w[0], "w.csv:2:6: cell 'History@10'") {
+		t.Errorf("Expected a warning for Thursday's History, got %q", w)
+	}
+}
+
+// writeLargeCSV writes a weekly table of rows rows with repeated names.
+func writeLargeCSV(t *testing.T, rows int) string {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString("Start,End,Tags,Mon,Tue,Wed,Thu,Fri,Sat,Sun\n")
+	for i := range rows {
+		m := (i * 5) % (24*60 - 5)
+		fmt.Fprintf(&b, "%02d:%02d,%02d:%02d,work;deep,Task %d,Review,Gym,Task %d,Lunch,Read,Task %d\n",
+			m/60, m%60, (m+5)/60, (m+5)%60, i%20, i%7, i%3)
+	}
+	return writeTemp(t, "large.csv", b.String())
+}
+
+func TestLoadCSV_Large(t *testing.T) {
+	if testing.Short() {
+		t.Skip("large fixture")
+	}
+	const rows = 50000
+	path := writeLargeCSV(t, rows)
+
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	cfg, err := LoadCSV(path, "")
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	if err != nil {
+		t.Fatalf("LoadCSV() returned error: %v", err)
+	}
+
+	tasks := 0
+	for _, d := range cfg.Days {
+		tasks += len(d.Tasks)
+	}
+	if tasks != 7*rows {
+		t.Errorf("Expected %d tasks, got %d", 7*rows, tasks)
+	}
+	// 350k tasks take about 90 MB themselves; reading everything into
+	// memory first and regrowing the day slices took over 450 MB. The race
+	// detector inflates both numbers, so only the result is checked there.
+	if !raceEnabled {
+		if alloc := (after.TotalAlloc - before.TotalAlloc) >> 20; alloc > 200 {
+			t.Errorf("Expected at most 200 MB allocated, got %d MB", alloc)
+		}
+		if elapsed > 10*time.Second {
+			t.Errorf("Expected the load to take under 10s, took %v", elapsed)
+		}
+	}
+	if a, b := cfg.Days[1].Tasks[0].Name, cfg.Days[1].Tasks[20].Name; a != b || a != "Task 0" {
+		t.Errorf("Expected repeated names to load alike, got %q and %q", a, b)
+	}
+}
+
+func TestLoadTmpCSV_Streaming(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("Start,End,Task\n")
+	for i := range 1000 {
+		fmt.Fprintf(&b, "%02d:%02d,%02d:%02d,Block %d\n", i/60%24, i%60, i/60%24, i%60, i%4)
+	}
+	b.WriteString(",10:00,No start\n")
+	cfg, err := LoadTmpCSV(writeTemp(t, "tmp.csv", b.String()))
+	if err != nil {
+		t.Fatalf("LoadTmpCSV() returned error: %v", err)
+	}
+	if len(cfg.Days) != 1 || len(cfg.Days[0].Tasks) != 1000 {
+		t.Fatalf("Expected one day with 1000 tasks, got %d days", len(cfg.Days))
+	}
+	if got := warningStrings(cfg.Warnings); !reflect.DeepEqual(got, []string{"tmp.csv:1002:1: row has no start time; skipped"}) {
+		t.Errorf("Unexpected warnings: %q", got)
+	}
+}
+
+func TestReadTmpCSV(t *testing.T) {
+	r := strings.NewReader("Start,End,Task\n09:00,10:00,Math\n10:00,11:00,\n")
+	cfg, err := ReadTmpCSV("<stdin>", r)
+	if err != nil {
+		t.Fatalf("ReadTmpCSV() returned error: %v", err)
+	}
+	if len(cfg.Days) != 1 || len(cfg.Days[0].Tasks) != 1 || cfg.Days[0].Tasks[0].Name != "Math" {
+		t.Fatalf("Expected one day with Math, got %+v", cfg.Days)
+	}
+	if len(cfg.Sources) != 0 {
+		t.Errorf("Expected no sources, got %q", cfg.Sources)
+	}
+	if got := warningStrings(cfg.Warnings); !reflect.DeepEqual(got, []string{"<stdin>:3:3: row has no task name; skipped"}) {
+		t.Errorf("Unexpected warnings: %q", got)
+	}
+}
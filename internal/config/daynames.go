@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weekdayNames lists the full weekday names ParseDayName understands, in
+// English, German, Spanish and French, indexed like time.Weekday
+// (0=Sunday). Accents are folded away, as in the input.
+var weekdayNames = [7][]string{
+	{"sunday", "sonntag", "domingo", "dimanche"},
+	{"monday", "montag", "lunes", "lundi"},
+	{"tuesday", "dienstag", "martes", "mardi"},
+	{"wednesday", "mittwoch", "miercoles", "mercredi"},
+	{"thursday", "donnerstag", "jueves", "jeudi"},
+	{"friday", "freitag", "viernes", "vendredi"},
+	{"saturday", "samstag", "sonnabend", "sabado", "samedi"},
+}
+
+// weekdayShortNames lists the accepted two-letter forms, English and
+// German, which never disagree. Other languages' two-letter forms are left
+// out because they clash with these (Spanish "do" is Sunday, German "do"
+// Thursday).
+var weekdayShortNames = map[string]int{
+	"su": 0, "so": 0,
+	"mo": 1,
+	"tu": 2, "di": 2,
+	"we": 3, "mi": 3,
+	"th": 4, "do": 4,
+	"fr": 5,
+	"sa": 6,
+}
+
+// foldAccents maps the accented letters of the Spanish and French names to
+// plain ones, so "Mié" and "mie" are the same.
+var foldAccents = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "è", "e", "ê", "e")
+
+// ParseDayName converts a day name or a number to a cycle day ID, with
+// 0=Sunday, 1=Monday, ..., 6=Saturday to match time.Weekday().
+//
+// A name is a two-letter form ("Mo", "Di") or at least the first three
+// letters of an English, German, Spanish or French weekday ("Mon",
+// "Montag", "Mié", "lun."), case- and accent-insensitive. Prefixes that fit
+// two different weekdays, such as "Son" (Sonntag or Sonnabend), are
+// rejected rather than guessed. Other names starting with the first three
+// letters of an English weekday ("Weds", "Mon-A") are still accepted.
+//
+// Numbers are day IDs, which agree with ISO weekday numbers for 1 (Monday)
+// to 6 (Saturday); ISO 7 (Sunday) maps to 0. Larger numbers are returned
+// as they are, for custom cycles; day 7 of such a cycle must be written as
+// a TOML integer rather than a string.
+func ParseDayName(name string) (int, error) {
+	name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+	if id, err := strconv.Atoi(name); err == nil {
+		switch {
+		case id < 0:
+			return -1, fmt.Errorf("invalid day number: %d", id)
+		case id == 7:
+			return 0, nil
+		}
+		return id, nil
+	}
+
+	id, err := lookupWeekday(name)
+	if err == nil {
+		return id, nil
+	}
+	// Anything starting like an English weekday, as before the table:
+	// "Weds", "Mon-A"
+	for id, names := range weekdayNames {
+		if strings.HasPrefix(name, names[0][:3]) {
+			return id, nil
+		}
+	}
+	return -1, err
+}
+
+// ParseWeekdayName reads a weekday name like ParseDayName, but not numbers
+// or names that only start like an English weekday, so that words such as
+// "month" in date arguments aren't taken for Monday.
+func ParseWeekdayName(name string) (time.Weekday, bool) {
+	id, err := lookupWeekday(strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), "."))
+	return time.Weekday(id), err == nil
+}
+
+// lookupWeekday finds a lower-case name in weekdayShortNames or as a
+// prefix of weekdayNames.
+func lookupWeekday(name string) (int, error) {
+	name = foldAccents.Replace(name)
+	if id, ok := weekdayShortNames[name]; ok {
+		return id, nil
+	}
+	if len(name) < 3 {
+		return -1, fmt.Errorf("invalid day name: %s", name)
+	}
+	found := -1
+	for id, names := range weekdayNames {
+		for _, full := range names {
+			if !strings.HasPrefix(full, name) {
+				continue
+			}
+			if found != -1 && found != id {
+				return -1, fmt.Errorf("ambiguous day name: %s (could be %s or %s)", name, weekdayNames[found][0], weekdayNames[id][0])
+			}
+			found = id
+		}
+	}
+	if found == -1 {
+		return -1, fmt.Errorf("invalid day name: %s", name)
+	}
+	return found, nil
+}
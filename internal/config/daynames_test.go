@@ -0,0 +1,58 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDayName(t *testing.T) {
+	accepted := map[int][]string{
+		0: {"Sunday", "sun", "Su", "So", "Sonntag", "Sonnt", "Domingo", "dom", "Dimanche", "dim.", "0", "7"},
+		1: {"Monday", "mon", "Mo", "Montag", "Lunes", "lun", "Lundi", "Mon-A", "1"},
+		2: {"Tuesday", "tue", "Tu", "Di", "Dienstag", "Martes", "mar", "Mardi", "2"},
+		3: {"Wednesday", "wed", "We", "Mi", "Mittwoch", "Miércoles", "Mié", "mie", "Mercredi", "mer.", "Weds", "3"},
+		4: {"Thursday", "thu", "Th", "Do", "Donnerstag", "don", "Jueves", "jue", "Jeudi", "jeu", "4"},
+		5: {"Friday", "fri", "Fr", "Freitag", "fre", "Viernes", "vie", "Vendredi", "ven", "5"},
+		6: {"Saturday", "sat", "Sa", "Samstag", "sam", "Sonnabend", "Sábado", "sab", "Samedi", " SAT ", "6"},
+		9: {"9"},
+	}
+	for want, names := range accepted {
+		for _, name := range names {
+			got, err := ParseDayName(name)
+			if err != nil || got != want {
+				t.Errorf("ParseDayName(%q) = %d, %v; want %d", name, got, err, want)
+			}
+		}
+	}
+
+	rejected := []string{
+		"",
+		"m",       // too short
+		"xx",      // unknown two-letter form
+		"son",     // Sonntag or Sonnabend
+		"sonn",    // Sonntag or Sonnabend
+		"Thrsday", // typo
+		"A-Woche", // not a day
+		"-1",      // negative number
+		"lu",      // Spanish/French two-letter forms aren't accepted
+	}
+	for _, name := range rejected {
+		if got, err := ParseDayName(name); err == nil {
+			t.Errorf("ParseDayName(%q) = %d; want an error", name, got)
+		}
+	}
+}
+
+func TestParseWeekdayName(t *testing.T) {
+	for name, want := range map[string]time.Weekday{"monday": time.Monday, "Mié": time.Wednesday, "fri.": time.Friday, "So": time.Sunday} {
+		if got, ok := ParseWeekdayName(name); !ok || got != want {
+			t.Errorf("ParseWeekdayName(%q) = %v, %v; want %v", name, got, ok, want)
+		}
+	}
+	// Names ParseDayName takes only as day IDs or by their English start
+	for _, name := range []string{"1", "7", "month", "Weds", "Mon-A", "son"} {
+		if got, ok := ParseWeekdayName(name); ok {
+			t.Errorf("ParseWeekdayName(%q) = %v; want no weekday", name, got)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultWindowKey is the day_window key used on weekdays without a window
+// of their own.
+const defaultWindowKey = "default"
+
+// DayWindows is the day_window setting: the part of the day free time and
+// utilization are measured against. It is either one window for every day,
+//
+//	day_window = "08:00-22:00"
+//
+// or a table with a default and windows for single weekdays, keyed by any
+// name ParseDayName understands:
+//
+//	day_window.default = "08:00-22:00"
+//	day_window.sat = "10:00-18:00"
+//
+// Weekdays without a window, when there is no default, have none. A nil
+// DayWindows has no window at all.
+type DayWindows map[string]string
+
+// UnmarshalText implements encoding.TextUnmarshaler for the single-window
+// form.
+func (w *DayWindows) UnmarshalText(text []byte) error {
+	*w = DayWindows{defaultWindowKey: string(text)}
+	return nil
+}
+
+// validate checks every window and that every key other than "default" is
+// a weekday.
+func (w DayWindows) validate() error {
+	keys := make([]string, 0, len(w))
+	for key := range w {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if key != defaultWindowKey {
+			if id, err := ParseDayName(key); err != nil || id > 6 {
+				return fmt.Errorf("invalid day_window key '%s' (expected default or a weekday)", key)
+			}
+		}
+		if _, _, err := ParseDayWindow(w[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// window returns the window string for weekday wd, or "" without one.
+func (w DayWindows) window(wd time.Weekday) string {
+	for key, s := range w {
+		if key == defaultWindowKey {
+			continue
+		}
+		if id, err := ParseDayName(key); err == nil && id == int(wd) {
+			return s
+		}
+	}
+	return w[defaultWindowKey]
+}
+
+// On returns the window for date's weekday as times on that date, and
+// false if the date has none. The windows must have been validated.
+func (w DayWindows) On(date time.Time) (from, to time.Time, ok bool) {
+	s := w.window(date.Weekday())
+	if s == "" {
+		return time.Time{}, time.Time{}, false
+	}
+	start, end, err := ParseDayWindow(s)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return timeOnDate(date, start), timeOnDate(date, end), true
+}
+
+// DayWindowOn returns the day window of date, and false if it has none.
+func (c *Config) DayWindowOn(date time.Time) (from, to time.Time, ok bool) {
+	return c.DayWindow.On(date)
+}
+
+// timeOnDate places a validated HH:MM time on date.
+func timeOnDate(date time.Time, hhmm string) time.Time {
+	t, _ := time.Parse("15:04", hhmm)
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, date.Location())
+}
+
+// ParseDayWindow splits a day window such as "08:00-18:00" into its start
+// and end times.
+func ParseDayWindow(s string) (start, end string, err error) {
+	start, end, ok := strings.Cut(s, "-")
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	if !ok {
+		return "", "", fmt.Errorf("invalid day_window '%s' (expected HH:MM-HH:MM)", s)
+	}
+	from, err1 := time.Parse("15:04", start)
+	to, err2 := time.Parse("15:04", end)
+	if err1 != nil || err2 != nil {
+		return "", "", fmt.Errorf("invalid day_window '%s' (expected HH:MM-HH:MM)", s)
+	}
+	if !to.After(from) {
+		return "", "", fmt.Errorf("day_window '%s' must end after it starts", s)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDayWindows_On(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		date    time.Time
+		want    string // "" without a window
+	}{
+		{
+			name:    "single",
+			content: "cycle_days = 7\nday_window = \"08:00-22:00\"\n",
+			date:    time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), // Saturday
+			want:    "08:00-22:00",
+		},
+		{
+			name:    "weekday_override",
+			content: "cycle_days = 7\nday_window.default = \"08:00-22:00\"\nday_window.sat = \"10:00-18:00\"\n",
+			date:    time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC),
+			want:    "10:00-18:00",
+		},
+		{
+			name:    "weekday_falls_back_to_default",
+			content: "cycle_days = 7\nday_window.default = \"08:00-22:00\"\nday_window.sat = \"10:00-18:00\"\n",
+			date:    time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), // Friday
+			want:    "08:00-22:00",
+		},
+		{
+			name:    "weekday_without_default",
+			content: "cycle_days = 7\n[day_window]\nsaturday = \"10:00-18:00\"\n",
+			date:    time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "none",
+			content: "cycle_days = 7\n",
+			date:    time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadTOMLString(t, tt.content)
+			if err != nil {
+				t.Fatalf("Load() returned error: %v", err)
+			}
+			from, to, ok := cfg.DayWindowOn(tt.date)
+			got := ""
+			if ok {
+				got = from.Format("15:04") + "-" + to.Format("15:04")
+				if !sameDay(from, tt.date) {
+					t.Errorf("Expected the window on %s, got %s", tt.date.Format("2006-01-02"), from)
+				}
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDayWindows_InvalidKey(t *testing.T) {
+	cfg, err := loadTOMLString(t, "cycle_days = 7\nday_window.weekend = \"10:00-18:00\"\n")
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected an error for a day_window key that isn't a weekday")
+	}
+}
+
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
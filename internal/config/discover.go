@@ -0,0 +1,82 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ConfigEnv names the environment variable that selects a config file
+// when --config isn't given.
+const ConfigEnv = "SKED_CONFIG"
+
+// ProjectFiles are the names of project-local configs, looked for in the
+// working directory and its parents. The first name found wins within a
+// directory.
+var ProjectFiles = []string{".sked.toml", ".sked.csv"}
+
+// Source says how the config file in use was chosen.
+type Source string
+
+const (
+	SourceFlag    Source = "--config"
+	SourceEnv     Source = "$" + ConfigEnv
+	SourceProject Source = "project file"
+	SourceDefault Source = "default location"
+)
+
+// NotFoundError reports a missing default config that wasn't created
+// automatically.
+type NotFoundError struct {
+	Path string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("no config found at %s; run 'sked init' to create one, or pass --config", e.Path)
+}
+
+// Resolve picks the config file: flag if set, else $SKED_CONFIG, else the
+// nearest project file at or above dir, else DefaultPath. It creates
+// nothing; see FindOrCreateDefault for a missing default.
+func Resolve(flag, dir string) (string, Source, error) {
+	if flag != "" {
+		return flag, SourceFlag, nil
+	}
+	if env := os.Getenv(ConfigEnv); env != "" {
+		path, err := ExpandTilde(env)
+		if err != nil {
+			return "", "", fmt.Errorf("%s: %w", ConfigEnv, err)
+		}
+		return path, SourceEnv, nil
+	}
+	if path := findProjectFile(dir); path != "" {
+		return path, SourceProject, nil
+	}
+	path, err := DefaultPath()
+	return path, SourceDefault, err
+}
+
+// findProjectFile returns the nearest of ProjectFiles in dir or one of its
+// parents, or "" if there is none.
+func findProjectFile(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		for _, name := range ProjectFiles {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	home := setHome(t)
+	t.Setenv(ConfigDirEnv, filepath.Join(home, "xdg"))
+	repo := filepath.Join(home, "src", "repo")
+	work := filepath.Join(repo, "cmd", "tool")
+	if err := os.MkdirAll(work, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	project := filepath.Join(repo, ".sked.toml")
+	if err := os.WriteFile(project, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	envFile := filepath.Join(home, "env.toml")
+
+	tests := []struct {
+		name   string
+		flag   string
+		env    string
+		dir    string
+		want   string
+		source Source
+	}{
+		{name: "flag", flag: "flag.toml", env: envFile, dir: work, want: "flag.toml", source: SourceFlag},
+		{name: "env", env: envFile, dir: work, want: envFile, source: SourceEnv},
+		{name: "env_tilde", env: "~/env.toml", dir: work, want: envFile, source: SourceEnv},
+		{name: "project_parent", dir: work, want: project, source: SourceProject},
+		{name: "project_here", dir: repo, want: project, source: SourceProject},
+		{name: "default", dir: home, want: filepath.Join(home, "xdg", "config.toml"), source: SourceDefault},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ConfigEnv, tt.env)
+			got, source, err := Resolve(tt.flag, tt.dir)
+			if err != nil {
+				t.Fatalf("Resolve() returned error: %v", err)
+			}
+			if got != tt.want || source != tt.source {
+				t.Errorf("Expected %q from %s, got %q from %s", tt.want, tt.source, got, source)
+			}
+		})
+	}
+}
+
+func TestResolve_ProjectFileOrder(t *testing.T) {
+	setHome(t)
+	t.Setenv(ConfigEnv, "")
+	outer := t.TempDir()
+	inner := filepath.Join(outer, "inner")
+	if err := os.Mkdir(inner, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{filepath.Join(outer, ".sked.toml"), filepath.Join(inner, ".sked.csv")} {
+		if err := os.WriteFile(p, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The nearest directory wins, whatever its file's format
+	if got, _, _ := Resolve("", inner); got != filepath.Join(inner, ".sked.csv") {
+		t.Errorf("Expected the inner .sked.csv, got %q", got)
+	}
+	// Within a directory .sked.toml comes first
+	if err := os.WriteFile(filepath.Join(inner, ".sked.toml"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, _, _ := Resolve("", inner); got != filepath.Join(inner, ".sked.toml") {
+		t.Errorf("Expected the inner .sked.toml, got %q", got)
+	}
+	// A directory of that name is not a config
+	if err := os.Mkdir(filepath.Join(outer, "d"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(outer, "d", ".sked.toml"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got, _, _ := Resolve("", filepath.Join(outer, "d")); got != filepath.Join(outer, ".sked.toml") {
+		t.Errorf("Expected the outer .sked.toml, got %q", got)
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DurationStyle selects how durations are displayed: DurationCompact
+// ("1h30m", the default), DurationClock ("1:30") or DurationWords
+// ("1 hour 30 minutes").
+type DurationStyle string
+
+const (
+	DurationCompact DurationStyle = "compact"
+	DurationClock   DurationStyle = "clock"
+	DurationWords   DurationStyle = "words"
+)
+
+func (s DurationStyle) validate() error {
+	switch s {
+	case "", DurationCompact, DurationClock, DurationWords:
+		return nil
+	}
+	return fmt.Errorf("invalid duration_style '%s' (expected compact, clock or words)", s)
+}
+
+// Durations formats displayed durations in Style. They are rounded to whole
+// minutes unless Precise is set, which keeps the seconds.
+type Durations struct {
+	Style   DurationStyle
+	Precise bool
+}
+
+// Format formats d rounded to the nearest minute, e.g. "1h30m" for 89m40s.
+// Negative durations count as zero.
+func (f Durations) Format(d time.Duration) string {
+	if !f.Precise {
+		d = d.Round(time.Minute)
+	}
+	return f.format(d)
+}
+
+// Left formats the time left until something ends, rounded up to the
+// minute, so a running task never shows "0m".
+func (f Durations) Left(d time.Duration) string {
+	if !f.Precise {
+		d = d.Round(time.Second)
+		if rem := d % time.Minute; rem > 0 {
+			d += time.Minute - rem
+		}
+	}
+	return f.format(d)
+}
+
+func (f Durations) format(d time.Duration) string {
+	d = max(d, 0).Truncate(time.Second)
+	h, m, s := int(d/time.Hour), int(d/time.Minute%60), int(d/time.Second%60)
+	switch f.Style {
+	case DurationClock:
+		if f.Precise {
+			return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+		}
+		return fmt.Sprintf("%d:%02d", h, m)
+	case DurationWords:
+		var parts []string
+		for _, u := range []struct {
+			n    int
+			name string
+		}{{h, "hour"}, {m, "minute"}, {s, "second"}} {
+			if u.n == 0 {
+				continue
+			}
+			if u.n == 1 {
+				parts = append(parts, "1 "+u.name)
+			} else {
+				parts = append(parts, fmt.Sprintf("%d %ss", u.n, u.name))
+			}
+		}
+		if len(parts) == 0 {
+			return "0 minutes"
+		}
+		return strings.Join(parts, " ")
+	}
+	// Compact: leading unit unpadded, later ones two digits, trailing
+	// zero units left out
+	switch {
+	case h > 0 && m == 0 && s == 0:
+		return fmt.Sprintf("%dh", h)
+	case h > 0 && s == 0:
+		return fmt.Sprintf("%dh%02dm", h, m)
+	case h > 0:
+		return fmt.Sprintf("%dh%02dm%02ds", h, m, s)
+	case s == 0:
+		return fmt.Sprintf("%dm", m)
+	case m > 0:
+		return fmt.Sprintf("%dm%02ds", m, s)
+	}
+	return fmt.Sprintf("%ds", s)
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDurations_Format(t *testing.T) {
+	tests := []struct {
+		d                      time.Duration
+		compact, clock, words  string
+		pCompact, pClock, pWds string // with Precise
+	}{
+		{0, "0m", "0:00", "0 minutes", "0m", "0:00:00", "0 minutes"},
+		{29 * time.Second, "0m", "0:00", "0 minutes", "29s", "0:00:29", "29 seconds"},
+		{59 * time.Second, "1m", "0:01", "1 minute", "59s", "0:00:59", "59 seconds"},
+		{60 * time.Second, "1m", "0:01", "1 minute", "1m", "0:01:00", "1 minute"},
+		{89*time.Minute + 40*time.Second, "1h30m", "1:30", "1 hour 30 minutes", "1h29m40s", "1:29:40", "1 hour 29 minutes 40 seconds"},
+		{89 * time.Minute, "1h29m", "1:29", "1 hour 29 minutes", "1h29m", "1:29:00", "1 hour 29 minutes"},
+		{65 * time.Minute, "1h05m", "1:05", "1 hour 5 minutes", "1h05m", "1:05:00", "1 hour 5 minutes"},
+		{25 * time.Hour, "25h", "25:00", "25 hours", "25h", "25:00:00", "25 hours"},
+		{-time.Minute, "0m", "0:00", "0 minutes", "0m", "0:00:00", "0 minutes"},
+	}
+	for _, tt := range tests {
+		for _, c := range []struct {
+			f    Durations
+			want string
+		}{
+			{Durations{}, tt.compact},
+			{Durations{Style: DurationCompact}, tt.compact},
+			{Durations{Style: DurationClock}, tt.clock},
+			{Durations{Style: DurationWords}, tt.words},
+			{Durations{Precise: true}, tt.pCompact},
+			{Durations{Style: DurationClock, Precise: true}, tt.pClock},
+			{Durations{Style: DurationWords, Precise: true}, tt.pWds},
+		} {
+			if got := c.f.Format(tt.d); got != c.want {
+				t.Errorf("%+v.Format(%s): Expected %q, got %q", c.f, tt.d, c.want, got)
+			}
+		}
+	}
+}
+
+func TestDurations_Left(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{time.Second, "1m"},
+		{59 * time.Second, "1m"},
+		{60 * time.Second, "1m"},
+		{61 * time.Second, "2m"},
+		{88*time.Minute + 1*time.Second, "1h29m"},
+		{25 * time.Hour, "25h"},
+	}
+	for _, tt := range tests {
+		if got := (Durations{}).Left(tt.d); got != tt.want {
+			t.Errorf("Left(%s): Expected %q, got %q", tt.d, tt.want, got)
+		}
+	}
+	if got := (Durations{Precise: true}).Left(61 * time.Second); got != "1m01s" {
+		t.Errorf("Expected precise seconds, got %q", got)
+	}
+}
+
+func TestValidate_DurationStyle(t *testing.T) {
+	cfg := &Config{CycleDays: 7, DurationStyle: "long"}
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "invalid duration_style 'long'") {
+		t.Errorf("Expected an invalid duration_style error, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HasTasks reports whether any day, event or rule of the config defines a
+// task. Without one, every query comes back empty.
+func (c *Config) HasTasks() bool {
+	for _, d := range c.Days {
+		if len(d.Tasks) > 0 {
+			return true
+		}
+	}
+	if len(c.Events) > 0 {
+		return true
+	}
+	for _, r := range c.Rules {
+		if r.AddTask != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// EmptyWarning explains a schedule without tasks, naming the files it was
+// read from and the header columns that were not recognized as days, which
+// is the usual cause. It returns "" if the schedule has tasks.
+func (c *Config) EmptyWarning() string {
+	if c.HasTasks() {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("the schedule has no tasks")
+	if len(c.Sources) > 0 {
+		fmt.Fprintf(&b, " (read from %s)", strings.Join(c.Sources, ", "))
+	}
+	var headers []string
+	for _, w := range c.Warnings {
+		if w.Header != "" {
+			headers = append(headers, fmt.Sprintf("'%s'", w.Header))
+		}
+	}
+	if len(headers) > 0 {
+		fmt.Fprintf(&b, "; these headers were not recognized as days: %s (expected weekday names like Mon or Monday, or day IDs)", strings.Join(headers, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmptyWarning(t *testing.T) {
+	path := writeTemp(t, "w.csv", "Start,End,A-Woche,B-Woche\n09:00,10:00,Mathe,Kunst\n")
+	cfg, err := LoadCSV(path, "")
+	if err != nil {
+		t.Fatalf("LoadCSV() returned error: %v", err)
+	}
+	if cfg.HasTasks() {
+		t.Fatalf("Expected no tasks, got %+v", cfg.Days)
+	}
+	msg := cfg.EmptyWarning()
+	for _, want := range []string{"no tasks", filepath.Base(path), "'A-Woche', 'B-Woche'"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("Expected the warning to contain %q, got %q", want, msg)
+		}
+	}
+
+	cfg, err = LoadCSV(writeTemp(t, "w.csv", "Start,End,Mon,Notes\n09:00,10:00,Math,\n"), "")
+	if err != nil {
+		t.Fatalf("LoadCSV() returned error: %v", err)
+	}
+	if msg := cfg.EmptyWarning(); msg != "" {
+		t.Errorf("Expected no warning for a schedule with tasks, got %q", msg)
+	}
+
+	events := &Config{CycleDays: 7, Events: []Event{{Task: Task{Name: "Trip", Start: "09:00", End: "17:00"}}}}
+	if !events.HasTasks() {
+		t.Error("Expected events to count as tasks")
+	}
+}
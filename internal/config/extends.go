@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ExpandDayIDs turns every day block with an ids list into one day per ID,
+// each with its own copy of the tasks, in place of the block. An ID listed
+// in ids may not also be defined by another block.
+func (c *Config) ExpandDayIDs() error {
+	defined := make(map[int]bool)
+	for _, d := range c.Days {
+		if len(d.IDs) == 0 {
+			defined[d.ID] = true
+		} else if d.ID != 0 {
+			return fmt.Errorf("day block with ids %s also sets id %d (use one or the other)", formatIDs(d.IDs), d.ID)
+		}
+	}
+	var days []Day
+	for _, d := range c.Days {
+		if len(d.IDs) == 0 {
+			days = append(days, d)
+			continue
+		}
+		for _, id := range d.IDs {
+			if defined[int(id)] {
+				return fmt.Errorf("day %d is defined more than once (ids %s)", id, formatIDs(d.IDs))
+			}
+			defined[int(id)] = true
+			days = append(days, Day{ID: int(id), Tasks: slices.Clone(d.Tasks), Extends: d.Extends})
+		}
+	}
+	c.Days = days
+	return nil
+}
+
+func formatIDs(ids []DayID) string {
+	parts := make([]int, len(ids))
+	for i, id := range ids {
+		parts[i] = int(id)
+	}
+	return strings.ReplaceAll(fmt.Sprint(parts), " ", ", ")
+}
+
+// ResolveExtends replaces the tasks of every day with an extends field by
+// those of the day it extends, combined with its own: a task starting at
+// the same time as one of the parent's replaces it, a "/" task removes the
+// parent's task at its start, and other tasks are appended. Chains are
+// followed; extending a missing day or a cycle of days is an error.
+func (c *Config) ResolveExtends() error {
+	index := make(map[int]int)
+	for i, d := range c.Days {
+		if _, ok := index[d.ID]; !ok {
+			index[d.ID] = i
+		}
+	}
+	resolved := make(map[int][]Task)
+	var resolve func(id int, chain []int) ([]Task, error)
+	resolve = func(id int, chain []int) ([]Task, error) {
+		if tasks, ok := resolved[id]; ok {
+			return tasks, nil
+		}
+		for i, prev := range chain {
+			if prev == id {
+				return nil, fmt.Errorf("days extend each other in a cycle: %s", formatChain(append(chain[i:], id)))
+			}
+		}
+		d := c.Days[index[id]]
+		if d.Extends == nil {
+			resolved[id] = d.Tasks
+			return d.Tasks, nil
+		}
+		parentID := int(*d.Extends)
+		if _, ok := index[parentID]; !ok {
+			return nil, fmt.Errorf("day %d extends day %d, which does not exist", id, parentID)
+		}
+		parent, err := resolve(parentID, append(chain, id))
+		if err != nil {
+			return nil, err
+		}
+		tasks := mergeTasks(parent, d.Tasks)
+		resolved[id] = tasks
+		return tasks, nil
+	}
+	for i := range c.Days {
+		tasks, err := resolve(c.Days[i].ID, nil)
+		if err != nil {
+			return err
+		}
+		if c.Days[i].Extends != nil {
+			c.Days[i].Tasks = tasks
+		}
+	}
+	return nil
+}
+
+// mergeTasks applies the tasks of a child day to a copy of its parent's.
+func mergeTasks(parent, child []Task) []Task {
+	tasks := make([]Task, len(parent))
+	copy(tasks, parent)
+	inherited := make([]bool, len(parent))
+	for i := range inherited {
+		inherited[i] = true
+	}
+	removed := make([]bool, len(parent))
+	for _, t := range child {
+		i := -1
+		for j, p := range tasks[:len(parent)] {
+			if inherited[j] && !removed[j] && p.Start == t.Start {
+				i = j
+				break
+			}
+		}
+		switch {
+		case i < 0:
+			tasks = append(tasks, t)
+		case t.Name == "/":
+			removed[i] = true
+		default:
+			tasks[i] = t
+			inherited[i] = false
+		}
+	}
+	out := tasks[:0]
+	for i, t := range tasks {
+		if i < len(parent) && removed[i] {
+			continue
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+func formatChain(ids []int) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
+	}
+	return strings.Join(parts, " -> ")
+}
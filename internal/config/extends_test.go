@@ -0,0 +1,164 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func loadTOMLString(t *testing.T, content string) (*Config, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return LoadTOML(path)
+}
+
+func TestResolveExtends(t *testing.T) {
+	cfg, err := loadTOMLString(t, `
+[[day]]
+id = 3
+extends = 2
+tasks = [{ name = "Gym", start = "17:00", end = "18:00" }]
+
+[[day]]
+id = 1
+tasks = [
+  { name = "Math", start = "09:00", end = "10:00" },
+  { name = "Art", start = "10:00", end = "11:00" },
+  { name = "Lab", start = "14:00", end = "16:00" },
+]
+
+[[day]]
+id = 2
+extends = "Mon"
+tasks = [
+  { name = "History", start = "14:00", end = "15:00" },
+  { name = "/", start = "10:00", end = "11:00" },
+  { name = "Music", start = "12:00", end = "13:00" },
+]
+`)
+	if err != nil {
+		t.Fatalf("LoadTOML() returned error: %v", err)
+	}
+
+	want := map[int]string{
+		1: "09:00 Math, 10:00 Art, 14:00 Lab",
+		2: "09:00 Math, 14:00 History, 12:00 Music",
+		// Chains resolve through day 2
+		3: "09:00 Math, 14:00 History, 12:00 Music, 17:00 Gym",
+	}
+	for _, d := range cfg.Days {
+		var got []string
+		for _, task := range d.Tasks {
+			got = append(got, task.Start+" "+task.Name)
+		}
+		if strings.Join(got, ", ") != want[d.ID] {
+			t.Errorf("Day %d: Expected %s, got %s", d.ID, want[d.ID], strings.Join(got, ", "))
+		}
+	}
+}
+
+func TestResolveExtends_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "missing",
+			content: "[[day]]\nid = 1\nextends = 5\n",
+			want:    "day 1 extends day 5, which does not exist",
+		},
+		{
+			name:    "cycle",
+			content: "[[day]]\nid = 1\nextends = 2\n[[day]]\nid = 2\nextends = 3\n[[day]]\nid = 3\nextends = 1\n",
+			want:    "days extend each other in a cycle: 1 -> 2 -> 3 -> 1",
+		},
+		{
+			name:    "self",
+			content: "[[day]]\nid = 4\nextends = 4\n",
+			want:    "days extend each other in a cycle: 4 -> 4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadTOMLString(t, tt.content)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Expected error %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestExpandDayIDs(t *testing.T) {
+	cfg, err := loadTOMLString(t, `
+[[day]]
+ids = [1, "Tue", 3]
+tasks = [{ name = "Math", start = "09:00", end = "10:00" }]
+
+[[day]]
+id = 5
+tasks = [{ name = "Gym", start = "17:00", end = "18:00" }]
+
+[[day]]
+id = 4
+extends = 3
+tasks = [{ name = "Art", start = "10:00", end = "11:00" }]
+`)
+	if err != nil {
+		t.Fatalf("LoadTOML() returned error: %v", err)
+	}
+	want := map[int]string{1: "Math", 2: "Math", 3: "Math", 4: "Math, Art", 5: "Gym"}
+	if len(cfg.Days) != len(want) {
+		t.Fatalf("Expected %d days, got %+v", len(want), cfg.Days)
+	}
+	for _, d := range cfg.Days {
+		var got []string
+		for _, task := range d.Tasks {
+			got = append(got, task.Name)
+		}
+		if strings.Join(got, ", ") != want[d.ID] {
+			t.Errorf("Day %d: Expected %s, got %s", d.ID, want[d.ID], strings.Join(got, ", "))
+		}
+	}
+	// Expanded days don't share their task slices
+	cfg.Days[0].Tasks[0].Name = "Changed"
+	if cfg.Days[1].Tasks[0].Name != "Math" {
+		t.Error("Expected expanded days to have their own tasks")
+	}
+}
+
+func TestExpandDayIDs_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "scalar_elsewhere",
+			content: "[[day]]\nid = 2\n[[day]]\nids = [1, 2]\n",
+			want:    "day 2 is defined more than once (ids [1, 2])",
+		},
+		{
+			name:    "two_lists",
+			content: "[[day]]\nids = [1, 2]\n[[day]]\nids = [3, 1]\n",
+			want:    "day 1 is defined more than once (ids [3, 1])",
+		},
+		{
+			name:    "id_and_ids",
+			content: "[[day]]\nid = 3\nids = [1, 2]\n",
+			want:    "day block with ids [1, 2] also sets id 3 (use one or the other)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadTOMLString(t, tt.content)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Expected error %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ParseInline builds a config for today only, like LoadTmpCSV, from a
+// schedule such as "09:00-10:00 Math; 10:05-11:00 History @Room 4".
+// Segments are separated by semicolons or newlines and read
+// "HH:MM-HH:MM name", optionally followed by "@location". Blank segments
+// are ignored. Errors name the offending segment and its column.
+func ParseInline(s string) (*Config, error) {
+	var tasks []Task
+	n, offset := 0, 0
+	for seg := range strings.FieldsFuncSeq(s, func(r rune) bool { return r == ';' || r == '\n' }) {
+		offset += strings.Index(s[offset:], seg)
+		column := offset + len(seg) - len(strings.TrimLeft(seg, " \t\r")) + 1
+		offset += len(seg)
+		seg = strings.TrimSpace(seg)
+		if seg == "" {
+			continue
+		}
+		n++
+		t, err := parseInlineTask(seg)
+		if err != nil {
+			return nil, fmt.Errorf("inline schedule segment %d (column %d) %q: %w", n, column, seg, err)
+		}
+		line := strings.Count(s[:column-1], "\n") + 1
+		t.Source = Origin{File: "<inline>", Line: line, Column: column - strings.LastIndex(s[:column-1], "\n") - 1}
+		tasks = append(tasks, t)
+	}
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("inline schedule has no tasks (expected e.g. \"09:00-10:00 Math\")")
+	}
+	today := startOfToday()
+	return &Config{
+		CycleDays: 7,
+		Days:      []Day{{ID: int(today.Weekday()), Tasks: tasks}},
+		TmpDate:   today,
+	}, nil
+}
+
+// parseInlineTask parses one "HH:MM-HH:MM name [@location]" segment.
+func parseInlineTask(seg string) (Task, error) {
+	span, rest, _ := strings.Cut(seg, " ")
+	start, end, ok := strings.Cut(span, "-")
+	if !ok {
+		return Task{}, fmt.Errorf("expected a time range such as 09:00-10:00, got '%s'", span)
+	}
+	for _, hm := range []string{start, end} {
+		if _, err := time.Parse("15:04", hm); err != nil {
+			return Task{}, fmt.Errorf("invalid time '%s' (expected HH:MM)", hm)
+		}
+	}
+	t := Task{Start: start, End: end}
+	t.Name = strings.TrimSpace(rest)
+	if i := strings.LastIndex(t.Name, "@"); i >= 0 {
+		t.Location = strings.TrimSpace(t.Name[i+1:])
+		t.Name = strings.TrimSpace(t.Name[:i])
+		if t.Location == "" {
+			return Task{}, fmt.Errorf("'@' must be followed by a location")
+		}
+	}
+	if t.Name == "" {
+		return Task{}, fmt.Errorf("missing task name after '%s'", span)
+	}
+	return t, nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseInline(t *testing.T) {
+	cfg, err := ParseInline("09:00-10:00 Math; 10:05-11:00 History @ Room 4\n\n 13:00-14:00 Lab @B2;")
+	if err != nil {
+		t.Fatalf("ParseInline failed: %v", err)
+	}
+	if len(cfg.Days) != 1 {
+		t.Fatalf("Expected 1 day, got %d", len(cfg.Days))
+	}
+	if want := int(time.Now().Weekday()); cfg.Days[0].ID != want {
+		t.Errorf("Expected day %d, got %d", want, cfg.Days[0].ID)
+	}
+	if y, m, d := time.Now().Date(); cfg.TmpDate != time.Date(y, m, d, 0, 0, 0, 0, time.Local) {
+		t.Errorf("Expected the schedule to be for today only, got TmpDate %v", cfg.TmpDate)
+	}
+	want := []Task{
+		{Name: "Math", Start: "09:00", End: "10:00", Source: Origin{File: "<inline>", Line: 1, Column: 1}},
+		{Name: "History", Start: "10:05", End: "11:00", Location: "Room 4", Source: Origin{File: "<inline>", Line: 1, Column: 19}},
+		{Name: "Lab", Start: "13:00", End: "14:00", Location: "B2", Source: Origin{File: "<inline>", Line: 3, Column: 2}},
+	}
+	if !reflect.DeepEqual(cfg.Days[0].Tasks, want) {
+		t.Errorf("Expected tasks %+v, got %+v", want, cfg.Days[0].Tasks)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected a valid config, got %v", err)
+	}
+}
+
+func TestParseInline_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "empty", input: " ; \n", want: "has no tasks"},
+		{name: "no_range", input: "09:00-10:00 Math; 11:00 History", want: `segment 2 (column 19) "11:00 History": expected a time range`},
+		{name: "bad_time", input: "25:00-26:00 Math", want: `segment 1 (column 1) "25:00-26:00 Math": invalid time '25:00'`},
+		{name: "no_name", input: "09:00-10:00 Math;\n  10:00-11:00", want: `segment 2 (column 21) "10:00-11:00": missing task name`},
+		{name: "no_location", input: "09:00-10:00 Math @", want: "must be followed by a location"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseInline(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
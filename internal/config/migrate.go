@@ -0,0 +1,263 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// CurrentVersion is the newest config_version this build of sked reads and
+// writes. Configs without config_version are version 1.
+const CurrentVersion = 2
+
+// migration upgrades a config from version from to from+1. upgrade applies
+// the change to the decoded document, as LoadTOML reads the file, and
+// reports whether anything changed; rewrite applies it to the lines of the
+// file for 'sked migrate', keeping comments and layout.
+type migration struct {
+	from    int
+	summary string
+	upgrade func(doc map[string]any) bool
+	rewrite func(lines []string) ([]string, error)
+}
+
+var migrations = []migration{
+	{
+		from:    1,
+		summary: "single-date [[override]] tables get an explicit end_date",
+		upgrade: rangeOverrides,
+		rewrite: rewriteRangeOverrides,
+	},
+}
+
+// documentVersion returns the config_version of a decoded document.
+func documentVersion(doc map[string]any) (int, error) {
+	v, ok := doc["config_version"]
+	if !ok {
+		return 1, nil
+	}
+	n, ok := v.(int64)
+	if !ok || n < 1 {
+		return 0, fmt.Errorf("invalid config_version %v (expected a positive number)", v)
+	}
+	if n > CurrentVersion {
+		return 0, fmt.Errorf("config_version %d is newer than this sked supports (up to %d); please upgrade sked", n, CurrentVersion)
+	}
+	return int(n), nil
+}
+
+// upgrade returns the TOML text data migrated in memory to CurrentVersion,
+// and whether any migration changed it. Unchanged text is returned as it is,
+// so positions in decode errors still match the file.
+func upgrade(data []byte) ([]byte, bool, error) {
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return nil, false, err
+	}
+	version, err := documentVersion(doc)
+	if err != nil {
+		return nil, false, err
+	}
+	changed := false
+	for _, m := range migrations {
+		if m.from >= version && m.upgrade(doc) {
+			changed = true
+		}
+	}
+	if !changed {
+		return data, false, nil
+	}
+	doc["config_version"] = int64(CurrentVersion)
+	out, err := toml.Marshal(doc)
+	return out, true, err
+}
+
+// decodeTOML migrates data and decodes it into cfg. Unknown keys become
+// warnings positioned in data, even if migrating rewrote the text.
+func decodeTOML(path string, data []byte, cfg *Config) error {
+	migrated, changed, err := upgrade(data)
+	if err != nil {
+		return err
+	}
+	cfg.CycleDays = 7
+	if err := strictDecode(path, migrated, cfg); err != nil {
+		return err
+	}
+	if changed && len(cfg.Warnings) > 0 {
+		// Positions in the migrated text mean nothing to the user
+		var orig Config
+		_ = strictDecode(path, data, &orig)
+		for i := range cfg.Warnings {
+			cfg.Warnings[i].Line, cfg.Warnings[i].Column = 0, 0
+			for _, o := range orig.Warnings {
+				if o.Key == cfg.Warnings[i].Key {
+					cfg.Warnings[i].Line, cfg.Warnings[i].Column = o.Line, o.Column
+					break
+				}
+			}
+		}
+	}
+	cfg.ConfigVersion = CurrentVersion
+	return nil
+}
+
+// strictDecode decodes data into cfg, turning unknown keys into warnings.
+func strictDecode(path string, data []byte, cfg *Config) error {
+	dec := toml.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return cfg.unknownKeys(path, err)
+	}
+	return nil
+}
+
+// rangeOverrides copies the date of every override without an end_date,
+// other than those until further notice.
+func rangeOverrides(doc map[string]any) bool {
+	overrides, _ := doc["override"].([]any)
+	changed := false
+	for _, o := range overrides {
+		t, ok := o.(map[string]any)
+		if !ok {
+			continue
+		}
+		if _, ok := t["end_date"]; ok {
+			continue
+		}
+		if open, _ := t["until_further_notice"].(bool); open {
+			continue
+		}
+		if date, ok := t["date"]; ok {
+			t["end_date"] = date
+			changed = true
+		}
+	}
+	return changed
+}
+
+var dateKey = regexp.MustCompile(`^(\s*)date\s*=`)
+
+// rewriteRangeOverrides adds an end_date line below the date of every
+// [[override]] table without one.
+func rewriteRangeOverrides(lines []string) ([]string, error) {
+	blocks, err := overrideBlocks(lines)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		b := blocks[i]
+		if b.override.EndDateStr != "" || b.override.UntilFurtherNotice {
+			continue
+		}
+		for j := b.start + 1; j < b.end; j++ {
+			if m := dateKey.FindStringSubmatch(lines[j]); m != nil {
+				line := fmt.Sprintf("%send_date = %q", m[1], b.override.DateStr)
+				lines = append(lines[:j+1], append([]string{line}, lines[j+1:]...)...)
+				break
+			}
+		}
+	}
+	return lines, nil
+}
+
+var versionKey = regexp.MustCompile(`^config_version\s*=`)
+
+// setVersion replaces the config_version line, or adds one above the first
+// key or table.
+func setVersion(lines []string) []string {
+	line := fmt.Sprintf("config_version = %d", CurrentVersion)
+	for i, l := range lines {
+		if versionKey.MatchString(strings.TrimSpace(l)) {
+			lines[i] = line
+			return lines
+		}
+	}
+	for i, l := range lines {
+		if t := strings.TrimSpace(l); t != "" && !strings.HasPrefix(t, "#") {
+			return append(lines[:i], append([]string{line, ""}, lines[i:]...)...)
+		}
+	}
+	return append(lines, line)
+}
+
+// MigrateResult describes what Migrate did.
+type MigrateResult struct {
+	From, To int
+	Backup   string   // backup of the old file; "" if none was kept
+	Changes  []string // summaries of the migrations that changed lines
+}
+
+// Migrate rewrites the TOML config at path in the syntax of CurrentVersion.
+// The rest of the file, including comments, is left untouched, and the
+// write goes through writeAtomic under the file's lock, which keeps the old
+// file as path.bak unless backups = 0. Migrate refuses to write if the
+// rewritten file would load differently from the old one.
+func Migrate(path string) (MigrateResult, error) {
+	unlock, err := lockFile(path)
+	if err != nil {
+		return MigrateResult{}, err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return MigrateResult{}, err
+	}
+	var doc map[string]any
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return MigrateResult{}, err
+	}
+	version, err := documentVersion(doc)
+	if err != nil {
+		return MigrateResult{}, err
+	}
+	res := MigrateResult{From: version, To: CurrentVersion}
+	if version == CurrentVersion {
+		return res, nil
+	}
+
+	lines, err := readLines(path)
+	if err != nil {
+		return MigrateResult{}, err
+	}
+	for _, m := range migrations {
+		if m.from < version {
+			continue
+		}
+		prev := slices.Clone(lines)
+		if lines, err = m.rewrite(lines); err != nil {
+			return MigrateResult{}, err
+		}
+		if !slices.Equal(prev, lines) {
+			res.Changes = append(res.Changes, m.summary)
+		}
+	}
+	lines = setVersion(lines)
+
+	var before, after Config
+	if err := decodeTOML(path, data, &before); err != nil {
+		return MigrateResult{}, err
+	}
+	content := []byte(strings.Join(lines, "\n") + "\n")
+	if err := decodeTOML(path, content, &after); err != nil {
+		return MigrateResult{}, err
+	}
+	before.Warnings, after.Warnings = nil, nil
+	if !reflect.DeepEqual(before, after) {
+		return MigrateResult{}, fmt.Errorf("could not migrate %s automatically: the rewritten file would load differently; set config_version = %d and update it by hand", path, CurrentVersion)
+	}
+
+	if err := writeLines(path, lines); err != nil {
+		return MigrateResult{}, err
+	}
+	if backupCount(path, content) > 0 {
+		res.Backup = backupName(path, 0)
+	}
+	return res, nil
+}
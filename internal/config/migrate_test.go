@@ -0,0 +1,203 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const v1Config = `# My schedule
+cycle_days = 7
+
+[[day]]
+id = 1
+tasks = [{ name = "Math", start = "09:00", end = "10:00" }]
+
+# Holiday
+[[override]]
+  date = "2025-01-02" # New year
+  is_off = true
+
+[[override]]
+date = "2025-07-01"
+end_date = "2025-07-14"
+is_off = true
+`
+
+const v2Config = `# My schedule
+config_version = 2
+
+cycle_days = 7
+
+[[day]]
+id = 1
+tasks = [{ name = "Math", start = "09:00", end = "10:00" }]
+
+# Holiday
+[[override]]
+  date = "2025-01-02" # New year
+  end_date = "2025-01-02"
+  is_off = true
+
+[[override]]
+date = "2025-07-01"
+end_date = "2025-07-14"
+is_off = true
+`
+
+func TestLoadTOML_MigratesInMemory(t *testing.T) {
+	cfg, err := loadTOMLString(t, v1Config)
+	if err != nil {
+		t.Fatalf("LoadTOML() returned error: %v", err)
+	}
+	if cfg.ConfigVersion != CurrentVersion {
+		t.Errorf("Expected config_version %d, got %d", CurrentVersion, cfg.ConfigVersion)
+	}
+	if o := cfg.Overrides[0]; o.EndDateStr != "2025-01-02" || o.IsRange() {
+		t.Errorf("Expected a single-date override with an explicit end_date, got %+v", o)
+	}
+}
+
+func TestLoadTOML_MigratedWarningPositions(t *testing.T) {
+	cfg, err := loadTOMLString(t, "[[override]]\ndate = '2025-01-02'\nof = true\n")
+	if err != nil {
+		t.Fatalf("LoadTOML() returned error: %v", err)
+	}
+	if len(cfg.Warnings) != 1 || cfg.Warnings[0].Line != 3 || cfg.Warnings[0].Key != "override.of" {
+		t.Errorf("Expected the unknown key at line 3 of the file, got %+v", cfg.Warnings)
+	}
+}
+
+func TestLoadTOML_NewerVersion(t *testing.T) {
+	_, err := loadTOMLString(t, "config_version = 99\n")
+	if err == nil || !strings.Contains(err.Error(), "please upgrade sked") {
+		t.Errorf("Expected an upgrade error, got %v", err)
+	}
+	_, err = loadTOMLString(t, "config_version = 'two'\n")
+	if err == nil || !strings.Contains(err.Error(), "invalid config_version") {
+		t.Errorf("Expected an invalid config_version error, got %v", err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(v1Config), 0600); err != nil {
+		t.Fatal(err)
+	}
+	before, err := LoadTOML(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Migrate(path)
+	if err != nil {
+		t.Fatalf("Migrate() returned error: %v", err)
+	}
+	if res.From != 1 || res.To != CurrentVersion || len(res.Changes) != 1 {
+		t.Errorf("Unexpected result %+v", res)
+	}
+	if res.Backup != path+".bak" {
+		t.Errorf("Expected the rotated backup, got %s", res.Backup)
+	}
+	if backup, err := os.ReadFile(res.Backup); err != nil || string(backup) != v1Config {
+		t.Errorf("Expected the backup to hold the old file, got %q (%v)", backup, err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != v2Config {
+		t.Errorf("Expected:\n%s\ngot:\n%s", v2Config, got)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the file mode to be kept, got %v (%v)", info.Mode(), err)
+	}
+
+	after, err := LoadTOML(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The day moved down two lines
+	before.Days[0].Tasks[0].Source.Line += 2
+	if !reflect.DeepEqual(before.Overrides, after.Overrides) || !reflect.DeepEqual(before.Days, after.Days) {
+		t.Errorf("Expected the migrated file to load the same config")
+	}
+
+	// Migrating again is a no-op
+	res, err = Migrate(path)
+	if err != nil || res.Backup != "" || res.From != CurrentVersion {
+		t.Errorf("Expected nothing to migrate, got %+v (%v)", res, err)
+	}
+}
+
+func TestMigrate_NoBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(path, []byte("backups = 0\n"+v1Config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := Migrate(path)
+	if err != nil {
+		t.Fatalf("Migrate() returned error: %v", err)
+	}
+	if res.Backup != "" || res.From != 1 {
+		t.Errorf("Expected a migration without a backup, got %+v", res)
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 1 {
+		t.Errorf("Expected only config.toml with backups = 0, got %v (%v)", entries, err)
+	}
+}
+
+func TestMigrate_OnlyVersion(t *testing.T) {
+	// Nothing but the missing config_version to change
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("cycle_days = 7\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := Migrate(path)
+	if err != nil {
+		t.Fatalf("Migrate() returned error: %v", err)
+	}
+	if res.From != 1 || len(res.Changes) != 0 {
+		t.Errorf("Expected a migration without changes, got %+v", res)
+	}
+}
+
+func TestMigrate_RefusesLossyRewrite(t *testing.T) {
+	// Inline overrides aren't [[override]] tables, so the rewrite misses them
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "override = [{ date = '2025-01-02', is_off = true }]\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Migrate(path)
+	if err == nil || !strings.Contains(err.Error(), "would load differently") {
+		t.Fatalf("Expected Migrate to refuse, got %v", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != content {
+		t.Errorf("Expected the file to be untouched, got %q", got)
+	}
+	if matches, _ := filepath.Glob(path + ".*.bak"); len(matches) != 0 {
+		t.Errorf("Expected no backup, got %v", matches)
+	}
+}
+
+func TestSampleConfigIsCurrent(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "sample_config.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadTOMLString(t, string(data))
+	if err != nil {
+		t.Fatalf("sample_config.toml failed to load: %v", err)
+	}
+	if len(cfg.Warnings) > 0 {
+		t.Errorf("Expected no warnings, got %v", cfg.Warnings)
+	}
+	if !strings.Contains(string(data), fmt.Sprintf("config_version = %d", CurrentVersion)) {
+		t.Errorf("Expected sample_config.toml to be at config_version %d", CurrentVersion)
+	}
+}
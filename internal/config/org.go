@@ -0,0 +1,206 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OrgSchedule is the schedule read from an org-mode file.
+type OrgSchedule struct {
+	Days      []Day
+	Events    []Event
+	Overrides []Override
+	Warnings  []OrgWarning
+}
+
+// OrgWarning describes an org entry that was skipped.
+type OrgWarning struct {
+	Line    int
+	Heading string
+	Reason  string
+}
+
+func (w OrgWarning) String() string {
+	return fmt.Sprintf("line %d '%s': %s", w.Line, w.Heading, w.Reason)
+}
+
+// OrgOffTag marks headlines whose dates are off days.
+const OrgOffTag = "off"
+
+var (
+	orgHeadline = regexp.MustCompile(`^\*+\s+(.*)$`)
+	orgTags     = regexp.MustCompile(`\s+(:[^\s:]+(?::[^\s:]+)*:)\s*$`)
+	orgKeyword  = regexp.MustCompile(`^(?:TODO|NEXT|DONE)\s+`)
+	orgPriority = regexp.MustCompile(`^\[#[A-Z0-9]\]\s+`)
+	// <2025-03-04 Tue 09:00-10:00 +1w>, optionally followed by --<end date>
+	orgTimestamp = regexp.MustCompile(`<(\d{4}-\d{2}-\d{2})(?:\s+[^\s\d>+.-]+)?(?:\s+(\d{1,2}:\d{2})(?:-(\d{1,2}:\d{2}))?)?(?:\s+([.+]?\+\d+[hdwmy]))?(?:\s+--?\d+[hdwmy])?>(?:--<(\d{4}-\d{2}-\d{2})[^>]*>)?`)
+)
+
+// orgEntry is a headline with the first active timestamp of its headline
+// or body.
+type orgEntry struct {
+	heading  string
+	tags     []string
+	line     int // line of the timestamp
+	stamp    []string
+	hasStamp bool
+}
+
+// LoadOrg reads the schedule from an org-mode file. Headlines with an active
+// timestamp repeating weekly (+1w) become tasks of the timestamp's weekday,
+// non-repeating timestamps become events on their date, and headlines tagged
+// :off: become off-day overrides, spanning a date range if the timestamp
+// has one. Other tags become task tags. Entries that can't be represented
+// are skipped with a warning.
+func LoadOrg(path string) (*OrgSchedule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFile(f, &err)
+
+	var entries []orgEntry
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if m := orgHeadline.FindStringSubmatch(line); m != nil {
+			entries = append(entries, parseOrgHeadline(m[1], n))
+			continue
+		}
+		if len(entries) == 0 || entries[len(entries)-1].hasStamp {
+			continue
+		}
+		if m := orgTimestamp.FindStringSubmatch(line); m != nil {
+			e := &entries[len(entries)-1]
+			e.stamp, e.hasStamp, e.line = m, true, n
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	org := &OrgSchedule{}
+	weekly := make(map[int][]Task)
+	for _, e := range entries {
+		if !e.hasStamp {
+			continue
+		}
+		warn := func(format string, args ...any) {
+			org.Warnings = append(org.Warnings, OrgWarning{Line: e.line, Heading: e.heading, Reason: fmt.Sprintf(format, args...)})
+		}
+		date, start, end, repeater, endDate := e.stamp[1], e.stamp[2], e.stamp[3], e.stamp[4], e.stamp[5]
+		d, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			warn("invalid date %s", date)
+			continue
+		}
+
+		tags, off := orgTaskTags(e.tags)
+		if off {
+			if repeater != "" {
+				warn("unsupported repeater %s on an :off: entry", repeater)
+				continue
+			}
+			if endDate == "" {
+				endDate = date
+			}
+			org.Overrides = append(org.Overrides, Override{DateStr: date, EndDateStr: endDate, IsOff: true, Note: e.heading})
+			continue
+		}
+		if endDate != "" {
+			warn("date ranges are only supported on :off: entries")
+			continue
+		}
+		if start == "" || end == "" {
+			warn("a start and end time are required, e.g. <%s 09:00-10:00>", date)
+			continue
+		}
+		task := Task{Name: e.heading, Start: padClock(start), End: padClock(end), Tags: tags, Source: Origin{File: path, Line: e.line}}
+		switch strings.TrimLeft(repeater, ".+") {
+		case "":
+			org.Events = append(org.Events, Event{DateStr: date, Task: task})
+		case "1w":
+			id := int(d.Weekday())
+			weekly[id] = append(weekly[id], task)
+		default:
+			warn("unsupported repeater %s (only +1w is supported)", repeater)
+		}
+	}
+
+	for id, tasks := range weekly {
+		org.Days = append(org.Days, Day{ID: id, Tasks: tasks})
+	}
+	sort.Slice(org.Days, func(i, j int) bool { return org.Days[i].ID < org.Days[j].ID })
+	return org, nil
+}
+
+// parseOrgHeadline splits the text after the stars into the heading, its
+// tags and a timestamp written in the headline itself.
+func parseOrgHeadline(text string, line int) orgEntry {
+	e := orgEntry{line: line}
+	if m := orgTags.FindStringSubmatchIndex(text); m != nil {
+		e.tags = strings.Split(strings.Trim(text[m[2]:m[3]], ":"), ":")
+		text = text[:m[0]]
+	}
+	if m := orgTimestamp.FindStringSubmatchIndex(text); m != nil {
+		e.stamp = make([]string, len(m)/2)
+		for i := range e.stamp {
+			if m[2*i] >= 0 {
+				e.stamp[i] = text[m[2*i]:m[2*i+1]]
+			}
+		}
+		e.hasStamp = true
+		text = text[:m[0]] + text[m[1]:]
+	}
+	text = orgKeyword.ReplaceAllString(strings.TrimSpace(text), "")
+	text = orgPriority.ReplaceAllString(text, "")
+	e.heading = strings.Join(strings.Fields(text), " ")
+	return e
+}
+
+// orgTaskTags returns tags without the off tag, and whether it was present.
+func orgTaskTags(tags []string) ([]string, bool) {
+	var out []string
+	off := false
+	for _, t := range tags {
+		if t == OrgOffTag {
+			off = true
+			continue
+		}
+		out = append(out, t)
+	}
+	return out, off
+}
+
+// padClock turns "9:00" into "09:00".
+func padClock(s string) string {
+	if len(s) == 4 {
+		return "0" + s
+	}
+	return s
+}
+
+// merge adds the tasks, events and overrides read from an org file. Tasks
+// join the day with the same ID, after the tasks defined in the TOML.
+func (c *Config) merge(org *OrgSchedule) {
+	for _, od := range org.Days {
+		found := false
+		for i := range c.Days {
+			if c.Days[i].ID == od.ID {
+				c.Days[i].Tasks = append(c.Days[i].Tasks, od.Tasks...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			c.Days = append(c.Days, od)
+		}
+	}
+	c.Events = append(c.Events, org.Events...)
+	c.Overrides = append(c.Overrides, org.Overrides...)
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadOrg_MatchesTOML(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	org, err := filepath.Abs(filepath.Join("testdata", "schedule.org"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("org_path = '"+org+"'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadTOML(path)
+	if err != nil {
+		t.Fatalf("LoadTOML() with org_path returned error: %v", err)
+	}
+	want, err := LoadTOML(filepath.Join("testdata", "schedule_org.toml"))
+	if err != nil {
+		t.Fatalf("LoadTOML() returned error: %v", err)
+	}
+
+	clearOrigins(got)
+	clearOrigins(want)
+	if !reflect.DeepEqual(got.Days, want.Days) {
+		t.Errorf("Expected days %+v, got %+v", want.Days, got.Days)
+	}
+	if !reflect.DeepEqual(got.Events, want.Events) {
+		t.Errorf("Expected events %+v, got %+v", want.Events, got.Events)
+	}
+	if !reflect.DeepEqual(got.Overrides, want.Overrides) {
+		t.Errorf("Expected overrides %+v, got %+v", want.Overrides, got.Overrides)
+	}
+	if len(got.Sources) != 2 || got.Sources[1] != org {
+		t.Errorf("Expected the org file among the sources, got %v", got.Sources)
+	}
+	if len(got.Warnings) != 2 || got.Warnings[0].File != org || got.Warnings[0].Line != 10 {
+		t.Errorf("Expected the skipped org entries as warnings, got %v", got.Warnings)
+	}
+}
+
+func TestLoadOrg_Warnings(t *testing.T) {
+	org, err := LoadOrg(filepath.Join("testdata", "schedule.org"))
+	if err != nil {
+		t.Fatalf("LoadOrg() returned error: %v", err)
+	}
+	want := []OrgWarning{
+		{Line: 10, Heading: "Daily review", Reason: "unsupported repeater +1d (only +1w is supported)"},
+		{Line: 16, Heading: "All-day fair", Reason: "a start and end time are required, e.g. <2025-03-13 09:00-10:00>"},
+	}
+	if !reflect.DeepEqual(org.Warnings, want) {
+		t.Errorf("Expected warnings %v, got %v", want, org.Warnings)
+	}
+}
+
+func TestLoadTOML_OrgPathRequiresWeek(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "cycle_days = 5\nanchor_date = '2025-01-06'\norg_path = 'schedule.org'\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTOML(path); err == nil {
+		t.Error("Expected an error for org_path with a 5-day cycle, got nil")
+	}
+}
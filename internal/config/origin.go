@@ -0,0 +1,119 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Origin tells where a task was defined, so "where did this task come
+// from" has an answer however the schedule was assembled. Loaders set it;
+// copies of a task made by ids, extends, repeats, overrides and rules keep
+// it.
+type Origin struct {
+	// File is the path of the file, or a label for other inputs such as
+	// "<stdin>", "<inline>" or "calendar 'Work'".
+	File    string
+	Line    int    // 1-based; 0 if unknown
+	Column  int    // 1-based CSV field or XLSX cell column; 0 if none
+	Section string // TOML table the task is part of, e.g. "[[day]]"
+}
+
+// String formats s as "file:line:column section", leaving out the parts
+// that are unknown, e.g. "config.toml:12 [[day]]" or "week.csv:4:3". It is
+// empty for the zero Origin.
+func (s Origin) String() string {
+	var b strings.Builder
+	b.WriteString(s.File)
+	if s.Line > 0 {
+		fmt.Fprintf(&b, ":%d", s.Line)
+		if s.Column > 0 {
+			fmt.Fprintf(&b, ":%d", s.Column)
+		}
+	}
+	if s.Section != "" {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(s.Section)
+	}
+	return b.String()
+}
+
+// Describe names t in messages, with its source if it has one, e.g.
+// "task 'Math' (config.toml:12 [[day]])".
+func (t Task) Describe() string {
+	if src := t.Source.String(); src != "" {
+		return fmt.Sprintf("task '%s' (%s)", t.Name, src)
+	}
+	return fmt.Sprintf("task '%s'", t.Name)
+}
+
+// tableHeader matches the header line of an array-of-tables entry, such as
+// "[[day]]", with its name in the first group.
+var tableHeader = regexp.MustCompile(`^\s*\[\[\s*([A-Za-z0-9_.-]+)\s*\]\]\s*(#.*)?$`)
+
+// tableLines returns the 1-based lines of the "[[name]]" headers in the
+// TOML text data, in order.
+func tableLines(data []byte, name string) []int {
+	var lines []int
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		if m := tableHeader.FindStringSubmatch(sc.Text()); m != nil && m[1] == name {
+			lines = append(lines, n)
+		}
+	}
+	return lines
+}
+
+// keyLine returns the line of the first top-level key of data named one of
+// names, before any table header, or 0 if there is none.
+func keyLine(data []byte, names ...string) int {
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "[") {
+			return 0
+		}
+		key, _, ok := strings.Cut(line, "=")
+		if ok && slices.Contains(names, strings.TrimSpace(key)) {
+			return n
+		}
+	}
+	return 0
+}
+
+// setTOMLSources sets the Source of the tasks of days, events and rules
+// just decoded from the TOML file at path, before ids, extends and repeats
+// copy them. Tasks point at the header of their [[day]], [[event]] or
+// [[rule]] table; if the headers can't be matched to the tables, as when
+// they are written inline, the line is left out.
+func (c *Config) setTOMLSources(path string, data []byte) {
+	source := func(table string, lines []int, n, i int) Origin {
+		src := Origin{File: path, Section: "[[" + table + "]]"}
+		if len(lines) == n {
+			src.Line = lines[i]
+		}
+		return src
+	}
+	lines := tableLines(data, "day")
+	for i := range c.Days {
+		src := source("day", lines, len(c.Days), i)
+		for j := range c.Days[i].Tasks {
+			c.Days[i].Tasks[j].Source = src
+		}
+	}
+	lines = tableLines(data, "event")
+	for i := range c.Events {
+		c.Events[i].Source = source("event", lines, len(c.Events), i)
+	}
+	lines = tableLines(data, "rule")
+	for i := range c.Rules {
+		if t := c.Rules[i].AddTask; t != nil {
+			t.Source = source("rule", lines, len(c.Rules), i)
+		}
+	}
+}
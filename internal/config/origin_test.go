@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// clearOrigins zeroes the Source of every task of cfg, for comparing
+// configs loaded from different files.
+func clearOrigins(cfg *Config) {
+	for i := range cfg.Days {
+		for j := range cfg.Days[i].Tasks {
+			cfg.Days[i].Tasks[j].Source = Origin{}
+		}
+	}
+	for i := range cfg.Events {
+		cfg.Events[i].Source = Origin{}
+	}
+	for i := range cfg.Rules {
+		if t := cfg.Rules[i].AddTask; t != nil {
+			t.Source = Origin{}
+		}
+	}
+}
+
+func TestOriginString(t *testing.T) {
+	tests := []struct {
+		origin Origin
+		want   string
+	}{
+		{Origin{}, ""},
+		{Origin{File: "config.toml", Line: 12, Section: "[[day]]"}, "config.toml:12 [[day]]"},
+		{Origin{File: "config.toml", Section: "[[day]]"}, "config.toml [[day]]"},
+		{Origin{File: "week.csv", Line: 4, Column: 3}, "week.csv:4:3"},
+		{Origin{File: "week.csv", Column: 3}, "week.csv"},
+		{Origin{File: "calendar 'Work'"}, "calendar 'Work'"},
+	}
+	for _, tt := range tests {
+		if got := tt.origin.String(); got != tt.want {
+			t.Errorf("%+v: expected %q, got %q", tt.origin, tt.want, got)
+		}
+	}
+}
+
+func TestLoadTOML_Origins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := `cycle_days = 7
+
+[[day]]
+id = 1
+tasks = [{ name = "Math", start = "09:00", end = "10:00" }]
+
+[[day]] # copies day 1
+ids = [2, 3]
+extends = 1
+tasks = [{ name = "Lab", start = "13:00", end = "14:00" }]
+
+[[event]]
+date = "2025-03-12"
+name = "Dentist"
+start = "14:00"
+end = "15:00"
+
+[[rule]]
+weekday = "friday"
+add_task = { name = "Review", start = "16:00", end = "17:00" }
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadTOML(path)
+	if err != nil {
+		t.Fatalf("LoadTOML() returned error: %v", err)
+	}
+
+	day := func(line int) string { return path + ":" + strconv.Itoa(line) + " [[day]]" }
+	got := map[string]string{}
+	for _, d := range cfg.Days {
+		for _, task := range d.Tasks {
+			got[strconv.Itoa(d.ID)+" "+task.Name] = task.Source.String()
+		}
+	}
+	want := map[string]string{
+		"1 Math": day(3),
+		"2 Math": day(3),
+		"2 Lab":  day(7),
+		"3 Math": day(3),
+		"3 Lab":  day(7),
+	}
+	for k, w := range want {
+		if got[k] != w {
+			t.Errorf("%s: expected source %q, got %q", k, w, got[k])
+		}
+	}
+	if want := path + ":12 [[event]]"; cfg.Events[0].Source.String() != want {
+		t.Errorf("Expected event source %q, got %q", want, cfg.Events[0].Source.String())
+	}
+	if want := path + ":18 [[rule]]"; cfg.Rules[0].AddTask.Source.String() != want {
+		t.Errorf("Expected rule task source %q, got %q", want, cfg.Rules[0].AddTask.Source.String())
+	}
+}
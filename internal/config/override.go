@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Override precedence: a single-date override beats a range, and among
+// overrides of the same kind the one later in the file wins.
+const (
+	precedenceRange = iota
+	precedenceSingle
+)
+
+// IsRange reports whether the override spans more than one date.
+func (o Override) IsRange() bool {
+	return o.UntilFurtherNotice || o.EndDate.After(o.Date)
+}
+
+// Covers reports whether the override applies to date, compared as calendar
+// dates in date's location. Both ends of a range are included.
+func (o Override) Covers(date time.Time) bool {
+	y, m, d := date.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, date.Location())
+	from := time.Date(o.Date.Year(), o.Date.Month(), o.Date.Day(), 0, 0, 0, 0, date.Location())
+	if o.UntilFurtherNotice {
+		return !day.Before(from)
+	}
+	to := time.Date(o.EndDate.Year(), o.EndDate.Month(), o.EndDate.Day(), 0, 0, 0, 0, date.Location())
+	return !day.Before(from) && !day.After(to)
+}
+
+// Describe summarizes the override, e.g. "2025-07-01..2025-07-14 off (PTO)",
+// or "2025-07-01.. as day 2" for one until further notice.
+func (o Override) Describe() string {
+	s := o.Date.Format("2006-01-02")
+	switch {
+	case o.UntilFurtherNotice:
+		s += ".."
+	case o.IsRange():
+		s += ".." + o.EndDate.Format("2006-01-02")
+	}
+	if o.IsOff {
+		s += " off"
+	} else {
+		s += fmt.Sprintf(" as day %d", o.UseDayID)
+	}
+	if o.MuteNotifications {
+		s += ", muted"
+	}
+	if o.Note != "" {
+		s += fmt.Sprintf(" (%s)", o.Note)
+	}
+	return s
+}
+
+func (o Override) precedence() int {
+	if o.IsRange() {
+		return precedenceRange
+	}
+	return precedenceSingle
+}
+
+// civilDate is a calendar date without a location.
+type civilDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func civilDateOf(t time.Time) civilDate {
+	y, m, d := t.Date()
+	return civilDate{y, m, d}
+}
+
+// overrideIndex finds the overrides covering a date without scanning all
+// of them: bounded overrides are listed under every date they cover, and
+// the few open-ended ones are checked one by one.
+type overrideIndex struct {
+	// count is the number of overrides indexed; the index is stale once
+	// overrides are added.
+	count     int
+	byDate    map[civilDate][]int
+	openEnded []int
+}
+
+// indexOverrides builds the override index of c from the processed
+// overrides.
+func (c *Config) indexOverrides() {
+	idx := &overrideIndex{count: len(c.Overrides), byDate: make(map[civilDate][]int)}
+	for i, o := range c.Overrides {
+		if o.UntilFurtherNotice {
+			idx.openEnded = append(idx.openEnded, i)
+			continue
+		}
+		for d := o.Date; !d.After(o.EndDate); d = d.AddDate(0, 0, 1) {
+			key := civilDateOf(d)
+			idx.byDate[key] = append(idx.byDate[key], i)
+		}
+	}
+	c.overrides = idx
+}
+
+// MatchingOverrides returns the indexes of the overrides covering date,
+// the effective one first.
+func (c *Config) MatchingOverrides(date time.Time) []int {
+	var matches []int
+	if idx := c.overrides; idx != nil && idx.count == len(c.Overrides) {
+		matches = append(matches, idx.byDate[civilDateOf(date)]...)
+		for _, i := range idx.openEnded {
+			if c.Overrides[i].Covers(date) {
+				matches = append(matches, i)
+			}
+		}
+	} else {
+		for i, o := range c.Overrides {
+			if o.Covers(date) {
+				matches = append(matches, i)
+			}
+		}
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		pa, pb := c.Overrides[matches[a]].precedence(), c.Overrides[matches[b]].precedence()
+		if pa != pb {
+			return pa > pb
+		}
+		return matches[a] > matches[b]
+	})
+	return matches
+}
+
+// EffectiveOverride returns the override that applies to date, or nil.
+func (c *Config) EffectiveOverride(date time.Time) *Override {
+	if matches := c.MatchingOverrides(date); len(matches) > 0 {
+		return &c.Overrides[matches[0]]
+	}
+	return nil
+}
+
+// OverrideConflict is a date matched by more than one override.
+type OverrideConflict struct {
+	Date time.Time
+	// Overrides holds the indexes of the matching overrides, the effective one first.
+	Overrides []int
+}
+
+// OverrideConflicts lists the dates matched by several overrides, in date
+// order. Open-ended overrides are followed up to the last date any override
+// starts or ends on; after it, the matches don't change.
+func (c *Config) OverrideConflicts() []OverrideConflict {
+	var last time.Time
+	for _, o := range c.Overrides {
+		for _, d := range []time.Time{o.Date, o.EndDate} {
+			if d.After(last) {
+				last = d
+			}
+		}
+	}
+	seen := make(map[time.Time]bool)
+	var dates []time.Time
+	for _, o := range c.Overrides {
+		end := o.EndDate
+		if o.UntilFurtherNotice {
+			end = last
+		}
+		for d := o.Date; !d.After(end); d = d.AddDate(0, 0, 1) {
+			if !seen[d] {
+				seen[d] = true
+				dates = append(dates, d)
+			}
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	var conflicts []OverrideConflict
+	for _, d := range dates {
+		if matches := c.MatchingOverrides(d); len(matches) > 1 {
+			conflicts = append(conflicts, OverrideConflict{Date: d, Overrides: matches})
+		}
+	}
+	return conflicts
+}
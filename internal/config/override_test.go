@@ -0,0 +1,191 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMatchingOverrides_Precedence(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC) }
+	cfg := &Config{Overrides: []Override{
+		{Date: day(3), EndDate: day(3), UseDayID: 1},  // single-date swap
+		{Date: day(1), EndDate: day(14), IsOff: true}, // vacation
+		{Date: day(10), EndDate: day(20), IsOff: true, Note: "later range"},
+		{Date: day(3), EndDate: day(3), UseDayID: 2}, // later single date
+	}}
+
+	tests := []struct {
+		date time.Time
+		want []int
+	}{
+		{day(2), []int{1}},
+		{day(3), []int{3, 0, 1}},
+		{day(12), []int{2, 1}},
+		{day(25), nil},
+	}
+	for _, tt := range tests {
+		got := cfg.MatchingOverrides(tt.date.Add(15 * time.Hour))
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.date.Format("2006-01-02"), tt.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: expected %v, got %v", tt.date.Format("2006-01-02"), tt.want, got)
+				break
+			}
+		}
+	}
+
+	conflicts := cfg.OverrideConflicts()
+	// Jul 3 and Jul 10-14
+	if len(conflicts) != 6 {
+		t.Fatalf("Expected 6 conflicting dates, got %d: %+v", len(conflicts), conflicts)
+	}
+	if !conflicts[0].Date.Equal(day(3)) || conflicts[0].Overrides[0] != 3 {
+		t.Errorf("Expected Jul 3 to be won by the last single-date override, got %+v", conflicts[0])
+	}
+}
+
+func TestLoadTOML_MuteNotifications(t *testing.T) {
+	path := writeTemp(t, "config.toml", `
+cycle_days = 7
+
+[[override]]
+date = "2025-03-12"
+use_day_id = 5
+note = "Conference"
+mute_notifications = true
+`)
+	cfg, err := LoadTOML(path)
+	if err != nil {
+		t.Fatalf("LoadTOML() returned error: %v", err)
+	}
+	o := cfg.Overrides[0]
+	if !o.MuteNotifications || o.Note != "Conference" {
+		t.Errorf("Expected a muted override noted Conference, got %+v", o)
+	}
+	if got, want := o.Describe(), "2025-03-12 as day 5, muted (Conference)"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestProcessOverrides_Ranges(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		override Override
+		covered  []time.Time
+		outside  []time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "single_day_range",
+			override: Override{DateStr: "2025-07-03", EndDateStr: "2025-07-03", IsOff: true},
+			covered:  []time.Time{day(7, 3)},
+			outside:  []time.Time{day(7, 2), day(7, 4)},
+		},
+		{
+			name:     "multi_week",
+			override: Override{DateStr: "2025-07-01", EndDateStr: "2025-08-15", UseDayID: 2},
+			covered:  []time.Time{day(7, 1), day(7, 20), day(8, 15)},
+			outside:  []time.Time{day(6, 30), day(8, 16)},
+		},
+		{
+			name:     "until_further_notice",
+			override: Override{DateStr: "2025-09-15", UntilFurtherNotice: true, UseDayID: 3},
+			covered:  []time.Time{day(9, 15), time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)},
+			outside:  []time.Time{day(9, 14)},
+		},
+		{name: "end_before_start", override: Override{DateStr: "2025-07-03", EndDateStr: "2025-07-02"}, wantErr: true},
+		{name: "invalid_end_date", override: Override{DateStr: "2025-07-03", EndDateStr: "July 4"}, wantErr: true},
+		{name: "end_date_until_further_notice", override: Override{DateStr: "2025-07-03", EndDateStr: "2025-07-10", UntilFurtherNotice: true}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{Overrides: []Override{tt.override}}
+			err := cfg.ProcessOverrides()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProcessOverrides() returned error: %v", err)
+			}
+			for _, d := range tt.covered {
+				if cfg.EffectiveOverride(d.Add(23*time.Hour)) == nil {
+					t.Errorf("Expected %s to be covered", d.Format("2006-01-02"))
+				}
+			}
+			for _, d := range tt.outside {
+				if cfg.EffectiveOverride(d.Add(23*time.Hour)) != nil {
+					t.Errorf("Expected %s not to be covered", d.Format("2006-01-02"))
+				}
+			}
+		})
+	}
+}
+
+func TestLoadTOML_UntilFurtherNotice(t *testing.T) {
+	path := writeTemp(t, "config.toml", `
+cycle_days = 7
+
+[[override]]
+date = "2025-09-15"
+end_date = ""
+until_further_notice = true
+use_day_id = 3
+note = "New timetable"
+`)
+	cfg, err := LoadTOML(path)
+	if err != nil {
+		t.Fatalf("LoadTOML() returned error: %v", err)
+	}
+	if got, want := cfg.Overrides[0].Describe(), "2025-09-15.. as day 3 (New timetable)"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestMatchingOverrides_Index(t *testing.T) {
+	cfg := &Config{Overrides: []Override{
+		{DateStr: "2025-07-01", EndDateStr: "2025-07-31", IsOff: true},
+		{DateStr: "2025-07-10", UseDayID: 1},
+		{DateStr: "2025-07-20", UntilFurtherNotice: true, UseDayID: 2},
+		{DateStr: "2025-08-01", UntilFurtherNotice: true, UseDayID: 3},
+		{DateStr: "2025-07-25", EndDateStr: "2025-08-05", UseDayID: 4},
+	}}
+	if err := cfg.ProcessOverrides(); err != nil {
+		t.Fatalf("ProcessOverrides() returned error: %v", err)
+	}
+	unindexed := &Config{Overrides: cfg.Overrides}
+
+	// The index must agree with checking every override, in any location
+	loc := time.FixedZone("UTC-10", -10*60*60)
+	for d := time.Date(2025, 6, 25, 0, 0, 0, 0, loc); d.Before(time.Date(2025, 8, 15, 0, 0, 0, 0, loc)); d = d.AddDate(0, 0, 1) {
+		got, want := cfg.MatchingOverrides(d.Add(22*time.Hour)), unindexed.MatchingOverrides(d.Add(22*time.Hour))
+		if len(got) != len(want) {
+			t.Errorf("%s: expected %v, got %v", d.Format("2006-01-02"), want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("%s: expected %v, got %v", d.Format("2006-01-02"), want, got)
+				break
+			}
+		}
+	}
+
+	// Open-ended overrides are followed up to Aug 5, the last date
+	// another override ends on
+	conflicts := cfg.OverrideConflicts()
+	if last := conflicts[len(conflicts)-1].Date; !last.Equal(time.Date(2025, 8, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the last conflict on 2025-08-05, got %s", last.Format("2006-01-02"))
+	}
+	// Jul 10, Jul 20-31 and Aug 1-5
+	if len(conflicts) != 18 {
+		t.Errorf("Expected 18 conflicting dates, got %d", len(conflicts))
+	}
+}
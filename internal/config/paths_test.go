@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setHome points os.UserHomeDir at a temporary directory on every platform.
+func setHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	return home
+}
+
+func TestExpandTilde(t *testing.T) {
+	home := setHome(t)
+	sep := string(filepath.Separator)
+	abs := filepath.Join(home, "elsewhere", "plan.csv")
+	tests := []struct {
+		name string
+		path string
+		want string
+		err  bool
+	}{
+		{name: "home", path: "~", want: home},
+		{name: "slash", path: "~/sked/plan.csv", want: filepath.Join(home, "sked", "plan.csv")},
+		{name: "separator", path: "~" + sep + "sked" + sep + "plan.csv", want: filepath.Join(home, "sked", "plan.csv")},
+		{name: "relative", path: filepath.Join("sked", "plan.csv"), want: filepath.Join("sked", "plan.csv")},
+		{name: "absolute", path: abs, want: abs},
+		{name: "other_user", path: "~bob/plan.csv", err: true},
+		{name: "other_user_bare", path: "~bob", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandTilde(tt.path)
+			if tt.err {
+				if err == nil || !strings.Contains(err.Error(), "absolute path") {
+					t.Errorf("Expected an error suggesting an absolute path, got %q, %v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpandTilde(%q) returned error: %v", tt.path, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	home := setHome(t)
+	configPath := filepath.Join(home, "cfg", "config.toml")
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "plan.csv", want: filepath.Join(home, "cfg", "plan.csv")},
+		{path: filepath.Join("..", "plan.csv"), want: filepath.Join(home, "plan.csv")},
+		{path: "~/plan.csv", want: filepath.Join(home, "plan.csv")},
+	}
+	for _, tt := range tests {
+		got, err := resolvePath(configPath, tt.path)
+		if err != nil {
+			t.Fatalf("resolvePath(%q) returned error: %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestDefaultPath_ConfigDirEnv(t *testing.T) {
+	home := setHome(t)
+	dir := filepath.Join(t.TempDir(), "portable")
+	t.Setenv(ConfigDirEnv, dir)
+	if got, err := DefaultPath(); err != nil || got != filepath.Join(dir, "config.toml") {
+		t.Errorf("Expected %q, got %q, %v", filepath.Join(dir, "config.toml"), got, err)
+	}
+
+	t.Setenv(ConfigDirEnv, "~/portable")
+	if got, err := DefaultPath(); err != nil || got != filepath.Join(home, "portable", "config.toml") {
+		t.Errorf("Expected %q, got %q, %v", filepath.Join(home, "portable", "config.toml"), got, err)
+	}
+
+	t.Setenv(ConfigDirEnv, "~bob/portable")
+	if _, err := DefaultPath(); err == nil || !strings.Contains(err.Error(), ConfigDirEnv) {
+		t.Errorf("Expected an error naming %s, got %v", ConfigDirEnv, err)
+	}
+}
+
+func TestFindOrCreateDefault_CopiedConfig(t *testing.T) {
+	home := setHome(t)
+	t.Setenv(ConfigDirEnv, filepath.Join(home, "dotfiles", "sked"))
+	path, err := FindOrCreateDefault()
+	if err != nil {
+		t.Fatalf("FindOrCreateDefault() returned error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `csv_path = "~/dotfiles/sked/sample.csv"`; !strings.Contains(string(data), want) {
+		t.Errorf("Expected the default config to contain %s", want)
+	}
+
+	// A copy passed with -c from another directory still finds sample.csv
+	copied := writeTemp(t, "config.toml", string(data))
+	cfg, err := Load(copied)
+	if err != nil {
+		t.Fatalf("Load() of the copied default config returned error: %v", err)
+	}
+	if len(cfg.Days) == 0 {
+		t.Errorf("Expected the sample schedule, got no days")
+	}
+	if want := filepath.Join(home, "dotfiles", "sked", "sample.csv"); !strings.Contains(strings.Join(cfg.Sources, "\n"), want) {
+		t.Errorf("Expected %q among the sources, got %q", want, cfg.Sources)
+	}
+}
+
+func TestHomeRelative(t *testing.T) {
+	home := setHome(t)
+	outside := filepath.Join(filepath.Dir(home), "other", "sample.csv")
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: filepath.Join(home, ".config", "sked", "sample.csv"), want: "~/.config/sked/sample.csv"},
+		{path: outside, want: outside},
+	}
+	for _, tt := range tests {
+		if got := homeRelative(tt.path); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build race
+
+package config
+
+// raceEnabled is set when tests run with -race, whose instrumentation
+// inflates allocations and slows loading.
+const raceEnabled = true
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExpandRepeats replaces every day task with repeat_at or every by one task
+// per instance, so the scheduler only sees plain tasks. Each instance
+// records the rule it came from in Rule. Instances overlapping each other
+// or running past until are errors.
+func (c *Config) ExpandRepeats() error {
+	for i := range c.Days {
+		var tasks []Task
+		for _, t := range c.Days[i].Tasks {
+			instances, err := t.expand()
+			if err != nil {
+				return fmt.Errorf("%s: %w", t.Describe(), err)
+			}
+			tasks = append(tasks, instances...)
+		}
+		c.Days[i].Tasks = tasks
+	}
+	return nil
+}
+
+// expand returns the instances of t, or t itself if it doesn't repeat.
+func (t Task) expand() ([]Task, error) {
+	if len(t.RepeatAt) == 0 && t.Every == "" {
+		if t.Duration != "" || t.From != "" || t.Until != "" {
+			return nil, fmt.Errorf("duration, from and until need repeat_at or every")
+		}
+		return []Task{t}, nil
+	}
+	if len(t.RepeatAt) > 0 && t.Every != "" {
+		return nil, fmt.Errorf("repeat_at and every are mutually exclusive")
+	}
+	if t.Start != "" || t.End != "" {
+		return nil, fmt.Errorf("a repeated task takes duration instead of start and end")
+	}
+	length, err := repeatDuration("duration", t.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	var starts []int
+	var rule string
+	until := -1
+	if len(t.RepeatAt) > 0 {
+		rule = "repeat_at " + strings.Join(t.RepeatAt, ", ")
+		for _, s := range t.RepeatAt {
+			m, err := clockMinutes(s)
+			if err != nil {
+				return nil, fmt.Errorf("repeat_at: %w", err)
+			}
+			starts = append(starts, m)
+		}
+		sort.Ints(starts)
+	} else {
+		rule = fmt.Sprintf("every %s from %s until %s", t.Every, t.From, t.Until)
+		step, err := repeatDuration("every", t.Every)
+		if err != nil {
+			return nil, err
+		}
+		from, err := clockMinutes(t.From)
+		if err != nil {
+			return nil, fmt.Errorf("from: %w", err)
+		}
+		until, err = clockMinutes(t.Until)
+		if err != nil {
+			return nil, fmt.Errorf("until: %w", err)
+		}
+		if until <= from {
+			return nil, fmt.Errorf("until %s is not after from %s", t.Until, t.From)
+		}
+		for m := from; m < until; m += step {
+			starts = append(starts, m)
+		}
+	}
+
+	instances := make([]Task, 0, len(starts))
+	for i, m := range starts {
+		if i > 0 && m < starts[i-1]+length {
+			return nil, fmt.Errorf("the instances at %s and %s overlap", formatMinutes(starts[i-1]), formatMinutes(m))
+		}
+		if until >= 0 && m+length > until {
+			return nil, fmt.Errorf("the instance at %s would run past until %s", formatMinutes(m), t.Until)
+		}
+		if m+length >= 24*60 {
+			return nil, fmt.Errorf("the instance at %s would not end before midnight", formatMinutes(m))
+		}
+		inst := t
+		inst.Start, inst.End = formatMinutes(m), formatMinutes(m+length)
+		inst.RepeatAt, inst.Every, inst.From, inst.Until, inst.Duration = nil, "", "", "", ""
+		inst.Rule = rule
+		instances = append(instances, inst)
+	}
+	return instances, nil
+}
+
+// repeatDuration parses a positive duration in whole minutes.
+func repeatDuration(name, s string) (int, error) {
+	if s == "" {
+		return 0, fmt.Errorf("%s is required for a repeated task", name)
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 || d%time.Minute != 0 {
+		return 0, fmt.Errorf("invalid %s '%s' (expected whole minutes, e.g. 15m or 2h)", name, s)
+	}
+	return int(d / time.Minute), nil
+}
+
+// clockMinutes parses HH:MM into minutes after midnight.
+func clockMinutes(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time '%s' (expected HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func formatMinutes(m int) string {
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
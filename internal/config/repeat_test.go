@@ -0,0 +1,75 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExpandRepeats(t *testing.T) {
+	cfg, err := loadTOMLString(t, `
+[[day]]
+id = 1
+tasks = [
+  { name = "Check email", repeat_at = ["17:00", "09:00", "13:00"], duration = "15m", tags = ["admin"] },
+  { name = "Stretch", every = "2h", from = "10:00", until = "16:00", duration = "10m" },
+  { name = "Lunch", start = "12:00", end = "13:00" },
+]
+`)
+	if err != nil {
+		t.Fatalf("LoadTOML() returned error: %v", err)
+	}
+
+	var got []string
+	for _, task := range cfg.Days[0].Tasks {
+		got = append(got, task.Start+"-"+task.End+" "+task.Name)
+	}
+	want := "09:00-09:15 Check email, 13:00-13:15 Check email, 17:00-17:15 Check email, " +
+		"10:00-10:10 Stretch, 12:00-12:10 Stretch, 14:00-14:10 Stretch, 12:00-13:00 Lunch"
+	if strings.Join(got, ", ") != want {
+		t.Errorf("Expected %s, got %s", want, strings.Join(got, ", "))
+	}
+
+	email, stretch, lunch := cfg.Days[0].Tasks[0], cfg.Days[0].Tasks[3], cfg.Days[0].Tasks[6]
+	if email.Rule != "repeat_at 17:00, 09:00, 13:00" || len(email.Tags) != 1 || email.RepeatAt != nil || email.Duration != "" {
+		t.Errorf("Expected a plain tagged instance with its rule, got %+v", email)
+	}
+	if stretch.Rule != "every 2h from 10:00 until 16:00" {
+		t.Errorf("Expected the every rule, got '%s'", stretch.Rule)
+	}
+	if lunch.Rule != "" {
+		t.Errorf("Expected no rule on a plain task, got '%s'", lunch.Rule)
+	}
+}
+
+func TestExpandRepeats_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		task string
+		want string
+	}{
+		{"overlap", `repeat_at = ["09:00", "09:10"], duration = "15m"`, "the instances at 09:00 and 09:10 overlap"},
+		{"every_overlap", `every = "10m", from = "09:00", until = "10:00", duration = "15m"`, "the instances at 09:00 and 09:10 overlap"},
+		{"past_until", `every = "2h", from = "09:00", until = "16:00", duration = "90m"`, "the instance at 15:00 would run past until 16:00"},
+		{"midnight", `repeat_at = ["23:50"], duration = "15m"`, "the instance at 23:50 would not end before midnight"},
+		{"no_duration", `repeat_at = ["09:00"]`, "duration is required for a repeated task"},
+		{"bad_duration", `repeat_at = ["09:00"], duration = "90s"`, "invalid duration '90s' (expected whole minutes, e.g. 15m or 2h)"},
+		{"bad_time", `repeat_at = ["9am"], duration = "15m"`, "repeat_at: invalid time '9am' (expected HH:MM)"},
+		{"with_start", `repeat_at = ["09:00"], start = "09:00", duration = "15m"`, "a repeated task takes duration instead of start and end"},
+		{"both", `repeat_at = ["09:00"], every = "1h", duration = "15m"`, "repeat_at and every are mutually exclusive"},
+		{"stray", `start = "09:00", end = "10:00", duration = "15m"`, "duration, from and until need repeat_at or every"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+			if err := os.WriteFile(path, []byte("[[day]]\nid = 1\ntasks = [{ name = \"Email\", "+tt.task+" }]\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadTOML(path)
+			if want := "task 'Email' (" + path + ":1 [[day]]): " + tt.want; err == nil || err.Error() != want {
+				t.Errorf("Expected error %q, got %v", want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Rule is a standing adjustment of every date on a weekday or cycle day,
+// such as "every Friday ends at 15:00". Rules change the tasks of the cycle
+// day; the events of a date are added after them, and dates an override
+// covers ignore rules.
+type Rule struct {
+	// Weekday or DayID selects the dates: a calendar weekday ("friday" or
+	// 5) or a cycle day. Exactly one is set.
+	Weekday *DayID `toml:"weekday"`
+	DayID   *DayID `toml:"day_id"`
+	// Ordinal limits a weekday rule to the nth such weekday of the month
+	// (1 to 5), or the last one (-1).
+	Ordinal int    `toml:"ordinal"`
+	Note    string `toml:"note"` // optional label, e.g. "all-hands"
+
+	// The action, exactly one of: drop the tasks from TrimAfter on and
+	// shorten those running past it, add AddTask, or use the tasks of cycle
+	// day ReplaceDay instead.
+	TrimAfter  string `toml:"trim_after"`
+	AddTask    *Task  `toml:"add_task"`
+	ReplaceDay *DayID `toml:"replace_day"`
+}
+
+// Matches reports whether the rule applies to date, which falls on cycle
+// day dayID.
+func (r Rule) Matches(date time.Time, dayID int) bool {
+	if r.DayID != nil {
+		return int(*r.DayID) == dayID
+	}
+	if r.Weekday == nil || date.Weekday() != time.Weekday(*r.Weekday) {
+		return false
+	}
+	switch {
+	case r.Ordinal > 0:
+		return (date.Day()-1)/7+1 == r.Ordinal
+	case r.Ordinal == -1:
+		y, m, d := date.Date()
+		return time.Date(y, m, d+7, 0, 0, 0, 0, date.Location()).Month() != m
+	}
+	return true
+}
+
+// Apply returns tasks adjusted by the rule. day returns the tasks of a cycle
+// day for replace_day. Tasks with invalid times are kept as they are.
+func (r Rule) Apply(tasks []Task, day func(id int) []Task) []Task {
+	switch {
+	case r.ReplaceDay != nil:
+		return append([]Task(nil), day(int(*r.ReplaceDay))...)
+	case r.AddTask != nil:
+		return append(append([]Task(nil), tasks...), *r.AddTask)
+	case r.TrimAfter != "":
+		cut, err := time.Parse("15:04", r.TrimAfter)
+		if err != nil {
+			return tasks
+		}
+		var out []Task
+		for _, t := range tasks {
+			start, err1 := time.Parse("15:04", t.Start)
+			end, err2 := time.Parse("15:04", t.End)
+			switch {
+			case err1 != nil || err2 != nil:
+			case !start.Before(cut):
+				continue
+			case end.After(cut):
+				t.End = r.TrimAfter
+			}
+			out = append(out, t)
+		}
+		return out
+	}
+	return tasks
+}
+
+// Describe summarizes the rule, e.g. "every first Monday: add All-hands
+// 09:00-10:00".
+func (r Rule) Describe() string {
+	var when string
+	switch {
+	case r.DayID != nil:
+		when = fmt.Sprintf("every day %d", *r.DayID)
+	case r.Weekday != nil:
+		name := time.Weekday(int(*r.Weekday) % 7).String()
+		switch r.Ordinal {
+		case 0:
+			when = "every " + name
+		case -1:
+			when = "every last " + name
+		default:
+			when = fmt.Sprintf("every %s %s", ordinalNames[min(r.Ordinal, len(ordinalNames)-1)], name)
+		}
+	}
+	var action string
+	switch {
+	case r.ReplaceDay != nil:
+		action = fmt.Sprintf("as day %d", *r.ReplaceDay)
+	case r.AddTask != nil:
+		action = fmt.Sprintf("add %s %s-%s", r.AddTask.Name, r.AddTask.Start, r.AddTask.End)
+	case r.TrimAfter != "":
+		action = "ends at " + r.TrimAfter
+	}
+	s := when + ": " + action
+	if r.Note != "" {
+		s += fmt.Sprintf(" (%s)", r.Note)
+	}
+	return s
+}
+
+var ordinalNames = []string{"", "first", "second", "third", "fourth", "fifth"}
+
+// validate checks the rule against a cycle of cycleDays days.
+func (r Rule) validate(cycleDays int) error {
+	switch {
+	case r.Weekday == nil && r.DayID == nil:
+		return fmt.Errorf("needs a weekday or a day_id")
+	case r.Weekday != nil && r.DayID != nil:
+		return fmt.Errorf("has both a weekday and a day_id")
+	case r.Weekday != nil && (*r.Weekday < 0 || *r.Weekday > 6):
+		return fmt.Errorf("weekday %d is out of range (expected 0 to 6 or a day name)", *r.Weekday)
+	case r.DayID != nil && (*r.DayID < 0 || int(*r.DayID) >= cycleDays):
+		return fmt.Errorf("day_id %d is out of range (expected 0 to %d)", *r.DayID, cycleDays-1)
+	case r.Ordinal != 0 && r.Weekday == nil:
+		return fmt.Errorf("ordinal requires a weekday")
+	case r.Ordinal < -1 || r.Ordinal > 5:
+		return fmt.Errorf("ordinal %d is out of range (expected 1 to 5, or -1 for the last)", r.Ordinal)
+	}
+
+	var actions []string
+	if r.TrimAfter != "" {
+		actions = append(actions, "trim_after")
+		if _, err := time.Parse("15:04", r.TrimAfter); err != nil {
+			return fmt.Errorf("invalid trim_after '%s' (expected HH:MM)", r.TrimAfter)
+		}
+	}
+	if t := r.AddTask; t != nil {
+		actions = append(actions, "add_task")
+		if t.Name == "" {
+			return fmt.Errorf("add_task needs a name")
+		}
+		if len(t.RepeatAt) > 0 || t.Every != "" {
+			return fmt.Errorf("add_task '%s' cannot repeat", t.Name)
+		}
+		start, err1 := time.Parse("15:04", t.Start)
+		end, err2 := time.Parse("15:04", t.End)
+		if err1 != nil || err2 != nil {
+			return fmt.Errorf("add_task '%s' has an invalid time (expected HH:MM)", t.Name)
+		}
+		if !end.After(start) {
+			return fmt.Errorf("add_task '%s' does not end after it starts", t.Name)
+		}
+	}
+	if r.ReplaceDay != nil {
+		actions = append(actions, "replace_day")
+		if *r.ReplaceDay < 0 || int(*r.ReplaceDay) >= cycleDays {
+			return fmt.Errorf("replace_day %d is out of range (expected 0 to %d)", *r.ReplaceDay, cycleDays-1)
+		}
+	}
+	switch len(actions) {
+	case 0:
+		return fmt.Errorf("needs an action: trim_after, add_task or replace_day")
+	case 1:
+		return nil
+	}
+	return fmt.Errorf("has several actions (%s); use one rule for each", strings.Join(actions, ", "))
+}
+
+// MatchingRules returns the rules applying to date, which falls on cycle
+// day dayID, in file order. Callers skip them on dates an override covers.
+func (c *Config) MatchingRules(date time.Time, dayID int) []Rule {
+	var matching []Rule
+	for _, r := range c.Rules {
+		if r.Matches(date, dayID) {
+			matching = append(matching, r)
+		}
+	}
+	return matching
+}
+
+// ApplyRules returns tasks adjusted by rules: replace_day first, then the
+// added tasks, then trim_after, each kind in the order of rules. day returns
+// the tasks of a cycle day.
+func ApplyRules(rules []Rule, tasks []Task, day func(id int) []Task) []Task {
+	for _, pass := range []func(Rule) bool{
+		func(r Rule) bool { return r.ReplaceDay != nil },
+		func(r Rule) bool { return r.AddTask != nil },
+		func(r Rule) bool { return r.TrimAfter != "" },
+	} {
+		for _, r := range rules {
+			if pass(r) {
+				tasks = r.Apply(tasks, day)
+			}
+		}
+	}
+	return tasks
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadTOML_Rules(t *testing.T) {
+	path := writeTemp(t, "config.toml", `
+cycle_days = 7
+
+[[day]]
+id = 5
+tasks = [{ name = "Math", start = "09:00", end = "10:00" }]
+
+[[rule]]
+weekday = "friday"
+trim_after = "15:00"
+
+[[rule]]
+weekday = "monday"
+ordinal = 1
+add_task = { name = "All-hands", start = "09:00", end = "10:00", tags = ["work"] }
+note = "company"
+`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(cfg.Rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(cfg.Rules))
+	}
+	want := []string{"every Friday: ends at 15:00", "every first Monday: add All-hands 09:00-10:00 (company)"}
+	for i, r := range cfg.Rules {
+		if got := r.Describe(); got != want[i] {
+			t.Errorf("Rule %d: Expected %q, got %q", i+1, want[i], got)
+		}
+	}
+}
+
+func TestRule_Matches(t *testing.T) {
+	monday, friday := DayID(1), DayID(5)
+	day3 := DayID(3)
+	date := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) } // March 2025 starts on a Saturday
+	tests := []struct {
+		name string
+		rule Rule
+		date time.Time
+		want bool
+	}{
+		{name: "weekday", rule: Rule{Weekday: &friday}, date: date(7), want: true},
+		{name: "other_weekday", rule: Rule{Weekday: &friday}, date: date(3)},
+		{name: "first", rule: Rule{Weekday: &monday, Ordinal: 1}, date: date(3), want: true},
+		{name: "not_first", rule: Rule{Weekday: &monday, Ordinal: 1}, date: date(10)},
+		{name: "fifth", rule: Rule{Weekday: &monday, Ordinal: 5}, date: date(31), want: true},
+		{name: "last", rule: Rule{Weekday: &monday, Ordinal: -1}, date: date(31), want: true},
+		{name: "not_last", rule: Rule{Weekday: &monday, Ordinal: -1}, date: date(24)},
+		{name: "cycle_day", rule: Rule{DayID: &day3}, date: date(7), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Cycle day 3 stands for whatever the cycle puts on the date
+			if got := tt.rule.Matches(tt.date, 3); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestApplyRules(t *testing.T) {
+	friday, two := DayID(5), DayID(2)
+	tasks := []Task{
+		{Name: "Math", Start: "09:00", End: "10:00"},
+		{Name: "Lab", Start: "14:00", End: "16:00"},
+		{Name: "Sport", Start: "16:00", End: "17:00"},
+	}
+	days := map[int][]Task{2: {{Name: "Art", Start: "10:00", End: "12:00"}}}
+	day := func(id int) []Task { return days[id] }
+
+	got := ApplyRules([]Rule{
+		{Weekday: &friday, TrimAfter: "15:00"},
+		{Weekday: &friday, AddTask: &Task{Name: "Review", Start: "14:30", End: "15:30"}},
+	}, tasks, day)
+	want := []Task{
+		{Name: "Math", Start: "09:00", End: "10:00"},
+		{Name: "Lab", Start: "14:00", End: "15:00"},
+		{Name: "Review", Start: "14:30", End: "15:00"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the added task to be trimmed too:\n got: %+v\nwant: %+v", got, want)
+	}
+
+	got = ApplyRules([]Rule{
+		{Weekday: &friday, AddTask: &Task{Name: "Review", Start: "13:00", End: "14:00"}},
+		{Weekday: &friday, ReplaceDay: &two},
+	}, tasks, day)
+	want = []Task{
+		{Name: "Art", Start: "10:00", End: "12:00"},
+		{Name: "Review", Start: "13:00", End: "14:00"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected replace_day before add_task:\n got: %+v\nwant: %+v", got, want)
+	}
+	if tasks[1].End != "16:00" {
+		t.Error("Expected ApplyRules to leave its input alone")
+	}
+}
+
+func TestValidate_Rules(t *testing.T) {
+	friday, nine := DayID(5), DayID(9)
+	tests := []struct {
+		name string
+		rule Rule
+		want string
+	}{
+		{name: "no_date", rule: Rule{TrimAfter: "15:00"}, want: "rule 1: needs a weekday or a day_id"},
+		{name: "both", rule: Rule{Weekday: &friday, DayID: &friday, TrimAfter: "15:00"}, want: "has both"},
+		{name: "bad_weekday", rule: Rule{Weekday: &nine, TrimAfter: "15:00"}, want: "weekday 9 is out of range"},
+		{name: "bad_day_id", rule: Rule{DayID: &nine, TrimAfter: "15:00"}, want: "day_id 9 is out of range"},
+		{name: "ordinal_without_weekday", rule: Rule{DayID: &friday, Ordinal: 1, TrimAfter: "15:00"}, want: "ordinal requires a weekday"},
+		{name: "bad_ordinal", rule: Rule{Weekday: &friday, Ordinal: 6, TrimAfter: "15:00"}, want: "ordinal 6 is out of range"},
+		{name: "no_action", rule: Rule{Weekday: &friday}, want: "needs an action"},
+		{name: "two_actions", rule: Rule{Weekday: &friday, TrimAfter: "15:00", ReplaceDay: &friday}, want: "several actions (trim_after, replace_day)"},
+		{name: "bad_trim", rule: Rule{Weekday: &friday, TrimAfter: "3pm"}, want: "invalid trim_after '3pm'"},
+		{name: "bad_task_time", rule: Rule{Weekday: &friday, AddTask: &Task{Name: "X", Start: "25:00", End: "26:00"}}, want: "add_task 'X' has an invalid time"},
+		{name: "backwards_task", rule: Rule{Weekday: &friday, AddTask: &Task{Name: "X", Start: "10:00", End: "09:00"}}, want: "does not end after it starts"},
+		{name: "task_url", rule: Rule{Weekday: &friday, AddTask: &Task{Name: "X", Start: "09:00", End: "10:00", URL: "nope"}}, want: "task 'X': invalid url"},
+		{name: "bad_replace", rule: Rule{Weekday: &friday, ReplaceDay: &nine}, want: "replace_day 9 is out of range"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{CycleDays: 7, Rules: []Rule{tt.rule}}
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
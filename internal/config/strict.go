@@ -0,0 +1,58 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// keyHints maps unknown keys, as dotted paths, to what to write instead.
+// Keys that are renamed or dropped as the config evolves belong here, as do
+// spellings carried over from the CSV formats.
+var keyHints = map[string]string{
+	"day.tasks.time-start": "use start",
+	"day.tasks.time-end":   "use end",
+	"day.tasks.task":       "use name",
+	"event.time-start":     "use start",
+	"event.time-end":       "use end",
+	"event.task":           "use name",
+	"override.day_id":      "use use_day_id",
+	"override.off":         "use is_off",
+}
+
+// unknownKeys turns the keys a strict decode could not place into warnings
+// naming each key and its position. Other decode errors are returned as
+// they are.
+func (c *Config) unknownKeys(path string, err error) error {
+	var missing *toml.StrictMissingError
+	if !errors.As(err, &missing) {
+		return err
+	}
+	for _, e := range missing.Errors {
+		key := strings.Join(e.Key(), ".")
+		line, column := e.Position()
+		msg := fmt.Sprintf("unknown key '%s'", key)
+		if hint, ok := keyHints[key]; ok {
+			msg += "; " + hint
+		}
+		c.Warnings = append(c.Warnings, Warning{File: path, Line: line, Column: column, Key: key, Message: msg})
+	}
+	return nil
+}
+
+// StrictError returns an error listing the unknown keys among c.Warnings,
+// or nil if there are none. It backs strict = true and --strict.
+func (c *Config) StrictError() error {
+	var keys []string
+	for _, w := range c.Warnings {
+		if w.Key != "" {
+			keys = append(keys, w.String())
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return fmt.Errorf("strict mode: %s", strings.Join(keys, "; "))
+}
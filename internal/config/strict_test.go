@@ -0,0 +1,86 @@
+package config
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadTOML_UnknownKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			"top_level",
+			"cycle_day = 6\n",
+			[]string{"config.toml:1:1: unknown key 'cycle_day'"},
+		},
+		{
+			"day",
+			"[[day]]\nid = 1\nname = 'Monday'\ntasks = []\n",
+			[]string{"config.toml:3:1: unknown key 'day.name'"},
+		},
+		{
+			"task",
+			"[[day]]\nid = 1\ntasks = [{ name = 'Math', start = '09:00', end = '10:00', colour = 'red' }]\n",
+			[]string{"config.toml:3:59: unknown key 'day.tasks.colour'"},
+		},
+		{
+			"task_hint",
+			"[[day]]\nid = 1\ntasks = [{ name = 'Math', time-start = '09:00', end = '10:00' }]\n",
+			[]string{"config.toml:3:27: unknown key 'day.tasks.time-start'; use start"},
+		},
+		{
+			"override_hint",
+			"[[override]]\ndate = '2025-01-01'\noff = true\n",
+			[]string{"config.toml:3:1: unknown key 'override.off'; use is_off"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadTOMLString(t, tt.content)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var got []string
+			for _, w := range cfg.Warnings {
+				w.File = filepath.Base(w.File)
+				got = append(got, w.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected warnings %q, got %q", tt.want, got)
+			}
+			if err := cfg.StrictError(); err == nil || !strings.Contains(err.Error(), tt.want[0][len("config.toml:"):]) {
+				t.Errorf("Expected StrictError to list the key, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadTOML_UnknownKeysKeepKnownOnes(t *testing.T) {
+	cfg, err := loadTOMLString(t, "cycle_days = 3\nanchor_date = '2025-01-06'\ncycle_dayz = 4\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.CycleDays != 3 || cfg.AnchorDate != "2025-01-06" {
+		t.Errorf("Expected the known keys to load, got cycle_days %d, anchor_date %q", cfg.CycleDays, cfg.AnchorDate)
+	}
+}
+
+func TestLoadTOML_Strict(t *testing.T) {
+	_, err := loadTOMLString(t, "strict = true\ncycle_day = 6\n")
+	if err == nil || !strings.Contains(err.Error(), "strict mode") || !strings.Contains(err.Error(), "unknown key 'cycle_day'") {
+		t.Errorf("Expected a strict mode error naming cycle_day, got %v", err)
+	}
+
+	cfg, err := loadTOMLString(t, "strict = true\ncycle_days = 7\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := cfg.StrictError(); err != nil {
+		t.Errorf("Expected no strict error without unknown keys, got %v", err)
+	}
+}
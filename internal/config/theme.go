@@ -0,0 +1,98 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TUIConfig configures the interactive timetable of 'sked show'.
+type TUIConfig struct {
+	// ShowGaps shows free time between tasks as rows from the start, as if
+	// toggled with f.
+	ShowGaps bool  `toml:"show_gaps"`
+	Theme    Theme `toml:"theme"`
+}
+
+// Theme holds the TUI colors. Values are 256-color numbers ("40") or hex
+// truecolor values ("#00d700"); empty keys come from the preset.
+type Theme struct {
+	Preset           string `toml:"preset"`            // default, light or high-contrast
+	Date             string `toml:"date"`              // date, labels and countdown
+	Border           string `toml:"border"`            // table and frame borders
+	ActiveBackground string `toml:"active_background"` // row of the task in progress
+	ActiveForeground string `toml:"active_foreground"`
+	Gap              string `toml:"gap"`      // border under a finished task while no task runs
+	Selected         string `toml:"selected"` // background of the selected row
+	OffDay           string `toml:"off_day"`  // off-day banner
+}
+
+// ThemePresets are the built-in themes selected by [tui.theme] preset.
+var ThemePresets = map[string]Theme{
+	"default": {
+		Date: "40", Border: "240", ActiveBackground: "22", ActiveForeground: "7",
+		Gap: "40", Selected: "237", OffDay: "40",
+	},
+	"light": {
+		Date: "28", Border: "245", ActiveBackground: "151", ActiveForeground: "0",
+		Gap: "28", Selected: "254", OffDay: "28",
+	},
+	"high-contrast": {
+		Date: "226", Border: "15", ActiveBackground: "21", ActiveForeground: "15",
+		Gap: "226", Selected: "240", OffDay: "226",
+	},
+}
+
+var hexColor = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// validColor reports whether c is a 256-color number or a hex truecolor value.
+func validColor(c string) bool {
+	if hexColor.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// Resolve returns the theme's preset with the theme's own colors on top.
+func (t Theme) Resolve() (Theme, error) {
+	name := t.Preset
+	if name == "" {
+		name = "default"
+	}
+	preset, ok := ThemePresets[name]
+	if !ok {
+		names := make([]string, 0, len(ThemePresets))
+		for n := range ThemePresets {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return Theme{}, fmt.Errorf("invalid tui.theme preset '%s' (expected %s)", t.Preset, strings.Join(names, ", "))
+	}
+	preset.Preset = name
+
+	for _, c := range []struct {
+		key   string
+		value string
+		dst   *string
+	}{
+		{"date", t.Date, &preset.Date},
+		{"border", t.Border, &preset.Border},
+		{"active_background", t.ActiveBackground, &preset.ActiveBackground},
+		{"active_foreground", t.ActiveForeground, &preset.ActiveForeground},
+		{"gap", t.Gap, &preset.Gap},
+		{"selected", t.Selected, &preset.Selected},
+		{"off_day", t.OffDay, &preset.OffDay},
+	} {
+		if c.value == "" {
+			continue
+		}
+		if !validColor(c.value) {
+			return Theme{}, fmt.Errorf("invalid tui.theme %s '%s' (expected 0-255 or #rrggbb)", c.key, c.value)
+		}
+		*c.dst = c.value
+	}
+	return preset, nil
+}
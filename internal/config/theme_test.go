@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestThemeResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		theme   Theme
+		want    Theme
+		wantErr string
+	}{
+		{name: "empty", theme: Theme{}, want: withPreset(ThemePresets["default"], "default")},
+		{name: "light", theme: Theme{Preset: "light"}, want: withPreset(ThemePresets["light"], "light")},
+		{
+			name:  "override",
+			theme: Theme{Preset: "high-contrast", Border: "#ffffff", Selected: "0"},
+			want: func() Theme {
+				th := withPreset(ThemePresets["high-contrast"], "high-contrast")
+				th.Border, th.Selected = "#ffffff", "0"
+				return th
+			}(),
+		},
+		{name: "unknown_preset", theme: Theme{Preset: "solarized"}, wantErr: "preset 'solarized'"},
+		{name: "out_of_range", theme: Theme{Date: "256"}, wantErr: "date '256'"},
+		{name: "name", theme: Theme{Gap: "green"}, wantErr: "gap 'green'"},
+		{name: "short_hex", theme: Theme{ActiveBackground: "#0f0"}, wantErr: "active_background '#0f0'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.theme.Resolve()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadTOML_Theme(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := "cycle_days = 7\n[tui.theme]\npreset = \"light\"\nborder = \"#808080\"\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadTOML(path)
+	if err != nil {
+		t.Fatalf("LoadTOML() returned error: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() returned error: %v", err)
+	}
+	if cfg.TUI.Theme.Preset != "light" || cfg.TUI.Theme.Border != "#808080" {
+		t.Errorf("Expected the light preset with a gray border, got %+v", cfg.TUI.Theme)
+	}
+}
+
+func withPreset(th Theme, name string) Theme {
+	th.Preset = name
+	return th
+}
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// Vacation is an off-day override, usually spanning a date range.
+type Vacation struct {
+	// ID is the 1-based position among the vacations of the config file.
+	ID   int       `json:"id"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	Note string    `json:"note,omitempty"`
+}
+
+// Days returns the number of days the vacation covers.
+func (v Vacation) Days() int {
+	return int(v.To.Sub(v.From).Hours()/24) + 1
+}
+
+// overrideBlock is an [[override]] table in the text of a TOML file,
+// spanning lines [start, end).
+type overrideBlock struct {
+	start, end int
+	override   Override
+}
+
+// Vacations returns the off-day overrides written as [[override]] tables in
+// the TOML config at path.
+func Vacations(path string) ([]Vacation, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+	blocks, err := overrideBlocks(lines)
+	if err != nil {
+		return nil, err
+	}
+	vacations, _ := vacationBlocks(blocks)
+	return vacations, nil
+}
+
+// AddVacation writes an off-day override from from to to into the TOML config
+// at path. Vacations overlapping or adjoining the range are merged into it.
+// The note defaults to the first note of the merged vacations.
+// The rest of the file, including comments, is left untouched.
+func AddVacation(path string, from, to time.Time, note string) (Vacation, error) {
+	if to.Before(from) {
+		return Vacation{}, fmt.Errorf("vacation end %s is before its start %s", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+	unlock, err := lockFile(path)
+	if err != nil {
+		return Vacation{}, err
+	}
+	defer unlock()
+	lines, err := readLines(path)
+	if err != nil {
+		return Vacation{}, err
+	}
+	blocks, err := overrideBlocks(lines)
+	if err != nil {
+		return Vacation{}, err
+	}
+	vacations, vacationBlock := vacationBlocks(blocks)
+
+	// Ranges are sorted by start, so a single pass absorbs chains of overlaps.
+	sort.SliceStable(vacations, func(i, j int) bool { return vacations[i].From.Before(vacations[j].From) })
+	merged := Vacation{From: from, To: to, Note: note}
+	var absorbed []overrideBlock
+	for _, v := range vacations {
+		if v.From.After(merged.To.AddDate(0, 0, 1)) || v.To.AddDate(0, 0, 1).Before(merged.From) {
+			continue
+		}
+		if v.From.Before(merged.From) {
+			merged.From = v.From
+		}
+		if v.To.After(merged.To) {
+			merged.To = v.To
+		}
+		if merged.Note == "" {
+			merged.Note = v.Note
+		}
+		absorbed = append(absorbed, vacationBlock[v.ID])
+	}
+
+	lines = removeBlocks(lines, absorbed)
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	block := []string{
+		"",
+		"[[override]]",
+		fmt.Sprintf("date = %q", merged.From.Format("2006-01-02")),
+	}
+	block = append(block, fmt.Sprintf("end_date = %q", merged.To.Format("2006-01-02")), "is_off = true")
+	if merged.Note != "" {
+		block = append(block, fmt.Sprintf("note = %q", merged.Note))
+	}
+	lines = append(lines, block...)
+
+	if err := writeLines(path, lines); err != nil {
+		return Vacation{}, err
+	}
+	all, err := Vacations(path)
+	if err != nil {
+		return Vacation{}, err
+	}
+	// The new vacation is the last table in the file.
+	return all[len(all)-1], nil
+}
+
+// RemoveVacation deletes the vacation with the given ID from the TOML
+// config at path.
+func RemoveVacation(path string, id int) (Vacation, error) {
+	return removeVacation(path, strconv.Itoa(id), func(v Vacation) bool { return v.ID == id })
+}
+
+// RemoveVacationOn deletes the vacation covering date from the TOML config
+// at path.
+func RemoveVacationOn(path string, date time.Time) (Vacation, error) {
+	return removeVacation(path, date.Format("2006-01-02"), func(v Vacation) bool {
+		return !date.Before(v.From) && !date.After(v.To)
+	})
+}
+
+// removeVacation deletes the first vacation for which match is true; ref
+// names it in errors.
+func removeVacation(path, ref string, match func(Vacation) bool) (Vacation, error) {
+	unlock, err := lockFile(path)
+	if err != nil {
+		return Vacation{}, err
+	}
+	defer unlock()
+	lines, err := readLines(path)
+	if err != nil {
+		return Vacation{}, err
+	}
+	blocks, err := overrideBlocks(lines)
+	if err != nil {
+		return Vacation{}, err
+	}
+	vacations, vacationBlock := vacationBlocks(blocks)
+
+	for _, v := range vacations {
+		if match(v) {
+			if err := writeLines(path, removeBlocks(lines, []overrideBlock{vacationBlock[v.ID]})); err != nil {
+				return Vacation{}, err
+			}
+			return v, nil
+		}
+	}
+	return Vacation{}, fmt.Errorf("no vacation matches '%s'", ref)
+}
+
+// vacationBlocks returns the off-day overrides among blocks, numbered in file
+// order, and the block each vacation ID was read from.
+func vacationBlocks(blocks []overrideBlock) ([]Vacation, map[int]overrideBlock) {
+	var vacations []Vacation
+	byID := make(map[int]overrideBlock)
+	for _, b := range blocks {
+		// Open-ended off days are a change of schedule, not a vacation
+		if !b.override.IsOff || b.override.UntilFurtherNotice {
+			continue
+		}
+		v := Vacation{ID: len(vacations) + 1, From: b.override.Date, To: b.override.EndDate, Note: b.override.Note}
+		vacations = append(vacations, v)
+		byID[v.ID] = b
+	}
+	return vacations, byID
+}
+
+// overrideBlocks finds the [[override]] tables in lines. A table ends at the
+// next table header; blank and comment lines before that header are left out.
+func overrideBlocks(lines []string) ([]overrideBlock, error) {
+	var blocks []overrideBlock
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "[[override]]" {
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[end]), "[") {
+			end++
+		}
+		for end > i+1 {
+			last := strings.TrimSpace(lines[end-1])
+			if last != "" && !strings.HasPrefix(last, "#") {
+				break
+			}
+			end--
+		}
+
+		var o Override
+		if err := toml.Unmarshal([]byte(strings.Join(lines[i+1:end], "\n")), &o); err != nil {
+			return nil, fmt.Errorf("line %d: invalid override: %w", i+1, err)
+		}
+		c := Config{Overrides: []Override{o}}
+		if err := c.ProcessOverrides(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		blocks = append(blocks, overrideBlock{start: i, end: end, override: c.Overrides[0]})
+		i = end - 1
+	}
+	return blocks, nil
+}
+
+// removeBlocks returns lines without the given blocks and the blank line
+// separating each of them from the preceding text.
+func removeBlocks(lines []string, blocks []overrideBlock) []string {
+	drop := make(map[int]bool)
+	for _, b := range blocks {
+		start := b.start
+		if start > 0 && strings.TrimSpace(lines[start-1]) == "" {
+			start--
+		}
+		for i := start; i < b.end; i++ {
+			drop[i] = true
+		}
+	}
+	var out []string
+	for i, l := range lines {
+		if !drop[i] {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+func readLines(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+}
+
+// writeLines replaces the file at path through writeAtomic, refusing to
+// write text that is no longer a valid config. Callers hold the lock.
+func writeLines(path string, lines []string) error {
+	content := strings.Join(lines, "\n") + "\n"
+	var cfg Config
+	if err := toml.Unmarshal([]byte(content), &cfg); err != nil {
+		return fmt.Errorf("refusing to write %s: the edit would make it invalid: %w", path, err)
+	}
+	return writeAtomic(path, []byte(content))
+}
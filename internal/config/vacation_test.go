@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const vacationFixture = `# My schedule
+cycle_days = 7
+
+[[override]]
+date = "2025-07-10"
+end_date = "2025-07-20"
+is_off = true
+note = "Summer"
+
+# Exam day follows Monday
+[[override]]
+date = "2025-06-04"
+use_day_id = "monday"
+
+[[day]]
+id = 1
+tasks = [{ name = "Math", start = "09:00", end = "10:00" }]
+`
+
+func writeVacationFixture(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(vacationFixture), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func date(s string) time.Time {
+	d, _ := time.Parse("2006-01-02", s)
+	return d
+}
+
+func TestAddVacation_Merges(t *testing.T) {
+	path := writeVacationFixture(t)
+
+	v, err := AddVacation(path, date("2025-07-01"), date("2025-07-14"), "")
+	if err != nil {
+		t.Fatalf("AddVacation() returned error: %v", err)
+	}
+	if !v.From.Equal(date("2025-07-01")) || !v.To.Equal(date("2025-07-20")) || v.Note != "Summer" {
+		t.Errorf("Expected the ranges merged into 2025-07-01..2025-07-20 Summer, got %+v", v)
+	}
+
+	// An adjoining range is merged too
+	if v, err = AddVacation(path, date("2025-07-21"), date("2025-07-21"), "PTO"); err != nil {
+		t.Fatalf("AddVacation() returned error: %v", err)
+	}
+	if !v.From.Equal(date("2025-07-01")) || !v.To.Equal(date("2025-07-21")) || v.Note != "PTO" {
+		t.Errorf("Expected 2025-07-01..2025-07-21 PTO, got %+v", v)
+	}
+
+	vacations, err := Vacations(path)
+	if err != nil {
+		t.Fatalf("Vacations() returned error: %v", err)
+	}
+	if len(vacations) != 1 || vacations[0].Days() != 21 {
+		t.Errorf("Expected one 21 day vacation, got %+v", vacations)
+	}
+
+	data, _ := os.ReadFile(path)
+	content := string(data)
+	for _, want := range []string{"# My schedule", "# Exam day follows Monday", `use_day_id = "monday"`} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Expected %q to be preserved, got:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "Summer") {
+		t.Errorf("Expected the merged vacation to be replaced, got:\n%s", content)
+	}
+
+	cfg, err := LoadTOML(path)
+	if err != nil {
+		t.Fatalf("LoadTOML() returned error: %v", err)
+	}
+	if len(cfg.Overrides) != 2 {
+		t.Errorf("Expected 2 overrides, got %d", len(cfg.Overrides))
+	}
+}
+
+func TestRemoveVacation(t *testing.T) {
+	byDate := func(date time.Time) func(string) (Vacation, error) {
+		return func(path string) (Vacation, error) { return RemoveVacationOn(path, date) }
+	}
+	byID := func(id int) func(string) (Vacation, error) {
+		return func(path string) (Vacation, error) { return RemoveVacation(path, id) }
+	}
+	tests := []struct {
+		name    string
+		remove  func(path string) (Vacation, error)
+		wantErr bool
+	}{
+		{name: "by_id", remove: byID(1)},
+		{name: "by_date", remove: byDate(time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC))},
+		{name: "unknown_id", remove: byID(2), wantErr: true},
+		{name: "uncovered_date", remove: byDate(time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeVacationFixture(t)
+			v, err := tt.remove(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RemoveVacation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if v.Note != "Summer" {
+				t.Errorf("Expected the Summer vacation, got %+v", v)
+			}
+			cfg, err := LoadTOML(path)
+			if err != nil {
+				t.Fatalf("LoadTOML() returned error: %v", err)
+			}
+			if len(cfg.Overrides) != 1 || cfg.Overrides[0].IsOff {
+				t.Errorf("Expected only the exam override to remain, got %+v", cfg.Overrides)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Warning describes data a loader skipped or ignored without failing, such
+// as a CSV row without a start time, an unrecognized header column or an
+// unknown TOML key.
+type Warning struct {
+	File    string
+	Line    int    // 1-based; 0 if the warning is about the whole file
+	Column  int    // 1-based field (CSV) or cell (XLSX) column; 0 if none
+	Key     string // dotted path of an unknown TOML key; see StrictError
+	Header  string // text of an unrecognized CSV/XLSX header column; see EmptyWarning
+	Message string
+}
+
+// String formats w as "file:line:column: message", leaving out the parts
+// that are unknown.
+func (w Warning) String() string {
+	var b strings.Builder
+	if w.File != "" {
+		b.WriteString(w.File)
+		if w.Line > 0 {
+			fmt.Fprintf(&b, ":%d", w.Line)
+			if w.Column > 0 {
+				fmt.Fprintf(&b, ":%d", w.Column)
+			}
+		}
+		b.WriteString(": ")
+	}
+	b.WriteString(w.Message)
+	return b.String()
+}
+
+// warnf appends a warning to c.Warnings.
+func (c *Config) warnf(file string, line, column int, format string, args ...any) {
+	c.Warnings = append(c.Warnings, Warning{File: file, Line: line, Column: column, Message: fmt.Sprintf(format, args...)})
+}
+
+// blank reports whether every field of a record is empty, as in a spacer
+// row, which loaders skip without a warning.
+func blank(record []string) bool {
+	for _, f := range record {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
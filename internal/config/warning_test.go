@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTemp(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func warningStrings(ws []Warning) []string {
+	var out []string
+	for _, w := range ws {
+		w.File = filepath.Base(w.File)
+		out = append(out, w.String())
+	}
+	return out
+}
+
+func TestLoadCSV_Warnings(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			"clean",
+			"# weekly plan\nStart,End,Mon,Tue\n\n09:00,10:00,Math,\n# lunch\n,,,\n10:00,11:00,,Art\n",
+			nil,
+		},
+		{
+			"unknown_column",
+			"Start,End,Mon,Thrsday,Notes\n09:00,10:00,Math,Art,\n",
+			[]string{
+				"w.csv:1:4: column 'Thrsday' is not Start, End, Tags or a day name; ignored",
+				"w.csv:1:5: column 'Notes' is not Start, End, Tags or a day name; ignored",
+			},
+		},
+		{
+			"no_start",
+			"Start,End,Mon\n,10:00,Math\n09:00,10:00,Art\n",
+			[]string{"w.csv:2:1: row has no start time; skipped"},
+		},
+		{
+			"cell_times",
+			"Start,End,Mon,Tue\n09:00,10:00,Math@9:30,Art@10:00-11:xx\n10:00,11:00,@10:15-11:00,Gym\n",
+			[]string{
+				"w.csv:2:3: cell 'Math@9:30': invalid time range '9:30' (expected HH:MM-HH:MM); using the row's times",
+				"w.csv:2:4: cell 'Art@10:00-11:xx': invalid time range '10:00-11:xx' (expected HH:MM-HH:MM); using the row's times",
+				"w.csv:3:3: cell '@10:15-11:00' has no task name before '@'; skipped",
+			},
+		},
+		{
+			"short_row",
+			"Mon,Start,End\nMath,09:00\n",
+			[]string{"w.csv:2: row has 2 field(s), fewer than the 3 needed for the Start and End columns; skipped"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadCSV(writeTemp(t, "w.csv", tt.content), "")
+			if err != nil {
+				t.Fatalf("LoadCSV() returned error: %v", err)
+			}
+			if got := warningStrings(cfg.Warnings); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected warnings %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadTmpCSV_Warnings(t *testing.T) {
+	path := writeTemp(t, "tmp.csv", "Start,End,Task\n09:00,10:00,Math\n10:00,11:00,\n,12:00,Art\n12:00\n,,\n")
+	cfg, err := LoadTmpCSV(path)
+	if err != nil {
+		t.Fatalf("LoadTmpCSV() returned error: %v", err)
+	}
+	want := []string{
+		"tmp.csv:3:3: row has no task name; skipped",
+		"tmp.csv:4:1: row has no start time; skipped",
+		"tmp.csv:5: row has 1 field(s), fewer than the 3 needed for the Start, End and Task columns; skipped",
+	}
+	if got := warningStrings(cfg.Warnings); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected warnings %q, got %q", want, got)
+	}
+	if len(cfg.Days) != 1 || len(cfg.Days[0].Tasks) != 1 {
+		t.Errorf("Expected only Math to load, got %+v", cfg.Days)
+	}
+}
+
+func TestLoadTOML_CSVWarnings(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "week.csv"), []byte("Start,End,Mon,Thrsday\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(path, []byte("csv_path = 'week.csv'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadTOML(path)
+	if err != nil {
+		t.Fatalf("LoadTOML() returned error: %v", err)
+	}
+	want := []string{"week.csv:1:4: column 'Thrsday' is not Start, End, Tags or a day name; ignored"}
+	if got := warningStrings(cfg.Warnings); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected warnings %q, got %q", want, got)
+	}
+}
+
+func TestWarningString(t *testing.T) {
+	tests := []struct {
+		w    Warning
+		want string
+	}{
+		{Warning{File: "a.csv", Line: 3, Column: 2, Message: "m"}, "a.csv:3:2: m"},
+		{Warning{File: "a.csv", Line: 3, Message: "m"}, "a.csv:3: m"},
+		{Warning{File: "a.csv", Message: "m"}, "a.csv: m"},
+		{Warning{Message: "m"}, "m"},
+	}
+	for _, tt := range tests {
+		if got := tt.w.String(); got != tt.want {
+			t.Errorf("Expected %q, got %q", tt.want, got)
+		}
+	}
+}
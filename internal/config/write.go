@@ -0,0 +1,172 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// DefaultBackups is how many previous versions of a config file are kept
+// when the config doesn't set backups.
+const DefaultBackups = 1
+
+// Lock file timing: waiting for another command gives up after lockTimeout,
+// and a lock older than lockStale is left over from a crash.
+const (
+	lockStale = time.Minute
+	lockPoll  = 50 * time.Millisecond
+)
+
+var lockTimeout = 5 * time.Second
+
+// renameFile replaces a file; tests swap it to simulate a crash.
+var renameFile = os.Rename
+
+// WriteFile replaces the file at path with data while holding its lock; see
+// writeAtomic.
+func WriteFile(path string, data []byte) error {
+	unlock, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return writeAtomic(path, data)
+}
+
+// writeAtomic replaces the file at path with data so that a crash leaves
+// either the old or the new version: data goes to a synced temp file in the
+// same directory, which is renamed over path. The old version is kept as
+// path.bak, older ones as path.bak.1 and so on, as many as the backups key
+// of data asks for. The file keeps its mode; a new one gets 0644.
+func writeAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	perm := os.FileMode(0o644)
+	old, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if info, err := os.Stat(path); err == nil {
+			perm = info.Mode().Perm()
+		}
+	case errors.Is(err, os.ErrNotExist):
+		old = nil
+	default:
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // fails harmlessly once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if old != nil {
+		if err := rotateBackups(path, old, perm, backupCount(path, data)); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+	if err := renameFile(tmpName, path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// backupName returns the name of the nth most recent backup of path.
+func backupName(path string, n int) string {
+	if n == 0 {
+		return path + ".bak"
+	}
+	return path + ".bak." + strconv.Itoa(n)
+}
+
+// rotateBackups shifts the backups of path up by one, dropping the oldest,
+// and writes old as the newest.
+func rotateBackups(path string, old []byte, perm os.FileMode, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	for n := keep - 1; n > 0; n-- {
+		if err := os.Rename(backupName(path, n-1), backupName(path, n)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	tmp := backupName(path, 0) + ".tmp"
+	if err := os.WriteFile(tmp, old, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, backupName(path, 0))
+}
+
+// backupCount reads the backups key of a TOML config, or returns
+// DefaultBackups.
+func backupCount(path string, data []byte) int {
+	if filepath.Ext(path) != ".toml" {
+		return DefaultBackups
+	}
+	var c struct {
+		Backups *int `toml:"backups"`
+	}
+	if err := toml.Unmarshal(data, &c); err != nil || c.Backups == nil || *c.Backups < 0 {
+		return DefaultBackups
+	}
+	return *c.Backups
+}
+
+// syncDir makes a rename in dir durable where the platform allows it.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
+
+// lockFile takes the lock file path.lock, waiting up to lockTimeout for
+// another sked command to release it. Locks older than lockStale are taken
+// over. The returned function releases the lock.
+func lockFile(path string) (func(), error) {
+	lock := path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lock), 0o755); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("another sked command is editing %s (remove %s if none is running)", path, lock)
+		}
+		time.Sleep(lockPoll)
+	}
+}
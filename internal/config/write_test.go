@@ -0,0 +1,127 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readString(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestWriteFile_Backups(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		backups []string // expected .bak, .bak.1, ... after writing v1, v2, v3
+	}{
+		{"default", "", []string{"v2"}},
+		{"two", "backups = 2\n", []string{"v2", "v1"}},
+		{"none", "backups = 0\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.toml")
+			for _, v := range []string{"v1", "v2", "v3"} {
+				if err := WriteFile(path, []byte(tt.header+"# "+v+"\n")); err != nil {
+					t.Fatalf("WriteFile() returned error: %v", err)
+				}
+			}
+			if got := readString(t, path); got != tt.header+"# v3\n" {
+				t.Errorf("Expected the latest version, got %q", got)
+			}
+			for n, want := range tt.backups {
+				if got := readString(t, backupName(path, n)); got != tt.header+"# "+want+"\n" {
+					t.Errorf("Expected backup %d to hold %s, got %q", n, want, got)
+				}
+			}
+			if _, err := os.Stat(backupName(path, len(tt.backups))); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("Expected no more than %d backup(s), got %v", len(tt.backups), err)
+			}
+			if _, err := os.Stat(path + ".lock"); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("Expected the lock to be released, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWriteFile_KeepsMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("# old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteFile(path, []byte("# new\n")); err != nil {
+		t.Fatalf("WriteFile() returned error: %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600 to be kept, got %v (%v)", info.Mode(), err)
+	}
+}
+
+func TestWriteFile_CrashBeforeRename(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(path, []byte("# original\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	renameFile = func(string, string) error { return errors.New("simulated crash") }
+	defer func() { renameFile = os.Rename }()
+
+	if err := WriteFile(path, []byte("# new\n")); err == nil {
+		t.Fatal("Expected the simulated crash to fail the write")
+	}
+	if got := readString(t, path); got != "# original\n" {
+		t.Errorf("Expected the original to survive, got %q", got)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".config.toml-") {
+			t.Errorf("Expected the temp file to be cleaned up, found %s", e.Name())
+		}
+	}
+}
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	old := lockTimeout
+	lockTimeout = 100 * time.Millisecond
+	defer func() { lockTimeout = old }()
+
+	unlock, err := lockFile(path)
+	if err != nil {
+		t.Fatalf("lockFile() returned error: %v", err)
+	}
+	if _, err := lockFile(path); err == nil || !strings.Contains(err.Error(), "another sked command is editing") {
+		t.Errorf("Expected a timeout while the lock is held, got %v", err)
+	}
+	if err := WriteFile(path, []byte("# new\n")); err == nil {
+		t.Error("Expected WriteFile to wait for the lock and give up")
+	}
+	unlock()
+
+	// A lock left over from a crash is taken over
+	if err := os.WriteFile(path+".lock", []byte("1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	stale := time.Now().Add(-2 * lockStale)
+	if err := os.Chtimes(path+".lock", stale, stale); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err = lockFile(path)
+	if err != nil {
+		t.Fatalf("Expected the stale lock to be taken over, got %v", err)
+	}
+	unlock()
+}
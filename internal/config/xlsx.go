@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/Daniel-42-z/sked/internal/xlsx"
+)
+
+var clockPattern = regexp.MustCompile(`^\d{1,2}:\d{2}$`)
+
+// LoadXLSX reads a weekly schedule in the CSV layout (Start, End, day
+// columns and an optional Tags column) from a worksheet of an .xlsx
+// workbook, the first one if sheet is empty. Merged cells repeat their value
+// over the merged range, empty rows are skipped, and times may be text or
+// Excel time values. Warnings give row and column numbers of the sheet.
+func LoadXLSX(path, sheet, dateFormat string) (*Config, error) {
+	s, err := xlsx.ReadSheet(path, sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	var cols tableColumns
+	var rows []tableRow
+	header := true
+	for r, row := range s.Rows {
+		record := make([]string, len(row))
+		empty := true
+		for c, cell := range row {
+			record[c] = strings.TrimSpace(cell.Value)
+			if record[c] != "" {
+				empty = false
+			}
+		}
+		if empty {
+			continue
+		}
+		if header {
+			if cols, err = parseTableHeader(tableRow{line: r + 1, fields: record}); err != nil {
+				return nil, fmt.Errorf("sheet '%s' row %d: %w", s.Name, r+1, err)
+			}
+			header = false
+			continue
+		}
+		for _, c := range []int{cols.start, cols.end} {
+			if c >= len(record) || record[c] == "" {
+				continue
+			}
+			if record[c], err = cellClock(row[c]); err != nil {
+				return nil, fmt.Errorf("sheet '%s' cell %s: %w", s.Name, xlsx.CellName(r, c), err)
+			}
+		}
+		rows = append(rows, tableRow{line: r + 1, fields: record})
+	}
+	if header {
+		return nil, fmt.Errorf("sheet '%s' is empty", s.Name)
+	}
+	return loadTable(path, cols, rows, dateFormat), nil
+}
+
+// cellClock returns the HH:MM time of a cell holding "9:00" or an Excel
+// time value, the fraction of a day.
+func cellClock(c xlsx.Cell) (string, error) {
+	if c.IsNumber {
+		// A date and time keeps the time in the fractional part
+		_, frac := math.Modf(c.Number)
+		minutes := int(math.Round(frac * 24 * 60))
+		if c.Number < 0 || minutes >= 24*60 || (c.Number >= 1 && frac == 0) {
+			return "", fmt.Errorf("invalid time %s (expected a time of day)", c.Value)
+		}
+		return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60), nil
+	}
+	v := strings.TrimSpace(c.Value)
+	if !clockPattern.MatchString(v) {
+		return "", fmt.Errorf("invalid time '%s' (expected HH:MM)", v)
+	}
+	return padClock(v), nil
+}
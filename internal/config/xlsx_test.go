@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// dayTasks renders the tasks of each day as "start-end name [tags]" lines.
+func dayTasks(cfg *Config) map[int][]string {
+	days := make(map[int][]string)
+	for _, d := range cfg.Days {
+		for _, t := range d.Tasks {
+			s := t.Start + "-" + t.End + " " + t.Name
+			if len(t.Tags) > 0 {
+				s += " " + strings.Join(t.Tags, ",")
+			}
+			days[d.ID] = append(days[d.ID], s)
+		}
+	}
+	return days
+}
+
+func TestLoadXLSX(t *testing.T) {
+	tests := []struct {
+		name  string
+		file  string
+		sheet string
+		want  map[int][]string
+	}{
+		{
+			name: "merged_cells",
+			file: "merged.xlsx",
+			want: map[int][]string{
+				1: {"09:00-10:00 Math", "10:00-11:00 History", "11:00-12:00 History", "13:00-14:00 Lunch"},
+				2: {"09:00-10:00 Math", "10:00-11:00 Art", "13:00-14:00 Lunch"},
+				3: {"09:00-10:00 Math", "11:00-12:00 Gym", "13:00-14:00 Lunch"},
+			},
+		},
+		{
+			name:  "sheet",
+			file:  "merged.xlsx",
+			sheet: "Term 2",
+			want:  map[int][]string{5: {"08:00-09:00 Chemistry"}},
+		},
+		{
+			name: "numeric_times",
+			file: "numeric_times.xlsx",
+			want: map[int][]string{
+				4: {"09:00-10:00 Physics work", "12:00-13:00 Lab work,lab", "13:30-14:30 Reading"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadXLSX(filepath.Join("testdata", tt.file), tt.sheet, "")
+			if err != nil {
+				t.Fatalf("LoadXLSX() returned error: %v", err)
+			}
+			if got := dayTasks(cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadXLSX_Errors(t *testing.T) {
+	tests := []struct {
+		sheet string
+		want  string
+	}{
+		{"Broken", "sheet 'Broken' cell B4: invalid time 9 (expected a time of day)"},
+		{"Term 3", "no sheet named 'Term 3' (sheets: Times, Broken)"},
+	}
+	for _, tt := range tests {
+		_, err := LoadXLSX(filepath.Join("testdata", "numeric_times.xlsx"), tt.sheet, "")
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Expected error %q, got %v", tt.want, err)
+		}
+	}
+}
+
+func TestLoadTOML_XLSXSheet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	book, err := filepath.Abs(filepath.Join("testdata", "merged.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := "csv_path = '" + book + "'\nsheet = 'Term 2'\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if got := dayTasks(cfg); len(got) != 1 || len(got[5]) != 1 {
+		t.Errorf("Expected the Term 2 sheet, got %v", got)
+	}
+	if cfg.Sheet != "Term 2" {
+		t.Errorf("Expected sheet 'Term 2' to be preserved, got '%s'", cfg.Sheet)
+	}
+}
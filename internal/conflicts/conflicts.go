@@ -0,0 +1,297 @@
+// Package conflicts checks every day of the cycle for overlapping, empty or
+// duplicated tasks, every override for borrowed days without tasks and every
+// rule for the issues its changes bring into a day.
+package conflicts
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// Severity tells whether an issue breaks the schedule or only looks suspicious.
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+)
+
+// Kind describes what is wrong.
+type Kind string
+
+const (
+	Overlap     Kind = "overlap"      // two tasks share time
+	Duration    Kind = "duration"     // a task ends at or before its start
+	InvalidTime Kind = "invalid_time" // a start or end is not HH:MM
+	Duplicate   Kind = "duplicate"    // the same name and times appear more than once
+	BorrowedDay Kind = "borrowed_day" // an override uses a day without tasks
+)
+
+// Task is a task as written in the config, or an instance of a repeated
+// task with the rule that generated it, and where it was defined.
+type Task struct {
+	Name   string `json:"name"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Rule   string `json:"rule,omitempty"`
+	Source string `json:"source,omitempty"` // e.g. "config.toml:12 [[day]]"
+}
+
+func (t Task) String() string {
+	s := fmt.Sprintf("%s %s-%s", t.Name, t.Start, t.End)
+	if t.Rule != "" {
+		s += fmt.Sprintf(" (%s)", t.Rule)
+	}
+	if t.Source != "" {
+		s += fmt.Sprintf(" (%s)", t.Source)
+	}
+	return s
+}
+
+// Issue is a single problem and the tasks involved.
+type Issue struct {
+	Kind     Kind     `json:"kind"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Tasks    []Task   `json:"tasks,omitempty"`
+}
+
+// Day holds the issues of one cycle day.
+type Day struct {
+	ID     int     `json:"day_id"`
+	Name   string  `json:"day_name"`
+	Issues []Issue `json:"issues"`
+}
+
+// Report lists the cycle days, overrides and rules with issues.
+type Report struct {
+	Days      []Day   `json:"days"`
+	Overrides []Issue `json:"overrides"`
+	Rules     []Issue `json:"rules"`
+	Errors    int     `json:"errors"`
+	Warnings  int     `json:"warnings"`
+}
+
+// Check walks every day ID of the cycle and every override and rule of cfg.
+func Check(cfg *config.Config) *Report {
+	sched := scheduler.New(cfg)
+	r := &Report{Days: []Day{}, Overrides: []Issue{}, Rules: []Issue{}}
+
+	tasks := make(map[int][]config.Task)
+	for _, d := range cfg.Days {
+		tasks[d.ID] = append(tasks[d.ID], d.Tasks...)
+	}
+	for id := 0; id < cfg.CycleDays; id++ {
+		if issues := CheckTasks(tasks[id]); len(issues) > 0 {
+			r.Days = append(r.Days, Day{ID: id, Name: sched.DayName(id), Issues: issues})
+			r.count(issues)
+		}
+	}
+
+	for _, o := range cfg.Overrides {
+		if o.IsOff {
+			continue
+		}
+		id := int(o.UseDayID)
+		var msg string
+		switch {
+		case id < 0 || id >= cfg.CycleDays:
+			msg = fmt.Sprintf("%s borrows day %d, which is outside the %d-day cycle", o.Describe(), id, cfg.CycleDays)
+		case len(tasks[id]) == 0:
+			msg = fmt.Sprintf("%s borrows %s, which has no tasks", o.Describe(), dayLabel(id, sched.DayName(id)))
+		default:
+			continue
+		}
+		issue := Issue{Kind: BorrowedDay, Severity: Error, Message: msg}
+		r.Overrides = append(r.Overrides, issue)
+		r.count([]Issue{issue})
+	}
+
+	dayTasks := func(id int) []config.Task { return tasks[id] }
+	for _, rule := range cfg.Rules {
+		for _, id := range ruleDays(cfg, rule) {
+			// Only report what the rule adds to the day's own issues
+			known := make(map[string]bool)
+			for _, i := range CheckTasks(tasks[id]) {
+				known[i.Message] = true
+			}
+			applied := config.ApplyRules([]config.Rule{rule}, tasks[id], dayTasks)
+			for _, issue := range CheckTasks(applied) {
+				if known[issue.Message] {
+					continue
+				}
+				issue.Message = fmt.Sprintf("%s, on %s: %s", rule.Describe(), dayLabel(id, sched.DayName(id)), issue.Message)
+				r.Rules = append(r.Rules, issue)
+				r.count([]Issue{issue})
+			}
+		}
+	}
+	return r
+}
+
+// ruleDays returns the cycle days rule can apply to: its day_id, the day of
+// its weekday in standard weeks, or else every day of the cycle.
+func ruleDays(cfg *config.Config, rule config.Rule) []int {
+	switch {
+	case rule.DayID != nil:
+		return []int{int(*rule.DayID)}
+	case cfg.CycleDays == 7 && cfg.AnchorDate == "":
+		return []int{int(*rule.Weekday)}
+	}
+	ids := make([]int, cfg.CycleDays)
+	for i := range ids {
+		ids[i] = i
+	}
+	return ids
+}
+
+func (r *Report) count(issues []Issue) {
+	for _, i := range issues {
+		if i.Severity == Error {
+			r.Errors++
+		} else {
+			r.Warnings++
+		}
+	}
+}
+
+// span is a task's interval in minutes after midnight.
+type span struct {
+	task       Task
+	start, end int
+}
+
+// CheckTasks reports invalid times, zero or negative durations, duplicates
+// and overlapping pairs among the tasks of one day. Empty slots ("/") take
+// part in every check but overlaps.
+func CheckTasks(tasks []config.Task) []Issue {
+	var issues []Issue
+	var spans []span
+	seen := make(map[Task]int)   // by name, times and rule
+	first := make(map[Task]Task) // the first of each, with its source
+	for _, t := range tasks {
+		key := Task{Name: t.Name, Start: t.Start, End: t.End, Rule: t.Rule}
+		task := key
+		task.Source = t.Source.String()
+		start, err1 := minutes(t.Start)
+		end, err2 := minutes(t.End)
+		if err1 != nil || err2 != nil {
+			issues = append(issues, Issue{
+				Kind:     InvalidTime,
+				Severity: Error,
+				Message:  fmt.Sprintf("%s has an invalid time (expected HH:MM)", task),
+				Tasks:    []Task{task},
+			})
+			continue
+		}
+		if end <= start {
+			issues = append(issues, Issue{
+				Kind:     Duration,
+				Severity: Error,
+				Message:  fmt.Sprintf("%s does not end after it starts", task),
+				Tasks:    []Task{task},
+			})
+			continue
+		}
+		if seen[key]++; seen[key] > 1 {
+			// Counted once below; a copy doesn't also overlap itself.
+			continue
+		}
+		first[key] = task
+		if t.Name != "/" {
+			spans = append(spans, span{task: task, start: start, end: end})
+		}
+	}
+
+	var dups []Task
+	for t, n := range seen {
+		if n > 1 {
+			dups = append(dups, t)
+		}
+	}
+	sort.Slice(dups, func(i, j int) bool { return dups[i].Start < dups[j].Start })
+	for _, key := range dups {
+		t := first[key]
+		issues = append(issues, Issue{
+			Kind:     Duplicate,
+			Severity: Warning,
+			Message:  fmt.Sprintf("%s appears %d times", t, seen[key]),
+			Tasks:    []Task{t},
+		})
+	}
+
+	for _, pair := range overlaps(spans) {
+		a, b := pair[0].task, pair[1].task
+		issues = append(issues, Issue{
+			Kind:     Overlap,
+			Severity: Error,
+			Message:  fmt.Sprintf("%s overlaps %s", a, b),
+			Tasks:    []Task{a, b},
+		})
+	}
+	return issues
+}
+
+// overlaps returns every pair of spans sharing time, ordered by start.
+// Spans that merely touch (one ends when the next starts) don't overlap.
+func overlaps(spans []span) [][2]span {
+	sorted := append([]span(nil), spans...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].start < sorted[j].start })
+	var pairs [][2]span
+	for i, a := range sorted {
+		for _, b := range sorted[i+1:] {
+			if b.start >= a.end {
+				break
+			}
+			pairs = append(pairs, [2]span{a, b})
+		}
+	}
+	return pairs
+}
+
+func minutes(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func dayLabel(id int, name string) string {
+	if name == fmt.Sprintf("Day %d", id) {
+		return name
+	}
+	return fmt.Sprintf("%s (day %d)", name, id)
+}
+
+// Write prints the report grouped by day, followed by override issues and totals.
+func Write(w io.Writer, r *Report) {
+	if r.Errors+r.Warnings == 0 {
+		fmt.Fprintln(w, "No conflicts.")
+		return
+	}
+	for _, d := range r.Days {
+		fmt.Fprintln(w, dayLabel(d.ID, d.Name))
+		writeIssues(w, d.Issues)
+	}
+	if len(r.Overrides) > 0 {
+		fmt.Fprintln(w, "Overrides")
+		writeIssues(w, r.Overrides)
+	}
+	if len(r.Rules) > 0 {
+		fmt.Fprintln(w, "Rules")
+		writeIssues(w, r.Rules)
+	}
+	fmt.Fprintf(w, "\n%d error(s), %d warning(s)\n", r.Errors, r.Warnings)
+}
+
+func writeIssues(w io.Writer, issues []Issue) {
+	for _, i := range issues {
+		fmt.Fprintf(w, "  %-7s  %s\n", i.Severity, i.Message)
+	}
+}
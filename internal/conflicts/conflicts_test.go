@@ -0,0 +1,190 @@
+package conflicts
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+func TestCheckTasks(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks []config.Task
+		want  []Kind
+	}{
+		{
+			name: "clean",
+			tasks: []config.Task{
+				{Name: "Math", Start: "09:00", End: "10:00"},
+				{Name: "Art", Start: "10:00", End: "11:00"}, // touching is fine
+			},
+		},
+		{
+			name: "overlap",
+			tasks: []config.Task{
+				{Name: "Art", Start: "10:30", End: "11:30"},
+				{Name: "Math", Start: "09:00", End: "11:00"},
+				{Name: "Gym", Start: "09:30", End: "10:00"},
+			},
+			want: []Kind{Overlap, Overlap},
+		},
+		{
+			name: "empty_slot_ignored",
+			tasks: []config.Task{
+				{Name: "Math", Start: "09:00", End: "11:00"},
+				{Name: "/", Start: "10:00", End: "12:00"},
+			},
+		},
+		{
+			name: "duration",
+			tasks: []config.Task{
+				{Name: "Zero", Start: "09:00", End: "09:00"},
+				{Name: "Backwards", Start: "23:00", End: "01:00"},
+			},
+			want: []Kind{Duration, Duration},
+		},
+		{
+			name:  "invalid_time",
+			tasks: []config.Task{{Name: "Math", Start: "9am", End: "10:00"}},
+			want:  []Kind{InvalidTime},
+		},
+		{
+			name: "duplicate",
+			tasks: []config.Task{
+				{Name: "Math", Start: "09:00", End: "10:00"},
+				{Name: "Math", Start: "09:00", End: "10:00"},
+			},
+			want: []Kind{Duplicate},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := CheckTasks(tt.tasks)
+			var got []Kind
+			for _, i := range issues {
+				got = append(got, i.Kind)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, issues)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+
+	issues := CheckTasks([]config.Task{
+		{Name: "Art", Start: "10:30", End: "11:30"},
+		{Name: "Math", Start: "09:00", End: "11:00"},
+	})
+	if want := "Math 09:00-11:00 overlaps Art 10:30-11:30"; len(issues) != 1 || issues[0].Message != want {
+		t.Errorf("Expected %q, got %v", want, issues)
+	}
+
+	// Instances of repeated tasks name their rule
+	issues = CheckTasks([]config.Task{
+		{Name: "Email", Start: "13:00", End: "13:15", Rule: "repeat_at 09:00, 13:00"},
+		{Name: "Lunch", Start: "12:30", End: "13:30"},
+	})
+	if want := "Lunch 12:30-13:30 overlaps Email 13:00-13:15 (repeat_at 09:00, 13:00)"; len(issues) != 1 || issues[0].Message != want {
+		t.Errorf("Expected %q, got %v", want, issues)
+	}
+	if issues[0].Tasks[1].Rule == "" {
+		t.Errorf("Expected the rule in the issue's tasks, got %+v", issues[0].Tasks)
+	}
+
+	// Tasks name where they were defined; copies from elsewhere are still
+	// duplicates
+	issues = CheckTasks([]config.Task{
+		{Name: "Math", Start: "09:00", End: "10:00", Source: config.Origin{File: "week.csv", Line: 2, Column: 4}},
+		{Name: "Math", Start: "09:00", End: "10:00", Source: config.Origin{File: "week.csv", Line: 2, Column: 5}},
+		{Name: "Gym", Start: "09:30", End: "10:30", Source: config.Origin{File: "config.toml", Line: 7, Section: "[[event]]"}},
+	})
+	if len(issues) != 2 {
+		t.Fatalf("Expected a duplicate and an overlap, got %v", issues)
+	}
+	if want := "Math 09:00-10:00 (week.csv:2:4) appears 2 times"; issues[0].Message != want {
+		t.Errorf("Expected %q, got %q", want, issues[0].Message)
+	}
+	if want := "Math 09:00-10:00 (week.csv:2:4) overlaps Gym 09:30-10:30 (config.toml:7 [[event]])"; issues[1].Message != want {
+		t.Errorf("Expected %q, got %q", want, issues[1].Message)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	day := time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 1, Tasks: []config.Task{
+				{Name: "Math", Start: "09:00", End: "10:00"},
+				{Name: "Math", Start: "09:00", End: "10:00"},
+			}},
+			{ID: 2, Tasks: []config.Task{
+				{Name: "Math", Start: "09:00", End: "10:00"},
+				{Name: "Art", Start: "09:30", End: "10:30"},
+			}},
+			{ID: 3, Tasks: []config.Task{{Name: "Gym", Start: "18:00", End: "19:00"}}},
+		},
+		Overrides: []config.Override{
+			{Date: day, EndDate: day, UseDayID: 3},
+			{Date: day, EndDate: day, UseDayID: 6},
+			{Date: day, EndDate: day, UseDayID: 9},
+			{Date: day, EndDate: day, IsOff: true},
+		},
+	}
+
+	r := Check(cfg)
+	if r.Errors != 3 || r.Warnings != 1 {
+		t.Errorf("Expected 3 errors and 1 warning, got %d and %d", r.Errors, r.Warnings)
+	}
+	if len(r.Days) != 2 || r.Days[0].Name != "Monday" || r.Days[1].ID != 2 {
+		t.Errorf("Expected issues on Monday and Tuesday, got %+v", r.Days)
+	}
+	if len(r.Overrides) != 2 || !strings.Contains(r.Overrides[0].Message, "Saturday (day 6), which has no tasks") ||
+		!strings.Contains(r.Overrides[1].Message, "outside the 7-day cycle") {
+		t.Errorf("Expected borrowed day issues for days 6 and 9, got %+v", r.Overrides)
+	}
+
+	var buf bytes.Buffer
+	Write(&buf, r)
+	out := buf.String()
+	for _, want := range []string{"Monday (day 1)\n  warning  Math 09:00-10:00 appears 2 times", "Overrides\n  error", "3 error(s), 1 warning(s)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestCheck_Rules(t *testing.T) {
+	monday := config.DayID(1)
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 1, Tasks: []config.Task{{Name: "Math", Start: "09:00", End: "10:00"}}},
+		},
+		Rules: []config.Rule{
+			{Weekday: &monday, Ordinal: 1, AddTask: &config.Task{Name: "All-hands", Start: "09:30", End: "10:30"}},
+			{Weekday: &monday, TrimAfter: "12:00"},
+		},
+	}
+
+	r := Check(cfg)
+	if r.Errors != 1 || len(r.Rules) != 1 {
+		t.Fatalf("Expected 1 rule error, got %d error(s) and %+v", r.Errors, r.Rules)
+	}
+	if !strings.HasPrefix(r.Rules[0].Message, "every first Monday: add All-hands 09:30-10:30, on Monday (day 1): ") {
+		t.Errorf("Expected the issue to name the rule and day, got %q", r.Rules[0].Message)
+	}
+
+	var buf bytes.Buffer
+	Write(&buf, r)
+	if out := buf.String(); !strings.Contains(out, "Rules\n  error") {
+		t.Errorf("Expected a Rules section, got:\n%s", out)
+	}
+}
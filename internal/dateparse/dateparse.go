@@ -0,0 +1,103 @@
+// Package dateparse reads the dates typed on the command line, such as
+// "2024-09-02", "tomorrow", "fri", "next monday" or "+3", so every command
+// accepts the same forms.
+package dateparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+// Forms lists the accepted forms, for help texts and error messages.
+const Forms = "YYYY-MM-DD, today, tomorrow, yesterday, a weekday, next/last <weekday>, next/last week or +N/-N days"
+
+// Parse returns the date s names, relative to today, at midnight in
+// today's location. s is case-insensitive and may be:
+//
+//   - a date, "2024-09-02"
+//   - "today", "tomorrow" or "yesterday"
+//   - a weekday in config.ParseDayName's syntax ("monday", "fri", "Mi"),
+//     meaning its next occurrence, today included: "monday" on a Monday is
+//     today
+//   - "next" or "last" and a weekday, its first occurrence after or before
+//     today: "next monday" on a Monday is a week later
+//   - "next week" or "last week", seven days after or before today
+//   - "+N" or "-N", N days after or before today
+func Parse(s string, today time.Time) (time.Time, error) {
+	return parse(s, today, false)
+}
+
+// ParsePast is Parse for dates that start a range reaching up to today,
+// such as 'sked stats --from': a bare weekday means its latest occurrence,
+// today included, so "monday" on a Wednesday is two days ago.
+func ParsePast(s string, today time.Time) (time.Time, error) {
+	return parse(s, today, true)
+}
+
+func parse(s string, today time.Time, past bool) (time.Time, error) {
+	y, m, d := today.Date()
+	today = time.Date(y, m, d, 0, 0, 0, 0, today.Location())
+	days := func(n int) (time.Time, error) { return today.AddDate(0, 0, n), nil }
+
+	fields := strings.Fields(strings.ToLower(s))
+	switch len(fields) {
+	case 1:
+		word := fields[0]
+		switch word {
+		case "today":
+			return today, nil
+		case "tomorrow":
+			return days(1)
+		case "yesterday":
+			return days(-1)
+		}
+		if word[0] == '+' || word[0] == '-' {
+			if n, err := strconv.Atoi(word); err == nil {
+				return days(n)
+			}
+			break
+		}
+		if date, err := time.ParseInLocation("2006-01-02", word, today.Location()); err == nil {
+			return date, nil
+		}
+		if wd, ok := weekday(word); ok {
+			ahead := (int(wd) - int(today.Weekday()) + 7) % 7
+			if past && ahead > 0 {
+				return days(ahead - 7)
+			}
+			return days(ahead)
+		}
+	case 2:
+		var sign int
+		switch fields[0] {
+		case "next":
+			sign = 1
+		case "last":
+			sign = -1
+		default:
+			return time.Time{}, invalid(s)
+		}
+		if fields[1] == "week" {
+			return days(7 * sign)
+		}
+		if wd, ok := weekday(fields[1]); ok {
+			// 1 to 7 days away, never today
+			away := (sign*(int(wd)-int(today.Weekday()))+6)%7 + 1
+			return days(sign * away)
+		}
+	}
+	return time.Time{}, invalid(s)
+}
+
+// weekday returns the weekday a name such as "monday" or "fri" stands for.
+func weekday(name string) (time.Weekday, bool) {
+	return config.ParseWeekdayName(name)
+}
+
+func invalid(s string) error {
+	return fmt.Errorf("invalid date '%s' (expected %s)", strings.TrimSpace(s), Forms)
+}
@@ -0,0 +1,112 @@
+package dateparse
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// Monday, Wednesday and Sunday of the same ISO week
+	monday := time.Date(2024, 1, 1, 15, 4, 5, 0, time.UTC)
+	wednesday := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	sunday := time.Date(2024, 1, 7, 23, 59, 0, 0, time.UTC)
+
+	tests := []struct {
+		in    string
+		today time.Time
+		want  string
+		past  string // ParsePast's result, if it differs
+	}{
+		{in: "2024-02-29", today: monday, want: "2024-02-29"},
+		{in: "today", today: monday, want: "2024-01-01"},
+		{in: " Today ", today: monday, want: "2024-01-01"},
+		{in: "tomorrow", today: sunday, want: "2024-01-08"},
+		{in: "yesterday", today: monday, want: "2023-12-31"},
+
+		// A bare weekday on that weekday is today
+		{in: "monday", today: monday, want: "2024-01-01"},
+		{in: "mon", today: monday, want: "2024-01-01"},
+		{in: "tuesday", today: monday, want: "2024-01-02", past: "2023-12-26"},
+		{in: "sunday", today: monday, want: "2024-01-07", past: "2023-12-31"},
+		{in: "monday", today: wednesday, want: "2024-01-08", past: "2024-01-01"},
+		{in: "Wed", today: wednesday, want: "2024-01-03"},
+		{in: "saturday", today: sunday, want: "2024-01-13", past: "2024-01-06"},
+		{in: "monday", today: sunday, want: "2024-01-08", past: "2024-01-01"},
+		{in: "Mi", today: monday, want: "2024-01-03", past: "2023-12-27"},
+		{in: "freitag", today: monday, want: "2024-01-05", past: "2023-12-29"},
+
+		// next and last never mean today
+		{in: "next monday", today: monday, want: "2024-01-08"},
+		{in: "next tuesday", today: monday, want: "2024-01-02"},
+		{in: "next sunday", today: monday, want: "2024-01-07"},
+		{in: "next monday", today: sunday, want: "2024-01-08"},
+		{in: "next  Sun", today: sunday, want: "2024-01-14"},
+		{in: "last monday", today: monday, want: "2023-12-25"},
+		{in: "last sunday", today: monday, want: "2023-12-31"},
+		{in: "last monday", today: wednesday, want: "2024-01-01"},
+		{in: "last thursday", today: wednesday, want: "2023-12-28"},
+		{in: "last saturday", today: sunday, want: "2024-01-06"},
+		{in: "next week", today: wednesday, want: "2024-01-10"},
+		{in: "last week", today: monday, want: "2023-12-25"},
+
+		{in: "+0", today: monday, want: "2024-01-01"},
+		{in: "+3", today: monday, want: "2024-01-04"},
+		{in: "-1", today: monday, want: "2023-12-31"},
+		{in: "+31", today: monday, want: "2024-02-01"},
+		{in: "-366", today: monday, want: "2022-12-31"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in+"@"+tt.today.Weekday().String(), func(t *testing.T) {
+			got, err := Parse(tt.in, tt.today)
+			if err != nil {
+				t.Fatalf("Parse() returned error: %v", err)
+			}
+			if got.Format("2006-01-02") != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got.Format("2006-01-02"))
+			}
+			if h, m, s := got.Clock(); h != 0 || m != 0 || s != 0 || got.Location() != tt.today.Location() {
+				t.Errorf("Expected midnight in %v, got %v", tt.today.Location(), got)
+			}
+
+			past := tt.past
+			if past == "" {
+				past = tt.want
+			}
+			got, err = ParsePast(tt.in, tt.today)
+			if err != nil {
+				t.Fatalf("ParsePast() returned error: %v", err)
+			}
+			if got.Format("2006-01-02") != past {
+				t.Errorf("Expected %s from ParsePast, got %s", past, got.Format("2006-01-02"))
+			}
+		})
+	}
+}
+
+func TestParse_Local(t *testing.T) {
+	loc := time.FixedZone("UTC-10", -10*60*60)
+	today := time.Date(2024, 1, 1, 23, 30, 0, 0, loc)
+	got, err := Parse("tomorrow", today)
+	if err != nil {
+		t.Fatalf("Parse() returned error: %v", err)
+	}
+	if want := time.Date(2024, 1, 2, 0, 0, 0, 0, loc); !got.Equal(want) || got.Location() != loc {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	today := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, in := range []string{"", "soon", "2024-13-01", "01/02/2024", "3", "+", "+3d", "next", "next month", "this monday", "last monday please", "son"} {
+		t.Run(in, func(t *testing.T) {
+			_, err := Parse(in, today)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), Forms) {
+				t.Errorf("Expected the error to list the accepted forms, got %v", err)
+			}
+		})
+	}
+}
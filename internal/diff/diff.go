@@ -0,0 +1,193 @@
+// Package diff compares the events two schedules produce over a date range,
+// or the tasks of two days.
+package diff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// Kind describes how an event changed.
+type Kind string
+
+const (
+	Added    Kind = "added"
+	Removed  Kind = "removed"
+	Modified Kind = "modified"
+)
+
+// Event is a task occurrence reduced to what the diff compares.
+type Event struct {
+	Name  string `json:"name"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Change is a single difference. Old is nil for added events, New for removed ones.
+// A modified event is either Renamed or TimeShifted.
+type Change struct {
+	Kind        Kind   `json:"kind"`
+	Old         *Event `json:"old"`
+	New         *Event `json:"new"`
+	Renamed     bool   `json:"renamed,omitempty"`
+	TimeShifted bool   `json:"time_shifted,omitempty"`
+}
+
+// Day holds the changes on one date.
+type Day struct {
+	Date    string   `json:"date"`
+	Changes []Change `json:"changes"`
+}
+
+// Schedules compares the events of old and updated for days consecutive dates
+// starting at from. Only dates with changes are returned.
+func Schedules(old, updated *scheduler.Scheduler, from time.Time, days int) ([]Day, error) {
+	result := []Day{}
+	for i := 0; i < days; i++ {
+		date := from.AddDate(0, 0, i)
+		oldEvents, err := events(old, date)
+		if err != nil {
+			return nil, fmt.Errorf("old schedule: %w", err)
+		}
+		newEvents, err := events(updated, date)
+		if err != nil {
+			return nil, fmt.Errorf("new schedule: %w", err)
+		}
+		if changes := Events(oldEvents, newEvents); len(changes) > 0 {
+			result = append(result, Day{Date: date.Format("2006-01-02"), Changes: changes})
+		}
+	}
+	return result, nil
+}
+
+func events(sched *scheduler.Scheduler, date time.Time) ([]Event, error) {
+	tasks, err := sched.GetTasksForDate(date)
+	if err != nil {
+		return nil, err
+	}
+	return FromTasks(tasks), nil
+}
+
+// FromTasks reduces task instances to the events the diff compares, leaving
+// out empty slots.
+func FromTasks(tasks []scheduler.TaskEvent) []Event {
+	var evs []Event
+	for _, t := range tasks {
+		if t.RawName == "/" {
+			continue // empty slot placeholder
+		}
+		evs = append(evs, Event{Name: t.Name, Start: t.StartTime.Format("15:04"), End: t.EndTime.Format("15:04")})
+	}
+	return evs
+}
+
+// Events compares two sets of events, ignoring their order. Events that only
+// changed name (same times) or only changed times (same name) are reported as
+// modified; everything else unmatched is added or removed.
+func Events(before, after []Event) []Change {
+	before, after = unmatched(before, after), unmatched(after, before)
+
+	var changes []Change
+	pair := func(same func(a, b Event) bool) {
+		for i := 0; i < len(before); i++ {
+			for j := 0; j < len(after); j++ {
+				if same(before[i], after[j]) {
+					o, n := before[i], after[j]
+					changes = append(changes, Change{
+						Kind:        Modified,
+						Old:         &o,
+						New:         &n,
+						Renamed:     o.Name != n.Name,
+						TimeShifted: o.Start != n.Start || o.End != n.End,
+					})
+					before = append(before[:i:i], before[i+1:]...)
+					after = append(after[:j:j], after[j+1:]...)
+					i--
+					break
+				}
+			}
+		}
+	}
+	pair(func(a, b Event) bool { return a.Start == b.Start && a.End == b.End })
+	pair(func(a, b Event) bool { return a.Name == b.Name })
+
+	for i := range before {
+		changes = append(changes, Change{Kind: Removed, Old: &before[i]})
+	}
+	for i := range after {
+		changes = append(changes, Change{Kind: Added, New: &after[i]})
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].sortKey() < changes[j].sortKey()
+	})
+	return changes
+}
+
+// unmatched returns the events of a without an identical counterpart in b,
+// matching duplicates one to one.
+func unmatched(a, b []Event) []Event {
+	remaining := make(map[Event]int)
+	for _, e := range b {
+		remaining[e]++
+	}
+	var out []Event
+	for _, e := range a {
+		if remaining[e] > 0 {
+			remaining[e]--
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func (c Change) sortKey() string {
+	if c.Old != nil {
+		return c.Old.Start
+	}
+	return c.New.Start
+}
+
+// Write prints the changes grouped by date.
+func Write(w io.Writer, days []Day) {
+	if len(days) == 0 {
+		fmt.Fprintln(w, "No differences.")
+		return
+	}
+	for i, d := range days {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		date, _ := time.Parse("2006-01-02", d.Date)
+		fmt.Fprintf(w, "%s (%s)\n", d.Date, date.Weekday())
+		WriteChanges(w, d.Changes)
+	}
+}
+
+// WriteChanges prints one indented line per change: "+" for added, "-" for
+// removed and "~" for modified events.
+func WriteChanges(w io.Writer, changes []Change) {
+	for _, c := range changes {
+		switch c.Kind {
+		case Added:
+			fmt.Fprintf(w, "  + %s %s-%s\n", c.New.Name, c.New.Start, c.New.End)
+		case Removed:
+			fmt.Fprintf(w, "  - %s %s-%s\n", c.Old.Name, c.Old.Start, c.Old.End)
+		case Modified:
+			if c.Renamed {
+				fmt.Fprintf(w, "  ~ %s -> %s %s-%s", c.Old.Name, c.New.Name, c.Old.Start, c.Old.End)
+			} else {
+				fmt.Fprintf(w, "  ~ %s %s-%s", c.Old.Name, c.Old.Start, c.Old.End)
+			}
+			if c.TimeShifted {
+				fmt.Fprintf(w, " -> %s-%s", c.New.Start, c.New.End)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
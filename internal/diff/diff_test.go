@@ -0,0 +1,97 @@
+package diff
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func TestEvents(t *testing.T) {
+	math := Event{Name: "Math", Start: "09:00", End: "10:00"}
+	art := Event{Name: "Art", Start: "11:00", End: "12:00"}
+
+	tests := []struct {
+		name   string
+		before []Event
+		after  []Event
+		want   []Kind
+	}{
+		{name: "identical", before: []Event{math, art}, after: []Event{math, art}},
+		{name: "reordered", before: []Event{math, art}, after: []Event{art, math}},
+		{name: "added", before: []Event{math}, after: []Event{math, art}, want: []Kind{Added}},
+		{name: "removed", before: []Event{math, art}, after: []Event{art}, want: []Kind{Removed}},
+		{name: "renamed", before: []Event{math}, after: []Event{{Name: "Algebra", Start: "09:00", End: "10:00"}}, want: []Kind{Modified}},
+		{name: "moved", before: []Event{math}, after: []Event{{Name: "Math", Start: "09:30", End: "10:30"}}, want: []Kind{Modified}},
+		{name: "replaced", before: []Event{math}, after: []Event{art}, want: []Kind{Removed, Added}},
+		{name: "duplicate_dropped", before: []Event{math, math}, after: []Event{math}, want: []Kind{Removed}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Events(tt.before, tt.after)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d changes, got %d: %+v", len(tt.want), len(got), got)
+			}
+			for i, kind := range tt.want {
+				if got[i].Kind != kind {
+					t.Errorf("Change %d: expected %s, got %s", i, kind, got[i].Kind)
+				}
+			}
+		})
+	}
+}
+
+func TestEventsFlags(t *testing.T) {
+	math := Event{Name: "Math", Start: "09:00", End: "10:00"}
+	tests := []struct {
+		name                 string
+		after                Event
+		renamed, timeShifted bool
+	}{
+		{name: "renamed", after: Event{Name: "Algebra", Start: "09:00", End: "10:00"}, renamed: true},
+		{name: "moved", after: Event{Name: "Math", Start: "09:30", End: "10:00"}, timeShifted: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Events([]Event{math}, []Event{tt.after})
+			if len(got) != 1 || got[0].Kind != Modified {
+				t.Fatalf("Expected one modified change, got %+v", got)
+			}
+			if got[0].Renamed != tt.renamed || got[0].TimeShifted != tt.timeShifted {
+				t.Errorf("Expected renamed=%v time_shifted=%v, got %+v", tt.renamed, tt.timeShifted, got[0])
+			}
+		})
+	}
+}
+
+func TestSchedules(t *testing.T) {
+	oldCfg := &config.Config{CycleDays: 7, Days: []config.Day{
+		{ID: 1, Tasks: []config.Task{{Name: "Math", Start: "09:00", End: "10:00"}, {Name: "Art", Start: "11:00", End: "12:00"}}},
+		{ID: 2, Tasks: []config.Task{{Name: "History", Start: "09:00", End: "10:00"}}},
+	}}
+	newCfg := &config.Config{CycleDays: 7, Days: []config.Day{
+		// Same Monday in a different order
+		{ID: 1, Tasks: []config.Task{{Name: "Art", Start: "11:00", End: "12:00"}, {Name: "Math", Start: "09:00", End: "10:00"}}},
+		{ID: 2, Tasks: []config.Task{{Name: "History", Start: "10:00", End: "11:00"}}},
+	}}
+
+	// Monday Jan 1, 2024
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	days, err := Schedules(scheduler.New(oldCfg), scheduler.New(newCfg), from, 7)
+	if err != nil {
+		t.Fatalf("Schedules() returned error: %v", err)
+	}
+	if len(days) != 1 || days[0].Date != "2024-01-02" {
+		t.Fatalf("Expected changes only on 2024-01-02, got %+v", days)
+	}
+
+	var buf bytes.Buffer
+	Write(&buf, days)
+	want := "2024-01-02 (Tuesday)\n  ~ History 09:00-10:00 -> 10:00-11:00\n"
+	if buf.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
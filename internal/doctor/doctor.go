@@ -0,0 +1,461 @@
+// Package doctor implements environment and configuration health checks.
+package doctor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/conflicts"
+)
+
+// Status is the outcome of a single check.
+type Status int
+
+const (
+	Pass Status = iota
+	Warn
+	Fail
+)
+
+func (s Status) String() string {
+	switch s {
+	case Pass:
+		return "PASS"
+	case Warn:
+		return "WARN"
+	default:
+		return "FAIL"
+	}
+}
+
+// Result describes the outcome of a check and how to fix it.
+type Result struct {
+	Name    string
+	Status  Status
+	Message string
+	Hint    string
+}
+
+// Env abstracts the parts of the system the checks look at, so tests can
+// substitute a fake filesystem and a stubbed exec lookup.
+type Env struct {
+	GOOS         string
+	Stat         func(name string) (fs.FileInfo, error)
+	ReadFile     func(name string) ([]byte, error)
+	LookPath     func(file string) (string, error)
+	LoadLocation func(name string) (*time.Location, error)
+	Getenv       func(key string) string
+	// CanWrite reports whether files can be created in dir.
+	CanWrite func(dir string) error
+	// IsTerminal reports whether stdout is a terminal.
+	IsTerminal func() bool
+}
+
+// SystemEnv returns an Env backed by the real operating system.
+func SystemEnv() Env {
+	return Env{
+		GOOS:         runtime.GOOS,
+		Stat:         os.Stat,
+		ReadFile:     os.ReadFile,
+		LookPath:     exec.LookPath,
+		LoadLocation: time.LoadLocation,
+		Getenv:       os.Getenv,
+		CanWrite: func(dir string) error {
+			f, err := os.CreateTemp(dir, ".sked-doctor-*")
+			if err != nil {
+				return err
+			}
+			f.Close()
+			return os.Remove(f.Name())
+		},
+		IsTerminal: func() bool {
+			fi, err := os.Stdout.Stat()
+			return err == nil && fi.Mode()&os.ModeCharDevice != 0
+		},
+	}
+}
+
+// Run executes all checks against the config at path (which need not exist).
+func Run(env Env, path string) []Result {
+	file := CheckConfigFile(env, path)
+	results := []Result{file, CheckConfigDir(env, filepath.Dir(path))}
+
+	var cfg *config.Config
+	if file.Status == Pass {
+		results = append(results, CheckSources(env, path)...)
+
+		var res Result
+		cfg, res = CheckConfigParse(path)
+		results = append(results, res)
+		if cfg != nil {
+			results = append(results, CheckWarnings(cfg), CheckTimes(cfg), CheckAnchor(cfg), CheckConflicts(cfg), CheckOverrides(cfg))
+		}
+	}
+
+	results = append(results,
+		CheckNotifier(env, cfg),
+		CheckTimezone(env),
+		CheckTerminal(env),
+	)
+	return results
+}
+
+// CheckConfigFile verifies that the config file exists.
+func CheckConfigFile(env Env, path string) Result {
+	r := Result{Name: "config file"}
+	if _, err := env.Stat(path); err != nil {
+		r.Status = Fail
+		r.Message = fmt.Sprintf("%s: %v", path, err)
+		r.Hint = "run 'sked init' (or 'sked' in a terminal) to create a default config, or pass --config"
+		return r
+	}
+	r.Message = path
+	return r
+}
+
+// CheckConfigSource reports how the config file was chosen (see
+// config.Resolve), noting a $SKED_CONFIG that --config overrides.
+func CheckConfigSource(env Env, source config.Source) Result {
+	r := Result{Name: "config source", Message: string(source)}
+	if v := env.Getenv(config.ConfigEnv); v != "" && source == config.SourceFlag {
+		r.Message += fmt.Sprintf(" (overrides %s=%s)", config.ConfigEnv, v)
+	}
+	return r
+}
+
+// CheckConfigDir verifies that the config directory is writable, which is
+// needed to create the default config and sample files.
+func CheckConfigDir(env Env, dir string) Result {
+	r := Result{Name: "config dir"}
+	if _, err := env.Stat(dir); err != nil {
+		r.Status = Warn
+		r.Message = fmt.Sprintf("%s does not exist", dir)
+		r.Hint = fmt.Sprintf("create it with 'mkdir -p %s'", dir)
+		return r
+	}
+	if err := env.CanWrite(dir); err != nil {
+		r.Status = Warn
+		r.Message = fmt.Sprintf("%s is not writable: %v", dir, err)
+		r.Hint = "fix the directory permissions; sked cannot create its default config there"
+		return r
+	}
+	r.Message = fmt.Sprintf("%s is writable", dir)
+	return r
+}
+
+// CheckSources verifies that every CSV file the config refers to exists, is
+// readable and doesn't start with a byte order mark. It works from the raw
+// csv_path/tmp_csv_path values so it still reports on files when the
+// schedule itself fails to load.
+func CheckSources(env Env, path string) []Result {
+	if ext := filepath.Ext(path); strings.EqualFold(ext, ".csv") || strings.EqualFold(ext, ".xlsx") {
+		return []Result{checkCSV(env, "csv", path, Fail)}
+	}
+	if _, err := env.Stat(path); err != nil {
+		return nil
+	}
+
+	csvPath, tmpPath, err := config.ReferencedFiles(path)
+	if err != nil {
+		// Reported by CheckConfigParse.
+		return nil
+	}
+
+	var results []Result
+	if csvPath != "" {
+		results = append(results, checkCSV(env, "csv_path", csvPath, Fail))
+	}
+	if tmpPath != "" {
+		// The temporary schedule is only used on demand.
+		results = append(results, checkCSV(env, "tmp_csv_path", tmpPath, Warn))
+	}
+	return results
+}
+
+func checkCSV(env Env, name, path string, missing Status) Result {
+	r := Result{Name: name}
+	if _, err := env.Stat(path); err != nil {
+		r.Status = missing
+		r.Message = fmt.Sprintf("%s: %v", path, err)
+		r.Hint = "create the file or fix the path in your config"
+		return r
+	}
+	data, err := env.ReadFile(path)
+	if err != nil {
+		r.Status = Fail
+		r.Message = fmt.Sprintf("%s is not readable: %v", path, err)
+		r.Hint = "fix the file permissions"
+		return r
+	}
+	if bytes.HasPrefix(data, []byte("\xef\xbb\xbf")) {
+		r.Status = Fail
+		r.Message = fmt.Sprintf("%s starts with a UTF-8 byte order mark, which breaks header detection", path)
+		r.Hint = "re-save the file as UTF-8 without BOM (spreadsheet apps often add one)"
+		return r
+	}
+	r.Message = path
+	return r
+}
+
+// CheckConfigParse loads and validates the config. It returns the config
+// when loading succeeded.
+func CheckConfigParse(path string) (*config.Config, Result) {
+	r := Result{Name: "config syntax"}
+	cfg, err := config.Load(path)
+	if err != nil {
+		r.Status = Fail
+		r.Message = err.Error()
+		r.Hint = "compare your config with sample_config.toml"
+		return nil, r
+	}
+	if err := cfg.Validate(); err != nil {
+		r.Status = Fail
+		r.Message = err.Error()
+		r.Hint = "compare your config with sample_config.toml"
+		return cfg, r
+	}
+	if msg := cfg.EmptyWarning(); msg != "" {
+		r.Status = Fail
+		r.Message = msg
+		r.Hint = "name the day columns Mon to Sun (or Monday to Sunday) or by day ID, or add tasks to the [[day]] tables"
+		return cfg, r
+	}
+	tasks := 0
+	for _, d := range cfg.Days {
+		tasks += len(d.Tasks)
+	}
+	r.Message = fmt.Sprintf("%d days, %d tasks", len(cfg.Days), tasks)
+	return cfg, r
+}
+
+// CheckWarnings warns about data the loaders skipped, such as CSV rows
+// without a start time or header columns that aren't day names. The anchor
+// warning is left to CheckAnchor.
+func CheckWarnings(cfg *config.Config) Result {
+	r := Result{Name: "skipped data"}
+	anchor := cfg.AnchorWarning()
+	warnings := slices.DeleteFunc(slices.Clone(cfg.Warnings), func(w config.Warning) bool {
+		return anchor != "" && w.Message == anchor
+	})
+	if len(warnings) == 0 {
+		r.Message = "nothing was skipped while loading"
+		return r
+	}
+	var lines []string
+	for i, w := range warnings {
+		if i == maxConflicts {
+			lines = append(lines, fmt.Sprintf("and %d more", len(warnings)-maxConflicts))
+			break
+		}
+		lines = append(lines, w.String())
+	}
+	r.Status = Warn
+	r.Message = fmt.Sprintf("%d warning(s): %s", len(warnings), strings.Join(lines, "; "))
+	r.Hint = "fix or remove the reported rows and columns; sked prints these warnings unless --quiet"
+	return r
+}
+
+// CheckTimes verifies that every task has valid HH:MM start and end times.
+func CheckTimes(cfg *config.Config) Result {
+	r := Result{Name: "task times"}
+	var bad []string
+	for _, d := range cfg.Days {
+		for _, t := range d.Tasks {
+			if _, err := time.Parse("15:04", t.Start); err != nil {
+				bad = append(bad, fmt.Sprintf("day %d '%s' start %q", d.ID, t.Name, t.Start))
+			}
+			if _, err := time.Parse("15:04", t.End); err != nil {
+				bad = append(bad, fmt.Sprintf("day %d '%s' end %q", d.ID, t.Name, t.End))
+			}
+		}
+	}
+	if len(bad) > 0 {
+		r.Status = Fail
+		r.Message = "invalid times: " + strings.Join(bad, ", ")
+		r.Hint = "use 24-hour HH:MM times, e.g. 09:00 or 14:30"
+		return r
+	}
+	r.Message = "all times are valid HH:MM"
+	return r
+}
+
+// CheckAnchor warns when a 7-day cycle's anchor puts day IDs out of step
+// with weekdays.
+func CheckAnchor(cfg *config.Config) Result {
+	r := Result{Name: "anchor"}
+	if w := cfg.AnchorWarning(); w != "" {
+		r.Status = Warn
+		r.Message = w
+		r.Hint = "with 7 days, id 0 is Sunday and 1 is Monday unless the anchor says otherwise"
+		return r
+	}
+	switch {
+	case cfg.AnchorDate == "":
+		r.Message = "days follow weekdays"
+	default:
+		r.Message = fmt.Sprintf("%s is day %d", cfg.AnchorDate, cfg.AnchorDayID)
+	}
+	return r
+}
+
+// CheckConflicts runs the cycle-wide conflict report and fails on overlapping
+// or empty tasks and borrowed days without tasks; duplicates only warn.
+func CheckConflicts(cfg *config.Config) Result {
+	r := Result{Name: "conflicts"}
+	report := conflicts.Check(cfg)
+	switch {
+	case report.Errors > 0:
+		r.Status = Fail
+	case report.Warnings > 0:
+		r.Status = Warn
+	default:
+		r.Message = fmt.Sprintf("no conflicts in %d cycle day(s)", cfg.CycleDays)
+		return r
+	}
+	r.Message = fmt.Sprintf("%d error(s), %d warning(s)", report.Errors, report.Warnings)
+	r.Hint = "run 'sked conflicts' for details"
+	return r
+}
+
+// maxConflicts is how many colliding override dates CheckOverrides lists.
+const maxConflicts = 5
+
+// CheckOverrides warns about dates matched by more than one override and
+// names the override that wins on each.
+func CheckOverrides(cfg *config.Config) Result {
+	r := Result{Name: "overrides"}
+	conflicts := cfg.OverrideConflicts()
+	if len(conflicts) == 0 {
+		r.Message = fmt.Sprintf("%d override(s), no overlapping dates", len(cfg.Overrides))
+		return r
+	}
+
+	var lines []string
+	for i, c := range conflicts {
+		if i == maxConflicts {
+			lines = append(lines, fmt.Sprintf("and %d more", len(conflicts)-maxConflicts))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s uses %s", c.Date.Format("2006-01-02"), cfg.Overrides[c.Overrides[0]].Describe()))
+	}
+	r.Status = Warn
+	r.Message = fmt.Sprintf("%d date(s) match several overrides: %s", len(conflicts), strings.Join(lines, "; "))
+	r.Hint = "single dates beat ranges, later entries beat earlier ones; see 'sked override list'"
+	return r
+}
+
+// CheckNotifier verifies that the desktop notification command is available.
+// cfg may be nil if the config failed to load.
+func CheckNotifier(env Env, cfg *config.Config) Result {
+	r := Result{Name: "notifications"}
+
+	var bin, hint string
+	switch {
+	case cfg != nil && len(cfg.NotifyCommand) > 0:
+		bin, hint = cfg.NotifyCommand[0], "check notify_command in your config"
+	case env.GOOS == "linux":
+		// gdbus is preferred, as it lets reminders replace each other;
+		// notify-send is the fallback.
+		if found, err := env.LookPath("gdbus"); err == nil {
+			r.Message = found
+			return r
+		}
+		bin, hint = "notify-send", "install libnotify (e.g. 'apt install libnotify-bin' or 'dnf install libnotify')"
+	case env.GOOS == "darwin":
+		bin, hint = "osascript", "osascript ships with macOS; check your PATH"
+	case env.GOOS == "windows":
+		bin, hint = "powershell", "make sure PowerShell is installed and on PATH"
+	default:
+		r.Status = Warn
+		r.Message = fmt.Sprintf("no desktop notification support on %s", env.GOOS)
+		r.Hint = "set notify_command or a [notify.webhook] in your config"
+		return r
+	}
+
+	found, err := env.LookPath(bin)
+	if err != nil {
+		r.Status = Warn
+		r.Message = fmt.Sprintf("%s not found; --notify-ahead will not work", bin)
+		r.Hint = hint
+		return r
+	}
+	r.Message = found
+	return r
+}
+
+// CheckTimezone verifies that the time zone database is available and that
+// TZ, if set, names a known zone.
+func CheckTimezone(env Env) Result {
+	r := Result{Name: "timezone"}
+	if tz := env.Getenv("TZ"); tz != "" {
+		if _, err := env.LoadLocation(strings.TrimPrefix(tz, ":")); err != nil {
+			r.Status = Warn
+			r.Message = fmt.Sprintf("TZ=%s is not a known time zone; times will be shown in UTC", tz)
+			r.Hint = "set TZ to an IANA name such as Europe/Berlin, or unset it"
+			return r
+		}
+	}
+	if _, err := env.LoadLocation("America/New_York"); err != nil {
+		r.Status = Warn
+		r.Message = "time zone database not found"
+		r.Hint = "install your system's tzdata package or set ZONEINFO"
+		return r
+	}
+	r.Message = "time zone database available"
+	return r
+}
+
+// CheckTerminal verifies that the TUI (sked show) can run here.
+func CheckTerminal(env Env) Result {
+	r := Result{Name: "terminal"}
+	if !env.IsTerminal() {
+		r.Status = Warn
+		r.Message = "stdout is not a terminal; 'sked show' needs an interactive terminal"
+		r.Hint = "run sked show directly in a terminal emulator"
+		return r
+	}
+	if term := env.Getenv("TERM"); term == "dumb" {
+		r.Status = Warn
+		r.Message = "TERM=dumb; the TUI cannot draw"
+		r.Hint = "set TERM to your terminal type, e.g. xterm-256color"
+		return r
+	}
+	r.Message = "interactive terminal"
+	return r
+}
+
+// Write prints results as aligned PASS/WARN/FAIL lines with hints for
+// anything that didn't pass.
+func Write(w io.Writer, results []Result) {
+	width := 0
+	for _, r := range results {
+		width = max(width, len(r.Name))
+	}
+	for _, r := range results {
+		fmt.Fprintf(w, "%s  %-*s  %s\n", r.Status, width, r.Name, r.Message)
+		if r.Status != Pass && r.Hint != "" {
+			fmt.Fprintf(w, "      %-*s  hint: %s\n", width, "", r.Hint)
+		}
+	}
+}
+
+// Failures counts the failed checks.
+func Failures(results []Result) int {
+	n := 0
+	for _, r := range results {
+		if r.Status == Fail {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,309 @@
+package doctor
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+// fakeEnv returns an Env backed by an in-memory filesystem rooted at "/".
+func fakeEnv(files fstest.MapFS, bins ...string) Env {
+	trim := func(name string) string { return strings.TrimPrefix(name, "/") }
+	return Env{
+		GOOS:     "linux",
+		Stat:     func(name string) (fs.FileInfo, error) { return fs.Stat(files, trim(name)) },
+		ReadFile: func(name string) ([]byte, error) { return fs.ReadFile(files, trim(name)) },
+		LookPath: func(file string) (string, error) {
+			for _, b := range bins {
+				if b == file {
+					return "/usr/bin/" + file, nil
+				}
+			}
+			return "", errors.New("executable file not found in $PATH")
+		},
+		LoadLocation: time.LoadLocation,
+		Getenv:       func(string) string { return "" },
+		CanWrite:     func(string) error { return nil },
+		IsTerminal:   func() bool { return true },
+	}
+}
+
+func TestCheckSources(t *testing.T) {
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(cfgPath, []byte("csv_path = \"week.csv\"\ntmp_csv_path = \"tmp.csv\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	csvPath := filepath.Join(dir, "week.csv")
+
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		want  []Status
+	}{
+		{
+			name:  "all_present",
+			files: fstest.MapFS{rel(csvPath): {Data: []byte("Start,End,Mon\n")}, rel(dir, "tmp.csv"): {}},
+			want:  []Status{Pass, Pass},
+		},
+		{
+			name:  "bom",
+			files: fstest.MapFS{rel(csvPath): {Data: []byte("\xef\xbb\xbfStart,End,Mon\n")}, rel(dir, "tmp.csv"): {}},
+			want:  []Status{Fail, Pass},
+		},
+		{
+			name:  "missing",
+			files: fstest.MapFS{},
+			want:  []Status{Fail, Warn},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := fakeEnv(tt.files)
+			// The config itself is read from disk; only the CSVs are faked.
+			env.Stat = func(name string) (fs.FileInfo, error) {
+				if name == cfgPath {
+					return os.Stat(name)
+				}
+				return fs.Stat(tt.files, rel(name))
+			}
+			got := CheckSources(env, cfgPath)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d results, got %d: %+v", len(tt.want), len(got), got)
+			}
+			for i, want := range tt.want {
+				if got[i].Status != want {
+					t.Errorf("%s: expected %s, got %s (%s)", got[i].Name, want, got[i].Status, got[i].Message)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckTimes(t *testing.T) {
+	cfg := &config.Config{Days: []config.Day{{ID: 1, Tasks: []config.Task{
+		{Name: "Math", Start: "09:00", End: "10:00"},
+		{Name: "Art", Start: "9am", End: "10:00"},
+	}}}}
+	r := CheckTimes(cfg)
+	if r.Status != Fail {
+		t.Fatalf("Expected FAIL, got %s", r.Status)
+	}
+	if !strings.Contains(r.Message, "Art") || strings.Contains(r.Message, "Math") {
+		t.Errorf("Expected only Art to be reported, got %q", r.Message)
+	}
+
+	cfg.Days[0].Tasks = cfg.Days[0].Tasks[:1]
+	if r := CheckTimes(cfg); r.Status != Pass {
+		t.Errorf("Expected PASS, got %s (%s)", r.Status, r.Message)
+	}
+}
+
+func TestCheckNotifier(t *testing.T) {
+	tests := []struct {
+		name string
+		goos string
+		bins []string
+		cfg  *config.Config
+		want Status
+	}{
+		{name: "linux_present", goos: "linux", bins: []string{"notify-send"}, want: Pass},
+		{name: "linux_gdbus", goos: "linux", bins: []string{"gdbus"}, want: Pass},
+		{name: "linux_missing", goos: "linux", want: Warn},
+		{name: "darwin", goos: "darwin", bins: []string{"osascript"}, want: Pass},
+		{name: "custom_command", goos: "linux", bins: []string{"dunstify"}, cfg: &config.Config{NotifyCommand: []string{"dunstify", "{title}"}}, want: Pass},
+		{name: "unsupported", goos: "plan9", want: Warn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := fakeEnv(nil, tt.bins...)
+			env.GOOS = tt.goos
+			if r := CheckNotifier(env, tt.cfg); r.Status != tt.want {
+				t.Errorf("Expected %s, got %s (%s)", tt.want, r.Status, r.Message)
+			}
+		})
+	}
+}
+
+func TestCheckTimezone(t *testing.T) {
+	env := fakeEnv(nil)
+	env.LoadLocation = func(name string) (*time.Location, error) {
+		if name == "Mars/Olympus" {
+			return nil, errors.New("unknown time zone")
+		}
+		return time.UTC, nil
+	}
+	if r := CheckTimezone(env); r.Status != Pass {
+		t.Errorf("Expected PASS, got %s (%s)", r.Status, r.Message)
+	}
+
+	env.Getenv = func(key string) string {
+		if key == "TZ" {
+			return "Mars/Olympus"
+		}
+		return ""
+	}
+	if r := CheckTimezone(env); r.Status != Warn {
+		t.Errorf("Expected WARN for unknown TZ, got %s", r.Status)
+	}
+}
+
+func TestCheckTerminal(t *testing.T) {
+	env := fakeEnv(nil)
+	if r := CheckTerminal(env); r.Status != Pass {
+		t.Errorf("Expected PASS, got %s", r.Status)
+	}
+	env.IsTerminal = func() bool { return false }
+	if r := CheckTerminal(env); r.Status != Warn {
+		t.Errorf("Expected WARN without a terminal, got %s", r.Status)
+	}
+}
+
+func TestCheckConfigSource(t *testing.T) {
+	env := fakeEnv(nil)
+	if r := CheckConfigSource(env, config.SourceProject); r.Status != Pass || r.Message != "project file" {
+		t.Errorf("Expected PASS naming the project file, got %s (%s)", r.Status, r.Message)
+	}
+	env.Getenv = func(key string) string {
+		if key == config.ConfigEnv {
+			return "/work/sked.toml"
+		}
+		return ""
+	}
+	if r := CheckConfigSource(env, config.SourceFlag); r.Message != "--config (overrides SKED_CONFIG=/work/sked.toml)" {
+		t.Errorf("Expected the overridden SKED_CONFIG to be noted, got %q", r.Message)
+	}
+}
+
+func TestCheckConfigDir(t *testing.T) {
+	env := fakeEnv(fstest.MapFS{"cfg": {Mode: fs.ModeDir}})
+	if r := CheckConfigDir(env, "/cfg"); r.Status != Pass {
+		t.Errorf("Expected PASS, got %s (%s)", r.Status, r.Message)
+	}
+	env.CanWrite = func(string) error { return fs.ErrPermission }
+	if r := CheckConfigDir(env, "/cfg"); r.Status != Warn {
+		t.Errorf("Expected WARN for read-only dir, got %s", r.Status)
+	}
+	if r := CheckConfigDir(env, "/missing"); r.Status != Warn {
+		t.Errorf("Expected WARN for missing dir, got %s", r.Status)
+	}
+}
+
+func TestCheckWarnings(t *testing.T) {
+	cfg := &config.Config{}
+	if r := CheckWarnings(cfg); r.Status != Pass {
+		t.Errorf("Expected PASS, got %s (%s)", r.Status, r.Message)
+	}
+
+	cfg.Warnings = []config.Warning{{File: "week.csv", Line: 1, Column: 4, Message: "column 'Thrsday' is not Start, End, Tags or a day name; ignored"}}
+	r := CheckWarnings(cfg)
+	if r.Status != Warn || !strings.Contains(r.Message, "week.csv:1:4: column 'Thrsday'") {
+		t.Errorf("Expected a warning naming the column, got %+v", r)
+	}
+}
+
+func TestCheckAnchor(t *testing.T) {
+	// 2025-01-20 is a Monday
+	cfg := &config.Config{CycleDays: 7, AnchorDate: "2025-01-20", Days: []config.Day{{ID: 1}}}
+	r := CheckAnchor(cfg)
+	if r.Status != Warn || !strings.Contains(r.Message, "set anchor_day_id = 1") {
+		t.Errorf("Expected a warning suggesting anchor_day_id = 1, got %+v", r)
+	}
+
+	cfg.AnchorDayID = 1
+	if r := CheckAnchor(cfg); r.Status != Pass {
+		t.Errorf("Expected PASS, got %s (%s)", r.Status, r.Message)
+	}
+
+	// The loader's copy of the warning is left to this check
+	cfg.AnchorDayID = 0
+	cfg.Warnings = []config.Warning{{File: "config.toml", Line: 3, Message: cfg.AnchorWarning()}}
+	if r := CheckWarnings(cfg); r.Status != Pass {
+		t.Errorf("Expected the anchor warning to be skipped by CheckWarnings, got %s (%s)", r.Status, r.Message)
+	}
+}
+
+func TestRun_Failures(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(path, []byte("cycle_days = 6\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	env := SystemEnv()
+	env.LookPath = func(string) (string, error) { return "/usr/bin/notify-send", nil }
+	env.IsTerminal = func() bool { return true }
+	results := Run(env, path)
+	if n := Failures(results); n != 1 {
+		t.Errorf("Expected 1 failure (missing anchor_date), got %d: %+v", n, results)
+	}
+
+	var buf bytes.Buffer
+	Write(&buf, results)
+	if !strings.Contains(buf.String(), "FAIL  config syntax") || !strings.Contains(buf.String(), "hint:") {
+		t.Errorf("Unexpected report:\n%s", buf.String())
+	}
+}
+
+func TestCheckConfigParse_Empty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "week.csv")
+	if err := os.WriteFile(path, []byte("Start,End,A-Woche\n09:00,10:00,Mathe\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	_, r := CheckConfigParse(path)
+	if r.Status != Fail || !strings.Contains(r.Message, "no tasks") || !strings.Contains(r.Message, "'A-Woche'") {
+		t.Errorf("Expected a failure naming the header, got %+v", r)
+	}
+}
+
+// rel joins elems and strips the leading slash for use as an fstest.MapFS key.
+func rel(elems ...string) string {
+	return strings.TrimPrefix(filepath.Join(elems...), "/")
+}
+
+func TestCheckOverrides(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 7, d, 0, 0, 0, 0, time.UTC) }
+	cfg := &config.Config{Overrides: []config.Override{
+		{Date: day(1), EndDate: day(14), IsOff: true, Note: "PTO"},
+		{Date: day(3), EndDate: day(3), UseDayID: 1},
+	}}
+	r := CheckOverrides(cfg)
+	if r.Status != Warn || !strings.Contains(r.Message, "2025-07-03 uses 2025-07-03 as day 1") {
+		t.Errorf("Expected a warning naming the single-date override, got %+v", r)
+	}
+
+	cfg.Overrides = cfg.Overrides[:1]
+	if r := CheckOverrides(cfg); r.Status != Pass {
+		t.Errorf("Expected pass without overlaps, got %+v", r)
+	}
+}
+
+func TestCheckConflicts(t *testing.T) {
+	cfg := &config.Config{CycleDays: 7, Days: []config.Day{{ID: 1, Tasks: []config.Task{
+		{Name: "Math", Start: "09:00", End: "10:00"},
+		{Name: "Math", Start: "09:00", End: "10:00"},
+	}}}}
+	if r := CheckConflicts(cfg); r.Status != Warn {
+		t.Errorf("Expected a warning for a duplicate, got %+v", r)
+	}
+
+	cfg.Days[0].Tasks[1] = config.Task{Name: "Art", Start: "09:30", End: "10:30"}
+	if r := CheckConflicts(cfg); r.Status != Fail || !strings.Contains(r.Message, "1 error(s)") {
+		t.Errorf("Expected a failure for an overlap, got %+v", r)
+	}
+
+	cfg.Days[0].Tasks = cfg.Days[0].Tasks[:1]
+	if r := CheckConflicts(cfg); r.Status != Pass {
+		t.Errorf("Expected pass without conflicts, got %+v", r)
+	}
+}
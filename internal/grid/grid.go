@@ -0,0 +1,199 @@
+// Package grid lays out consecutive days of a schedule as a table with one
+// column per day and one row per time slot, for 'sked week'.
+package grid
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/output"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// OffMarker labels the columns of off days.
+const OffMarker = "OFF"
+
+// Day is one column: a date as resolved through overrides.
+type Day struct {
+	Date  time.Time
+	IsOff bool
+	Note  string
+	Tasks []scheduler.TaskEvent // without empty slots
+}
+
+// Slot is one row, a start and end time shared by tasks on any of the days.
+type Slot struct {
+	Start, End string // HH:MM
+}
+
+// Grid holds the days and the union of their slots, sorted by start and
+// then end time.
+type Grid struct {
+	Days  []Day
+	Slots []Slot
+}
+
+// Build resolves count days from start through sched.
+func Build(sched *scheduler.Scheduler, start time.Time, count int) (*Grid, error) {
+	g := &Grid{}
+	seen := make(map[Slot]bool)
+	for i := range count {
+		date := start.AddDate(0, 0, i)
+		info, err := sched.GetDayInfo(date)
+		if err != nil {
+			return nil, err
+		}
+		tasks, err := sched.GetTasksForDate(date)
+		if err != nil {
+			return nil, err
+		}
+		day := Day{Date: info.Date, IsOff: info.IsOff, Note: info.Note}
+		for _, t := range tasks {
+			if t.RawName == "/" {
+				continue
+			}
+			day.Tasks = append(day.Tasks, t)
+			slot := slotOf(t)
+			if !seen[slot] {
+				seen[slot] = true
+				g.Slots = append(g.Slots, slot)
+			}
+		}
+		g.Days = append(g.Days, day)
+	}
+	sort.Slice(g.Slots, func(i, j int) bool {
+		if g.Slots[i].Start != g.Slots[j].Start {
+			return g.Slots[i].Start < g.Slots[j].Start
+		}
+		return g.Slots[i].End < g.Slots[j].End
+	})
+	return g, nil
+}
+
+func slotOf(t scheduler.TaskEvent) Slot {
+	return Slot{Start: t.StartTime.Format("15:04"), End: t.EndTime.Format("15:04")}
+}
+
+// Label returns the row title of the slot with its times on clock, e.g.
+// "09:00-10:00".
+func (s Slot) Label(clock config.Clock) string {
+	start, err1 := time.Parse("15:04", s.Start)
+	end, err2 := time.Parse("15:04", s.End)
+	if err1 != nil || err2 != nil {
+		return s.Start + "-" + s.End
+	}
+	return clock.Format(start) + "-" + clock.Format(end)
+}
+
+// Cell returns the names of the day's tasks in slot, joined by " / ".
+func (d Day) Cell(slot Slot) string {
+	var names []string
+	for _, t := range d.Tasks {
+		if slotOf(t) == slot {
+			names = append(names, t.Name)
+		}
+	}
+	return strings.Join(names, " / ")
+}
+
+// Header returns the column title of the day, e.g. "Mon 01-08 OFF".
+func (d Day) Header() string {
+	h := d.Date.Format("Mon 01-02")
+	if d.IsOff {
+		h += " " + OffMarker
+	}
+	return h
+}
+
+// WriteText prints g as aligned plain-text columns, with times on clock.
+func WriteText(w io.Writer, g *Grid, clock config.Clock) error {
+	var buf strings.Builder
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	row := []string{"Time"}
+	for _, d := range g.Days {
+		row = append(row, d.Header())
+	}
+	fmt.Fprintln(tw, strings.Join(row, "\t"))
+	for _, s := range g.Slots {
+		row = []string{s.Label(clock)}
+		for _, d := range g.Days {
+			row = append(row, d.Cell(s))
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	// Empty cells at the end of a row would leave trailing padding
+	for _, line := range strings.SplitAfter(buf.String(), "\n") {
+		if line == "" {
+			continue
+		}
+		if _, err := io.WriteString(w, strings.TrimRight(line, " \n")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteMarkdown prints g as a GitHub-flavored Markdown table, with times on
+// clock.
+func WriteMarkdown(w io.Writer, g *Grid, clock config.Clock) error {
+	row := []string{"Time"}
+	sep := []string{"---"}
+	for _, d := range g.Days {
+		row = append(row, markdownEscape(d.Header()))
+		sep = append(sep, "---")
+	}
+	lines := []string{markdownRow(row), markdownRow(sep)}
+	for _, s := range g.Slots {
+		row = []string{s.Label(clock)}
+		for _, d := range g.Days {
+			row = append(row, markdownEscape(d.Cell(s)))
+		}
+		lines = append(lines, markdownRow(row))
+	}
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+func markdownRow(cells []string) string {
+	return "| " + strings.Join(cells, " | ") + " |"
+}
+
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// JSONDay is the JSON form of a day of the grid.
+type JSONDay struct {
+	Date    string             `json:"date"`
+	Weekday string             `json:"weekday"`
+	IsOff   bool               `json:"is_off"`
+	Note    string             `json:"note,omitempty"`
+	Tasks   []*output.JSONTask `json:"tasks"`
+}
+
+// JSON returns the days of g in their JSON form.
+func JSON(g *Grid) []JSONDay {
+	days := make([]JSONDay, 0, len(g.Days))
+	for _, d := range g.Days {
+		jd := JSONDay{
+			Date:    d.Date.Format("2006-01-02"),
+			Weekday: d.Date.Weekday().String(),
+			IsOff:   d.IsOff,
+			Note:    d.Note,
+			Tasks:   make([]*output.JSONTask, 0, len(d.Tasks)),
+		}
+		for i := range d.Tasks {
+			jd.Tasks = append(jd.Tasks, output.NewJSONTask(&d.Tasks[i]))
+		}
+		days = append(days, jd)
+	}
+	return days
+}
@@ -0,0 +1,113 @@
+package grid
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func testGrid(t *testing.T) *Grid {
+	t.Helper()
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 1, Tasks: []config.Task{
+				{Name: "Math", Start: "09:00", End: "10:00"},
+				{Name: "Art|Craft", Start: "10:00", End: "11:00"},
+			}},
+			{ID: 2, Tasks: []config.Task{
+				{Name: "Gym", Start: "09:30", End: "10:30"},
+				{Name: "Math", Start: "09:00", End: "10:00"},
+				{Name: "/", Start: "11:00", End: "12:00"},
+			}},
+			{ID: 3, Tasks: []config.Task{{Name: "Math", Start: "09:00", End: "10:00"}}},
+		},
+		Overrides: []config.Override{{DateStr: "2024-01-03", IsOff: true, Note: "Holiday"}},
+	}
+	if err := cfg.ProcessOverrides(); err != nil {
+		t.Fatalf("ProcessOverrides() returned error: %v", err)
+	}
+	// Monday to Wednesday
+	g, err := Build(scheduler.New(cfg), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 3)
+	if err != nil {
+		t.Fatalf("Build() returned error: %v", err)
+	}
+	return g
+}
+
+func TestBuild(t *testing.T) {
+	g := testGrid(t)
+
+	// The union of the days' slots, without the empty slot
+	want := []Slot{{"09:00", "10:00"}, {"09:30", "10:30"}, {"10:00", "11:00"}}
+	if len(g.Slots) != len(want) {
+		t.Fatalf("Expected %d slots, got %v", len(want), g.Slots)
+	}
+	for i := range want {
+		if g.Slots[i] != want[i] {
+			t.Errorf("Slot %d: Expected %v, got %v", i, want[i], g.Slots[i])
+		}
+	}
+	if !g.Days[2].IsOff || g.Days[2].Cell(want[0]) != "" || g.Days[2].Header() != "Wed 01-03 OFF" {
+		t.Errorf("Expected the holiday to be an empty OFF column, got %+v", g.Days[2])
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	tests := []struct {
+		clock config.Clock
+		want  string
+	}{
+		{config.Clock24, `Time         Mon 01-01  Tue 01-02  Wed 01-03 OFF
+09:00-10:00  Math       Math
+09:30-10:30             Gym
+10:00-11:00  Art|Craft
+`},
+		{config.Clock12, `Time               Mon 01-01  Tue 01-02  Wed 01-03 OFF
+9:00 AM-10:00 AM   Math       Math
+9:30 AM-10:30 AM              Gym
+10:00 AM-11:00 AM  Art|Craft
+`},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		if err := WriteText(&b, testGrid(t), tt.clock); err != nil {
+			t.Fatalf("WriteText() returned error: %v", err)
+		}
+		if b.String() != tt.want {
+			t.Errorf("%s: Expected:\n%s\ngot:\n%s", tt.clock, tt.want, b.String())
+		}
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var b strings.Builder
+	if err := WriteMarkdown(&b, testGrid(t), config.Clock24); err != nil {
+		t.Fatalf("WriteMarkdown() returned error: %v", err)
+	}
+	want := `| Time | Mon 01-01 | Tue 01-02 | Wed 01-03 OFF |
+| --- | --- | --- | --- |
+| 09:00-10:00 | Math | Math |  |
+| 09:30-10:30 |  | Gym |  |
+| 10:00-11:00 | Art\|Craft |  |  |
+`
+	if b.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, b.String())
+	}
+}
+
+func TestJSON(t *testing.T) {
+	days := JSON(testGrid(t))
+	if len(days) != 3 {
+		t.Fatalf("Expected 3 days, got %d", len(days))
+	}
+	if days[1].Date != "2024-01-02" || days[1].Weekday != "Tuesday" || len(days[1].Tasks) != 2 {
+		t.Errorf("Expected Tuesday with 2 tasks, got %+v", days[1])
+	}
+	if !days[2].IsOff || days[2].Note != "Holiday" || days[2].Tasks == nil {
+		t.Errorf("Expected an off day with a note and an empty task list, got %+v", days[2])
+	}
+}
@@ -0,0 +1,117 @@
+// Package hooks runs user-defined commands when the schedule changes state.
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// DefaultTimeout bounds how long a single hook command may run.
+const DefaultTimeout = 30 * time.Second
+
+// Runner executes hook commands asynchronously but in the order they were queued.
+type Runner struct {
+	Timeout time.Duration
+	jobs    chan job
+}
+
+type job struct {
+	command string
+	env     []string
+}
+
+// Transition describes a change of the current task.
+type Transition struct {
+	Previous *scheduler.TaskEvent // task that just ended, if any
+	Current  *scheduler.TaskEvent // task that just started, if any
+	Next     *scheduler.TaskEvent // upcoming task, if any
+}
+
+// NewRunner creates a Runner and starts its worker.
+func NewRunner() *Runner {
+	r := &Runner{
+		Timeout: DefaultTimeout,
+		jobs:    make(chan job, 32),
+	}
+	go r.loop()
+	return r
+}
+
+// Run queues command with the task environment derived from task and tr.
+// Empty commands are ignored.
+func (r *Runner) Run(command string, task *scheduler.TaskEvent, tr Transition) {
+	if command == "" {
+		return
+	}
+	r.jobs <- job{command: command, env: Env(task, tr)}
+}
+
+func (r *Runner) loop() {
+	for j := range r.jobs {
+		if err := r.exec(j); err != nil {
+			slog.Warn("hook failed", "command", j.command, "err", err)
+		}
+	}
+}
+
+func (r *Runner) exec(j job) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.Timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", j.command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", j.command)
+	}
+	cmd.Env = append(os.Environ(), j.env...)
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out after %s", r.Timeout)
+		}
+		return err
+	}
+	return nil
+}
+
+// Env builds the SKED_* environment variables exported to hook commands.
+// task is the task the hook is about; tr supplies the surrounding context.
+func Env(task *scheduler.TaskEvent, tr Transition) []string {
+	env := []string{
+		"SKED_TASK_NAME=",
+		"SKED_TASK_START=",
+		"SKED_TASK_END=",
+		"SKED_PREV_TASK=" + taskName(tr.Previous),
+		"SKED_NEXT_TASK=" + taskName(tr.Next),
+	}
+	if task != nil {
+		env[0] += task.Name
+		env[1] += task.StartTime.Format("15:04")
+		env[2] += task.EndTime.Format("15:04")
+	}
+	return env
+}
+
+func taskName(t *scheduler.TaskEvent) string {
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
+
+// SameTask reports whether a and b refer to the same task instance.
+func SameTask(a, b *scheduler.TaskEvent) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Name == b.Name && a.StartTime.Equal(b.StartTime) && a.EndTime.Equal(b.EndTime)
+}
@@ -0,0 +1,54 @@
+package hooks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func TestEnv(t *testing.T) {
+	a := &scheduler.TaskEvent{
+		Name:      "Math",
+		StartTime: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	b := &scheduler.TaskEvent{
+		Name:      "History",
+		StartTime: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	got := Env(b, Transition{Previous: a, Current: b})
+	want := []string{
+		"SKED_TASK_NAME=History",
+		"SKED_TASK_START=10:00",
+		"SKED_TASK_END=11:00",
+		"SKED_PREV_TASK=Math",
+		"SKED_NEXT_TASK=",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Env() returned %d entries, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Env()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSameTask(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	a := &scheduler.TaskEvent{Name: "Math", StartTime: start, EndTime: start.Add(time.Hour)}
+	b := &scheduler.TaskEvent{Name: "Math", StartTime: start, EndTime: start.Add(time.Hour)}
+
+	if !SameTask(a, b) {
+		t.Errorf("Expected identical task instances to match")
+	}
+	if SameTask(a, nil) {
+		t.Errorf("Expected task and nil not to match")
+	}
+	if !SameTask(nil, nil) {
+		t.Errorf("Expected nil and nil to match")
+	}
+}
@@ -0,0 +1,212 @@
+// Package i18n translates user-facing messages and the day and month names
+// in formatted dates.
+//
+// Each locale is a TOML file in locales/ named after its language, such as
+// de.toml, with a [messages] table keyed like en.toml and optional lists of
+// day and month names. Messages are fmt formats; translations may reorder
+// their arguments with %[1]s. A message or name list missing from a locale
+// falls back to English.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+//go:embed locales/*.toml
+var files embed.FS
+
+// Locale holds the messages and date names of one language.
+type Locale struct {
+	Tag         string
+	messages    map[string]string
+	days        []string // Sunday first
+	shortDays   []string
+	months      []string // January first
+	shortMonths []string
+}
+
+// file is the layout of a locale file.
+type file struct {
+	Messages    map[string]string `toml:"messages"`
+	Days        []string          `toml:"days"`
+	ShortDays   []string          `toml:"short_days"`
+	Months      []string          `toml:"months"`
+	ShortMonths []string          `toml:"short_months"`
+}
+
+var (
+	locales = mustLoad()
+	// English is the fallback for every other locale.
+	English = locales["en"]
+)
+
+// mustLoad parses the bundled locale files.
+func mustLoad() map[string]*Locale {
+	names, err := files.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	out := make(map[string]*Locale, len(names))
+	for _, e := range names {
+		data, err := files.ReadFile(path.Join("locales", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var f file
+		if err := toml.Unmarshal(data, &f); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", e.Name(), err))
+		}
+		tag := strings.TrimSuffix(e.Name(), ".toml")
+		out[tag] = &Locale{
+			Tag:         tag,
+			messages:    f.Messages,
+			days:        names7(f.Days),
+			shortDays:   names7(f.ShortDays),
+			months:      names12(f.Months),
+			shortMonths: names12(f.ShortMonths),
+		}
+	}
+	return out
+}
+
+// names7 and names12 drop name lists of the wrong length, which then fall
+// back to English.
+func names7(s []string) []string {
+	if len(s) != 7 {
+		return nil
+	}
+	return s
+}
+
+func names12(s []string) []string {
+	if len(s) != 12 {
+		return nil
+	}
+	return s
+}
+
+// Tags returns the bundled locales, sorted.
+func Tags() []string {
+	tags := make([]string, 0, len(locales))
+	for tag := range locales {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// Lookup returns the locale for tag, a language ("de") or a POSIX locale
+// name ("de_DE.UTF-8"), matching the language if there is no locale for
+// the region. It reports false, returning English, for unknown tags.
+func Lookup(tag string) (*Locale, bool) {
+	tag, _, _ = strings.Cut(tag, ".")
+	tag, _, _ = strings.Cut(tag, "@")
+	tag = strings.ReplaceAll(strings.ToLower(tag), "-", "_")
+	if l, ok := locales[tag]; ok {
+		return l, true
+	}
+	lang, _, _ := strings.Cut(tag, "_")
+	if l, ok := locales[lang]; ok {
+		return l, true
+	}
+	return English, false
+}
+
+// FromEnv returns the locale named by LC_ALL, LC_MESSAGES or LANG, the
+// first one set, or "" if none is.
+func FromEnv() string {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// T returns the message id formatted with args, in English if l is nil or
+// lacks it, or id itself if English lacks it too.
+func (l *Locale) T(id string, args ...any) string {
+	msg := l.message(id)
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+func (l *Locale) message(id string) string {
+	if l != nil {
+		if msg := l.messages[id]; msg != "" {
+			return msg
+		}
+	}
+	if msg := English.messages[id]; msg != "" {
+		return msg
+	}
+	return id
+}
+
+// Weekday returns the name of d, e.g. "Montag".
+func (l *Locale) Weekday(d time.Weekday) string {
+	return pick(l, func(l *Locale) []string { return l.days }, int(d), d.String())
+}
+
+// Format is time.Format with the day and month names of l.
+func (l *Locale) Format(t time.Time, layout string) string {
+	if l == nil || l == English {
+		return t.Format(layout)
+	}
+	var b strings.Builder
+	for {
+		i, tok := nextName(layout)
+		if i < 0 {
+			b.WriteString(t.Format(layout))
+			return b.String()
+		}
+		b.WriteString(t.Format(layout[:i]))
+		switch tok {
+		case "Monday":
+			b.WriteString(l.Weekday(t.Weekday()))
+		case "Mon":
+			b.WriteString(pick(l, func(l *Locale) []string { return l.shortDays }, int(t.Weekday()), t.Format("Mon")))
+		case "January":
+			b.WriteString(pick(l, func(l *Locale) []string { return l.months }, int(t.Month())-1, t.Month().String()))
+		case "Jan":
+			b.WriteString(pick(l, func(l *Locale) []string { return l.shortMonths }, int(t.Month())-1, t.Format("Jan")))
+		}
+		layout = layout[i+len(tok):]
+	}
+}
+
+// nameTokens are the layout elements that Format translates, longer ones
+// first, as "Mon" is a prefix of "Monday".
+var nameTokens = []string{"Monday", "Mon", "January", "Jan"}
+
+// nextName returns the index and text of the first name element in layout,
+// or -1.
+func nextName(layout string) (int, string) {
+	at, tok := -1, ""
+	for _, t := range nameTokens {
+		if i := strings.Index(layout, t); i >= 0 && (at < 0 || i < at) {
+			at, tok = i, t
+		}
+	}
+	return at, tok
+}
+
+// pick returns names(l)[i], or fallback if l has no such list.
+func pick(l *Locale, names func(*Locale) []string, i int, fallback string) string {
+	if l != nil {
+		if s := names(l); s != nil {
+			return s[i]
+		}
+	}
+	return fallback
+}
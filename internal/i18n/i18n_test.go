@@ -0,0 +1,116 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+		ok   bool
+	}{
+		{tag: "de", want: "de", ok: true},
+		{tag: "de_DE.UTF-8", want: "de", ok: true},
+		{tag: "de-AT", want: "de", ok: true},
+		{tag: "en_US.UTF-8", want: "en", ok: true},
+		{tag: "C", want: "en", ok: false},
+		{tag: "", want: "en", ok: false},
+		{tag: "xx", want: "en", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			l, ok := Lookup(tt.tag)
+			if l.Tag != tt.want || ok != tt.ok {
+				t.Errorf("Expected %s (%v), got %s (%v)", tt.want, tt.ok, l.Tag, ok)
+			}
+		})
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "de_DE.UTF-8")
+	t.Setenv("LANG", "en_US.UTF-8")
+	if got := FromEnv(); got != "de_DE.UTF-8" {
+		t.Errorf("Expected LC_MESSAGES to win over LANG, got %q", got)
+	}
+	t.Setenv("LC_ALL", "C")
+	if got := FromEnv(); got != "C" {
+		t.Errorf("Expected LC_ALL to win, got %q", got)
+	}
+}
+
+func TestT(t *testing.T) {
+	de, _ := Lookup("de")
+	if got := de.T("next", "Mathe", "05:00"); got != "Als Nächstes: Mathe in 05:00" {
+		t.Errorf("Expected the German message, got %q", got)
+	}
+	if got := (&Locale{Tag: "xx"}).T("tasks", 3); got != "3 tasks" {
+		t.Errorf("Expected the English fallback, got %q", got)
+	}
+	var none *Locale
+	if got := none.T("no_task"); got != "No task currently." {
+		t.Errorf("Expected English for a nil locale, got %q", got)
+	}
+	if got := de.T("no_such_message"); got != "no_such_message" {
+		t.Errorf("Expected the id for an unknown message, got %q", got)
+	}
+}
+
+// Every bundled locale only translates messages English has, with the same
+// number of arguments, and has complete name lists.
+func TestLocalesComplete(t *testing.T) {
+	for _, tag := range Tags() {
+		l := locales[tag]
+		for id, msg := range l.messages {
+			en, ok := English.messages[id]
+			if !ok {
+				t.Errorf("%s: message %q is not in en.toml", tag, id)
+				continue
+			}
+			if countVerbs(msg) != countVerbs(en) {
+				t.Errorf("%s: message %q has %d verbs, English has %d", tag, id, countVerbs(msg), countVerbs(en))
+			}
+		}
+		if l.days == nil || l.shortDays == nil || l.months == nil || l.shortMonths == nil {
+			t.Errorf("%s: expected 7 day and 12 month names", tag)
+		}
+	}
+}
+
+func countVerbs(s string) int {
+	n := 0
+	for i := 0; i < len(s)-1; i++ {
+		if s[i] == '%' {
+			if s[i+1] != '%' {
+				n++
+			}
+			i++
+		}
+	}
+	return n
+}
+
+func TestFormat(t *testing.T) {
+	de, _ := Lookup("de")
+	date := time.Date(2025, time.March, 3, 9, 5, 0, 0, time.UTC)
+	tests := []struct {
+		layout string
+		want   string
+	}{
+		{layout: "2006-01-02 Mon", want: "2025-03-03 Mo"},
+		{layout: "Monday, 2 January 2006", want: "Montag, 3 März 2025"},
+		{layout: "Mon Jan 2 15:04", want: "Mo Mär 3 09:05"},
+		{layout: "02.01.2006", want: "03.03.2025"},
+	}
+	for _, tt := range tests {
+		if got := de.Format(date, tt.layout); got != tt.want {
+			t.Errorf("Format(%q): expected %q, got %q", tt.layout, tt.want, got)
+		}
+	}
+	if got := English.Format(date, "Mon Jan 2"); got != "Mon Mar 3" {
+		t.Errorf("Expected English names, got %q", got)
+	}
+}
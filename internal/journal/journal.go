@@ -0,0 +1,174 @@
+// Package journal records whether scheduled task instances were done or skipped.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// Status is the outcome recorded for a task instance.
+type Status string
+
+const (
+	Done    Status = "done"
+	Skipped Status = "skipped"
+)
+
+// Record is a single line of the journal.
+type Record struct {
+	Date      string `json:"date"`  // YYYY-MM-DD of the task instance
+	Task      string `json:"task"`  // task name
+	Start     string `json:"start"` // HH:MM start time
+	Status    Status `json:"status"`
+	Timestamp string `json:"timestamp"` // RFC3339 time the record was written
+}
+
+// Key identifies a task instance.
+type Key struct {
+	Date  string
+	Task  string
+	Start string
+}
+
+// KeyOf returns the key of a scheduled task instance. Tasks are recorded by
+// their raw name so records survive changes to aliases.
+func KeyOf(t scheduler.TaskEvent) Key {
+	return Key{Date: t.StartTime.Format("2006-01-02"), Task: t.RawName, Start: t.StartTime.Format("15:04")}
+}
+
+func (r Record) key() Key {
+	return Key{Date: r.Date, Task: r.Task, Start: r.Start}
+}
+
+// Journal is an append-only log of records stored as JSON lines.
+type Journal struct {
+	path string
+}
+
+// DefaultPath returns the journal location in the user data directory
+// ($XDG_DATA_HOME, or ~/.local/share).
+func DefaultPath() (string, error) {
+	dataDir := os.Getenv("XDG_DATA_HOME")
+	if dataDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not find user data directory: %w", err)
+		}
+		dataDir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataDir, "sked", "journal.jsonl"), nil
+}
+
+// New returns a journal stored at path. The file is created on first write.
+func New(path string) *Journal {
+	return &Journal{path: path}
+}
+
+// Records returns all records in the order they were written. Lines that
+// cannot be parsed (e.g. a write cut short) are skipped.
+func (j *Journal) Records() ([]Record, error) {
+	f, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		records = append(records, r)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Range returns the records for task instances dated between from and to, inclusive.
+func (j *Journal) Range(from, to time.Time) ([]Record, error) {
+	records, err := j.Records()
+	if err != nil {
+		return nil, err
+	}
+	lo, hi := from.Format("2006-01-02"), to.Format("2006-01-02")
+	var out []Record
+	for _, r := range records {
+		if r.Date >= lo && r.Date <= hi {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// ModTime returns when the journal was last written, or the zero time if it
+// doesn't exist yet.
+func (j *Journal) ModTime() time.Time {
+	info, err := os.Stat(j.path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// Statuses returns the latest status of every recorded task instance.
+func (j *Journal) Statuses() (map[Key]Status, error) {
+	records, err := j.Records()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make(map[Key]Status, len(records))
+	for _, r := range records {
+		statuses[r.key()] = r.Status
+	}
+	return statuses, nil
+}
+
+// Mark records status for task at now. It returns false without writing if
+// the task instance already has that status, so marking twice is harmless.
+func (j *Journal) Mark(task scheduler.TaskEvent, status Status, now time.Time) (bool, error) {
+	statuses, err := j.Statuses()
+	if err != nil {
+		return false, err
+	}
+	key := KeyOf(task)
+	if statuses[key] == status {
+		return false, nil
+	}
+
+	line, err := json.Marshal(Record{
+		Date:      key.Date,
+		Task:      key.Task,
+		Start:     key.Start,
+		Status:    status,
+		Timestamp: now.Format(time.RFC3339),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0o755); err != nil {
+		return false, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return false, err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return false, err
+	}
+	return true, f.Close()
+}
@@ -0,0 +1,87 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func TestMark_Idempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sked", "journal.jsonl")
+	j := New(path)
+	math := scheduler.TaskEvent{
+		Name:      "Math",
+		RawName:   "Math",
+		StartTime: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	now := time.Date(2024, 1, 1, 9, 45, 0, 0, time.UTC)
+
+	steps := []struct {
+		status Status
+		want   bool
+	}{
+		{Done, true},
+		{Done, false},
+		{Skipped, true},
+		{Done, true},
+	}
+	for i, s := range steps {
+		added, err := j.Mark(math, s.status, now)
+		if err != nil {
+			t.Fatalf("Step %d: Mark() returned error: %v", i, err)
+		}
+		if added != s.want {
+			t.Errorf("Step %d: expected added=%v, got %v", i, s.want, added)
+		}
+	}
+
+	records, err := j.Records()
+	if err != nil {
+		t.Fatalf("Records() returned error: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+	want := Record{Date: "2024-01-01", Task: "Math", Start: "09:00", Status: Done, Timestamp: "2024-01-01T09:45:00Z"}
+	if records[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, records[0])
+	}
+
+	statuses, err := j.Statuses()
+	if err != nil {
+		t.Fatalf("Statuses() returned error: %v", err)
+	}
+	if got := statuses[KeyOf(math)]; got != Done {
+		t.Errorf("Expected latest status done, got %q", got)
+	}
+}
+
+func TestRecords_SkipsCorruptLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	content := `{"date":"2024-01-01","task":"Math","start":"09:00","status":"done","timestamp":"2024-01-01T09:45:00Z"}
+{"date":"2024-01-02","task":"Art","sta
+{"date":"2024-01-03","task":"Art","start":"11:00","status":"skipped","timestamp":"2024-01-03T11:05:00Z"}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write journal: %v", err)
+	}
+
+	records, err := New(path).Range(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Range() returned error: %v", err)
+	}
+	if len(records) != 1 || records[0].Task != "Art" {
+		t.Errorf("Expected only the Jan 3 record, got %+v", records)
+	}
+}
+
+func TestRecords_MissingFile(t *testing.T) {
+	records, err := New(filepath.Join(t.TempDir(), "missing.jsonl")).Records()
+	if err != nil || records != nil {
+		t.Errorf("Expected no records and no error, got %v, %v", records, err)
+	}
+}
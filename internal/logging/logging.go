@@ -0,0 +1,89 @@
+// Package logging sets up the structured logger shared by all commands: a
+// terse text log on stderr whose level follows --verbose, and optionally a
+// JSON-lines file for looking back at what a long-running watch did.
+package logging
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// Level maps the number of -v flags to the lowest level written to stderr:
+// warnings by default, info with -v and debug with -vv or more.
+func Level(verbosity int) slog.Level {
+	switch {
+	case verbosity <= 0:
+		return slog.LevelWarn
+	case verbosity == 1:
+		return slog.LevelInfo
+	default:
+		return slog.LevelDebug
+	}
+}
+
+// New returns a logger writing text lines to stderr at Level(verbosity) and,
+// if file is not nil, JSON lines to file. The file always records info
+// events so reloads and notifications can be traced without -v.
+func New(stderr io.Writer, verbosity int, file io.Writer) *slog.Logger {
+	level := Level(verbosity)
+	handlers := []slog.Handler{slog.NewTextHandler(stderr, &slog.HandlerOptions{
+		Level: level,
+		// The terminal doesn't need timestamps; the file keeps them.
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})}
+	if file != nil {
+		handlers = append(handlers, slog.NewJSONHandler(file, &slog.HandlerOptions{Level: min(level, slog.LevelInfo)}))
+	}
+	return slog.New(fanout(handlers))
+}
+
+// OpenFile opens path for appending log lines, creating it if needed.
+func OpenFile(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+}
+
+// fanout passes each record to every handler that accepts its level.
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		verbosity int
+		want      slog.Level
+	}{
+		{0, slog.LevelWarn},
+		{1, slog.LevelInfo},
+		{2, slog.LevelDebug},
+		{5, slog.LevelDebug},
+	}
+	for _, tt := range tests {
+		if got := Level(tt.verbosity); got != tt.want {
+			t.Errorf("Level(%d): Expected %v, got %v", tt.verbosity, tt.want, got)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	var stderr, file bytes.Buffer
+	logger := New(&stderr, 0, &file)
+	logger.Debug("wake-up scheduled")
+	logger.Info("config loaded", "tasks", 3)
+	logger.Warn("notification failed", "backend", "desktop")
+
+	// Quiet by default: only the warning reaches stderr, without a timestamp
+	if got := strings.TrimSpace(stderr.String()); got != `level=WARN msg="notification failed" backend=desktop` {
+		t.Errorf("Unexpected stderr output: %q", got)
+	}
+
+	// The file records info and above as JSON lines
+	lines := strings.Split(strings.TrimSpace(file.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines in the log file, got %d: %q", len(lines), file.String())
+	}
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatalf("Invalid JSON line %q: %v", lines[0], err)
+	}
+	if rec["msg"] != "config loaded" || rec["tasks"] != float64(3) || rec["time"] == nil {
+		t.Errorf("Unexpected record: %v", rec)
+	}
+}
+
+func TestNew_Verbose(t *testing.T) {
+	var stderr, file bytes.Buffer
+	New(&stderr, 2, &file).With("component", "watch").Debug("wake-up scheduled")
+	if !strings.Contains(stderr.String(), "component=watch") {
+		t.Errorf("Expected debug line on stderr, got %q", stderr.String())
+	}
+	if !strings.Contains(file.String(), `"component":"watch"`) {
+		t.Errorf("Expected debug line in file, got %q", file.String())
+	}
+}
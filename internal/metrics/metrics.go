@@ -0,0 +1,160 @@
+// Package metrics exposes schedule state in the Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// Registry holds the current metric values. It is safe for concurrent use.
+type Registry struct {
+	mu sync.Mutex
+
+	active           map[string]bool
+	remainingSeconds float64
+	nextInSeconds    float64
+	hasNext          bool
+	tasksToday       int
+	remainingToday   scheduler.Remaining
+
+	notificationsSent uint64
+	configReloads     uint64
+
+	// OnScrape, if set, is called before each scrape to refresh the gauges.
+	OnScrape func()
+}
+
+// New creates an empty Registry.
+func New() *Registry {
+	return &Registry{active: make(map[string]bool)}
+}
+
+// Update refreshes the gauges from the schedule state at now. Empty slots
+// ("/") are not tasks.
+func (r *Registry) Update(now time.Time, current, next *scheduler.TaskEvent, dayTasks []scheduler.TaskEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.active = make(map[string]bool)
+	r.tasksToday = 0
+	for _, t := range dayTasks {
+		if t.RawName == "/" {
+			continue
+		}
+		r.active[t.Name] = false
+		r.tasksToday++
+	}
+	if current != nil {
+		r.active[current.Name] = true
+	}
+
+	r.remainingSeconds = 0
+	if current != nil {
+		r.remainingSeconds = current.EndTime.Sub(now).Seconds()
+	}
+
+	r.hasNext = next != nil
+	r.nextInSeconds = 0
+	if next != nil {
+		r.nextInSeconds = next.StartTime.Sub(now).Seconds()
+	}
+
+	r.remainingToday = scheduler.RemainingTasks(dayTasks, now)
+}
+
+// IncNotifications counts a sent notification.
+func (r *Registry) IncNotifications() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notificationsSent++
+}
+
+// IncConfigReloads counts a successful config reload.
+func (r *Registry) IncConfigReloads() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.configReloads++
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if r.OnScrape != nil {
+			r.OnScrape()
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteTo(w)
+	})
+}
+
+// WriteTo writes all metrics to w.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP sked_task_active Whether a task is currently active (1) or not (0).\n")
+	b.WriteString("# TYPE sked_task_active gauge\n")
+	names := make([]string, 0, len(r.active))
+	for name := range r.active {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := 0
+		if r.active[name] {
+			value = 1
+		}
+		fmt.Fprintf(&b, "sked_task_active{name=\"%s\"} %d\n", escapeLabel(name), value)
+	}
+
+	b.WriteString("# HELP sked_task_remaining_seconds Seconds until the current task ends (0 if none).\n")
+	b.WriteString("# TYPE sked_task_remaining_seconds gauge\n")
+	fmt.Fprintf(&b, "sked_task_remaining_seconds %g\n", r.remainingSeconds)
+
+	if r.hasNext {
+		b.WriteString("# HELP sked_next_task_starts_in_seconds Seconds until the next task starts.\n")
+		b.WriteString("# TYPE sked_next_task_starts_in_seconds gauge\n")
+		fmt.Fprintf(&b, "sked_next_task_starts_in_seconds %g\n", r.nextInSeconds)
+	}
+
+	b.WriteString("# HELP sked_tasks_today_total Number of tasks scheduled today.\n")
+	b.WriteString("# TYPE sked_tasks_today_total gauge\n")
+	fmt.Fprintf(&b, "sked_tasks_today_total %d\n", r.tasksToday)
+
+	b.WriteString("# HELP sked_tasks_remaining_today Number of today's tasks that haven't ended.\n")
+	b.WriteString("# TYPE sked_tasks_remaining_today gauge\n")
+	fmt.Fprintf(&b, "sked_tasks_remaining_today %d\n", r.remainingToday.Tasks)
+
+	b.WriteString("# HELP sked_remaining_today_seconds Seconds of today's tasks still ahead, counting the rest of the current one.\n")
+	b.WriteString("# TYPE sked_remaining_today_seconds gauge\n")
+	fmt.Fprintf(&b, "sked_remaining_today_seconds %g\n", r.remainingToday.Time.Seconds())
+
+	b.WriteString("# HELP sked_notifications_sent_total Notifications sent since startup.\n")
+	b.WriteString("# TYPE sked_notifications_sent_total counter\n")
+	fmt.Fprintf(&b, "sked_notifications_sent_total %d\n", r.notificationsSent)
+
+	b.WriteString("# HELP sked_config_reloads_total Successful config reloads since startup.\n")
+	b.WriteString("# TYPE sked_config_reloads_total counter\n")
+	fmt.Fprintf(&b, "sked_config_reloads_total %d\n", r.configReloads)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// escapeLabel makes an arbitrary task name safe to use as a label value.
+func escapeLabel(s string) string {
+	s = strings.ToValidUTF8(s, "�")
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return s
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func TestHandler_Scrape(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)
+	current := scheduler.TaskEvent{
+		Name:      `Math "101"`,
+		StartTime: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	next := scheduler.TaskEvent{
+		Name:      "History\nII",
+		StartTime: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	reg := New()
+	reg.OnScrape = func() {
+		empty := scheduler.TaskEvent{Name: "/", RawName: "/", StartTime: current.EndTime, EndTime: next.StartTime}
+		reg.Update(now, &current, &next, []scheduler.TaskEvent{current, empty, next})
+	}
+	reg.IncNotifications()
+	reg.IncConfigReloads()
+
+	ts := httptest.NewServer(reg.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read body: %v", err)
+	}
+
+	want := []string{
+		`sked_task_active{name="Math \"101\""} 1`,
+		`sked_task_active{name="History\nII"} 0`,
+		"sked_task_remaining_seconds 1800",
+		"sked_next_task_starts_in_seconds 5400",
+		"sked_tasks_today_total 2",
+		"sked_tasks_remaining_today 2",
+		"sked_remaining_today_seconds 5400",
+		"sked_notifications_sent_total 1",
+		"sked_config_reloads_total 1",
+	}
+	for _, line := range want {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("Expected scrape to contain %q, got:\n%s", line, body)
+		}
+	}
+	if strings.Contains(string(body), `name="/"`) {
+		t.Errorf("Expected no gauge for the empty slot, got:\n%s", body)
+	}
+}
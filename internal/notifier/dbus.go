@@ -0,0 +1,103 @@
+package notifier
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// busNotification holds the arguments of an
+// org.freedesktop.Notifications.Notify call.
+type busNotification struct {
+	ReplacesID uint32
+	Icon       string
+	Summary    string
+	Body       string
+	Urgency    string
+	Category   string
+	ExpireMs   int // 0 uses the server default
+}
+
+// notificationBus is the part of the org.freedesktop.Notifications D-Bus
+// interface sked uses.
+type notificationBus interface {
+	// Notify shows a notification and returns the ID the server assigned.
+	Notify(n busNotification) (uint32, error)
+}
+
+// gdbusBus calls the notification server with the gdbus tool.
+type gdbusBus struct{}
+
+// Notify implements notificationBus.
+func (gdbusBus) Notify(n busNotification) (uint32, error) {
+	out, err := runOutput("gdbus", gdbusNotifyArgs(n)...)
+	if err != nil {
+		return 0, err
+	}
+	id, err := parseGVariantUint(out)
+	return uint32(id), err
+}
+
+// gdbusNotifyArgs builds the gdbus arguments for n. Every argument is
+// written in GVariant text format, so titles can't be mistaken for syntax,
+// and after "--", so the default expire timeout (-1) isn't read as an option.
+func gdbusNotifyArgs(n busNotification) []string {
+	var hints []string
+	switch n.Urgency {
+	case UrgencyLow:
+		hints = append(hints, "'urgency': <byte 0>")
+	case UrgencyNormal:
+		hints = append(hints, "'urgency': <byte 1>")
+	case UrgencyCritical:
+		hints = append(hints, "'urgency': <byte 2>")
+	}
+	if n.Category != "" {
+		hints = append(hints, "'category': <"+gvariantString(n.Category)+">")
+	}
+	expire := -1
+	if n.ExpireMs > 0 {
+		expire = n.ExpireMs
+	}
+	return []string{
+		"call", "--session",
+		"--dest", "org.freedesktop.Notifications",
+		"--object-path", "/org/freedesktop/Notifications",
+		"--method", "org.freedesktop.Notifications.Notify",
+		"--",
+		gvariantString("sked"),
+		strconv.FormatUint(uint64(n.ReplacesID), 10),
+		gvariantString(n.Icon),
+		gvariantString(n.Summary),
+		gvariantString(n.Body),
+		"@as []",
+		"@a{sv} {" + strings.Join(hints, ", ") + "}",
+		strconv.Itoa(expire),
+	}
+}
+
+// gvariantString quotes s as a GVariant text format string.
+func gvariantString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&b, `\u%04x`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -2,35 +2,248 @@
 package notifier
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os/exec"
 	"runtime"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// Notification is a single message delivered to one or more backends.
+type Notification struct {
+	Title   string
+	Message string
+	Options SendOptions
+
+	// Task the notification is about, if any.
+	TaskName  string
+	TaskStart time.Time
+	TaskEnd   time.Time
+	TaskURL   string
+}
+
+// Backend delivers notifications somewhere.
+type Backend interface {
+	Name() string
+	Notify(n Notification) error
+}
+
+// Replacer is a Backend whose notifications can replace an earlier one
+// instead of stacking up.
+type Replacer interface {
+	Backend
+	// NotifyID sends n in place of the notification n.Options.ReplacesID
+	// (0 for none) and returns the new notification's ID, or 0 if the
+	// platform gave none.
+	NotifyID(n Notification) (uint32, error)
+}
+
+// Fanout delivers each notification to every backend.
+type Fanout []Backend
+
+// Notify sends n to all backends. A failing backend doesn't stop the others;
+// all failures are returned joined together.
+func (f Fanout) Notify(n Notification) error {
+	_, err := f.NotifyID(n)
+	return err
+}
+
+// NotifyID is like Notify, passing n.Options.ReplacesID to the backends that
+// support replacement and returning the first notification ID they report.
+func (f Fanout) NotifyID(n Notification) (uint32, error) {
+	var id uint32
+	var errs []error
+	for _, b := range f {
+		var err error
+		if r, ok := b.(Replacer); ok {
+			var got uint32
+			got, err = r.NotifyID(n)
+			if id == 0 {
+				id = got
+			}
+		} else {
+			err = b.Notify(n)
+		}
+		if err != nil {
+			slog.Info("notification failed", "backend", b.Name(), "title", n.Title, "err", err)
+			errs = append(errs, fmt.Errorf("%s: %w", b.Name(), err))
+			continue
+		}
+		slog.Info("notification sent", "backend", b.Name(), "title", n.Title)
+	}
+	return id, errors.Join(errs...)
+}
+
 // Notifier handles sending desktop notifications.
-type Notifier struct{}
+type Notifier struct {
+	// Command, if set, replaces the built-in platform backend. It is executed
+	// directly (no shell), after substituting {title}, {message}, {urgency},
+	// {icon} and {url} placeholders in each argument.
+	Command []string
+
+	// bus sends Linux notifications; nil uses gdbus.
+	bus notificationBus
+}
 
 // New creates a new Notifier.
 func New() *Notifier {
 	return &Notifier{}
 }
 
-// Send sends a notification with the given title and message.
-func (n *Notifier) Send(title, message string) error {
+// Name implements Backend.
+func (n *Notifier) Name() string {
+	return "desktop"
+}
+
+// Notify implements Backend.
+func (n *Notifier) Notify(msg Notification) error {
+	_, err := n.NotifyID(msg)
+	return err
+}
+
+// NotifyID implements Replacer. Only the built-in Linux backend reports
+// IDs; a custom command never does.
+func (n *Notifier) NotifyID(msg Notification) (uint32, error) {
+	if len(n.Command) > 0 {
+		return 0, sendCommand(n.Command, msg)
+	}
+	return n.SendWithOptions(msg.Title, msg.Message, msg.Options)
+}
+
+// Urgency levels understood by SendOptions.
+const (
+	UrgencyLow      = "low"
+	UrgencyNormal   = "normal"
+	UrgencyCritical = "critical"
+)
+
+// SendOptions customizes how a notification is displayed.
+// Backends that don't support an option ignore it.
+type SendOptions struct {
+	Urgency  string // low, normal or critical
+	IconName string
+	ExpireMs int // 0 uses the desktop default
+	Category string
+	// ReplacesID is the ID of an earlier notification to replace, where the
+	// platform supports it; 0 sends a new one.
+	ReplacesID uint32
+}
+
+// RaiseUrgency returns the urgency one level above u.
+func RaiseUrgency(u string) string {
+	switch u {
+	case UrgencyLow:
+		return UrgencyNormal
+	case UrgencyNormal, "":
+		return UrgencyCritical
+	default:
+		return u
+	}
+}
+
+// Send sends a notification with the given title and message. It returns
+// the platform's notification ID, or 0 if there is none.
+func (n *Notifier) Send(title, message string) (uint32, error) {
+	return n.SendWithOptions(title, message, SendOptions{})
+}
+
+// SendWithOptions sends a notification with the given title, message and
+// display options. It returns the platform's notification ID, which can be
+// passed as SendOptions.ReplacesID later, or 0 if there is none.
+func (n *Notifier) SendWithOptions(title, message string, opts SendOptions) (uint32, error) {
+	if len(n.Command) > 0 {
+		return 0, sendCommand(n.Command, Notification{Title: title, Message: message, Options: opts})
+	}
+
 	switch runtime.GOOS {
 	case "linux":
-		return sendLinux(title, message)
+		return n.sendLinux(title, message, opts)
 	case "darwin":
-		return sendDarwin(title, message)
+		return 0, sendDarwin(title, message)
 	case "windows":
-		return sendWindows(title, message)
+		return 0, sendWindows(title, message)
 	default:
-		return fmt.Errorf("notifications not supported on %s", runtime.GOOS)
+		return 0, fmt.Errorf("notifications not supported on %s", runtime.GOOS)
+	}
+}
+
+// CommandArgs substitutes the placeholders in argv with the fields of n.
+func CommandArgs(argv []string, n Notification) []string {
+	r := strings.NewReplacer(
+		"{title}", n.Title,
+		"{message}", n.Message,
+		"{urgency}", n.Options.Urgency,
+		"{icon}", n.Options.IconName,
+		"{url}", n.TaskURL,
+	)
+	args := make([]string, len(argv))
+	for i, a := range argv {
+		args[i] = r.Replace(a)
+	}
+	return args
+}
+
+func sendCommand(argv []string, n Notification) error {
+	args := CommandArgs(argv, n)
+	cmd := exec.Command(args[0], args[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("notify command %q exited with code %d: %s", args[0], exitErr.ExitCode(), strings.TrimSpace(stderr.String()))
+		}
+		return fmt.Errorf("failed to run notify command %q: %w", args[0], err)
+	}
+	return nil
+}
+
+// sendLinux sends over D-Bus, which reports IDs and honors
+// opts.ReplacesID. If the bus can't be reached it falls back to notify-send,
+// which does neither.
+func (n *Notifier) sendLinux(title, message string, opts SendOptions) (uint32, error) {
+	bus := n.bus
+	if bus == nil {
+		bus = gdbusBus{}
+	}
+	id, err := bus.Notify(busNotification{
+		ReplacesID: opts.ReplacesID,
+		Icon:       opts.IconName,
+		Summary:    title,
+		Body:       message,
+		Urgency:    opts.Urgency,
+		Category:   opts.Category,
+		ExpireMs:   opts.ExpireMs,
+	})
+	if err == nil {
+		return id, nil
 	}
+	slog.Debug("sending over D-Bus failed, using notify-send", "err", err)
+	return 0, sendNotifySend(title, message, opts)
 }
 
-func sendLinux(title, message string) error {
-	cmd := exec.Command("notify-send", title, message)
+func sendNotifySend(title, message string, opts SendOptions) error {
+	var args []string
+	if opts.Urgency != "" {
+		args = append(args, "--urgency", opts.Urgency)
+	}
+	if opts.IconName != "" {
+		args = append(args, "--icon", opts.IconName)
+	}
+	if opts.ExpireMs > 0 {
+		args = append(args, "--expire-time", strconv.Itoa(opts.ExpireMs))
+	}
+	if opts.Category != "" {
+		args = append(args, "--category", opts.Category)
+	}
+	args = append(args, "--", title, message)
+
+	cmd := exec.Command("notify-send", args...)
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("failed to send notification: %w", err)
 	}
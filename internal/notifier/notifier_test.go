@@ -0,0 +1,32 @@
+package notifier
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommandArgs(t *testing.T) {
+	argv := []string{"dunstify", "-a", "sked", "-u", "{urgency}", "{title}", "{message}\n{url}"}
+	got := CommandArgs(argv, Notification{
+		Title:   "Math; rm -rf /",
+		Message: "Starts at 09:00",
+		Options: SendOptions{Urgency: "low"},
+		TaskURL: "https://meet.example.com/abc?x=1&y=$(id)",
+	})
+	want := []string{"dunstify", "-a", "sked", "-u", "low", "Math; rm -rf /", "Starts at 09:00\nhttps://meet.example.com/abc?x=1&y=$(id)"}
+
+	if strings.Join(got, "\x00") != strings.Join(want, "\x00") {
+		t.Errorf("CommandArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestSendCommand_ReportsExitCodeAndStderr(t *testing.T) {
+	n := &Notifier{Command: []string{"sh", "-c", "echo oops >&2; exit 3"}}
+	_, err := n.Send("title", "message")
+	if err == nil {
+		t.Fatal("Expected error from failing command")
+	}
+	if !strings.Contains(err.Error(), "code 3") || !strings.Contains(err.Error(), "oops") {
+		t.Errorf("Expected exit code and stderr in error, got: %v", err)
+	}
+}
@@ -0,0 +1,226 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Presence reports whether the user is likely to see a notification.
+type Presence interface {
+	// IdleTime returns how long the session has had no input.
+	IdleTime() (time.Duration, error)
+	// DoNotDisturb reports whether the desktop is holding back notifications.
+	DoNotDisturb() (bool, error)
+}
+
+// DesktopPresence queries the Linux desktop over D-Bus with gdbus, falling
+// back to logind's IdleHint via loginctl. Other platforms report errors,
+// which Gate treats as "present".
+type DesktopPresence struct {
+	// run executes a command and returns its standard output.
+	run func(name string, args ...string) (string, error)
+	now func() time.Time
+}
+
+// NewDesktopPresence creates a DesktopPresence for the current session.
+func NewDesktopPresence() *DesktopPresence {
+	return &DesktopPresence{run: runOutput, now: time.Now}
+}
+
+func runOutput(name string, args ...string) (string, error) {
+	out, err := exec.Command(name, args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// IdleTime implements Presence. It asks org.freedesktop.ScreenSaver (KDE and
+// most other desktops), then GNOME's Mutter idle monitor, then logind.
+func (p *DesktopPresence) IdleTime() (time.Duration, error) {
+	if runtime.GOOS != "linux" {
+		return 0, fmt.Errorf("idle detection not supported on %s", runtime.GOOS)
+	}
+	var errs []error
+	out, err := p.run("gdbus", "call", "--session",
+		"--dest", "org.freedesktop.ScreenSaver",
+		"--object-path", "/org/freedesktop/ScreenSaver",
+		"--method", "org.freedesktop.ScreenSaver.GetSessionIdleTime")
+	if err == nil {
+		// Reported in milliseconds
+		ms, perr := parseGVariantUint(out)
+		if perr == nil {
+			return time.Duration(ms) * time.Millisecond, nil
+		}
+		err = perr
+	}
+	errs = append(errs, err)
+
+	out, err = p.run("gdbus", "call", "--session",
+		"--dest", "org.gnome.Mutter.IdleMonitor",
+		"--object-path", "/org/gnome/Mutter/IdleMonitor/Core",
+		"--method", "org.gnome.Mutter.IdleMonitor.GetIdletime")
+	if err == nil {
+		ms, perr := parseGVariantUint(out)
+		if perr == nil {
+			return time.Duration(ms) * time.Millisecond, nil
+		}
+		err = perr
+	}
+	errs = append(errs, err)
+
+	// Without a session ID, loginctl shows the caller's session
+	args := []string{"show-session"}
+	if id := os.Getenv("XDG_SESSION_ID"); id != "" {
+		args = append(args, id)
+	}
+	out, err = p.run("loginctl", append(args, "-p", "IdleHint", "-p", "IdleSinceHint")...)
+	if err == nil {
+		idle, perr := parseLogindIdle(out, p.now())
+		if perr == nil {
+			return idle, nil
+		}
+		err = perr
+	}
+	errs = append(errs, err)
+	return 0, errors.Join(errs...)
+}
+
+// DoNotDisturb implements Presence. It reads the Inhibited property of the
+// notification server (KDE Plasma), then GNOME's show-banners setting.
+func (p *DesktopPresence) DoNotDisturb() (bool, error) {
+	if runtime.GOOS != "linux" {
+		return false, fmt.Errorf("do-not-disturb detection not supported on %s", runtime.GOOS)
+	}
+	var errs []error
+	out, err := p.run("gdbus", "call", "--session",
+		"--dest", "org.freedesktop.Notifications",
+		"--object-path", "/org/freedesktop/Notifications",
+		"--method", "org.freedesktop.DBus.Properties.Get",
+		"org.freedesktop.Notifications", "Inhibited")
+	if err == nil {
+		on, perr := parseGVariantBool(out)
+		if perr == nil {
+			return on, nil
+		}
+		err = perr
+	}
+	errs = append(errs, err)
+
+	out, err = p.run("gsettings", "get", "org.gnome.desktop.notifications", "show-banners")
+	if err == nil {
+		switch out {
+		case "true":
+			return false, nil
+		case "false":
+			return true, nil
+		}
+		err = fmt.Errorf("unexpected show-banners value %q", out)
+	}
+	errs = append(errs, err)
+	return false, errors.Join(errs...)
+}
+
+// parseGVariantUint parses a gdbus reply holding one unsigned integer,
+// such as "(uint32 1234,)" or "(uint64 1234,)".
+func parseGVariantUint(s string) (uint64, error) {
+	v := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), "("), ",)")
+	if _, num, ok := strings.Cut(v, " "); ok && strings.HasPrefix(v, "uint") {
+		v = num
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected gdbus reply %q", s)
+	}
+	return n, nil
+}
+
+// parseGVariantBool parses a gdbus property reply such as "(<true>,)".
+func parseGVariantBool(s string) (bool, error) {
+	v := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), "("), ",)")
+	switch strings.Trim(v, "<>") {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("unexpected gdbus reply %q", s)
+}
+
+// parseLogindIdle parses loginctl's IdleHint and IdleSinceHint (microseconds
+// since the epoch) properties into an idle duration at now.
+func parseLogindIdle(s string, now time.Time) (time.Duration, error) {
+	var hint, since string
+	for _, line := range strings.Split(s, "\n") {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), "=")
+		switch key {
+		case "IdleHint":
+			hint = value
+		case "IdleSinceHint":
+			since = value
+		}
+	}
+	switch hint {
+	case "no":
+		return 0, nil
+	case "yes":
+	default:
+		return 0, fmt.Errorf("unexpected loginctl output %q", s)
+	}
+	us, err := strconv.ParseInt(since, 10, 64)
+	if err != nil || us <= 0 {
+		return 0, fmt.Errorf("unexpected IdleSinceHint %q", since)
+	}
+	idle := now.Sub(time.UnixMicro(us))
+	if idle < 0 {
+		idle = 0
+	}
+	return idle, nil
+}
+
+// Reasons Gate.Check gives for holding back a notification.
+const (
+	SuppressedIdle = "idle"
+	SuppressedDND  = "do-not-disturb"
+)
+
+// Gate decides whether notifications should be sent now.
+type Gate struct {
+	Presence Presence
+	// IdleAfter suppresses notifications once the session has been idle
+	// this long; 0 disables the check.
+	IdleAfter time.Duration
+	// DND suppresses notifications while Do-Not-Disturb is on.
+	DND bool
+}
+
+// Check returns why notifications should be held back now, or "" to send
+// them. Presence that can't be determined lets notifications through.
+func (g *Gate) Check() string {
+	if g.IdleAfter > 0 {
+		idle, err := g.Presence.IdleTime()
+		switch {
+		case err != nil:
+			slog.Debug("cannot determine idle time", "err", err)
+		case idle >= g.IdleAfter:
+			return SuppressedIdle
+		}
+	}
+	if g.DND {
+		on, err := g.Presence.DoNotDisturb()
+		switch {
+		case err != nil:
+			slog.Debug("cannot determine do-not-disturb state", "err", err)
+		case on:
+			return SuppressedDND
+		}
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+package notifier
+
+import (
+	"errors"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestParseGVariantUint(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint64
+		wantErr bool
+	}{
+		{in: "(uint32 1234,)", want: 1234},
+		{in: "(uint64 600000,)\n", want: 600000},
+		{in: "(5,)", want: 5},
+		{in: "('org.freedesktop.DBus.Error.ServiceUnknown',)", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseGVariantUint(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseGVariantBool(t *testing.T) {
+	if on, err := parseGVariantBool("(<true>,)"); err != nil || !on {
+		t.Errorf("Expected true, got %v (%v)", on, err)
+	}
+	if on, err := parseGVariantBool("(<false>,)"); err != nil || on {
+		t.Errorf("Expected false, got %v (%v)", on, err)
+	}
+	if _, err := parseGVariantBool("(<'x'>,)"); err == nil {
+		t.Error("Expected error for a non-boolean reply")
+	}
+}
+
+func TestParseLogindIdle(t *testing.T) {
+	now := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	since := now.Add(-15 * time.Minute).UnixMicro()
+
+	idle, err := parseLogindIdle("IdleHint=yes\nIdleSinceHint="+strconv.FormatInt(since, 10), now)
+	if err != nil || idle != 15*time.Minute {
+		t.Errorf("Expected 15m, got %v (%v)", idle, err)
+	}
+	idle, err = parseLogindIdle("IdleHint=no\nIdleSinceHint="+strconv.FormatInt(since, 10), now)
+	if err != nil || idle != 0 {
+		t.Errorf("Expected 0 while active, got %v (%v)", idle, err)
+	}
+	if _, err := parseLogindIdle("", now); err == nil {
+		t.Error("Expected error for empty output")
+	}
+}
+
+func TestDesktopPresence_Fallbacks(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("presence is only queried on Linux")
+	}
+	var calls []string
+	p := &DesktopPresence{
+		run: func(name string, args ...string) (string, error) {
+			calls = append(calls, name)
+			switch name {
+			case "gdbus":
+				return "", errors.New("service unknown")
+			case "gsettings":
+				return "false", nil
+			}
+			return "IdleHint=no", nil
+		},
+		now: time.Now,
+	}
+
+	idle, err := p.IdleTime()
+	if err != nil || idle != 0 {
+		t.Errorf("Expected logind fallback to report 0, got %v (%v)", idle, err)
+	}
+	on, err := p.DoNotDisturb()
+	if err != nil || !on {
+		t.Errorf("Expected GNOME show-banners=false to mean do-not-disturb, got %v (%v)", on, err)
+	}
+	want := []string{"gdbus", "gdbus", "loginctl", "gdbus", "gsettings"}
+	if len(calls) != len(want) {
+		t.Fatalf("Expected calls %v, got %v", want, calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("Expected calls %v, got %v", want, calls)
+			break
+		}
+	}
+}
+
+type fakePresence struct {
+	idle    time.Duration
+	dnd     bool
+	idleErr error
+}
+
+func (f fakePresence) IdleTime() (time.Duration, error) { return f.idle, f.idleErr }
+func (f fakePresence) DoNotDisturb() (bool, error)      { return f.dnd, nil }
+
+func TestGate_Check(t *testing.T) {
+	tests := []struct {
+		name string
+		gate Gate
+		want string
+	}{
+		{name: "active", gate: Gate{Presence: fakePresence{idle: time.Minute}, IdleAfter: 10 * time.Minute}, want: ""},
+		{name: "idle", gate: Gate{Presence: fakePresence{idle: 11 * time.Minute}, IdleAfter: 10 * time.Minute}, want: SuppressedIdle},
+		{name: "idle_check_disabled", gate: Gate{Presence: fakePresence{idle: time.Hour}}, want: ""},
+		{name: "unknown_idle_sends", gate: Gate{Presence: fakePresence{idleErr: errors.New("no dbus")}, IdleAfter: time.Minute}, want: ""},
+		{name: "dnd", gate: Gate{Presence: fakePresence{dnd: true}, DND: true}, want: SuppressedDND},
+		{name: "dnd_ignored", gate: Gate{Presence: fakePresence{dnd: true}}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.gate.Check(); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package notifier
+
+import (
+	"sync"
+	"time"
+)
+
+// Replacements remembers the ID of the last notification shown for each
+// task instance, so a later reminder or the "starting now" notification
+// replaces the earlier bubble rather than stacking up. It is safe for
+// concurrent use.
+type Replacements struct {
+	mu  sync.Mutex
+	ids map[string]replacement
+}
+
+type replacement struct {
+	id uint32
+	// until is when the entry may be forgotten: the task's end.
+	until time.Time
+}
+
+// NewReplacements creates an empty Replacements.
+func NewReplacements() *Replacements {
+	return &Replacements{ids: make(map[string]replacement)}
+}
+
+// replaceKey identifies the task instance n is about, or "" if there is none.
+func replaceKey(n Notification) string {
+	if n.TaskName == "" || n.TaskStart.IsZero() {
+		return ""
+	}
+	return n.TaskName + "|" + n.TaskStart.Format(time.RFC3339)
+}
+
+// Send delivers n through b, replacing the task's previous notification on
+// backends that support it, and forgets tasks that ended before now.
+func (r *Replacements) Send(b Fanout, n Notification, now time.Time) error {
+	key := replaceKey(n)
+
+	r.mu.Lock()
+	for k, e := range r.ids {
+		if e.until.Before(now) {
+			delete(r.ids, k)
+		}
+	}
+	if key != "" {
+		n.Options.ReplacesID = r.ids[key].id
+	}
+	r.mu.Unlock()
+
+	id, err := b.NotifyID(n)
+	if key == "" || id == 0 {
+		return err
+	}
+	until := n.TaskEnd
+	if until.IsZero() {
+		until = n.TaskStart
+	}
+	r.mu.Lock()
+	r.ids[key] = replacement{id: id, until: until}
+	r.mu.Unlock()
+	return err
+}
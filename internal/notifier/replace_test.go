@@ -0,0 +1,117 @@
+package notifier
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeBus records Notify calls and hands out increasing IDs, like a
+// notification server that keeps the ID of a replaced notification.
+type fakeBus struct {
+	calls []busNotification
+	next  uint32
+}
+
+func (f *fakeBus) Notify(n busNotification) (uint32, error) {
+	f.calls = append(f.calls, n)
+	if n.ReplacesID != 0 {
+		return n.ReplacesID, nil
+	}
+	f.next++
+	return f.next, nil
+}
+
+func TestReplacements_ReplacesID(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the D-Bus backend is only used on Linux")
+	}
+	bus := &fakeBus{}
+	backends := Fanout{&Notifier{bus: bus}}
+	r := NewReplacements()
+
+	start := time.Date(2025, 3, 3, 10, 0, 0, 0, time.UTC)
+	math := Notification{Title: "Math in 10m", TaskName: "Math", TaskStart: start, TaskEnd: start.Add(time.Hour)}
+	art := Notification{Title: "Art in 10m", TaskName: "Art", TaskStart: start.Add(time.Hour), TaskEnd: start.Add(2 * time.Hour)}
+
+	steps := []struct {
+		n    Notification
+		at   time.Time
+		want uint32
+	}{
+		{n: math, at: start.Add(-10 * time.Minute), want: 0},
+		{n: art, at: start.Add(-10 * time.Minute), want: 0},
+		{n: math, at: start.Add(-time.Minute), want: 1},
+		{n: math, at: start, want: 1},
+		// Notifications without a task never replace anything
+		{n: Notification{Title: "Test"}, at: start, want: 0},
+		{n: Notification{Title: "Test"}, at: start, want: 0},
+		// Math has ended, so its ID is forgotten
+		{n: math, at: start.Add(2 * time.Hour), want: 0},
+	}
+	for i, s := range steps {
+		if err := r.Send(backends, s.n, s.at); err != nil {
+			t.Fatalf("step %d: unexpected error: %v", i, err)
+		}
+		if got := bus.calls[i].ReplacesID; got != s.want {
+			t.Errorf("step %d (%s): Expected replaces-id %d, got %d", i, s.n.Title, s.want, got)
+		}
+	}
+}
+
+func TestFanout_NotifyID_WithoutReplacement(t *testing.T) {
+	// Backends without replacement semantics get every notification as
+	// before and report no ID.
+	var got []Notification
+	f := Fanout{recordingBackend{sent: &got}}
+	id, err := f.NotifyID(Notification{Title: "Math", Options: SendOptions{ReplacesID: 7}})
+	if err != nil || id != 0 {
+		t.Errorf("Expected ID 0 and no error, got %d (%v)", id, err)
+	}
+	if len(got) != 1 {
+		t.Errorf("Expected 1 notification, got %d", len(got))
+	}
+}
+
+type recordingBackend struct {
+	sent *[]Notification
+}
+
+func (b recordingBackend) Name() string { return "recording" }
+
+func (b recordingBackend) Notify(n Notification) error {
+	*b.sent = append(*b.sent, n)
+	return nil
+}
+
+func TestGdbusNotifyArgs(t *testing.T) {
+	args := gdbusNotifyArgs(busNotification{
+		ReplacesID: 42,
+		Icon:       "appointment-soon",
+		Summary:    `Say "hi"`,
+		Body:       "Line 1\nC:\\path",
+		Urgency:    UrgencyCritical,
+		ExpireMs:   5000,
+	})
+	tail := args[len(args)-8:]
+	want := []string{`"sked"`, "42", `"appointment-soon"`, `"Say \"hi\""`, `"Line 1\nC:\\path"`, "@as []", "@a{sv} {'urgency': <byte 2>}", "5000"}
+	for i := range want {
+		if tail[i] != want[i] {
+			t.Errorf("Expected argument %d to be %s, got %s", i, want[i], tail[i])
+		}
+	}
+}
+
+func TestGdbusNotifyArgs_NoOptionAfterMethod(t *testing.T) {
+	// Without a timeout the last argument is -1, which gdbus would take for
+	// an option if the method arguments didn't follow "--"
+	args := gdbusNotifyArgs(busNotification{Summary: "-v"})
+	method := slices.Index(args, "org.freedesktop.Notifications.Notify")
+	if method < 0 || method+1 >= len(args) || args[method+1] != "--" {
+		t.Fatalf("Expected -- right after the method name, got %q", args)
+	}
+	if got := args[len(args)-1]; got != "-1" {
+		t.Errorf("Expected the expire timeout -1 last, got %s", got)
+	}
+}
@@ -0,0 +1,116 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// stateRetention is how long a notified signature is remembered.
+const stateRetention = 24 * time.Hour
+
+// State remembers which task instances have already been notified about,
+// so that restarting watch mode does not repeat a notification.
+type State struct {
+	path     string
+	Notified map[string]time.Time `json:"notified"`
+}
+
+// Signature identifies a single notification for a task instance.
+func Signature(name string, start time.Time, offset time.Duration) string {
+	return fmt.Sprintf("%s|%s|%s", name, start.Format(time.RFC3339), offset)
+}
+
+// DefaultStatePath returns the location of the state file under the user cache directory.
+func DefaultStatePath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("could not find user cache directory: %w", err)
+	}
+	return filepath.Join(cacheDir, "sked", "notify_state.json"), nil
+}
+
+// NewMemoryState creates a State that is never persisted.
+func NewMemoryState() *State {
+	return &State{Notified: make(map[string]time.Time)}
+}
+
+// LoadState reads the state file at path. A missing file yields an empty state.
+func LoadState(path string) (*State, error) {
+	s := NewMemoryState()
+	s.path = path
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return s, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return s, fmt.Errorf("invalid notification state %s: %w", path, err)
+	}
+	if s.Notified == nil {
+		s.Notified = make(map[string]time.Time)
+	}
+	return s, nil
+}
+
+// Seen reports whether sig has already been notified.
+func (s *State) Seen(sig string) bool {
+	_, ok := s.Notified[sig]
+	return ok
+}
+
+// Mark records sig as notified at the given time.
+func (s *State) Mark(sig string, at time.Time) {
+	s.Notified[sig] = at
+}
+
+// Prune drops entries recorded more than a day before now.
+func (s *State) Prune(now time.Time) {
+	for sig, at := range s.Notified {
+		if now.Sub(at) > stateRetention {
+			delete(s.Notified, sig)
+		}
+	}
+}
+
+// Save atomically writes the state to disk. It is a no-op for in-memory states.
+func (s *State) Save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".notify_state-*.json")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, s.path); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package notifier
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestState_SaveLoadPrune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sked", "notify_state.json")
+
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() on missing file returned error: %v", err)
+	}
+
+	now := time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC)
+	oldSig := Signature("Math", now.Add(-48*time.Hour), 5*time.Minute)
+	newSig := Signature("History", now.Add(time.Hour), 5*time.Minute)
+
+	state.Mark(oldSig, now.Add(-47*time.Hour))
+	state.Mark(newSig, now)
+	if err := state.Save(); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() returned error: %v", err)
+	}
+	if !loaded.Seen(oldSig) || !loaded.Seen(newSig) {
+		t.Fatalf("Expected both signatures to be persisted, got %v", loaded.Notified)
+	}
+
+	loaded.Prune(now)
+	if loaded.Seen(oldSig) {
+		t.Errorf("Expected entry older than a day to be pruned")
+	}
+	if !loaded.Seen(newSig) {
+		t.Errorf("Expected recent entry to be kept")
+	}
+}
+
+func TestSignature_IncludesOffset(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	if Signature("Math", start, 5*time.Minute) == Signature("Math", start, 10*time.Minute) {
+		t.Errorf("Expected different offsets to produce different signatures")
+	}
+}
@@ -0,0 +1,170 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Webhook payload formats.
+const (
+	FormatGeneric = "generic"
+	FormatSlack   = "slack"
+	FormatDiscord = "discord"
+)
+
+// Webhook posts notifications to an HTTP endpoint.
+type Webhook struct {
+	URL     string
+	Method  string
+	Headers map[string]string
+	Format  string
+	Client  *http.Client
+
+	body *template.Template
+}
+
+// webhookTemplateData is what a custom body template can reference.
+type webhookTemplateData struct {
+	Title     string
+	Message   string
+	TaskName  string
+	TaskStart string
+	TaskEnd   string
+	TaskURL   string
+}
+
+// genericPayload is the body sent by the generic format.
+type genericPayload struct {
+	Title   string `json:"title"`
+	Message string `json:"message"`
+	Task    struct {
+		Name  string `json:"name"`
+		Start string `json:"start"`
+		End   string `json:"end"`
+		URL   string `json:"url,omitempty"`
+	} `json:"task"`
+}
+
+// NewWebhook creates a webhook backend. bodyTemplate, if set, is a text/template
+// rendering the request body; it overrides the format preset. Use {{json .Title}}
+// to insert a value as a quoted JSON string.
+func NewWebhook(url, method, format, bodyTemplate string, headers map[string]string) (*Webhook, error) {
+	if url == "" {
+		return nil, fmt.Errorf("webhook url is required")
+	}
+	if method == "" {
+		method = http.MethodPost
+	}
+	if format == "" {
+		format = FormatGeneric
+	}
+	switch format {
+	case FormatGeneric, FormatSlack, FormatDiscord:
+	default:
+		return nil, fmt.Errorf("unknown webhook format '%s' (expected generic, slack or discord)", format)
+	}
+
+	w := &Webhook{
+		URL:     url,
+		Method:  strings.ToUpper(method),
+		Headers: headers,
+		Format:  format,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if bodyTemplate != "" {
+		tmpl, err := template.New("webhook").Funcs(template.FuncMap{
+			"json": func(v any) (string, error) {
+				b, err := json.Marshal(v)
+				return string(b), err
+			},
+		}).Parse(bodyTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook body template: %w", err)
+		}
+		w.body = tmpl
+	}
+	return w, nil
+}
+
+// Name implements Backend.
+func (w *Webhook) Name() string {
+	return "webhook"
+}
+
+// Notify implements Backend.
+func (w *Webhook) Notify(n Notification) error {
+	payload, err := w.Payload(n)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(w.Method, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range w.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Payload renders the request body for n.
+func (w *Webhook) Payload(n Notification) ([]byte, error) {
+	data := webhookTemplateData{
+		Title:    n.Title,
+		Message:  n.Message,
+		TaskName: n.TaskName,
+		TaskURL:  n.TaskURL,
+	}
+	if !n.TaskStart.IsZero() {
+		data.TaskStart = n.TaskStart.Format(time.RFC3339)
+	}
+	if !n.TaskEnd.IsZero() {
+		data.TaskEnd = n.TaskEnd.Format(time.RFC3339)
+	}
+
+	if w.body != nil {
+		var buf bytes.Buffer
+		if err := w.body.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("failed to render webhook body: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	switch w.Format {
+	case FormatSlack:
+		return json.Marshal(map[string]string{
+			"text": fmt.Sprintf("*%s*\n%s", data.Title, data.Message),
+		})
+	case FormatDiscord:
+		return json.Marshal(map[string]string{
+			"content": fmt.Sprintf("**%s**\n%s", data.Title, data.Message),
+		})
+	default:
+		out := genericPayload{Title: data.Title, Message: data.Message}
+		out.Task.Name = data.TaskName
+		out.Task.Start = data.TaskStart
+		out.Task.End = data.TaskEnd
+		out.Task.URL = data.TaskURL
+		return json.Marshal(out)
+	}
+}
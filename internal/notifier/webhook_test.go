@@ -0,0 +1,139 @@
+package notifier
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebhook_Presets(t *testing.T) {
+	n := Notification{
+		Title:     "Math",
+		Message:   "Starts at 09:00 (in 5m0s)",
+		TaskName:  "Math",
+		TaskStart: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		TaskEnd:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		TaskURL:   "https://meet.example.com/math",
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		body   string
+		want   string
+	}{
+		{
+			name:   "slack",
+			format: FormatSlack,
+			want:   `{"text":"*Math*\nStarts at 09:00 (in 5m0s)"}`,
+		},
+		{
+			name:   "discord",
+			format: FormatDiscord,
+			want:   `{"content":"**Math**\nStarts at 09:00 (in 5m0s)"}`,
+		},
+		{
+			name: "generic",
+			want: `{"title":"Math","message":"Starts at 09:00 (in 5m0s)","task":{"name":"Math","start":"2024-01-01T09:00:00Z","end":"2024-01-01T10:00:00Z","url":"https://meet.example.com/math"}}`,
+		},
+		{
+			name: "template",
+			body: `{"msg": {{json .Message}}, "at": "{{.TaskStart}}", "link": {{json .TaskURL}}}`,
+			want: `{"msg": "Starts at 09:00 (in 5m0s)", "at": "2024-01-01T09:00:00Z", "link": "https://meet.example.com/math"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBody, gotHeader, gotMethod string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				gotBody = string(b)
+				gotHeader = r.Header.Get("X-Token")
+				gotMethod = r.Method
+			}))
+			defer ts.Close()
+
+			wh, err := NewWebhook(ts.URL, "", tt.format, tt.body, map[string]string{"X-Token": "secret"})
+			if err != nil {
+				t.Fatalf("NewWebhook() returned error: %v", err)
+			}
+			if err := wh.Notify(n); err != nil {
+				t.Fatalf("Notify() returned error: %v", err)
+			}
+
+			if gotBody != tt.want {
+				t.Errorf("Payload mismatch:\n got: %s\nwant: %s", gotBody, tt.want)
+			}
+			if gotHeader != "secret" {
+				t.Errorf("Expected custom header to be sent, got %q", gotHeader)
+			}
+			if gotMethod != http.MethodPost {
+				t.Errorf("Expected POST, got %s", gotMethod)
+			}
+		})
+	}
+}
+
+func TestWebhook_ErrorStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	wh, err := NewWebhook(ts.URL, "", "", "", nil)
+	if err != nil {
+		t.Fatalf("NewWebhook() returned error: %v", err)
+	}
+	if err := wh.Notify(Notification{Title: "x"}); err == nil {
+		t.Errorf("Expected error for 500 response")
+	}
+}
+
+type fakeBackend struct {
+	err   error
+	calls int
+}
+
+func (f *fakeBackend) Name() string { return "fake" }
+
+func (f *fakeBackend) Notify(n Notification) error {
+	f.calls++
+	return f.err
+}
+
+func TestFanout_ContinuesAfterFailure(t *testing.T) {
+	failing := &fakeBackend{err: errors.New("boom")}
+	ok := &fakeBackend{}
+
+	err := Fanout{failing, ok}.Notify(Notification{Title: "x"})
+	if err == nil {
+		t.Errorf("Expected the failure to be reported")
+	}
+	if ok.calls != 1 {
+		t.Errorf("Expected the second backend to still be called, got %d calls", ok.calls)
+	}
+}
+
+func TestFanout_Logs(t *testing.T) {
+	var buf bytes.Buffer
+	defer slog.SetDefault(slog.Default())
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	Fanout{&fakeBackend{err: errors.New("boom")}, &fakeBackend{}}.Notify(Notification{Title: "Math"})
+
+	for _, want := range []string{
+		`msg="notification failed" backend=fake title=Math err=boom`,
+		`msg="notification sent" backend=fake title=Math`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected log line with %q, got:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Package opener opens URLs with the platform's default handler.
+package opener
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// Command returns the argv that opens url on goos. The URL is passed as a
+// single argument and never through a shell, so config values can't inject
+// commands. On Windows, "start" is a cmd.exe builtin, so the URL handler is
+// invoked through rundll32 instead.
+func Command(goos, url string) ([]string, error) {
+	switch goos {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return []string{"xdg-open", url}, nil
+	case "darwin":
+		return []string{"open", url}, nil
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler", url}, nil
+	default:
+		return nil, fmt.Errorf("opening URLs is not supported on %s", goos)
+	}
+}
+
+// Open starts the platform opener for url without waiting for it to exit.
+func Open(url string) error {
+	argv, err := Command(runtime.GOOS, url)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(argv[0], argv[1:]...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run %s: %w", argv[0], err)
+	}
+	// Reap the opener in the background; it usually exits right away.
+	go cmd.Wait()
+	return nil
+}
@@ -0,0 +1,38 @@
+package opener
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	url := "https://meet.example.com/abc?x=1&y=$(id)"
+	tests := []struct {
+		goos    string
+		want    []string
+		wantErr bool
+	}{
+		{goos: "linux", want: []string{"xdg-open", url}},
+		{goos: "darwin", want: []string{"open", url}},
+		{goos: "windows", want: []string{"rundll32", "url.dll,FileProtocolHandler", url}},
+		{goos: "plan9", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			got, err := Command(tt.goos, url)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Command() returned error: %v", err)
+			}
+			if strings.Join(got, "\x00") != strings.Join(tt.want, "\x00") {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// renderAgenda renders the whole day as a Markdown checklist or as org-mode
+// entries. Tasks recorded as done are checked, skipped ones struck through
+// or canceled, and a task's url follows as a sub-line.
+func renderAgenda(ctx DayContext) (string, error) {
+	return Agenda(ctx.Day, ctx.Options), nil
+}
+
+// Agenda renders day in opts.Format, FormatMarkdown or FormatOrg.
+func Agenda(day *Day, opts Options) string {
+	var b strings.Builder
+	if day.Info.IsOff {
+		text := opts.offDayText()
+		if opts.Format == FormatOrg {
+			text = orgEscape(text)
+		} else {
+			text = markdownEscape(text)
+		}
+		b.WriteString(text + "\n")
+		return b.String()
+	}
+	for _, t := range day.Tasks {
+		if t.RawName == "/" {
+			continue
+		}
+		status := ""
+		if opts.Status != nil {
+			status = opts.Status(t)
+		}
+		if opts.Format == FormatOrg {
+			writeOrgEntry(&b, t, status, opts.Icons)
+		} else {
+			writeMarkdownItem(&b, t, status, opts.Icons, opts.Clock)
+		}
+	}
+	return b.String()
+}
+
+func agendaName(t scheduler.TaskEvent, icons bool) string {
+	if icons {
+		return t.Label()
+	}
+	return t.Name
+}
+
+func writeMarkdownItem(b *strings.Builder, t scheduler.TaskEvent, status string, icons bool, clock config.Clock) {
+	box := "[ ]"
+	if status == "done" {
+		box = "[x]"
+	}
+	text := fmt.Sprintf("%s–%s %s", clock.Format(t.StartTime), clock.Format(t.EndTime), markdownEscape(agendaName(t, icons)))
+	if status == "skipped" {
+		text = "~~" + text + "~~"
+	}
+	fmt.Fprintf(b, "- %s %s\n", box, text)
+	if t.URL != "" {
+		fmt.Fprintf(b, "  - <%s>\n", t.URL)
+	}
+}
+
+func writeOrgEntry(b *strings.Builder, t scheduler.TaskEvent, status string, icons bool) {
+	keyword := "TODO"
+	switch status {
+	case "done":
+		keyword = "DONE"
+	case "skipped":
+		keyword = "CANCELED"
+	}
+	fmt.Fprintf(b, "* %s %s\n", keyword, orgEscape(agendaName(t, icons)))
+	fmt.Fprintf(b, "  SCHEDULED: <%s %s-%s>\n", t.StartTime.Format("2006-01-02 Mon"), t.StartTime.Format("15:04"), t.EndTime.Format("15:04"))
+	if t.URL != "" {
+		fmt.Fprintf(b, "  [[%s]]\n", t.URL)
+	}
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"|", `\|`, "<", `\<`, ">", `\>`, "~", `\~`, "#", `\#`,
+)
+
+// markdownEscape keeps s literal in Markdown text.
+func markdownEscape(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// Org has no backslash escapes; a zero-width space breaks up emphasis,
+// links, statistics cookies and tables instead.
+var orgEscaper = strings.NewReplacer(
+	"*", "*\u200b", "[", "[\u200b", "]", "\u200b]", "|", "\u200b|",
+)
+
+// orgEscape keeps s literal in an org-mode headline.
+func orgEscape(s string) string {
+	return orgEscaper.Replace(s)
+}
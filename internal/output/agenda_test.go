@@ -0,0 +1,75 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func TestAgenda(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2024, 1, 1, h, m, 0, 0, time.UTC) }
+	day := &Day{Tasks: []scheduler.TaskEvent{
+		{Name: "Math [A] *x*", RawName: "MATH", StartTime: at(9, 0), EndTime: at(9, 50), URL: "https://meet.example.com/a"},
+		{Name: "/", RawName: "/", StartTime: at(10, 0), EndTime: at(11, 0)},
+		{Name: "Art|Craft", RawName: "Art|Craft", StartTime: at(11, 0), EndTime: at(12, 0), Icon: "🎨"},
+		{Name: "Gym", RawName: "Gym", StartTime: at(17, 0), EndTime: at(18, 0)},
+	}}
+	status := func(t scheduler.TaskEvent) string {
+		switch t.RawName {
+		case "Art|Craft":
+			return "done"
+		case "Gym":
+			return "skipped"
+		}
+		return ""
+	}
+
+	tests := []struct {
+		name string
+		day  *Day
+		opts Options
+		want string
+	}{
+		{
+			name: "markdown",
+			day:  day,
+			opts: Options{Format: FormatMarkdown, Status: status},
+			want: "- [ ] 09:00–09:50 Math \\[A\\] \\*x\\*\n" +
+				"  - <https://meet.example.com/a>\n" +
+				"- [x] 11:00–12:00 Art\\|Craft\n" +
+				"- [ ] ~~17:00–18:00 Gym~~\n",
+		},
+		{
+			name: "org",
+			day:  day,
+			opts: Options{Format: FormatOrg, Status: status, Icons: true},
+			want: "* TODO Math [\u200bA\u200b] *\u200bx*\u200b\n" +
+				"  SCHEDULED: <2024-01-01 Mon 09:00-09:50>\n" +
+				"  [[https://meet.example.com/a]]\n" +
+				"* DONE 🎨 Art\u200b|Craft\n" +
+				"  SCHEDULED: <2024-01-01 Mon 11:00-12:00>\n" +
+				"* CANCELED Gym\n" +
+				"  SCHEDULED: <2024-01-01 Mon 17:00-18:00>\n",
+		},
+		{
+			name: "no_journal",
+			day:  &Day{Tasks: day.Tasks[3:]},
+			opts: Options{Format: FormatMarkdown},
+			want: "- [ ] 17:00–18:00 Gym\n",
+		},
+		{
+			name: "off_day",
+			day:  &Day{Info: scheduler.DayInfo{IsOff: true}},
+			opts: Options{Format: FormatMarkdown, OffDayText: "Holiday *yay*"},
+			want: "Holiday \\*yay\\*\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Agenda(tt.day, tt.opts); got != tt.want {
+				t.Errorf("Expected:\n%q\ngot:\n%q", tt.want, got)
+			}
+		})
+	}
+}
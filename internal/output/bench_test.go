@@ -0,0 +1,35 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// BenchmarkWriteJSON renders watch mode's --json --all document for a day of
+// 40 tasks.
+func BenchmarkWriteJSON(b *testing.B) {
+	date := time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC)
+	var tasks []scheduler.TaskEvent
+	for i := range 40 {
+		start := date.Add(8*time.Hour + time.Duration(i)*20*time.Minute)
+		tasks = append(tasks, scheduler.TaskEvent{
+			Name:      fmt.Sprintf("Task %d", i),
+			RawName:   fmt.Sprintf("task-%d", i),
+			StartTime: start,
+			EndTime:   start.Add(20 * time.Minute),
+			Tags:      []string{"work"},
+		})
+	}
+	day := &Day{Info: scheduler.DayInfo{Date: date, DayID: 3}, Name: "Wednesday", Tasks: tasks}
+	opts := Options{Format: FormatJSON, Compact: true, Now: tasks[18].StartTime.Add(5 * time.Minute)}
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := writeJSON(io.Discard, &tasks[17], &tasks[18], &tasks[19], day, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
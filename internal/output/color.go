@@ -0,0 +1,87 @@
+package output
+
+import (
+	"os"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+const (
+	defaultCurrentColor = "2" // green
+	defaultSoonColor    = "3" // yellow
+
+	// soonWindow is how close a task's start must be to count as "soon".
+	soonWindow = 5 * time.Minute
+)
+
+// renderer writes ANSI sequences regardless of what stdout is; callers decide
+// whether to colorize at all (see ColorEnabled).
+var renderer = func() *lipgloss.Renderer {
+	r := lipgloss.NewRenderer(os.Stdout)
+	r.SetColorProfile(termenv.ANSI256)
+	return r
+}()
+
+// ColorEnabled decides whether to colorize output for the --color mode
+// ("auto", "always" or "never"). In auto mode color is used only when stdout
+// is a terminal and NO_COLOR is unset.
+func ColorEnabled(mode string) bool {
+	switch mode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	fi, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// isSoon reports whether task hasn't started yet but will within soonWindow.
+func isSoon(task *scheduler.TaskEvent, now time.Time) bool {
+	if now.IsZero() || !task.StartTime.After(now) {
+		return false
+	}
+	return task.StartTime.Sub(now) <= soonWindow
+}
+
+func colorizeName(task *scheduler.TaskEvent, opts Options) string {
+	return renderer.NewStyle().Foreground(lipgloss.Color(taskColor(task, opts))).Render(task.Name)
+}
+
+// taskColor returns the color of task: the soon color if it starts soon,
+// else its own color or the current color.
+func taskColor(task *scheduler.TaskEvent, opts Options) string {
+	color := opts.Colors.Current
+	if color == "" {
+		color = defaultCurrentColor
+	}
+	if isSoon(task, opts.Now) {
+		color = opts.Colors.Soon
+		if color == "" {
+			color = defaultSoonColor
+		}
+	} else if task.Color != "" {
+		color = task.Color
+	}
+	return color
+}
+
+func colorizeTime(s string, opts Options) string {
+	style := renderer.NewStyle()
+	if opts.Colors.Time != "" {
+		style = style.Foreground(lipgloss.Color(opts.Colors.Time))
+	} else {
+		style = style.Faint(true)
+	}
+	return style.Render(s)
+}
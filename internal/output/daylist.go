@@ -0,0 +1,69 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// currentMarker leads the task in progress in a day list.
+const currentMarker = ">"
+
+// DayList renders day as a header with the date, day name, override note
+// and any Shift label, followed by one line per task with its time range
+// on opts.Clock, aligned, and current marked with ">". Empty slots are left
+// out. Off days list the off day text instead of tasks.
+func DayList(day *Day, current *scheduler.TaskEvent, opts Options) string {
+	var b strings.Builder
+	header := opts.Locale.Format(day.Info.Date, "2006-01-02")
+	if day.Name != "" {
+		header += " · " + dayName(day, opts)
+	}
+	if day.Info.Note != "" {
+		header += " · " + day.Info.Note
+	}
+	b.WriteString(header + opts.shiftLabel() + "\n")
+
+	if day.Info.IsOff {
+		b.WriteString("  " + opts.offDayText() + "\n")
+		return b.String()
+	}
+	width := opts.Clock.Width()
+	n := 0
+	for i := range day.Tasks {
+		t := &day.Tasks[i]
+		if t.RawName == "/" {
+			continue
+		}
+		n++
+		marker := " "
+		if current != nil && t.Name == current.Name && t.StartTime.Equal(current.StartTime) && t.EndTime.Equal(current.EndTime) {
+			marker = currentMarker
+		}
+		timeRange := fmt.Sprintf("%*s - %*s", width, opts.Clock.Format(t.StartTime), width, opts.Clock.Format(t.EndTime))
+		name := t.Name
+		if opts.Color {
+			timeRange = colorizeTime(timeRange, opts)
+			name = colorizeName(t, opts)
+		}
+		if opts.Icons && t.Icon != "" {
+			name = t.Icon + " " + name
+		}
+		b.WriteString(marker + " " + timeRange + "  " + name + "\n")
+	}
+	if n == 0 {
+		b.WriteString("  " + opts.Locale.T("no_tasks") + "\n")
+	}
+	return b.String()
+}
+
+// dayName returns day's name in opts.Locale: the weekday on weekly cycles,
+// else "Day N".
+func dayName(day *Day, opts Options) string {
+	if wd := time.Weekday(day.Info.DayID % 7); day.Name == wd.String() {
+		return opts.Locale.Weekday(wd)
+	}
+	return opts.Locale.T("cycle_day", day.Info.DayID)
+}
@@ -0,0 +1,77 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func TestDayList(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return date.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	tasks := []scheduler.TaskEvent{
+		{Name: "Math", RawName: "Math", Icon: "📐", StartTime: at(9, 0), EndTime: at(9, 50)},
+		{Name: "/", RawName: "/", StartTime: at(9, 50), EndTime: at(10, 0)},
+		{Name: "History", RawName: "History", StartTime: at(10, 0), EndTime: at(10, 50)},
+		{Name: "Art", RawName: "Art", StartTime: at(13, 0), EndTime: at(14, 30)},
+	}
+	day := &Day{Info: scheduler.DayInfo{Date: date, DayID: 1}, Name: "Monday", Tasks: tasks}
+
+	tests := []struct {
+		name    string
+		day     *Day
+		current *scheduler.TaskEvent
+		opts    Options
+		want    string
+	}{
+		{
+			name:    "current_marked",
+			day:     day,
+			current: &tasks[2],
+			want: "2024-01-01 · Monday\n" +
+				"  09:00 - 09:50  Math\n" +
+				"> 10:00 - 10:50  History\n" +
+				"  13:00 - 14:30  Art\n",
+		},
+		{
+			name: "twelve_hour_icons",
+			day:  day,
+			opts: Options{Clock: config.Clock12, Icons: true, Shift: 10 * time.Minute},
+			want: "2024-01-01 · Monday (in 10m)\n" +
+				"   9:00 AM -  9:50 AM  📐 Math\n" +
+				"  10:00 AM - 10:50 AM  History\n" +
+				"   1:00 PM -  2:30 PM  Art\n",
+		},
+		{
+			name: "off_day",
+			day:  &Day{Info: scheduler.DayInfo{Date: date, DayID: -1, IsOff: true, Note: "PTO"}},
+			want: "2024-01-01 · PTO\n  Day off.\n",
+		},
+		{
+			name: "german",
+			day:  &Day{Info: scheduler.DayInfo{Date: date, DayID: 5}, Name: "Friday"},
+			opts: Options{Locale: german},
+			want: "2024-01-01 · Freitag\n  Keine Aufgaben\n",
+		},
+		{
+			name: "cycle_day",
+			day:  &Day{Info: scheduler.DayInfo{Date: date, DayID: 3}, Name: "Day 3"},
+			opts: Options{Locale: german},
+			want: "2024-01-01 · Tag 3\n  Keine Aufgaben\n",
+		},
+		{
+			name: "no_tasks",
+			day:  &Day{Info: scheduler.DayInfo{Date: date, DayID: 2}, Name: "Tuesday"},
+			want: "2024-01-01 · Tuesday\n  No tasks\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DayList(tt.day, tt.current, tt.opts); got != tt.want {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// Shells that WriteEnv writes variable assignments for.
+const (
+	ShellSh         = "sh"
+	ShellFish       = "fish"
+	ShellPowerShell = "powershell"
+)
+
+// EnvVar is one variable of 'sked env'.
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// EnvVars returns the variables of the JSON document out: SKED_IS_OFF,
+// SKED_GENERATED_AT and, for each of previous, current and next, the task
+// fields as SKED_CURRENT_NAME, SKED_CURRENT_END_UNIX and so on. Every
+// variable is present whether or not there is such a task, empty if there
+// isn't, so scripts running under set -u can test them. Values are those of
+// the JSON output, with tags joined by commas.
+func EnvVars(out JSONOutput) []EnvVar {
+	vars := []EnvVar{
+		{"SKED_IS_OFF", strconv.FormatBool(out.IsOff)},
+		{"SKED_GENERATED_AT", out.GeneratedAt},
+	}
+	for _, p := range []struct {
+		prefix string
+		task   *JSONTask
+	}{{"SKED_PREVIOUS_", out.Previous}, {"SKED_CURRENT_", out.Current}, {"SKED_NEXT_", out.Next}} {
+		t := p.task
+		if t == nil {
+			t = &JSONTask{}
+		}
+		number := func(v int64) string {
+			if p.task == nil {
+				return ""
+			}
+			return strconv.FormatInt(v, 10)
+		}
+		vars = append(vars,
+			EnvVar{p.prefix + "NAME", t.Name},
+			EnvVar{p.prefix + "RAW_NAME", t.RawName},
+			EnvVar{p.prefix + "START", t.Start},
+			EnvVar{p.prefix + "END", t.End},
+			EnvVar{p.prefix + "START_UNIX", number(t.StartUnix)},
+			EnvVar{p.prefix + "END_UNIX", number(t.EndUnix)},
+			EnvVar{p.prefix + "DURATION_SECONDS", number(t.DurationSeconds)},
+			EnvVar{p.prefix + "COLOR", t.Color},
+			EnvVar{p.prefix + "ICON", t.Icon},
+			EnvVar{p.prefix + "TAGS", strings.Join(t.Tags, ",")},
+			EnvVar{p.prefix + "URL", t.URL},
+			EnvVar{p.prefix + "LOCATION", t.Location},
+			EnvVar{p.prefix + "STATUS", t.Status},
+		)
+	}
+	return vars
+}
+
+// envAssignments writes an assignment of a variable in each shell.
+var envAssignments = map[string]func(name, value string) string{
+	ShellSh:         func(name, value string) string { return "export " + name + "=" + quoteSh(value) },
+	ShellFish:       func(name, value string) string { return "set -gx " + name + " " + quoteFish(value) },
+	ShellPowerShell: func(name, value string) string { return "$env:" + name + " = " + quotePowerShell(value) },
+}
+
+// CheckShell returns an error unless shell is one WriteEnv supports.
+func CheckShell(shell string) error {
+	if _, ok := envAssignments[shell]; !ok {
+		return fmt.Errorf("invalid shell '%s' (expected sh, fish or powershell)", shell)
+	}
+	return nil
+}
+
+// WriteEnv writes the variables of the JSON output for the given tasks as
+// assignments in the syntax of shell (ShellSh, ShellFish or
+// ShellPowerShell), one per line, for scripts to eval.
+func WriteEnv(w io.Writer, previous, current, next *scheduler.TaskEvent, opts Options, shell string) error {
+	assign, ok := envAssignments[shell]
+	if !ok {
+		return CheckShell(shell)
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	out := newJSONDocument(previous, current, next, nil, opts, now)
+	var b strings.Builder
+	for _, v := range EnvVars(out) {
+		b.WriteString(assign(v.Name, v.Value))
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// quoteSh single-quotes s for POSIX shells, where nothing inside single
+// quotes is special; a quote in s ends the quoting, is escaped with a
+// backslash and starts it again.
+func quoteSh(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// quoteFish single-quotes s for fish, which takes \' and \\ as escapes
+// inside single quotes.
+func quoteFish(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// quotePowerShell single-quotes s for PowerShell, which doubles quotes
+// inside single quotes. The typographic single quotes count as quotes too.
+func quotePowerShell(s string) string {
+	return "'" + strings.NewReplacer("'", "''", "‘", "‘‘", "’", "’’", "‚", "‚‚", "‛", "‛‛").Replace(s) + "'"
+}
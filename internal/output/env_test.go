@@ -0,0 +1,128 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// evalEnv evals script in a subprocess of shell (sh with set -u) and returns
+// the values of names, one per line.
+func evalEnv(t *testing.T, shell, script string, names []string) []string {
+	t.Helper()
+	var prog string
+	var cmd *exec.Cmd
+	switch shell {
+	case ShellSh:
+		prog = "set -eu\n" + script
+		for _, n := range names {
+			prog += "printf '%s\\n' \"$" + n + "\"\n"
+		}
+		cmd = exec.Command("sh", "-c", prog)
+	case ShellFish:
+		prog = script
+		for _, n := range names {
+			prog += "printf '%s\\n' \"$" + n + "\"\n"
+		}
+		cmd = exec.Command("fish", "-c", prog)
+	}
+	if _, err := exec.LookPath(cmd.Args[0]); err != nil {
+		t.Skipf("%s not installed", cmd.Args[0])
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("%s failed: %v\n%s", shell, err, script)
+	}
+	return strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+}
+
+func TestWriteEnv_Eval(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 48, 0, 0, time.UTC)
+	current := &scheduler.TaskEvent{
+		Name:      `It's "$(rm -rf ~)" \ time`,
+		Tags:      []string{"lab", "exam"},
+		StartTime: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	names := []string{"SKED_CURRENT_NAME", "SKED_CURRENT_END_UNIX", "SKED_CURRENT_TAGS", "SKED_NEXT_NAME", "SKED_NEXT_START_UNIX", "SKED_IS_OFF"}
+	want := []string{current.Name, strconv.FormatInt(current.EndTime.Unix(), 10), "lab,exam", "", "", "true"}
+
+	for _, shell := range []string{ShellSh, ShellFish} {
+		t.Run(shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteEnv(&buf, nil, current, nil, Options{Now: now, OffDay: true}, shell); err != nil {
+				t.Fatalf("WriteEnv failed: %v", err)
+			}
+			got := evalEnv(t, shell, buf.String(), names)
+			if len(got) != len(want) {
+				t.Fatalf("Expected %d values, got %d: %q", len(want), len(got), got)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("Expected %s to be %q, got %q", names[i], want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteEnv_MatchesJSON(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 48, 0, 0, time.UTC)
+	next := &scheduler.TaskEvent{
+		Name:      "Art",
+		RawName:   "art",
+		URL:       "https://example.com/art",
+		StartTime: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	opts := Options{Now: now, Flat: true, Status: func(scheduler.TaskEvent) string { return "skipped" }}
+
+	var js bytes.Buffer
+	if err := writeJSON(&js, nil, nil, next, nil, opts); err != nil {
+		t.Fatalf("writeJSON failed: %v", err)
+	}
+	var flat map[string]any
+	if err := json.Unmarshal(js.Bytes(), &flat); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	vars := EnvVars(newJSONDocument(nil, nil, next, nil, opts, now))
+	for _, v := range vars {
+		key := strings.ToLower(strings.TrimPrefix(v.Name, "SKED_"))
+		var want string
+		switch val := flat[key].(type) {
+		case nil:
+		case string:
+			want = val
+		case float64:
+			want = strconv.FormatFloat(val, 'f', -1, 64)
+		case bool:
+			want = strconv.FormatBool(val)
+		default:
+			t.Fatalf("unexpected JSON value for %s: %v", key, val)
+		}
+		if v.Value != want {
+			t.Errorf("Expected %s to be %q as in the JSON output, got %q", v.Name, want, v.Value)
+		}
+	}
+	if len(vars) != 2+3*13 {
+		t.Errorf("Expected %d variables, got %d", 2+3*13, len(vars))
+	}
+}
+
+func TestQuotePowerShell(t *testing.T) {
+	if got, want := quotePowerShell("It's $x"), "'It''s $x'"; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestCheckShell(t *testing.T) {
+	if err := CheckShell("csh"); err == nil {
+		t.Error("Expected error for csh, got nil")
+	}
+}
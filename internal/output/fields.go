@@ -0,0 +1,179 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// CheckField reports whether path, a dotted path such as "current.name" or
+// "day.tasks.0.name", names a field of the JSON document. List elements are
+// selected by index.
+func CheckField(path string) error {
+	t := reflect.TypeFor[JSONOutput]()
+	parent := ""
+	for seg := range strings.SplitSeq(path, ".") {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			f, ok := jsonField(t, seg)
+			if !ok {
+				return fmt.Errorf("unknown field '%s' in '%s'", seg, path)
+			}
+			t = f.Type
+		case reflect.Slice:
+			if _, err := strconv.Atoi(seg); err != nil {
+				return fmt.Errorf("expected a list index instead of '%s' in '%s'", seg, path)
+			}
+			t = t.Elem()
+		default:
+			return fmt.Errorf("'%s' has no field '%s'", parent, seg)
+		}
+		parent = strings.TrimPrefix(parent+"."+seg, ".")
+	}
+	return nil
+}
+
+// jsonField returns the field of struct type t that is encoded as name,
+// looking into embedded structs.
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	return t.FieldByNameFunc(func(field string) bool {
+		f, _ := t.FieldByName(field)
+		return jsonName(f) == name
+	})
+}
+
+// jsonName returns the key f is encoded under, or "" if it is not.
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" || f.Anonymous {
+		return ""
+	}
+	return name
+}
+
+// selectField returns the value at path in out, which CheckField accepted,
+// or an invalid value if the path runs into a null or past a list's end.
+// Lists left out of the document, such as missing tags, count as null.
+func selectField(out JSONOutput, path string) reflect.Value {
+	v := reflect.ValueOf(out)
+	for seg := range strings.SplitSeq(path, ".") {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		if v.Kind() == reflect.Slice {
+			i, _ := strconv.Atoi(seg)
+			if i < 0 || i >= v.Len() {
+				return reflect.Value{}
+			}
+			v = v.Index(i)
+			continue
+		}
+		f, _ := jsonField(v.Type(), seg)
+		v = v.FieldByIndex(f.Index)
+	}
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.Value{}
+	}
+	return v
+}
+
+// writeFields writes the value of each of fields on its own line: strings
+// as they are, objects and lists as compact JSON, and nulls as def.
+func writeFields(w io.Writer, out JSONOutput, fields []string, def string) error {
+	var b strings.Builder
+	for _, path := range fields {
+		v := selectField(out, path)
+		switch {
+		case !v.IsValid():
+			b.WriteString(def)
+		case v.Kind() == reflect.String:
+			b.WriteString(v.String())
+		default:
+			data, err := json.Marshal(v.Interface())
+			if err != nil {
+				return err
+			}
+			b.Write(data)
+		}
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// flatten returns out as a single-level object whose keys join the path to
+// each value with underscores, e.g. current_name or day_tasks_0_end. Keys
+// only contain lowercase letters, digits and underscores. The fields of a
+// null task are present with null values, so the keys of previous, current
+// and next don't depend on the time of day.
+func flatten(out JSONOutput) map[string]any {
+	flat := make(map[string]any)
+	flattenValue(flat, "", reflect.ValueOf(out), reflect.TypeFor[JSONOutput]())
+	return flat
+}
+
+func flattenValue(flat map[string]any, prefix string, v reflect.Value, t reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		if v.IsValid() {
+			if v.IsNil() {
+				v = reflect.Value{}
+			} else {
+				v = v.Elem()
+			}
+		}
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := range t.NumField() {
+			f := t.Field(i)
+			var fv reflect.Value
+			if v.IsValid() {
+				fv = v.Field(i)
+			}
+			if f.Anonymous {
+				flattenValue(flat, prefix, fv, f.Type)
+				continue
+			}
+			name := jsonName(f)
+			if name == "" {
+				continue
+			}
+			// Optional objects such as day and pomodoro are left out
+			// while absent, as in the JSON document.
+			if f.Type.Kind() == reflect.Pointer && f.Type.Elem().Kind() == reflect.Struct &&
+				strings.Contains(f.Tag.Get("json"), ",omitempty") && (!fv.IsValid() || fv.IsNil()) {
+				continue
+			}
+			flattenValue(flat, prefix+name+"_", fv, f.Type)
+		}
+	case reflect.Slice:
+		if v.IsValid() {
+			for i := range v.Len() {
+				flattenValue(flat, prefix+strconv.Itoa(i)+"_", v.Index(i), t.Elem())
+			}
+		}
+	default:
+		key := strings.TrimSuffix(prefix, "_")
+		if v.IsValid() {
+			flat[key] = v.Interface()
+		} else {
+			flat[key] = nil
+		}
+	}
+}
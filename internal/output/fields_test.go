@@ -0,0 +1,98 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func TestCheckField(t *testing.T) {
+	tests := []struct {
+		path string
+		want string // error substring, "" if valid
+	}{
+		{path: "current.name"},
+		{path: "next.start_unix"},
+		{path: "current.pomodoro.phase"},
+		{path: "day.tasks.0.is_current"},
+		{path: "day.tasks"},
+		{path: "schema_version"},
+		{path: "current.nme", want: "unknown field 'nme' in 'current.nme'"},
+		{path: "current.name.x", want: "'current.name' has no field 'x'"},
+		{path: "day.tasks.first", want: "expected a list index instead of 'first'"},
+		{path: "day.JSONTask", want: "unknown field"},
+		{path: "", want: "unknown field ''"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			err := CheckField(tt.path)
+			if tt.want == "" && err != nil {
+				t.Errorf("Expected a valid path, got %v", err)
+			}
+			if tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestWriteJSON_Fields(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)
+	current := &scheduler.TaskEvent{Name: "Math", StartTime: now.Add(-30 * time.Minute), EndTime: now.Add(30 * time.Minute), Tags: []string{"a", "b"}}
+	day := &Day{Info: scheduler.DayInfo{Date: now, DayID: 1}, Tasks: []scheduler.TaskEvent{*current}}
+	opts := Options{
+		Now:          now,
+		Fields:       []string{"current.name", "current.end_unix", "next.name", "current.tags", "current.url", "day.tasks.0.is_current", "day.tasks.3.name"},
+		FieldDefault: "-",
+	}
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, nil, current, nil, day, opts); err != nil {
+		t.Fatalf("writeJSON failed: %v", err)
+	}
+	want := "Math\n1704103200\n-\n[\"a\",\"b\"]\n\ntrue\n-\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestWriteJSON_Flat(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)
+	current := &scheduler.TaskEvent{Name: "Math", StartTime: now.Add(-30 * time.Minute), EndTime: now.Add(30 * time.Minute)}
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, nil, current, nil, nil, Options{Now: now, Flat: true}); err != nil {
+		t.Fatalf("writeJSON failed: %v", err)
+	}
+	var flat map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &flat); err != nil {
+		t.Fatalf("Expected a JSON object, got %v", err)
+	}
+	for key, want := range map[string]any{
+		"current_name":     "Math",
+		"current_end":      "2024-01-01T10:00:00Z",
+		"current_end_unix": float64(1704103200),
+		"next_name":        nil,
+		"previous_end":     nil,
+		"is_off":           false,
+		"schema_version":   float64(SchemaVersion),
+	} {
+		got, ok := flat[key]
+		if !ok || got != want {
+			t.Errorf("%s: Expected %v, got %v (present: %v)", key, want, got, ok)
+		}
+	}
+	for key, v := range flat {
+		if _, ok := v.(map[string]any); ok {
+			t.Errorf("%s: Expected a flat value, got an object", key)
+		}
+		if strings.Trim(key, "abcdefghijklmnopqrstuvwxyz0123456789_") != "" {
+			t.Errorf("Expected a shell-safe key, got %q", key)
+		}
+	}
+	if _, ok := flat["current_pomodoro_phase"]; ok {
+		t.Error("Expected no pomodoro keys without a pomodoro phase")
+	}
+}
@@ -2,79 +2,246 @@
 package output
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/i18n"
 	"github.com/Daniel-42-z/sked/internal/scheduler"
 )
 
-// Print displays the task information.
-func Print(previous *scheduler.TaskEvent, current *scheduler.TaskEvent, next *scheduler.TaskEvent, dayTasks []scheduler.TaskEvent, asJSON bool, showTime bool, noTaskText string) error {
-	if asJSON {
-		return printJSON(previous, current, next, dayTasks)
-	}
-	// JSON mode outputs all three tasks (previous, current, next).
-	// Natural language mode outputs only the 'current' task (which main sets based on flags).
+// The built-in output formats, registered by this package; see Register.
+const (
+	FormatNatural = "natural"
+	FormatJSON    = "json"
+	FormatTmux    = "tmux"
+	// The day's agenda as a Markdown checklist or org-mode entries.
+	FormatMarkdown = "markdown"
+	FormatOrg      = "org"
+)
+
+// DefaultOffDayText is printed on off days when no off day text is
+// configured, translated by Options.Locale.
+const DefaultOffDayText = "Day off."
 
-	return printNatural(current, showTime, noTaskText)
+// Options controls how Print renders task information.
+type Options struct {
+	// Format is the name of a registered format: FormatNatural (the
+	// default), FormatJSON, FormatTmux, FormatMarkdown, FormatOrg or one
+	// added with Register.
+	Format   string
+	ShowTime bool
+	// Relative adds to natural output when the task ends, ended or starts
+	// relative to Now, e.g. "ended 12m ago".
+	Relative bool
+	// Clock formats the times of natural and Markdown output, and Durations
+	// the time left in tmux output.
+	Clock      config.Clock
+	Durations  config.Durations
+	NoTaskText string
+	// NoTaskBefore, NoTaskBetween and NoTaskAfter replace NoTaskText when
+	// the day's first task is still ahead, between two tasks of the day and
+	// after its last one. Like the other idle texts, they may contain
+	// {next_name} and {next_in}.
+	NoTaskBefore  string
+	NoTaskBetween string
+	NoTaskAfter   string
+	// OffDay marks the date of Now as an off day. Natural output then prints
+	// OffDayText (DefaultOffDayText if empty) instead of NoTaskText, and JSON
+	// reports is_off.
+	OffDay     bool
+	OffDayText string
+	// DayNote, the note of the override applying to the date of Now, leads
+	// the off day and no-task texts of natural output, e.g. "Conference —
+	// No task currently.".
+	DayNote string
+	// MaxWidth truncates task names in tmux and prompt output
+	// (DefaultMaxWidth if 0).
+	MaxWidth int
+	// PromptIcon leads the shell prompt segment, and HideWhenFree leaves it
+	// empty while no task is in progress.
+	PromptIcon   string
+	HideWhenFree bool
+	// Compact prints JSON on a single line.
+	Compact bool
+	// Fields, paths accepted by CheckField, replaces the JSON document with
+	// their values, one per line, printing FieldDefault for nulls. Flat
+	// prints the document as a single-level object instead.
+	Fields       []string
+	FieldDefault string
+	Flat         bool
+
+	// Icons prefixes task names with their icons in natural and tmux output.
+	Icons bool
+	// Locale translates the built-in texts of natural, tmux and agenda
+	// output (English if nil). JSON output is never translated.
+	Locale *i18n.Locale
+
+	// Color enables terminal colors in natural output using Colors.
+	Color  bool
+	Colors config.Colors
+	// Now is the reference time used to detect tasks starting soon and
+	// reported as generated_at in JSON.
+	Now time.Time
+	// Shift, if nonzero, is how far Now is from the real clock (watch mode's
+	// --lookahead with --lookahead-label). Natural output then ends with
+	// "(in 5m)" or "(5m ago)", so a shifted display is recognizable.
+	Shift time.Duration
+	// Status, if set, returns the recorded status ("done", "skipped" or "")
+	// of a task instance, reported in JSON output.
+	Status func(task scheduler.TaskEvent) string
+	// Conflicts, if set, returns the base tasks a task of a temporary
+	// schedule overlaps, reported as conflicts_with in JSON output.
+	Conflicts func(task scheduler.TaskEvent) []string
 }
 
-type ExtendedTaskEvent struct {
-	scheduler.TaskEvent
-	IsCurrent bool `json:"is_current"`
+// Print renders the task information in opts.Format and writes it to
+// stdout. day, if non-nil, adds the full day context to JSON output and
+// makes natural output list the whole day with current marked.
+func Print(previous *scheduler.TaskEvent, current *scheduler.TaskEvent, next *scheduler.TaskEvent, day *Day, opts Options) error {
+	s, err := Render(DayContext{Previous: previous, Current: current, Next: next, Day: day, Options: opts})
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.WriteString(s)
+	return err
 }
 
-type jsonOutput struct {
-	Previous *scheduler.TaskEvent `json:"previous"`
-	Current  *scheduler.TaskEvent `json:"current"`
-	Next     *scheduler.TaskEvent `json:"next"`
-	Tasks    []ExtendedTaskEvent  `json:"tasks,omitempty"`
+// renderNatural outputs only the current task (which main sets based on
+// flags); previous and next pick the text printed without one. With a day,
+// it lists the whole day instead.
+func renderNatural(ctx DayContext) (string, error) {
+	if ctx.Day != nil {
+		return DayList(ctx.Day, ctx.Current, ctx.Options), nil
+	}
+	return naturalLine(ctx.Previous, ctx.Current, ctx.Next, ctx.Options) + "\n", nil
 }
 
-func printJSON(previous *scheduler.TaskEvent, current *scheduler.TaskEvent, next *scheduler.TaskEvent, dayTasks []scheduler.TaskEvent) error {
-	var extendedTasks []ExtendedTaskEvent
-	if len(dayTasks) > 0 {
-		extendedTasks = make([]ExtendedTaskEvent, len(dayTasks))
-		for i, t := range dayTasks {
-			isCurrent := false
-			if current != nil {
-				// Compare exact times and name to identify the current task
-				if t.Name == current.Name && t.StartTime.Equal(current.StartTime) && t.EndTime.Equal(current.EndTime) {
-					isCurrent = true
-				}
-			}
-			extendedTasks[i] = ExtendedTaskEvent{
-				TaskEvent: t,
-				IsCurrent: isCurrent,
-			}
+// naturalLine describes task, or the idle text without one.
+func naturalLine(previous, task, next *scheduler.TaskEvent, opts Options) string {
+	if task == nil {
+		return opts.idleText(previous, next) + opts.shiftLabel()
+	}
+
+	name := task.Name
+	if opts.Color {
+		name = colorizeName(task, opts)
+	}
+	if opts.Icons && task.Icon != "" {
+		name = task.Icon + " " + name
+	}
+
+	line := name
+	var details []string
+	if opts.ShowTime {
+		details = append(details, opts.Clock.Format(task.StartTime)+" - "+opts.Clock.Format(task.EndTime))
+	}
+	if opts.Relative {
+		details = append(details, relativeTime(task, opts))
+	}
+	if len(details) > 0 {
+		timeRange := "(" + strings.Join(details, ", ") + ")"
+		if opts.Color {
+			timeRange = colorizeTime(timeRange, opts)
 		}
+		line += " " + timeRange
 	}
+	if p := task.PomodoroPhase(opts.Now); p != nil {
+		line += fmt.Sprintf(" [%s]", p.Describe(opts.Now))
+	}
+	return line + opts.shiftLabel()
+}
 
-	out := jsonOutput{
-		Previous: previous,
-		Current:  current,
-		Next:     next,
-		Tasks:    extendedTasks,
+// shiftLabel returns " (in 5m)" or " (5m ago)" for a nonzero Shift, else "".
+func (o Options) shiftLabel() string {
+	switch {
+	case o.Shift > 0:
+		return " " + o.Locale.T("shifted_ahead", o.Durations.Format(o.Shift))
+	case o.Shift < 0:
+		return " " + o.Locale.T("shifted_back", o.Durations.Format(-o.Shift))
 	}
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	return enc.Encode(out)
+	return ""
 }
 
-func printNatural(task *scheduler.TaskEvent, showTime bool, noTaskText string) error {
-	if task == nil {
-		if noTaskText != "" {
-			fmt.Println(noTaskText)
-		} else {
-			fmt.Println("No task currently.")
+// relativeTime describes task relative to opts.Now: when it ended, ends or
+// starts.
+func relativeTime(task *scheduler.TaskEvent, opts Options) string {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	switch {
+	case !task.EndTime.After(now):
+		return opts.Locale.T("ended_ago", opts.Durations.Format(now.Sub(task.EndTime)))
+	case task.StartTime.After(now):
+		return opts.Locale.T("starts_in", opts.Durations.Left(task.StartTime.Sub(now)))
+	}
+	return opts.Locale.T("ends_in_rel", opts.Durations.Left(task.EndTime.Sub(now)))
+}
+
+// idleText returns the natural output line without a task: the off day
+// text, the no-task text of where Now falls between previous and next, or
+// the plain no-task text, with its placeholders filled in from next and
+// after DayNote if set.
+func (o Options) idleText(previous, next *scheduler.TaskEvent) string {
+	text := o.NoTaskText
+	switch {
+	case o.OffDay:
+		text = o.offDayText()
+	case o.gapText(previous, next) != "":
+		text = o.gapText(previous, next)
+	case text == "":
+		text = o.Locale.T("no_task")
+	}
+	text = o.fillNext(text, next)
+	if o.DayNote != "" {
+		text = o.DayNote + " — " + text
+	}
+	return text
+}
+
+// gapText returns NoTaskBefore, NoTaskBetween or NoTaskAfter depending on
+// whether previous ended and next starts on the date of Now, or "" if
+// neither does or the matching text isn't set.
+func (o Options) gapText(previous, next *scheduler.TaskEvent) string {
+	afterFirst := previous != nil && sameDay(previous.EndTime, o.Now)
+	beforeLast := next != nil && sameDay(next.StartTime, o.Now)
+	switch {
+	case afterFirst && beforeLast:
+		return o.NoTaskBetween
+	case beforeLast:
+		return o.NoTaskBefore
+	case afterFirst:
+		return o.NoTaskAfter
+	}
+	return ""
+}
+
+// fillNext replaces {next_name} and {next_in} in text with the name of
+// next and the time until it starts, or with nothing if there is no next
+// task.
+func (o Options) fillNext(text string, next *scheduler.TaskEvent) string {
+	if !strings.Contains(text, "{next_") {
+		return text
+	}
+	var name, in string
+	if next != nil {
+		now := o.Now
+		if now.IsZero() {
+			now = time.Now()
 		}
-		return nil
+		name = next.Name
+		in = o.Durations.Left(next.StartTime.Sub(now))
 	}
+	return strings.NewReplacer("{next_name}", name, "{next_in}", in).Replace(text)
+}
 
-	if showTime {
-		fmt.Printf("%s (%s - %s)\n", task.Name, task.StartTime.Format("15:04"), task.EndTime.Format("15:04"))
-	} else {
-		fmt.Println(task.Name)
+// offDayText returns OffDayText, or the translated DefaultOffDayText.
+func (o Options) offDayText() string {
+	if o.OffDayText != "" {
+		return o.OffDayText
 	}
-	return nil
+	return o.Locale.T("day_off")
 }
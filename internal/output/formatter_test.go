@@ -0,0 +1,119 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/i18n"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 12, 20, 0, time.UTC)
+	at := func(h, m int) time.Time { return time.Date(2024, 1, 1, h, m, 0, 0, time.UTC) }
+	task := func(start, end time.Time) *scheduler.TaskEvent {
+		return &scheduler.TaskEvent{Name: "History", StartTime: start, EndTime: end}
+	}
+	de, _ := i18n.Lookup("de")
+	tests := []struct {
+		name string
+		task *scheduler.TaskEvent
+		opts Options
+		want string
+	}{
+		{name: "ended", task: task(at(9, 0), at(10, 0)), want: "ended 12m ago"},
+		{name: "running", task: task(at(10, 0), at(11, 0)), want: "ends in 48m"},
+		{name: "upcoming", task: task(at(12, 30), at(13, 0)), want: "starts in 2h18m"},
+		{name: "precise", task: task(at(9, 0), at(10, 0)), opts: Options{Durations: config.Durations{Precise: true}}, want: "ended 12m20s ago"},
+		{name: "german", task: task(at(9, 0), at(10, 0)), opts: Options{Locale: de}, want: "vor 12m beendet"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Now = now
+			if got := relativeTime(tt.task, tt.opts); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIdleText(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{name: "free", want: "No task currently."},
+		{name: "custom", opts: Options{NoTaskText: "Free"}, want: "Free"},
+		{name: "off", opts: Options{OffDay: true, NoTaskText: "Free"}, want: "Day off."},
+		{name: "off_with_note", opts: Options{OffDay: true, DayNote: "Conference"}, want: "Conference — Day off."},
+		{name: "note", opts: Options{DayNote: "Conference"}, want: "Conference — No task currently."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.idleText(nil, nil); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIdleText_Gaps(t *testing.T) {
+	at := func(d, h, m int) time.Time { return time.Date(2024, 1, d, h, m, 0, 0, time.UTC) }
+	math := &scheduler.TaskEvent{Name: "Math", StartTime: at(1, 9, 0), EndTime: at(1, 10, 0)}
+	art := &scheduler.TaskEvent{Name: "Art", StartTime: at(1, 13, 0), EndTime: at(1, 14, 0)}
+	yesterday := &scheduler.TaskEvent{Name: "Gym", StartTime: at(0, 18, 0), EndTime: at(0, 19, 0)}
+	tomorrow := &scheduler.TaskEvent{Name: "Math", StartTime: at(2, 9, 0), EndTime: at(2, 10, 0)}
+	texts := Options{
+		NoTaskText:    "Free",
+		NoTaskBefore:  "{next_name} in {next_in}",
+		NoTaskBetween: "Break until {next_name}",
+		NoTaskAfter:   "Done for today",
+	}
+	tests := []struct {
+		name           string
+		now            time.Time
+		previous, next *scheduler.TaskEvent
+		opts           Options
+		want           string
+	}{
+		{name: "before", now: at(1, 8, 15), previous: yesterday, next: math, opts: texts, want: "Math in 45m"},
+		{name: "between", now: at(1, 11, 0), previous: math, next: art, opts: texts, want: "Break until Art"},
+		{name: "after", now: at(1, 15, 0), previous: art, next: tomorrow, opts: texts, want: "Done for today"},
+		{name: "no_tasks_today", now: at(1, 12, 0), previous: yesterday, next: tomorrow, opts: texts, want: "Free"},
+		{name: "unset_falls_back", now: at(1, 11, 0), previous: math, next: art, opts: Options{NoTaskText: "Free"}, want: "Free"},
+		{name: "default", now: at(1, 11, 0), previous: math, next: art, want: "No task currently."},
+		{name: "no_next", now: at(1, 15, 0), opts: Options{NoTaskText: "Next: {next_name}"}, want: "Next: "},
+		{name: "off_day_placeholders", now: at(1, 12, 0), next: tomorrow, opts: Options{OffDay: true, OffDayText: "Off, {next_name} in {next_in}"}, want: "Off, Math in 21h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Now = tt.now
+			if got := tt.opts.idleText(tt.previous, tt.next); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestShiftLabel(t *testing.T) {
+	de, _ := i18n.Lookup("de")
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{name: "unshifted"},
+		{name: "ahead", opts: Options{Shift: 5 * time.Minute}, want: " (in 5m)"},
+		{name: "back", opts: Options{Shift: -10 * time.Minute}, want: " (10m ago)"},
+		{name: "german", opts: Options{Shift: -10 * time.Minute, Locale: de}, want: " (vor 10m)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.shiftLabel(); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
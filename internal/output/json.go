@@ -0,0 +1,309 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// SchemaVersion is bumped whenever the JSON output changes incompatibly.
+// The format is pinned by schema.json.
+const SchemaVersion = 2
+
+// JSONTask is the JSON representation of a single task.
+type JSONTask struct {
+	Name            string   `json:"name"`
+	RawName         string   `json:"raw_name"` // name as written in the config, before aliases
+	Start           string   `json:"start"`
+	End             string   `json:"end"`
+	StartUnix       int64    `json:"start_unix"`
+	EndUnix         int64    `json:"end_unix"`
+	DurationSeconds int64    `json:"duration_seconds"`
+	Color           string   `json:"color,omitempty"`
+	Icon            string   `json:"icon,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	URL             string   `json:"url,omitempty"`
+	Location        string   `json:"location,omitempty"`
+	Source          string   `json:"source,omitempty"` // where the task was defined, e.g. "config.toml:12 [[day]]"
+	Status          string   `json:"status,omitempty"` // "done" or "skipped" if recorded
+	// ConflictsWith lists the base tasks a temporary task overlaps, e.g.
+	// "Math 13:30-14:20".
+	ConflictsWith []string `json:"conflicts_with,omitempty"`
+	// Pomodoro is the running phase; set only on the current task.
+	Pomodoro *JSONPomodoro `json:"pomodoro,omitempty"`
+	// Segments are the tasks merge_adjacent merged into this one.
+	Segments []JSONSegment `json:"segments,omitempty"`
+}
+
+// JSONSegment is the span of one of the tasks merged into a JSONTask.
+type JSONSegment struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// JSONPomodoro is the JSON representation of a pomodoro phase.
+type JSONPomodoro struct {
+	Phase            string `json:"phase"` // "focus" or "break"
+	Index            int    `json:"index"`
+	Total            int    `json:"total"`
+	End              string `json:"end"`
+	RemainingSeconds int64  `json:"remaining_seconds"`
+}
+
+// ExtendedTaskEvent is a task in a day listing.
+type ExtendedTaskEvent struct {
+	JSONTask
+	IsCurrent  bool `json:"is_current"`
+	IsPast     bool `json:"is_past"`
+	IsUpcoming bool `json:"is_upcoming"`
+}
+
+// JSONDay is the JSON representation of a single date's schedule.
+type JSONDay struct {
+	Date            string `json:"date"`
+	DayID           *int   `json:"day_id"` // null on off days
+	DayName         string `json:"day_name"`
+	IsOff           bool   `json:"is_off"`
+	OverrideApplied bool   `json:"override_applied"`
+	Note            string `json:"note,omitempty"` // note of the applied override
+	// NotificationsMuted is set when the override mutes notifications.
+	NotificationsMuted bool    `json:"notifications_muted"`
+	FirstStart         *string `json:"first_start"` // null without tasks
+	LastEnd            *string `json:"last_end"`
+	// ScheduledMinutes counts overlapping tasks once, within day_window if set.
+	ScheduledMinutes int `json:"scheduled_minutes"`
+	// Tasks that haven't ended at generated_at and the minutes they still
+	// cover, counting only the rest of a task in progress
+	RemainingToday        int                 `json:"remaining_today"`
+	RemainingMinutesToday int                 `json:"remaining_minutes_today"`
+	Utilization           *float64            `json:"utilization"` // share of day_window, null without one
+	Tasks                 []ExtendedTaskEvent `json:"tasks"`
+}
+
+// JSONOutput is the document printed in JSON mode.
+type JSONOutput struct {
+	SchemaVersion int       `json:"schema_version"`
+	GeneratedAt   string    `json:"generated_at"`
+	Previous      *JSONTask `json:"previous"`
+	Current       *JSONTask `json:"current"`
+	Next          *JSONTask `json:"next"`
+	IsOff         bool      `json:"is_off"` // today is an off day
+	Day           *JSONDay  `json:"day,omitempty"`
+}
+
+// Day bundles a date's resolved cycle day, its tasks and how much of it is scheduled.
+type Day struct {
+	Info  scheduler.DayInfo
+	Name  string
+	Tasks []scheduler.TaskEvent
+	Usage scheduler.Usage
+}
+
+// LoadDay collects the schedule for the given date.
+func LoadDay(sched *scheduler.Scheduler, date time.Time) (*Day, error) {
+	info, err := sched.GetDayInfo(date)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := sched.GetTasksForDate(date)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := sched.GetUsage(date)
+	if err != nil {
+		return nil, err
+	}
+	return &Day{Info: info, Name: sched.DayName(info.DayID), Tasks: tasks, Usage: usage}, nil
+}
+
+// NewJSONTask converts a task to its JSON representation. It returns nil for a nil task.
+func NewJSONTask(t *scheduler.TaskEvent) *JSONTask {
+	if t == nil {
+		return nil
+	}
+	task := &JSONTask{
+		Name:            t.Name,
+		RawName:         t.RawName,
+		Start:           t.StartTime.Format(time.RFC3339),
+		End:             t.EndTime.Format(time.RFC3339),
+		StartUnix:       t.StartTime.Unix(),
+		EndUnix:         t.EndTime.Unix(),
+		DurationSeconds: int64(t.EndTime.Sub(t.StartTime).Seconds()),
+		Color:           t.Color,
+		Icon:            t.Icon,
+		Tags:            t.Tags,
+		URL:             t.URL,
+		Location:        t.Location,
+		Source:          t.Source,
+	}
+	for _, seg := range t.Segments {
+		task.Segments = append(task.Segments, JSONSegment{Start: seg.StartTime.Format(time.RFC3339), End: seg.EndTime.Format(time.RFC3339)})
+	}
+	return task
+}
+
+// NewJSONOutput assembles the JSON document. day is included only when non-nil.
+func NewJSONOutput(previous *scheduler.TaskEvent, current *scheduler.TaskEvent, next *scheduler.TaskEvent, day *Day, now time.Time) JSONOutput {
+	out := JSONOutput{
+		SchemaVersion: SchemaVersion,
+		GeneratedAt:   now.Format(time.RFC3339),
+		Previous:      NewJSONTask(previous),
+		Current:       NewJSONTask(current),
+		Next:          NewJSONTask(next),
+	}
+	if current != nil {
+		if p := current.PomodoroPhase(now); p != nil {
+			out.Current.Pomodoro = &JSONPomodoro{
+				Phase:            p.Kind,
+				Index:            p.Index,
+				Total:            p.Total,
+				End:              p.End.Format(time.RFC3339),
+				RemainingSeconds: int64(p.End.Sub(now).Seconds()),
+			}
+		}
+	}
+	if day != nil {
+		jsonDay := NewJSONDay(*day, current, now)
+		out.Day = &jsonDay
+	}
+	return out
+}
+
+// NewJSONDay converts a day to its JSON representation, classifying each task
+// relative to current and now.
+func NewJSONDay(day Day, current *scheduler.TaskEvent, now time.Time) JSONDay {
+	out := JSONDay{
+		Date:               day.Info.Date.Format("2006-01-02"),
+		DayName:            day.Name,
+		IsOff:              day.Info.IsOff,
+		OverrideApplied:    day.Info.Overridden,
+		Note:               day.Info.Note,
+		NotificationsMuted: day.Info.Muted,
+		ScheduledMinutes:   int(day.Usage.Scheduled.Minutes()),
+		Tasks:              ExtendTasks(day.Tasks, current, now),
+	}
+	remaining := scheduler.RemainingTasks(day.Tasks, now)
+	out.RemainingToday, out.RemainingMinutesToday = remaining.Tasks, int(remaining.Time.Minutes())
+	if !day.Info.IsOff {
+		id := day.Info.DayID
+		out.DayID = &id
+	}
+	if day.Usage.Window > 0 {
+		u := math.Round(day.Usage.Utilization()*1000) / 1000
+		out.Utilization = &u
+	}
+	if first, last := scheduler.DayBounds(day.Tasks); first != nil {
+		start, end := first.StartTime.Format(time.RFC3339), last.EndTime.Format(time.RFC3339)
+		out.FirstStart, out.LastEnd = &start, &end
+	}
+	if out.Tasks == nil {
+		out.Tasks = []ExtendedTaskEvent{}
+	}
+	return out
+}
+
+// ExtendTasks annotates dayTasks with whether each one is current, past or upcoming.
+func ExtendTasks(dayTasks []scheduler.TaskEvent, current *scheduler.TaskEvent, now time.Time) []ExtendedTaskEvent {
+	var extendedTasks []ExtendedTaskEvent
+	if len(dayTasks) > 0 {
+		extendedTasks = make([]ExtendedTaskEvent, len(dayTasks))
+		for i, t := range dayTasks {
+			isCurrent := false
+			if current != nil {
+				// Compare exact times and name to identify the current task
+				if t.Name == current.Name && t.StartTime.Equal(current.StartTime) && t.EndTime.Equal(current.EndTime) {
+					isCurrent = true
+				}
+			}
+			extendedTasks[i] = ExtendedTaskEvent{
+				JSONTask:   *NewJSONTask(&t),
+				IsCurrent:  isCurrent,
+				IsPast:     !t.EndTime.After(now),
+				IsUpcoming: t.StartTime.After(now),
+			}
+		}
+	}
+	return extendedTasks
+}
+
+// renderJSON renders the JSON document, or the values of Options.Fields.
+func renderJSON(ctx DayContext) (string, error) {
+	var b strings.Builder
+	err := writeJSON(&b, ctx.Previous, ctx.Current, ctx.Next, ctx.Day, ctx.Options)
+	return b.String(), err
+}
+
+// jsonBuffers holds the buffers writeJSON encodes into, so watch mode,
+// which prints on every change, doesn't grow a new one each time.
+var jsonBuffers = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// writeJSON writes the JSON document to w in a single write.
+func writeJSON(w io.Writer, previous *scheduler.TaskEvent, current *scheduler.TaskEvent, next *scheduler.TaskEvent, day *Day, opts Options) error {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	out := newJSONDocument(previous, current, next, day, opts, now)
+	if len(opts.Fields) > 0 {
+		return writeFields(w, out, opts.Fields, opts.FieldDefault)
+	}
+	var doc any = out
+	if opts.Flat {
+		doc = flatten(out)
+	}
+	buf := jsonBuffers.Get().(*bytes.Buffer)
+	defer jsonBuffers.Put(buf)
+	buf.Reset()
+	enc := json.NewEncoder(buf)
+	if !opts.Compact {
+		enc.SetIndent("", "  ")
+	}
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// newJSONDocument assembles the JSON document with the off-day flag, task
+// statuses and conflicts of opts.
+func newJSONDocument(previous, current, next *scheduler.TaskEvent, day *Day, opts Options, now time.Time) JSONOutput {
+	out := NewJSONOutput(previous, current, next, day, now)
+	out.IsOff = opts.OffDay
+	if opts.Status != nil || opts.Conflicts != nil {
+		annotate(&out, previous, current, next, day, opts)
+	}
+	return out
+}
+
+// annotate fills in the recorded status and the conflicts of every task in
+// out.
+func annotate(out *JSONOutput, previous, current, next *scheduler.TaskEvent, day *Day, opts Options) {
+	set := func(j *JSONTask, t scheduler.TaskEvent) {
+		if opts.Status != nil {
+			j.Status = opts.Status(t)
+		}
+		if opts.Conflicts != nil {
+			j.ConflictsWith = opts.Conflicts(t)
+		}
+	}
+	for _, p := range []struct {
+		task *scheduler.TaskEvent
+		json *JSONTask
+	}{{previous, out.Previous}, {current, out.Current}, {next, out.Next}} {
+		if p.task != nil {
+			set(p.json, *p.task)
+		}
+	}
+	if day != nil && out.Day != nil {
+		for i, t := range day.Tasks {
+			set(&out.Day.Tasks[i].JSONTask, t)
+		}
+	}
+}
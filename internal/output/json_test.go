@@ -0,0 +1,131 @@
+package output
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func TestNewJSONDay(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return date.Add(time.Duration(h) * time.Hour) }
+	tasks := []scheduler.TaskEvent{
+		{Name: "A", StartTime: at(8), EndTime: at(9)},
+		{Name: "B", StartTime: at(9), EndTime: at(10)},
+		{Name: "C", StartTime: at(11), EndTime: at(12)},
+	}
+	now := at(9).Add(30 * time.Minute)
+
+	day := NewJSONDay(Day{
+		Info:  scheduler.DayInfo{Date: date, DayID: 1},
+		Name:  "Monday",
+		Tasks: tasks,
+	}, &tasks[1], now)
+
+	want := []struct{ current, past, upcoming bool }{
+		{past: true},
+		{current: true},
+		{upcoming: true},
+	}
+	for i, w := range want {
+		got := day.Tasks[i]
+		if got.IsCurrent != w.current || got.IsPast != w.past || got.IsUpcoming != w.upcoming {
+			t.Errorf("Task %s: got current=%v past=%v upcoming=%v", got.Name, got.IsCurrent, got.IsPast, got.IsUpcoming)
+		}
+	}
+	if day.DayID == nil || *day.DayID != 1 {
+		t.Errorf("Expected day_id 1, got %v", day.DayID)
+	}
+	if day.FirstStart == nil || *day.FirstStart != "2024-01-01T08:00:00Z" || day.LastEnd == nil || *day.LastEnd != "2024-01-01T12:00:00Z" {
+		t.Errorf("Expected the day to span 08:00-12:00, got %v-%v", day.FirstStart, day.LastEnd)
+	}
+	// The rest of B and all of C
+	if day.RemainingToday != 2 || day.RemainingMinutesToday != 90 {
+		t.Errorf("Expected 2 tasks (90 minutes) remaining, got %d (%d)", day.RemainingToday, day.RemainingMinutesToday)
+	}
+}
+
+func TestNewJSONDay_OffDay(t *testing.T) {
+	day := NewJSONDay(Day{
+		Info: scheduler.DayInfo{Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), DayID: -1, IsOff: true, Overridden: true, Note: "Conference"},
+	}, nil, time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC))
+
+	b, err := json.Marshal(day)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := `{"date":"2024-01-02","day_id":null,"day_name":"","is_off":true,"override_applied":true,"note":"Conference","notifications_muted":false,"first_start":null,"last_end":null,"scheduled_minutes":0,"remaining_today":0,"remaining_minutes_today":0,"utilization":null,"tasks":[]}`
+	if string(b) != want {
+		t.Errorf("Unexpected JSON:\n got: %s\nwant: %s", b, want)
+	}
+}
+
+func TestNewJSONOutput_Pomodoro(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	current := &scheduler.TaskEvent{Name: "Deep work", StartTime: start, EndTime: start.Add(3 * time.Hour), Pomodoro: "25m/5m"}
+	next := &scheduler.TaskEvent{Name: "Lunch", StartTime: start.Add(3 * time.Hour), EndTime: start.Add(4 * time.Hour), Pomodoro: "25m/5m"}
+
+	out := NewJSONOutput(nil, current, next, nil, start.Add(71*time.Minute))
+
+	want := JSONPomodoro{Phase: "focus", Index: 3, Total: 6, End: "2024-01-01T10:25:00Z", RemainingSeconds: 14 * 60}
+	if out.Current.Pomodoro == nil || *out.Current.Pomodoro != want {
+		t.Errorf("Expected %+v, got %+v", want, out.Current.Pomodoro)
+	}
+	if out.Next.Pomodoro != nil {
+		t.Errorf("Expected no phase on the next task, got %+v", out.Next.Pomodoro)
+	}
+}
+
+func TestNewJSONDocument_Conflicts(t *testing.T) {
+	start := time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC)
+	current := &scheduler.TaskEvent{Name: "Dentist", StartTime: start, EndTime: start.Add(time.Hour)}
+	next := &scheduler.TaskEvent{Name: "Gym", StartTime: start.Add(2 * time.Hour), EndTime: start.Add(3 * time.Hour)}
+	opts := Options{Conflicts: func(t scheduler.TaskEvent) []string {
+		if t.Name == "Dentist" {
+			return []string{"Math 13:30-14:20"}
+		}
+		return nil
+	}}
+
+	b, err := json.Marshal(newJSONDocument(nil, current, next, nil, opts, start))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var out struct {
+		Current map[string]any `json:"current"`
+		Next    map[string]any `json:"next"`
+	}
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if got, ok := out.Current["conflicts_with"].([]any); !ok || len(got) != 1 || got[0] != "Math 13:30-14:20" {
+		t.Errorf("Expected the current task to conflict with Math, got %v", out.Current["conflicts_with"])
+	}
+	if _, ok := out.Next["conflicts_with"]; ok {
+		t.Errorf("Expected no conflicts_with on the next task, got %v", out.Next["conflicts_with"])
+	}
+}
+
+func TestNewJSONTask_Segments(t *testing.T) {
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	merged := &scheduler.TaskEvent{Name: "Math", StartTime: start, EndTime: start.Add(100 * time.Minute), Segments: []scheduler.Segment{
+		{StartTime: start, EndTime: start.Add(50 * time.Minute)},
+		{StartTime: start.Add(50 * time.Minute), EndTime: start.Add(100 * time.Minute)},
+	}}
+
+	want := []JSONSegment{{Start: "2024-01-01T09:00:00Z", End: "2024-01-01T09:50:00Z"}, {Start: "2024-01-01T09:50:00Z", End: "2024-01-01T10:40:00Z"}}
+	if got := NewJSONTask(merged).Segments; !slices.Equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	b, err := json.Marshal(NewJSONTask(&scheduler.TaskEvent{Name: "Art", StartTime: start, EndTime: start.Add(time.Hour)}))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(string(b), "segments") {
+		t.Errorf("Expected no segments on an unmerged task, got %s", b)
+	}
+}
@@ -0,0 +1,81 @@
+package output
+
+import (
+	"io"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// WritePrompt writes the shell prompt segment of PromptLine to w. It never
+// writes a newline, and writes nothing at all when the segment is empty, so
+// prompts can test for empty output.
+func WritePrompt(w io.Writer, current, next *scheduler.TaskEvent, opts Options) error {
+	line := PromptLine(current, next, opts)
+	if line == "" {
+		return nil
+	}
+	_, err := io.WriteString(w, line)
+	return err
+}
+
+// PromptLine renders a shell prompt segment such as "Math 12m" for the
+// current task, or "→ Art in 1h05m" for the next one when nothing is in
+// progress, after opts.PromptIcon if set. It is empty on off days, when
+// nothing more is scheduled today, and while free if opts.HideWhenFree is
+// set. ANSI colors are only used with opts.Color.
+func PromptLine(current, next *scheduler.TaskEvent, opts Options) string {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if next != nil && !sameDay(next.StartTime, now) {
+		next = nil
+	}
+	if opts.OffDay || (current == nil && (next == nil || opts.HideWhenFree)) {
+		return ""
+	}
+	maxWidth := opts.MaxWidth
+	if maxWidth <= 0 {
+		maxWidth = DefaultMaxWidth
+	}
+
+	var line string
+	if current != nil {
+		name := truncate(current.Name, maxWidth)
+		if opts.Color {
+			name = renderer.NewStyle().Foreground(lipgloss.Color(taskColor(current, opts))).Render(name)
+		}
+		left := opts.Durations.Left(current.EndTime.Sub(now))
+		if opts.Color {
+			left = colorizeTime(left, opts)
+		}
+		line = promptName(current, name, opts) + " " + left
+	} else {
+		name := truncate(next.Name, maxWidth)
+		line = "→ " + opts.Locale.T("next_in", promptName(next, name, opts), opts.Durations.Left(next.StartTime.Sub(now)))
+		if opts.Color {
+			line = renderer.NewStyle().Faint(true).Render(line)
+		}
+	}
+	if opts.PromptIcon != "" {
+		line = opts.PromptIcon + " " + line
+	}
+	return line
+}
+
+// promptName returns name, after the icon of t if opts.Icons is set.
+func promptName(t *scheduler.TaskEvent, name string, opts Options) string {
+	if opts.Icons && t.Icon != "" {
+		return t.Icon + " " + name
+	}
+	return name
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
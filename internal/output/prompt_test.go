@@ -0,0 +1,78 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func TestPromptLine(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 48, 0, 0, time.UTC)
+	current := &scheduler.TaskEvent{
+		Name:      "Math",
+		Icon:      "📐",
+		StartTime: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	next := &scheduler.TaskEvent{
+		Name:      "Theoretical Physics",
+		StartTime: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	tomorrow := &scheduler.TaskEvent{
+		Name:      "Art",
+		StartTime: time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name    string
+		current *scheduler.TaskEvent
+		next    *scheduler.TaskEvent
+		opts    Options
+		want    string
+	}{
+		{name: "current", current: current, next: next, want: "Math 12m"},
+		{name: "icons", current: current, opts: Options{PromptIcon: "⏱", Icons: true}, want: "⏱ 📐 Math 12m"},
+		{name: "precise", current: current, opts: Options{Durations: config.Durations{Precise: true}}, want: "Math 12m"},
+		{name: "next", next: next, opts: Options{MaxWidth: 11}, want: "→ Theoretica… in 1h12m"},
+		{name: "next_german", next: next, opts: Options{Locale: german, MaxWidth: 7}, want: "→ Theore… in 1h12m"},
+		// The empty-output contract
+		{name: "hide_when_free", next: next, opts: Options{HideWhenFree: true}},
+		{name: "off_day", current: current, next: next, opts: Options{OffDay: true}},
+		{name: "nothing_today", next: tomorrow},
+		{name: "nothing", opts: Options{PromptIcon: "⏱"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Now = now
+			var buf bytes.Buffer
+			if err := WritePrompt(&buf, tt.current, tt.next, tt.opts); err != nil {
+				t.Fatalf("WritePrompt failed: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestPromptLine_Color(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 48, 0, 0, time.UTC)
+	current := &scheduler.TaskEvent{
+		Name:      "Math",
+		StartTime: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	if got := PromptLine(current, nil, Options{Now: now}); strings.Contains(got, "\x1b") {
+		t.Errorf("Expected no ANSI codes without Color, got %q", got)
+	}
+	got := PromptLine(current, nil, Options{Now: now, Color: true})
+	if !strings.Contains(got, "\x1b[") || strings.HasSuffix(got, "\n") {
+		t.Errorf("Expected ANSI codes and no newline with Color, got %q", got)
+	}
+}
@@ -0,0 +1,114 @@
+package output
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// DayContext is what a renderer draws from: the tasks around Options.Now
+// and, for formats that list the day, the day itself.
+type DayContext struct {
+	// Current is the task to show, which the CLI's --next and --previous
+	// may replace; Previous and Next are the tasks around it.
+	Previous, Current, Next *scheduler.TaskEvent
+	// Day is the day of Options.Now. It is loaded for formats registered
+	// with Day set, and for the others with --all; nil otherwise.
+	Day     *Day
+	Options Options
+}
+
+// Renderer turns a DayContext into the text of one output format.
+type Renderer interface {
+	Render(ctx DayContext) (string, error)
+}
+
+// RenderFunc adapts a function to a Renderer.
+type RenderFunc func(ctx DayContext) (string, error)
+
+// Render calls f(ctx).
+func (f RenderFunc) Render(ctx DayContext) (string, error) {
+	return f(ctx)
+}
+
+// Format is an output format selectable with --output.
+type Format struct {
+	Name        string
+	Description string // one line, for 'sked --output list'
+	Renderer    Renderer
+	// Day marks formats that render the whole day, so DayContext.Day is
+	// always set, and which print once rather than in watch mode.
+	Day bool
+}
+
+// FormatList is the --output value that lists the formats instead of
+// rendering one.
+const FormatList = "list"
+
+var (
+	formatsMu sync.RWMutex
+	formats   = make(map[string]Format)
+)
+
+func init() {
+	Register(Format{Name: FormatNatural, Description: "the current task as a sentence, or the day's tasks with --all", Renderer: RenderFunc(renderNatural)})
+	Register(Format{Name: FormatJSON, Description: "the previous, current and next tasks as a versioned JSON document", Renderer: RenderFunc(renderJSON)})
+	Register(Format{Name: FormatTmux, Description: "a tmux status-line segment for the current or next task", Renderer: RenderFunc(renderTmux)})
+	Register(Format{Name: FormatMarkdown, Description: "the day's agenda as a Markdown checklist", Renderer: RenderFunc(renderAgenda), Day: true})
+	Register(Format{Name: FormatOrg, Description: "the day's agenda as org-mode entries", Renderer: RenderFunc(renderAgenda), Day: true})
+}
+
+// Register adds f to the formats Render and the CLI's --output accept. Like
+// the built-in formats, it is meant to be called from an init function: it
+// panics if f has no name or renderer, or if the name is taken.
+func Register(f Format) {
+	if f.Name == "" || f.Name == FormatList || f.Renderer == nil {
+		panic(fmt.Sprintf("output: invalid format %q", f.Name))
+	}
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	if _, ok := formats[f.Name]; ok {
+		panic(fmt.Sprintf("output: format %q registered twice", f.Name))
+	}
+	formats[f.Name] = f
+}
+
+// LookupFormat returns the format registered under name.
+func LookupFormat(name string) (Format, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	f, ok := formats[name]
+	return f, ok
+}
+
+// Formats returns the registered formats sorted by name.
+func Formats() []Format {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	list := make([]Format, 0, len(formats))
+	for _, f := range formats {
+		list = append(list, f)
+	}
+	slices.SortFunc(list, func(a, b Format) int { return strings.Compare(a.Name, b.Name) })
+	return list
+}
+
+// Render renders ctx in the format named by ctx.Options.Format, natural if
+// it is empty.
+func Render(ctx DayContext) (string, error) {
+	name := ctx.Options.Format
+	if name == "" {
+		name = FormatNatural
+	}
+	f, ok := LookupFormat(name)
+	if !ok {
+		return "", fmt.Errorf("unknown output format '%s'", name)
+	}
+	if f.Day && ctx.Day == nil {
+		return "", fmt.Errorf("%s output needs the day's tasks", name)
+	}
+	return f.Renderer.Render(ctx)
+}
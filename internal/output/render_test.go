@@ -0,0 +1,180 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// fixtureScheduler is the schedule every golden file is rendered from:
+// a Monday with an empty slot, an icon, tags, a url and a location, and an
+// off Tuesday.
+func fixtureScheduler() *scheduler.Scheduler {
+	day := config.Origin{File: "fixture.toml", Line: 3, Section: "[[day]]"}
+	tuesday := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	return scheduler.New(&config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Math", Start: "09:00", End: "09:50", Icon: "📐", Tags: []string{"school"}, Source: day},
+			{Name: "/", Start: "09:50", End: "10:00", Source: day},
+			{Name: "History", Start: "10:00", End: "10:50", Location: "Room 4", Source: day},
+			{Name: "Art", Start: "13:00", End: "14:30", URL: "https://example.com/art", Color: "magenta", Source: day},
+		}}},
+		Overrides: []config.Override{{DateStr: "2024-01-02", IsOff: true, Note: "PTO", Date: tuesday, EndDate: tuesday}},
+	})
+}
+
+// fixtureContext builds the DayContext of now the way the CLI does with
+// --time and --icons, with the day if all is set.
+func fixtureContext(t *testing.T, format string, now time.Time, all bool) DayContext {
+	t.Helper()
+	sched := fixtureScheduler()
+	dc, err := sched.GetDayContext(now)
+	if err != nil {
+		t.Fatalf("GetDayContext() returned error: %v", err)
+	}
+	info, err := sched.GetDayInfo(now)
+	if err != nil {
+		t.Fatalf("GetDayInfo() returned error: %v", err)
+	}
+	ctx := DayContext{
+		Previous: dc.Previous,
+		Current:  dc.Current,
+		Next:     dc.Next,
+		Options: Options{
+			Format:   format,
+			Now:      now,
+			OffDay:   info.IsOff,
+			DayNote:  info.Note,
+			ShowTime: true,
+			Icons:    true,
+		},
+	}
+	if all {
+		if ctx.Day, err = LoadDay(sched, now); err != nil {
+			t.Fatalf("LoadDay() returned error: %v", err)
+		}
+	}
+	return ctx
+}
+
+func TestRender_Golden(t *testing.T) {
+	at := func(d, h, m int) time.Time { return time.Date(2024, 1, d, h, m, 0, 0, time.UTC) }
+	scenarios := []struct {
+		name string
+		now  time.Time
+	}{
+		{"busy", at(1, 10, 15)},
+		{"idle", at(1, 11, 30)},
+		{"off", at(2, 10, 0)},
+	}
+	formats := []struct {
+		name string
+		all  bool
+	}{
+		{FormatNatural, false},
+		{FormatNatural, true},
+		{FormatJSON, false},
+		{FormatJSON, true},
+		{FormatTmux, false},
+		{FormatMarkdown, true},
+		{FormatOrg, true},
+	}
+	for _, f := range formats {
+		for _, sc := range scenarios {
+			name := f.name
+			if f.all && f.name != FormatMarkdown && f.name != FormatOrg {
+				name += "_all"
+			}
+			name += "_" + sc.name
+			t.Run(name, func(t *testing.T) {
+				got, err := Render(fixtureContext(t, f.name, sc.now, f.all))
+				if err != nil {
+					t.Fatalf("Render() returned error: %v", err)
+				}
+
+				path := filepath.Join("testdata", name+".golden")
+				if *update {
+					if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+						t.Fatalf("Failed to write %s: %v", path, err)
+					}
+				}
+				want, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatalf("Failed to read %s (run with -update to create it): %v", path, err)
+				}
+				if got != string(want) {
+					t.Errorf("Output no longer matches %s. If the change is intentional, run 'go test ./internal/output -run TestRender_Golden -update'.\n%s", path, got)
+				}
+			})
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	// A third format as an embedding program would add it: one CSV line
+	// per task of the day.
+	Register(Format{
+		Name:        "test-csv",
+		Description: "the day's tasks as CSV",
+		Day:         true,
+		Renderer: RenderFunc(func(ctx DayContext) (string, error) {
+			var b strings.Builder
+			for _, task := range ctx.Day.Tasks {
+				if task.RawName != "/" {
+					b.WriteString(task.StartTime.Format("15:04") + "," + task.EndTime.Format("15:04") + "," + task.Name + "\n")
+				}
+			}
+			return b.String(), nil
+		}),
+	})
+
+	f, ok := LookupFormat("test-csv")
+	if !ok || !f.Day {
+		t.Fatalf("Expected the registered format, got %+v", f)
+	}
+	var names []string
+	for _, f := range Formats() {
+		names = append(names, f.Name)
+	}
+	if want := []string{"json", "markdown", "natural", "org", "test-csv", "tmux"}; !slices.Equal(names, want) {
+		t.Errorf("Expected formats %v, got %v", want, names)
+	}
+
+	now := time.Date(2024, 1, 1, 10, 15, 0, 0, time.UTC)
+	got, err := Render(fixtureContext(t, "test-csv", now, true))
+	if err != nil {
+		t.Fatalf("Render() returned error: %v", err)
+	}
+	if want := "09:00,09:50,Math\n10:00,10:50,History\n13:00,14:30,Art\n"; got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+	if _, err := Render(fixtureContext(t, "test-csv", now, false)); err == nil {
+		t.Error("Expected an error for a day format without the day")
+	}
+	if _, err := Render(fixtureContext(t, "nope", now, false)); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+
+	for _, bad := range []Format{
+		{Name: "test-csv", Renderer: f.Renderer},
+		{Name: FormatList, Renderer: f.Renderer},
+		{Name: "test-empty"},
+		{Renderer: f.Renderer},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected Register(%q) to panic", bad.Name)
+				}
+			}()
+			Register(bad)
+		}()
+	}
+}
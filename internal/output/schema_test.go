@@ -0,0 +1,87 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "regenerate schema.json and the golden files in testdata")
+
+// jsonSchema builds a JSON Schema for t from its json struct tags.
+func jsonSchema(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := jsonSchema(t.Elem())
+		s["type"] = []any{s["type"], "null"}
+		return s
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": jsonSchema(t.Elem())}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Struct:
+		props := map[string]any{}
+		required := []string{}
+		collectFields(t, props, &required)
+		return map[string]any{
+			"type":                 "object",
+			"properties":           props,
+			"required":             required,
+			"additionalProperties": false,
+		}
+	}
+	panic("unsupported kind " + t.Kind().String())
+}
+
+func collectFields(t reflect.Type, props map[string]any, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			collectFields(f.Type, props, required)
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		props[name] = jsonSchema(f.Type)
+		if opts != "omitempty" {
+			*required = append(*required, name)
+		}
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	schema := jsonSchema(reflect.TypeOf(JSONOutput{}))
+	schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	schema["title"] = "sked JSON output"
+	props := schema["properties"].(map[string]any)
+	props["schema_version"].(map[string]any)["const"] = SchemaVersion
+
+	got, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal schema: %v", err)
+	}
+	got = append(got, '\n')
+
+	if *update {
+		if err := os.WriteFile("schema.json", got, 0o644); err != nil {
+			t.Fatalf("Failed to write schema.json: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile("schema.json")
+	if err != nil {
+		t.Fatalf("Failed to read schema.json (run with -update to create it): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("JSON output no longer matches schema.json. If the change is intentional, bump SchemaVersion when needed and run 'go test ./internal/output -update'.\n%s", got)
+	}
+}
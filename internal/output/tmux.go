@@ -0,0 +1,97 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// DefaultMaxWidth is the default task name width for tmux output.
+const DefaultMaxWidth = 24
+
+// renderTmux renders a single status-line segment using tmux style codes.
+// It has no trailing newline, as tmux shows it verbatim.
+func renderTmux(ctx DayContext) (string, error) {
+	return TmuxLine(ctx.Current, ctx.Next, ctx.Options), nil
+}
+
+// TmuxLine renders the tmux status-line segment for the current task, or the
+// next task in a compact form when nothing is in progress.
+func TmuxLine(current *scheduler.TaskEvent, next *scheduler.TaskEvent, opts Options) string {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	maxWidth := opts.MaxWidth
+	if maxWidth <= 0 {
+		maxWidth = DefaultMaxWidth
+	}
+
+	if current != nil {
+		color := opts.Colors.Current
+		if current.Color != "" {
+			color = current.Color
+		}
+		if color == "" {
+			color = defaultCurrentColor
+		}
+		return fmt.Sprintf("#[fg=%s]%s#[default] %s",
+			tmuxColor(color), tmuxName(current, maxWidth, opts.Icons), opts.Durations.Left(current.EndTime.Sub(now)))
+	}
+
+	if next != nil {
+		color := "default"
+		if isSoon(next, now) {
+			color = opts.Colors.Soon
+			if color == "" {
+				color = defaultSoonColor
+			}
+			color = tmuxColor(color)
+		}
+		return fmt.Sprintf("#[fg=%s,dim]→ %s#[default]",
+			color, opts.Locale.T("next_in", tmuxName(next, maxWidth, opts.Icons), opts.Durations.Left(next.StartTime.Sub(now))))
+	}
+
+	text := opts.NoTaskText
+	if text == "" {
+		text = opts.Locale.T("no_task")
+	}
+	return tmuxEscape(text)
+}
+
+// tmuxName returns the escaped, truncated task name, after its icon if icons
+// is set. The icon doesn't count towards maxWidth.
+func tmuxName(t *scheduler.TaskEvent, maxWidth int, icons bool) string {
+	name := tmuxEscape(truncate(t.Name, maxWidth))
+	if icons && t.Icon != "" {
+		name = tmuxEscape(t.Icon) + " " + name
+	}
+	return name
+}
+
+// tmuxColor converts a config color ("2" or "#00ff00") to tmux syntax.
+func tmuxColor(c string) string {
+	if strings.HasPrefix(c, "#") {
+		return c
+	}
+	return "colour" + c
+}
+
+// tmuxEscape escapes '#' so task names can't inject tmux formats.
+func tmuxEscape(s string) string {
+	return strings.ReplaceAll(s, "#", "##")
+}
+
+// truncate shortens s to at most width runes, ending with an ellipsis when cut.
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string(r[:width])
+	}
+	return string(r[:width-1]) + "…"
+}
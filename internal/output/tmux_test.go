@@ -0,0 +1,91 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/i18n"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+var german, _ = i18n.Lookup("de")
+
+func TestTmuxLine(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 48, 0, 0, time.UTC)
+	current := &scheduler.TaskEvent{
+		Name:      "Math",
+		StartTime: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	long := &scheduler.TaskEvent{
+		Name:      "Advanced #1 Theoretical Physics Seminar",
+		StartTime: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	soon := &scheduler.TaskEvent{
+		Name:      "History",
+		StartTime: time.Date(2024, 1, 1, 9, 51, 0, 0, time.UTC),
+		EndTime:   time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name    string
+		current *scheduler.TaskEvent
+		next    *scheduler.TaskEvent
+		opts    Options
+		want    string
+	}{
+		{
+			name:    "current",
+			current: current,
+			want:    "#[fg=colour2]Math#[default] 12m",
+		},
+		{
+			name: "next_truncated_and_escaped",
+			next: long,
+			opts: Options{MaxWidth: 12},
+			want: "#[fg=default,dim]→ Advanced ##1… in 1h12m#[default]",
+		},
+		{
+			name: "next_soon",
+			next: soon,
+			want: "#[fg=colour3,dim]→ History in 3m#[default]",
+		},
+		{
+			name:    "icon",
+			current: &scheduler.TaskEvent{Name: "Math", Icon: "📚", EndTime: current.EndTime},
+			opts:    Options{Icons: true},
+			want:    "#[fg=colour2]📚 Math#[default] 12m",
+		},
+		{
+			name:    "icon_omitted",
+			current: &scheduler.TaskEvent{Name: "Math", Icon: "📚", EndTime: current.EndTime},
+			want:    "#[fg=colour2]Math#[default] 12m",
+		},
+		{
+			name: "free",
+			opts: Options{NoTaskText: "Free"},
+			want: "Free",
+		},
+		{
+			name: "free_translated",
+			opts: Options{Locale: german},
+			want: "Gerade keine Aufgabe.",
+		},
+		{
+			name: "next_translated",
+			next: soon,
+			opts: Options{Locale: german},
+			want: "#[fg=colour3,dim]→ History in 3m#[default]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Now = now
+			if got := TmuxLine(tt.current, tt.next, tt.opts); got != tt.want {
+				t.Errorf("TmuxLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
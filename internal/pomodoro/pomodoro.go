@@ -0,0 +1,77 @@
+// Package pomodoro splits a task into alternating focus and break phases.
+package pomodoro
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Kinds of phases.
+const (
+	Focus = "focus"
+	Break = "break"
+)
+
+// Spec is a focus/break rhythm such as "25m/5m".
+type Spec struct {
+	Focus time.Duration
+	Break time.Duration
+}
+
+// Parse parses a "<focus>/<break>" pair of durations, e.g. "25m/5m".
+func Parse(s string) (Spec, error) {
+	focus, brk, ok := strings.Cut(s, "/")
+	if !ok {
+		return Spec{}, fmt.Errorf("invalid pomodoro '%s' (expected focus/break, e.g. 25m/5m)", s)
+	}
+	var spec Spec
+	var err error
+	if spec.Focus, err = time.ParseDuration(strings.TrimSpace(focus)); err != nil || spec.Focus <= 0 {
+		return Spec{}, fmt.Errorf("invalid pomodoro focus duration '%s'", focus)
+	}
+	if spec.Break, err = time.ParseDuration(strings.TrimSpace(brk)); err != nil || spec.Break < 0 {
+		return Spec{}, fmt.Errorf("invalid pomodoro break duration '%s'", brk)
+	}
+	return spec, nil
+}
+
+// Phase is one focus or break interval of a task.
+type Phase struct {
+	Kind string
+	// Index counts focus intervals from 1; a break shares the index of the
+	// focus interval before it. Total is the number of focus intervals.
+	Index int
+	Total int
+	Start time.Time
+	End   time.Time
+}
+
+// At returns the phase of a task running from start to end that contains
+// now. Phases repeat from the task start and the last one is cut off at the
+// task end. ok is false when now is outside the task.
+func (s Spec) At(start, end, now time.Time) (p Phase, ok bool) {
+	if now.Before(start) || !now.Before(end) {
+		return Phase{}, false
+	}
+	cycle := s.Focus + s.Break
+	total := int(math.Ceil(float64(end.Sub(start)) / float64(cycle)))
+	n := int(now.Sub(start) / cycle)
+	cycleStart := start.Add(time.Duration(n) * cycle)
+
+	p = Phase{Kind: Focus, Index: n + 1, Total: total, Start: cycleStart, End: cycleStart.Add(s.Focus)}
+	if !now.Before(p.End) {
+		p.Kind, p.Start, p.End = Break, p.End, cycleStart.Add(cycle)
+	}
+	if p.End.After(end) {
+		p.End = end
+	}
+	return p, true
+}
+
+// Describe renders the phase as e.g. "focus 3/6, 14m left".
+func (p Phase) Describe(now time.Time) string {
+	left := int(math.Ceil(p.End.Sub(now).Minutes()))
+	return fmt.Sprintf("%s %d/%d, %dm left", p.Kind, p.Index, p.Total, left)
+}
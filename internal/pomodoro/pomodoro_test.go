@@ -0,0 +1,68 @@
+package pomodoro
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Spec
+		wantErr bool
+	}{
+		{in: "25m/5m", want: Spec{Focus: 25 * time.Minute, Break: 5 * time.Minute}},
+		{in: " 50m / 10m ", want: Spec{Focus: 50 * time.Minute, Break: 10 * time.Minute}},
+		{in: "45m/0s", want: Spec{Focus: 45 * time.Minute}},
+		{in: "25m", wantErr: true},
+		{in: "0s/5m", wantErr: true},
+		{in: "25m/five", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q): expected %+v, got %+v", tt.in, tt.want, got)
+		}
+	}
+}
+
+func TestAt(t *testing.T) {
+	spec := Spec{Focus: 25 * time.Minute, Break: 5 * time.Minute}
+	clock := func(hour, min int) time.Time { return time.Date(2024, 1, 1, hour, min, 0, 0, time.UTC) }
+	// A 2h40m block: five full cycles and a 10 minute focus cut off at the end
+	start, end := clock(9, 0), clock(11, 40)
+
+	tests := []struct {
+		now   time.Time
+		kind  string
+		index int
+		end   time.Time
+	}{
+		{clock(9, 0), Focus, 1, clock(9, 25)},
+		{clock(9, 25), Break, 1, clock(9, 30)},
+		{clock(10, 11), Focus, 3, clock(10, 25)},
+		{clock(11, 35), Focus, 6, clock(11, 40)},
+	}
+	for _, tt := range tests {
+		p, ok := spec.At(start, end, tt.now)
+		if !ok {
+			t.Fatalf("At(%s): expected a phase", tt.now.Format("15:04"))
+		}
+		if p.Kind != tt.kind || p.Index != tt.index || p.Total != 6 || !p.End.Equal(tt.end) {
+			t.Errorf("At(%s): expected %s %d/6 until %s, got %+v", tt.now.Format("15:04"), tt.kind, tt.index, tt.end.Format("15:04"), p)
+		}
+	}
+
+	if _, ok := spec.At(start, end, end); ok {
+		t.Errorf("Expected no phase at the task end")
+	}
+
+	p, _ := spec.At(start, end, clock(10, 11))
+	if got := p.Describe(clock(10, 11).Add(30 * time.Second)); got != "focus 3/6, 14m left" {
+		t.Errorf("Unexpected description %q", got)
+	}
+}
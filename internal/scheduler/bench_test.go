@@ -0,0 +1,135 @@
+package scheduler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+// benchConfig returns a week of 40 tasks a day, 08:00 to 21:20 in 20-minute
+// slots, with aliases, icons, tags and a vacation, as a busy config would have.
+func benchConfig() *config.Config {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Aliases:   map[string]string{"mtg-*": "Meeting", "Standup": "Daily standup"},
+		Icons:     map[string]string{"Meeting": "📅", "Lunch": "🍜", "study-*": "📚"},
+		Overrides: []config.Override{{DateStr: "2024-08-05", EndDateStr: "2024-08-16", IsOff: true}},
+	}
+	names := []string{"Standup", "mtg-%d", "study-%d", "Lunch", "Deep work %d"}
+	for day := range 7 {
+		d := config.Day{ID: day}
+		for i := range 40 {
+			start := 8*60 + i*20
+			d.Tasks = append(d.Tasks, config.Task{
+				Name:  fmt.Sprintf(names[i%len(names)], i),
+				Start: fmt.Sprintf("%02d:%02d", start/60, start%60),
+				End:   fmt.Sprintf("%02d:%02d", (start+20)/60, (start+20)%60),
+				Tags:  []string{"work"},
+			})
+		}
+		cfg.Days = append(cfg.Days, d)
+	}
+	if err := cfg.ProcessOverrides(); err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// benchNow is a Wednesday afternoon, between two tasks of the fixture.
+var benchNow = time.Date(2024, 3, 6, 14, 10, 0, 0, time.UTC)
+
+func BenchmarkGetCurrentTask(b *testing.B) {
+	sched := New(benchConfig())
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := sched.GetCurrentTask(benchNow); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetNextTask(b *testing.B) {
+	sched := New(benchConfig())
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := sched.GetNextTask(benchNow); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetTasksForDate(b *testing.B) {
+	sched := New(benchConfig())
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := sched.GetTasksForDate(benchNow); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// benchInstants returns n instants a minute apart from Monday morning of
+// the fixture's week, as a log spanning several days would have.
+func benchInstants(n int) []time.Time {
+	times := make([]time.Time, n)
+	start := time.Date(2024, 3, 4, 6, 0, 0, 0, time.UTC)
+	for i := range times {
+		times[i] = start.Add(time.Duration(i) * time.Minute)
+	}
+	return times
+}
+
+func BenchmarkGetTasksAt(b *testing.B) {
+	sched := New(benchConfig())
+	times := benchInstants(10000)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := sched.GetTasksAt(times); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGetCurrentTask_Repeated answers the same instants as
+// BenchmarkGetTasksAt one GetCurrentTask call at a time.
+func BenchmarkGetCurrentTask_Repeated(b *testing.B) {
+	sched := New(benchConfig())
+	times := benchInstants(10000)
+	b.ReportAllocs()
+	for b.Loop() {
+		for _, t := range times {
+			if _, err := sched.GetCurrentTask(t); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// TestQueryAllocs guards the benchmarks above against gross regressions:
+// each query allocates only what it returns.
+func TestQueryAllocs(t *testing.T) {
+	sched := New(benchConfig())
+	tests := []struct {
+		name  string
+		query func() error
+	}{
+		{name: "GetCurrentTask", query: func() error { _, err := sched.GetCurrentTask(benchNow); return err }},
+		{name: "GetNextTask", query: func() error { _, err := sched.GetNextTask(benchNow); return err }},
+		{name: "GetPreviousTask", query: func() error { _, err := sched.GetPreviousTask(benchNow); return err }},
+		{name: "GetTasksForDate", query: func() error { _, err := sched.GetTasksForDate(benchNow); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			allocs := testing.AllocsPerRun(100, func() { err = tt.query() })
+			if err != nil {
+				t.Fatalf("%s returned error: %v", tt.name, err)
+			}
+			if allocs > 2 {
+				t.Errorf("Expected at most 2 allocations, got %v", allocs)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// Conflict is a task of a temporary schedule that overlaps a task of the
+// schedule it stands in for, its base (config.Config.Base).
+type Conflict struct {
+	Tmp  TaskEvent
+	Base TaskEvent
+}
+
+// String describes the conflict, with where each task was defined if
+// known, e.g. "tmp 'Dentist 14:00-15:00' (tmp.csv:2:2) overlaps
+// 'Math 13:30-14:20' (config.toml:5 [[day]])".
+func (c Conflict) String() string {
+	return fmt.Sprintf("tmp %s overlaps %s", quoteSummary(c.Tmp), quoteSummary(c.Base))
+}
+
+// quoteSummary returns the quoted Summary of t followed by its source.
+func quoteSummary(t TaskEvent) string {
+	if t.Source == "" {
+		return "'" + t.Summary() + "'"
+	}
+	return "'" + t.Summary() + "' (" + t.Source + ")"
+}
+
+// Summary returns the name and time range of the task, e.g.
+// "Math 13:30-14:20".
+func (t TaskEvent) Summary() string {
+	return t.Name + " " + t.StartTime.Format("15:04") + "-" + t.EndTime.Format("15:04")
+}
+
+// FindConflicts pairs each task of tmp with the tasks of base it overlaps,
+// in the order of tmp, then base. A tmp task with exactly the time range of
+// a base task replaces that slot and conflicts with nothing else over it, so
+// such pairs are left out; so are empty slots.
+func FindConflicts(tmp, base []TaskEvent) []Conflict {
+	var conflicts []Conflict
+	for _, t := range tmp {
+		if t.RawName == "/" {
+			continue
+		}
+		for _, b := range base {
+			if b.RawName == "/" {
+				continue
+			}
+			if t.StartTime.Equal(b.StartTime) && t.EndTime.Equal(b.EndTime) {
+				continue
+			}
+			if t.StartTime.Before(b.EndTime) && b.StartTime.Before(t.EndTime) {
+				conflicts = append(conflicts, Conflict{Tmp: t, Base: b})
+			}
+		}
+	}
+	return conflicts
+}
+
+// GetConflicts returns the tasks of date that overlap tasks of the base
+// schedule on the same date, or nil if the configuration has no base.
+func (s *Scheduler) GetConflicts(date time.Time) ([]Conflict, error) {
+	if s.base == nil {
+		return nil, nil
+	}
+	tmp, err := s.GetTasksForDate(date)
+	if err != nil {
+		return nil, err
+	}
+	base, err := s.base.GetTasksForDate(date)
+	if err != nil {
+		return nil, err
+	}
+	return FindConflicts(tmp, base), nil
+}
+
+// ConflictsWith returns the summaries of the base tasks task overlaps
+// according to conflicts, or nil if it overlaps none.
+func ConflictsWith(conflicts []Conflict, task TaskEvent) []string {
+	var with []string
+	for _, c := range conflicts {
+		if c.Tmp.Name == task.Name && c.Tmp.StartTime.Equal(task.StartTime) && c.Tmp.EndTime.Equal(task.EndTime) {
+			with = append(with, c.Base.Summary())
+		}
+	}
+	return with
+}
@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+func TestFindConflicts(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2024, 1, 1, h, m, 0, 0, time.UTC) }
+	task := func(name string, sh, sm, eh, em int) TaskEvent {
+		return TaskEvent{Name: name, RawName: name, StartTime: at(sh, sm), EndTime: at(eh, em)}
+	}
+	base := []TaskEvent{
+		task("Math", 13, 30, 14, 20),
+		task("/", 14, 20, 14, 30),
+		task("History", 14, 30, 15, 20),
+	}
+
+	tests := []struct {
+		name string
+		tmp  []TaskEvent
+		want []string
+	}{
+		{
+			name: "overlap",
+			tmp:  []TaskEvent{task("Dentist", 14, 0, 15, 0)},
+			want: []string{
+				"tmp 'Dentist 14:00-15:00' overlaps 'Math 13:30-14:20'",
+				"tmp 'Dentist 14:00-15:00' overlaps 'History 14:30-15:20'",
+			},
+		},
+		{name: "identical_slot", tmp: []TaskEvent{task("Dentist", 13, 30, 14, 20)}},
+		{name: "adjacent", tmp: []TaskEvent{task("Dentist", 12, 30, 13, 30)}},
+		{name: "empty_slot", tmp: []TaskEvent{task("Coffee", 14, 20, 14, 30)}},
+		{name: "tmp_empty_slot", tmp: []TaskEvent{task("/", 13, 0, 16, 0)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range FindConflicts(tt.tmp, base) {
+				got = append(got, c.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetConflicts(t *testing.T) {
+	tmp, err := config.ParseInline("14:00-15:00 Dentist")
+	if err != nil {
+		t.Fatalf("ParseInline() returned error: %v", err)
+	}
+	now := time.Now()
+	if got, err := New(tmp).GetConflicts(now); err != nil || got != nil {
+		t.Fatalf("Expected no conflicts without a base, got %v (%v)", got, err)
+	}
+
+	tmp.Base = &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: int(now.Weekday()), Tasks: []config.Task{
+			{Name: "Math", Start: "13:30", End: "14:20", Source: config.Origin{File: "config.toml", Line: 5, Section: "[[day]]"}},
+		}}},
+	}
+	conflicts, err := New(tmp).GetConflicts(now)
+	if err != nil {
+		t.Fatalf("GetConflicts() returned error: %v", err)
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("Expected 1 conflict, got %d", len(conflicts))
+	}
+	if want := "tmp 'Dentist 14:00-15:00' (<inline>:1:1) overlaps 'Math 13:30-14:20' (config.toml:5 [[day]])"; conflicts[0].String() != want {
+		t.Errorf("Expected %q, got %q", want, conflicts[0].String())
+	}
+	dentist := conflicts[0].Tmp
+	if got := ConflictsWith(conflicts, dentist); !slices.Equal(got, []string{"Math 13:30-14:20"}) {
+		t.Errorf("Expected Dentist to conflict with Math, got %q", got)
+	}
+	if got := ConflictsWith(conflicts, conflicts[0].Base); got != nil {
+		t.Errorf("Expected no conflicts for a base task, got %q", got)
+	}
+}
@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+// Untagged is the pseudo-tag of tasks without tags, so filters can include
+// ("untagged") or exclude ("-untagged") them.
+const Untagged = "untagged"
+
+// TagFilter selects tasks by tag. A task is excluded if any of its tags is in
+// Exclude; otherwise it is kept if Include is empty or shares a tag with it.
+// Tasks without tags carry the Untagged pseudo-tag.
+type TagFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// ParseTagFilter builds a filter from --tag values: "work" includes a tag,
+// "-work" excludes it.
+func ParseTagFilter(specs []string) TagFilter {
+	var f TagFilter
+	for _, s := range specs {
+		s = strings.TrimSpace(s)
+		if tag, ok := strings.CutPrefix(s, "-"); ok {
+			if tag != "" {
+				f.Exclude = append(f.Exclude, tag)
+			}
+		} else if s != "" {
+			f.Include = append(f.Include, s)
+		}
+	}
+	return f
+}
+
+// IsZero reports whether the filter keeps every task.
+func (f TagFilter) IsZero() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// Match reports whether a task with tags passes the filter.
+func (f TagFilter) Match(tags []string) bool {
+	if len(tags) == 0 {
+		tags = []string{Untagged}
+	}
+	for _, t := range tags {
+		if slices.Contains(f.Exclude, t) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, t := range tags {
+		if slices.Contains(f.Include, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filtered returns a scheduler that only sees the tasks passing f, so every
+// query (current, next, day listings, usage) ignores the others. It returns s
+// itself for a zero filter.
+func (s *Scheduler) Filtered(f TagFilter) *Scheduler {
+	if f.IsZero() {
+		return s
+	}
+	cfg := *s.cfg
+	cfg.Days = make([]config.Day, len(s.cfg.Days))
+	for i, d := range s.cfg.Days {
+		d.Tasks = slices.DeleteFunc(slices.Clone(d.Tasks), func(t config.Task) bool {
+			return !f.Match(t.Tags)
+		})
+		cfg.Days[i] = d
+	}
+	cfg.Events = slices.DeleteFunc(slices.Clone(s.cfg.Events), func(e config.Event) bool {
+		return !f.Match(e.Tags)
+	})
+	cfg.Rules = slices.DeleteFunc(slices.Clone(s.cfg.Rules), func(r config.Rule) bool {
+		return r.AddTask != nil && !f.Match(r.AddTask.Tags)
+	})
+	return New(&cfg)
+}
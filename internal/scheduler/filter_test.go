@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+func TestTagFilter_Match(t *testing.T) {
+	tests := []struct {
+		specs []string
+		tags  []string
+		want  bool
+	}{
+		{nil, nil, true},
+		{nil, []string{"work"}, true},
+		{[]string{"work"}, []string{"work", "deep"}, true},
+		{[]string{"work"}, []string{"health"}, false},
+		{[]string{"work"}, nil, false},
+		{[]string{"work", "untagged"}, nil, true},
+		{[]string{"-untagged"}, nil, false},
+		{[]string{"-untagged"}, []string{"health"}, true},
+		{[]string{"-health"}, []string{"health", "personal"}, false},
+		{[]string{"personal", "-health"}, []string{"health", "personal"}, false},
+		{[]string{" work ", "-", ""}, []string{"work"}, true},
+	}
+	for _, tt := range tests {
+		if got := ParseTagFilter(tt.specs).Match(tt.tags); got != tt.want {
+			t.Errorf("%q matching %q: Expected %v, got %v", tt.specs, tt.tags, tt.want, got)
+		}
+	}
+}
+
+func TestFiltered(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Gym", Start: "07:00", End: "08:00", Tags: []string{"health"}},
+			{Name: "Report", Start: "09:00", End: "10:00", Tags: []string{"work"}},
+			{Name: "Lunch", Start: "12:00", End: "13:00"},
+		}}},
+	}
+	sched := New(cfg)
+	if sched.Filtered(TagFilter{}) != sched {
+		t.Errorf("Expected a zero filter to return the scheduler itself")
+	}
+
+	work := sched.Filtered(ParseTagFilter([]string{"work"}))
+	monday := time.Date(2024, 1, 1, 7, 30, 0, 0, time.UTC)
+	current, err := work.GetCurrentTask(monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current != nil {
+		t.Errorf("Expected Gym to be filtered out, got %s", current.Name)
+	}
+	next, err := work.GetNextTask(monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next == nil || next.Name != "Report" || next.Tags[0] != "work" {
+		t.Errorf("Expected Report tagged work, got %+v", next)
+	}
+
+	events, err := sched.Filtered(ParseTagFilter([]string{"-untagged"})).GetTasksForDate(monday)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("Expected Lunch to be excluded, got %+v", events)
+	}
+	// The original configuration is untouched
+	if len(cfg.Days[0].Tasks) != 3 {
+		t.Errorf("Expected the config to keep 3 tasks, got %d", len(cfg.Days[0].Tasks))
+	}
+}
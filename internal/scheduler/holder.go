@@ -0,0 +1,28 @@
+package scheduler
+
+import "sync/atomic"
+
+// Holder holds the Scheduler in use by long-running commands and swaps it
+// atomically when the config is reloaded. Queries Load the Scheduler once
+// and use it throughout, so each sees one config from start to end, while
+// a reload Stores a new Scheduler without waiting for them.
+type Holder struct {
+	p atomic.Pointer[Scheduler]
+}
+
+// NewHolder creates a Holder holding sched.
+func NewHolder(sched *Scheduler) *Holder {
+	h := &Holder{}
+	h.Store(sched)
+	return h
+}
+
+// Load returns the Scheduler currently held.
+func (h *Holder) Load() *Scheduler {
+	return h.p.Load()
+}
+
+// Store replaces the Scheduler held by sched.
+func (h *Holder) Store(sched *Scheduler) {
+	h.p.Store(sched)
+}
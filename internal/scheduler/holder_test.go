@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+func holderConfig(name string) *config.Config {
+	return &config.Config{
+		CycleDays:  3,
+		AnchorDate: "2024-01-01",
+		Days: []config.Day{
+			{ID: 0, Tasks: []config.Task{{Name: name, Start: "09:00", End: "10:00"}}},
+			{ID: 1, Tasks: []config.Task{{Name: name, Start: "11:00", End: "12:00"}}},
+		},
+		Overrides: []config.Override{{DateStr: "2024-01-02", EndDateStr: "2024-01-02", IsOff: true}},
+	}
+}
+
+// Run with -race: queries share Schedulers while reloads swap them.
+func TestHolderConcurrentSwap(t *testing.T) {
+	h := NewHolder(New(holderConfig("A")))
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			date := time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				sched := h.Load()
+				current, err := sched.GetCurrentTask(date)
+				if err != nil {
+					t.Errorf("GetCurrentTask() returned error: %v", err)
+					return
+				}
+				// One query sees one config: the task names all agree
+				tasks, err := sched.GetTasksForDate(date)
+				if err != nil || len(tasks) != 1 || current == nil || tasks[0].Name != current.Name {
+					t.Errorf("Expected one task named %v, got %v (err %v)", current, tasks, err)
+					return
+				}
+				if _, err := sched.GetDayInfo(date.AddDate(0, 0, 1)); err != nil {
+					t.Errorf("GetDayInfo() returned error: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	for i := range 1000 {
+		name := "A"
+		if i%2 == 0 {
+			name = "B"
+		}
+		h.Store(New(holderConfig(name)))
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestNewAnchorError(t *testing.T) {
+	sched := New(&config.Config{CycleDays: 3, AnchorDate: "not a date"})
+	if _, err := sched.GetDayInfo(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Error("Expected an error for an invalid anchor_date, got nil")
+	}
+}
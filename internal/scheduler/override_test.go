@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"github.com/Daniel-42-z/sked/internal/config"
+	"strings"
 	"testing"
 	"time"
 )
@@ -156,3 +157,153 @@ func TestRangeOverrides(t *testing.T) {
 		t.Errorf("Expected Task A on next Monday, got %v", task)
 	}
 }
+
+func TestGetDayInfo_OverrideNote(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Overrides: []config.Override{{
+			DateStr: "2024-07-01",
+			IsOff:   true,
+			Note:    "PTO",
+			Date:    time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+			EndDate: time.Date(2024, 7, 14, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+	s := New(cfg)
+
+	info, err := s.GetDayInfo(time.Date(2024, 7, 10, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetDayInfo() returned error: %v", err)
+	}
+	if !info.IsOff || info.Note != "PTO" {
+		t.Errorf("Expected an off day noted PTO, got %+v", info)
+	}
+	if off, err := s.IsOffDay(time.Date(2024, 7, 14, 23, 0, 0, 0, time.UTC)); err != nil || !off {
+		t.Errorf("Expected Jul 14 to be off, got %v, %v", off, err)
+	}
+
+	info, err = s.GetDayInfo(time.Date(2024, 7, 15, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetDayInfo() returned error: %v", err)
+	}
+	if info.Overridden || info.Note != "" {
+		t.Errorf("Expected no override after the range, got %+v", info)
+	}
+	if off, err := s.IsOffDay(info.Date); err != nil || off {
+		t.Errorf("Expected Jul 15 not to be off, got %v, %v", off, err)
+	}
+}
+
+func TestRules(t *testing.T) {
+	monday := config.DayID(1)
+	friday := config.DayID(5)
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 1, Tasks: []config.Task{{Name: "Math", Start: "09:00", End: "10:00"}}},
+			{ID: 5, Tasks: []config.Task{
+				{Name: "Math", Start: "09:00", End: "10:00"},
+				{Name: "Lab", Start: "14:00", End: "16:00"},
+				{Name: "Gym", Start: "16:00", End: "17:00"},
+			}},
+		},
+		Rules: []config.Rule{
+			{Weekday: &monday, Ordinal: 1, AddTask: &config.Task{Name: "All-hands", Start: "10:00", End: "11:00"}},
+			{Weekday: &friday, TrimAfter: "15:00"},
+		},
+		Events: []config.Event{{
+			Task: config.Task{Name: "Drinks", Start: "17:00", End: "18:00"},
+			Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		}},
+		Overrides: []config.Override{{
+			DateStr:  "2024-01-12",
+			UseDayID: 5,
+			Date:     time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC),
+			EndDate:  time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+	s := New(cfg)
+
+	names := func(date time.Time) string {
+		tasks, err := s.GetTasksForDate(date)
+		if err != nil {
+			t.Fatalf("GetTasksForDate(%s) returned error: %v", date.Format(time.DateOnly), err)
+		}
+		var out []string
+		for _, task := range tasks {
+			out = append(out, task.Name+" "+task.EndTime.Format("15:04"))
+		}
+		return strings.Join(out, ", ")
+	}
+
+	tests := []struct {
+		date time.Time
+		want string
+	}{
+		// Jan 1, 2024 is the first Monday of the month.
+		{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "Math 10:00, All-hands 11:00"},
+		{time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), "Math 10:00"},
+		// Events are added after the trim.
+		{time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), "Math 10:00, Lab 15:00, Drinks 18:00"},
+		// Overridden dates ignore rules.
+		{time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC), "Math 10:00, Lab 16:00, Gym 17:00"},
+	}
+	for _, tt := range tests {
+		if got := names(tt.date); got != tt.want {
+			t.Errorf("Expected %q on %s, got %q", tt.want, tt.date.Format(time.DateOnly), got)
+		}
+	}
+}
+
+func TestTaskSources(t *testing.T) {
+	friday := config.DayID(5)
+	day := config.Origin{File: "config.toml", Line: 3, Section: "[[day]]"}
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 5, Tasks: []config.Task{{Name: "Lab", Start: "14:00", End: "16:00", Source: day}}},
+		},
+		Rules: []config.Rule{
+			{Weekday: &friday, TrimAfter: "15:00"},
+			{Weekday: &friday, AddTask: &config.Task{
+				Name: "Review", Start: "12:00", End: "13:00", Source: config.Origin{File: "config.toml", Line: 9, Section: "[[rule]]"},
+			}},
+		},
+		Events: []config.Event{{
+			Task: config.Task{Name: "Drinks", Start: "17:00", End: "18:00", Source: config.Origin{File: "calendar 'Work'"}},
+			Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		}},
+		Overrides: []config.Override{{
+			DateStr:  "2024-01-08",
+			UseDayID: 5,
+			Date:     time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+			EndDate:  time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+	s := New(cfg)
+
+	sources := func(date time.Time) string {
+		tasks, err := s.GetTasksForDate(date)
+		if err != nil {
+			t.Fatalf("GetTasksForDate(%s) returned error: %v", date.Format(time.DateOnly), err)
+		}
+		var out []string
+		for _, task := range tasks {
+			out = append(out, task.Name+" "+task.Source)
+		}
+		return strings.Join(out, ", ")
+	}
+	// Trimmed and added by rules on Friday, copied by the override on Monday
+	if want, got := "Review config.toml:9 [[rule]], Lab config.toml:3 [[day]], Drinks calendar 'Work'", sources(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if want, got := "Lab config.toml:3 [[day]]", sources(time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	cfg.Days[0].Tasks[0].Start = "2pm"
+	_, err := New(cfg).GetTasksForDate(time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC))
+	if err == nil || !strings.Contains(err.Error(), "task 'Lab' (config.toml:3 [[day]]) start") {
+		t.Errorf("Expected the error to name where Lab is defined, got %v", err)
+	}
+}
@@ -2,27 +2,119 @@
 package scheduler
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"sort"
 	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/pomodoro"
 	"time"
 )
 
-// Scheduler handles task lookups based on the configuration.
+// Scheduler handles task lookups based on the configuration. It is
+// immutable once New returns, so one Scheduler may serve queries from many
+// goroutines; to change the schedule, build a new one and swap it in, e.g.
+// with a Holder. cfg must not be modified after it is passed to New.
 type Scheduler struct {
 	cfg *config.Config
+
+	days      map[int]*daySchedule // tasks of each cycle day, by day ID
+	events    map[dateKey][]slot   // dated events, by date
+	anchor    time.Time            // parsed anchor_date
+	anchorErr error                // why anchor_date didn't parse
+	empty     bool                 // no day, event or rule defines a task
+	base      *Scheduler           // schedule of cfg.Base, if any
+	mergeGap  int                  // cfg.MergeGap()
+	tmpDate   dateKey              // cfg.TmpDate, if set
 }
 
-// New creates a new Scheduler.
+// ErrEmptySchedule is returned by GetNextTask when the schedule has no tasks
+// at all, so there is no next task to wait for; see config.EmptyWarning.
+var ErrEmptySchedule = errors.New("the schedule has no tasks")
+
+// New creates a new Scheduler, deriving everything its queries need from cfg.
 func New(cfg *config.Config) *Scheduler {
-	return &Scheduler{cfg: cfg}
+	slog.Debug("scheduler created", "cycle_days", cfg.CycleDays, "anchor_date", cfg.AnchorDate, "overrides", len(cfg.Overrides))
+	s := &Scheduler{cfg: cfg, days: make(map[int]*daySchedule, len(cfg.Days)), events: make(map[dateKey][]slot), empty: !cfg.HasTasks(), mergeGap: cfg.MergeGap()}
+	for _, d := range cfg.Days {
+		// The first block of a day ID wins, as it always has
+		if _, ok := s.days[d.ID]; ok {
+			continue
+		}
+		slots := make([]slot, len(d.Tasks))
+		for i, t := range d.Tasks {
+			slots[i] = s.newSlot(t)
+		}
+		s.days[d.ID] = s.newDaySchedule(slots)
+	}
+	for _, e := range cfg.Events {
+		k := keyOf(e.Date)
+		s.events[k] = append(s.events[k], s.newSlot(e.Task))
+	}
+	if !cfg.TmpDate.IsZero() {
+		s.tmpDate = keyOf(cfg.TmpDate)
+	}
+	if cfg.AnchorDate != "" {
+		s.anchor, s.anchorErr = time.Parse("2006-01-02", cfg.AnchorDate)
+	}
+	if cfg.Base != nil {
+		s.base = New(cfg.Base)
+	}
+	return s
+}
+
+// Config returns the configuration the scheduler was created with.
+func (s *Scheduler) Config() *config.Config {
+	return s.cfg
 }
 
 // TaskEvent represents a scheduled task instance.
 type TaskEvent struct {
-	Name      string
+	Name      string // display name, after aliases
+	RawName   string `json:",omitempty"` // name as written in the config
 	StartTime time.Time
 	EndTime   time.Time
+	Color     string   `json:",omitempty"`
+	Pomodoro  string   `json:",omitempty"` // focus/break rhythm, e.g. "25m/5m"
+	Icon      string   `json:",omitempty"` // e.g. "📚", from the task or [icons]
+	Tags      []string `json:",omitempty"`
+	URL       string   `json:",omitempty"`
+	Location  string   `json:",omitempty"`
+	Source    string   `json:",omitempty"` // where the task was defined, e.g. "config.toml:12 [[day]]"
+	// Segments are the tasks merge_adjacent merged into this one; nil
+	// unless it merged some.
+	Segments []Segment `json:",omitempty"`
+}
+
+// Segment is the span of one of the tasks merged into a TaskEvent.
+type Segment struct {
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// Label returns the name prefixed with the icon, if the task has one.
+func (t TaskEvent) Label() string {
+	if t.Icon == "" {
+		return t.Name
+	}
+	return t.Icon + " " + t.Name
+}
+
+// PomodoroPhase returns the pomodoro phase of the task at now, or nil if the
+// task has no pomodoro rhythm or isn't running at now.
+func (t TaskEvent) PomodoroPhase(now time.Time) *pomodoro.Phase {
+	if t.Pomodoro == "" {
+		return nil
+	}
+	spec, err := pomodoro.Parse(t.Pomodoro)
+	if err != nil {
+		return nil
+	}
+	p, ok := spec.At(t.StartTime, t.EndTime, now)
+	if !ok {
+		return nil
+	}
+	return &p
 }
 
 // GetCurrentTask returns the task currently in progress, if any.
@@ -32,32 +124,90 @@ func (s *Scheduler) GetCurrentTask(now time.Time) (*TaskEvent, error) {
 		return nil, err
 	}
 
-	// If dayID is -1 (Off day), getTasksForDay returns nil/empty, loop doesn't run, returns nil.
-	tasks := s.getTasksForDay(dayID)
-	for _, t := range tasks {
-		start, end, err := s.parseTaskTimes(now, t)
-		if err != nil {
-			return nil, err
-		}
-
+	// Off days have no slots, so the loop doesn't run and nil is returned.
+	day := s.scheduleOn(now, dayID)
+	if day.err != nil {
+		return nil, day.err
+	}
+	for i := range day.slots {
+		sl := &day.slots[i]
+		start, end := sl.times(now)
 		if (now.Equal(start) || now.After(start)) && now.Before(end) {
-			if t.Name == "/" {
+			if sl.task.Name == "/" {
 				return nil, nil
 			}
-			return &TaskEvent{
-				Name:      t.Name,
-				StartTime: start,
-				EndTime:   end,
-			}, nil
+			ev := sl.event(start, end)
+			return &ev, nil
 		}
 	}
 
 	return nil, nil
 }
 
+// GetTasksAt returns the task in progress at each of times, as
+// GetCurrentTask would, with nil where there is none. Instants on the same
+// date share that date's schedule, which is resolved only once, so
+// annotating a long log costs little more than one query per date.
+func (s *Scheduler) GetTasksAt(times []time.Time) ([]*TaskEvent, error) {
+	type dateIn struct {
+		date dateKey
+		loc  *time.Location
+	}
+	byDate := make(map[dateIn][]int)
+	var dates []dateIn
+	for i, t := range times {
+		k := dateIn{keyOf(t), t.Location()}
+		if _, ok := byDate[k]; !ok {
+			dates = append(dates, k)
+		}
+		byDate[k] = append(byDate[k], i)
+	}
+
+	tasks := make([]*TaskEvent, len(times))
+	// One backing array for the answers; its capacity keeps the pointers
+	// into it valid
+	found := make([]TaskEvent, 0, len(times))
+	for _, k := range dates {
+		indexes := byDate[k]
+		date := times[indexes[0]]
+		dayID, err := s.getCycleDayID(date)
+		if err != nil {
+			return nil, err
+		}
+		day := s.scheduleOn(date, dayID)
+		if day.err != nil {
+			return nil, day.err
+		}
+		starts := make([]time.Time, len(day.slots))
+		ends := make([]time.Time, len(day.slots))
+		for i := range day.slots {
+			starts[i], ends[i] = day.slots[i].times(date)
+		}
+		for _, idx := range indexes {
+			now := times[idx]
+			for i := range day.slots {
+				if now.Before(starts[i]) || !now.Before(ends[i]) {
+					continue
+				}
+				if sl := &day.slots[i]; sl.task.Name != "/" {
+					found = append(found, sl.event(starts[i], ends[i]))
+					tasks[idx] = &found[len(found)-1]
+				}
+				break
+			}
+		}
+	}
+	return tasks, nil
+}
+
 // GetNextTask returns the next upcoming task.
-// It searches up to 2 full cycles ahead to find the next event.
+// It searches up to 2 full cycles ahead to find the next event, and fails
+// with ErrEmptySchedule if the schedule has no tasks at all.
 func (s *Scheduler) GetNextTask(now time.Time) (*TaskEvent, error) {
+	if s.empty {
+		return nil, ErrEmptySchedule
+	}
+
 	// Search for the next task starting from 'now'
 	// We'll check the current day, then subsequent days.
 
@@ -74,33 +224,21 @@ func (s *Scheduler) GetNextTask(now time.Time) (*TaskEvent, error) {
 			return nil, err
 		}
 
-		tasks := s.getTasksForDay(dayID)
-
-		// Sort tasks by start time to ensure we find the earliest one
-		var dayEvents []TaskEvent
-		for _, t := range tasks {
-			start, end, err := s.parseTaskTimes(checkDate, t)
-			if err != nil {
-				// Log error? Skip? For now, return error to be safe.
-				return nil, fmt.Errorf("invalid time in config: %w", err)
-			}
-			dayEvents = append(dayEvents, TaskEvent{
-				Name:      t.Name,
-				StartTime: start,
-				EndTime:   end,
-			})
+		day := s.scheduleOn(checkDate, dayID)
+		if day.err != nil {
+			return nil, fmt.Errorf("invalid time in config: %w", day.err)
 		}
 
-		sort.Slice(dayEvents, func(j, k int) bool {
-			return dayEvents[j].StartTime.Before(dayEvents[k].StartTime)
-		})
-
-		for _, event := range dayEvents {
-			if event.StartTime.After(now) {
-				if event.Name == "/" {
+		// Walk the tasks by start time to find the earliest one
+		for _, j := range day.byStart {
+			sl := &day.slots[j]
+			start, end := sl.times(checkDate)
+			if start.After(now) {
+				if sl.task.Name == "/" {
 					continue
 				}
-				return &event, nil
+				ev := sl.event(start, end)
+				return &ev, nil
 			}
 		}
 	}
@@ -115,28 +253,251 @@ func (s *Scheduler) GetTasksForDate(date time.Time) ([]TaskEvent, error) {
 		return nil, err
 	}
 
-	tasks := s.getTasksForDay(dayID)
-	var events []TaskEvent
-	for _, t := range tasks {
-		start, end, err := s.parseTaskTimes(date, t)
-		if err != nil {
-			return nil, fmt.Errorf("invalid time in config: %w", err)
+	return s.scheduleOn(date, dayID).eventsOn(date)
+}
+
+// GetTasksForDay returns the tasks of cycle day dayID laid out on date, as
+// written in the config: overrides, rules and events of date don't apply.
+func (s *Scheduler) GetTasksForDay(dayID int, date time.Time) ([]TaskEvent, error) {
+	if dayID < 0 || dayID >= s.cfg.CycleDays {
+		return nil, fmt.Errorf("day %d is outside the %d-day cycle", dayID, s.cfg.CycleDays)
+	}
+	day := s.days[dayID]
+	if day == nil {
+		return nil, nil
+	}
+	return day.eventsOn(date)
+}
+
+// GetDayBounds returns the task starting first and the task ending last on
+// the given date, ignoring empty slots. Both are nil on days without tasks.
+func (s *Scheduler) GetDayBounds(date time.Time) (first, last *TaskEvent, err error) {
+	events, err := s.GetTasksForDate(date)
+	if err != nil {
+		return nil, nil, err
+	}
+	first, last = DayBounds(events)
+	return first, last, nil
+}
+
+// DayBounds returns the task starting first and the task ending last among
+// events, ignoring empty slots.
+func DayBounds(events []TaskEvent) (first, last *TaskEvent) {
+	for i := range events {
+		e := &events[i]
+		if e.RawName == "/" {
+			continue
+		}
+		if first == nil || e.StartTime.Before(first.StartTime) {
+			first = e
+		}
+		if last == nil || e.EndTime.After(last.EndTime) {
+			last = e
 		}
-		events = append(events, TaskEvent{
-			Name:      t.Name,
-			StartTime: start,
-			EndTime:   end,
-		})
 	}
+	return first, last
+}
+
+// BoundaryKind says whether a Boundary is the start or the end of a task.
+type BoundaryKind string
+
+const (
+	BoundaryStart BoundaryKind = "start"
+	BoundaryEnd   BoundaryKind = "end"
+)
+
+// Boundary is an instant at which a task starts or ends.
+type Boundary struct {
+	At   time.Time
+	Kind BoundaryKind
+	Task *TaskEvent
+}
+
+// GetNextBoundary returns the first boundary after now: the end of the task
+// in progress or the start of the next one, whichever comes first. It
+// reports false if there is neither.
+func (s *Scheduler) GetNextBoundary(now time.Time) (Boundary, bool, error) {
+	current, err := s.GetCurrentTask(now)
+	if err != nil {
+		return Boundary{}, false, err
+	}
+	next, err := s.GetNextTask(now)
+	if err != nil {
+		return Boundary{}, false, err
+	}
+	b, ok := NextBoundary(current, next, now)
+	return b, ok, nil
+}
 
-	sort.Slice(events, func(i, j int) bool {
-		return events[i].StartTime.Before(events[j].StartTime)
-	})
+// NextBoundary returns the earlier of the end of current and the start of
+// next that is after now, as GetNextBoundary does for tasks already looked
+// up. Either task may be nil.
+func NextBoundary(current, next *TaskEvent, now time.Time) (Boundary, bool) {
+	var b Boundary
+	if current != nil && current.EndTime.After(now) {
+		b = Boundary{At: current.EndTime, Kind: BoundaryEnd, Task: current}
+	}
+	if next != nil && next.StartTime.After(now) && (b.Task == nil || next.StartTime.Before(b.At)) {
+		b = Boundary{At: next.StartTime, Kind: BoundaryStart, Task: next}
+	}
+	return b, b.Task != nil
+}
 
-	return events, nil
+// Usage summarizes how much of a date is scheduled.
+type Usage struct {
+	// Scheduled is the time covered by tasks, counting overlaps once and
+	// only within the day window if one is configured.
+	Scheduled time.Duration
+	// Window is the length of the configured day window, or 0 without one.
+	Window time.Duration
 }
 
-// GetPreviousTask returns the most recently finished task.
+// Utilization returns the scheduled share of the day window, or 0 without one.
+func (u Usage) Utilization() float64 {
+	if u.Window == 0 {
+		return 0
+	}
+	return u.Scheduled.Seconds() / u.Window.Seconds()
+}
+
+// GetUsage returns how much of the given date is scheduled. Off days have
+// no scheduled time.
+func (s *Scheduler) GetUsage(date time.Time) (Usage, error) {
+	events, err := s.GetTasksForDate(date)
+	if err != nil {
+		return Usage{}, err
+	}
+	var u Usage
+	from, to, ok := s.cfg.DayWindowOn(date)
+	if ok {
+		u.Window = to.Sub(from)
+	}
+	u.Scheduled = ScheduledTime(events, from, to)
+	return u, nil
+}
+
+// Remaining is what is left of a day's tasks at some instant.
+type Remaining struct {
+	// Tasks counts the tasks that haven't ended, the one in progress
+	// included.
+	Tasks int
+	// Time is the time they still cover, counting only the rest of a task
+	// in progress and overlaps once.
+	Time time.Duration
+}
+
+// RemainingTasks returns what is left of events at now, ignoring empty
+// slots.
+func RemainingTasks(events []TaskEvent, now time.Time) Remaining {
+	var r Remaining
+	var left []TaskEvent
+	for _, e := range events {
+		if e.RawName != "/" && e.EndTime.After(now) {
+			left = append(left, e)
+		}
+	}
+	r.Tasks = len(left)
+	r.Time = ScheduledTime(left, now, time.Time{})
+	return r
+}
+
+// GetRemaining returns what is left of the tasks of now's date. Off days
+// have nothing left.
+func (s *Scheduler) GetRemaining(now time.Time) (Remaining, error) {
+	events, err := s.GetTasksForDate(now)
+	if err != nil {
+		return Remaining{}, err
+	}
+	return RemainingTasks(events, now), nil
+}
+
+// ScheduledTime returns the length of the union of the events' intervals,
+// ignoring empty slots. If from and to are set, only the part of each event
+// between them counts.
+func ScheduledTime(events []TaskEvent, from, to time.Time) time.Duration {
+	type span struct{ start, end time.Time }
+	var spans []span
+	for _, e := range events {
+		if e.RawName == "/" {
+			continue
+		}
+		sp := span{e.StartTime, e.EndTime}
+		if !from.IsZero() && sp.start.Before(from) {
+			sp.start = from
+		}
+		if !to.IsZero() && sp.end.After(to) {
+			sp.end = to
+		}
+		if sp.end.After(sp.start) {
+			spans = append(spans, sp)
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+
+	var total time.Duration
+	var cur span
+	for i, sp := range spans {
+		if i > 0 && !sp.start.After(cur.end) {
+			if sp.end.After(cur.end) {
+				cur.end = sp.end
+			}
+			continue
+		}
+		total += cur.end.Sub(cur.start)
+		cur = sp
+	}
+	return total + cur.end.Sub(cur.start)
+}
+
+// Gap is a free interval between events.
+type Gap struct {
+	Start, End time.Time
+}
+
+// FreeGaps returns the intervals between consecutive events, with
+// overlapping events merged first. Empty slots count as events, so callers
+// wanting them free should filter them out.
+func FreeGaps(events []TaskEvent) []Gap {
+	sorted := make([]TaskEvent, len(events))
+	copy(sorted, events)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartTime.Before(sorted[j].StartTime) })
+
+	var gaps []Gap
+	var end time.Time
+	for i, e := range sorted {
+		if i > 0 && e.StartTime.After(end) {
+			gaps = append(gaps, Gap{Start: end, End: e.StartTime})
+		}
+		if i == 0 || e.EndTime.After(end) {
+			end = e.EndTime
+		}
+	}
+	return gaps
+}
+
+// ClipGaps cuts gaps to the window from to, dropping those outside it. A
+// zero window leaves the gaps as they are.
+func ClipGaps(gaps []Gap, from, to time.Time) []Gap {
+	if from.IsZero() && to.IsZero() {
+		return gaps
+	}
+	var out []Gap
+	for _, g := range gaps {
+		if g.Start.Before(from) {
+			g.Start = from
+		}
+		if g.End.After(to) {
+			g.End = to
+		}
+		if g.End.After(g.Start) {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
+// GetPreviousTask returns the most recently finished task. Temporary
+// schedules are not searched before their TmpDate.
 func (s *Scheduler) GetPreviousTask(now time.Time) (*TaskEvent, error) {
 	// Search backwards from 'now'
 	maxDays := s.cfg.CycleDays * 2
@@ -146,38 +507,30 @@ func (s *Scheduler) GetPreviousTask(now time.Time) (*TaskEvent, error) {
 
 	for i := 0; i < maxDays; i++ {
 		checkDate := now.AddDate(0, 0, -i)
+		if !s.cfg.TmpDate.IsZero() && checkDate.Before(s.cfg.TmpDate) {
+			break
+		}
 		dayID, err := s.getCycleDayID(checkDate)
 		if err != nil {
 			return nil, err
 		}
 
-		tasks := s.getTasksForDay(dayID)
-
-		var dayEvents []TaskEvent
-		for _, t := range tasks {
-			start, end, err := s.parseTaskTimes(checkDate, t)
-			if err != nil {
-				return nil, fmt.Errorf("invalid time in config: %w", err)
-			}
-			dayEvents = append(dayEvents, TaskEvent{
-				Name:      t.Name,
-				StartTime: start,
-				EndTime:   end,
-			})
+		day := s.scheduleOn(checkDate, dayID)
+		if day.err != nil {
+			return nil, fmt.Errorf("invalid time in config: %w", day.err)
 		}
 
-		// Sort by EndTime descending to find the latest one
-		sort.Slice(dayEvents, func(j, k int) bool {
-			return dayEvents[j].EndTime.After(dayEvents[k].EndTime)
-		})
-
-		for _, event := range dayEvents {
+		// Walk the tasks by EndTime descending to find the latest one
+		for _, j := range day.byEnd {
+			sl := &day.slots[j]
+			start, end := sl.times(checkDate)
 			// We want the task with the latest EndTime that is <= now.
-			if !event.EndTime.After(now) {
-				if event.Name == "/" {
+			if !end.After(now) {
+				if sl.task.Name == "/" {
 					continue
 				}
-				return &event, nil
+				ev := sl.event(start, end)
+				return &ev, nil
 			}
 		}
 	}
@@ -185,29 +538,119 @@ func (s *Scheduler) GetPreviousTask(now time.Time) (*TaskEvent, error) {
 	return nil, nil
 }
 
+// DayContext is the schedule around an instant: the task that ended last,
+// the one in progress, the next one and the date's DayInfo.
+type DayContext struct {
+	Previous, Current, Next *TaskEvent
+	Day                     DayInfo
+}
+
+// GetDayContext looks up the previous, current and next tasks at now and
+// the day they fall on in one call. Next is nil rather than an error when
+// the schedule has no tasks.
+func (s *Scheduler) GetDayContext(now time.Time) (DayContext, error) {
+	var c DayContext
+	var err error
+	if c.Day, err = s.GetDayInfo(now); err != nil {
+		return c, err
+	}
+	if c.Previous, err = s.GetPreviousTask(now); err != nil {
+		return c, err
+	}
+	if c.Current, err = s.GetCurrentTask(now); err != nil {
+		return c, err
+	}
+	if c.Next, err = s.GetNextTask(now); err != nil && !errors.Is(err, ErrEmptySchedule) {
+		return c, err
+	}
+	return c, nil
+}
+
+// DayInfo describes how a calendar date maps onto the schedule cycle.
+type DayInfo struct {
+	Date       time.Time
+	DayID      int    // resolved cycle day ID, -1 for an off day
+	IsOff      bool   // no tasks are scheduled because of an override
+	Overridden bool   // an override applied to this date
+	Note       string // note of the applied override, e.g. "PTO"
+	Muted      bool   // the applied override mutes notifications
+}
+
+// GetDayInfo resolves which cycle day applies to the given date.
+func (s *Scheduler) GetDayInfo(date time.Time) (DayInfo, error) {
+	dayID, override, err := s.resolveDay(date)
+	if err != nil {
+		return DayInfo{}, err
+	}
+	y, m, d := date.Date()
+	info := DayInfo{
+		Date:       time.Date(y, m, d, 0, 0, 0, 0, date.Location()),
+		DayID:      dayID,
+		IsOff:      dayID == -1,
+		Overridden: override != nil,
+	}
+	if override != nil {
+		info.Note = override.Note
+		info.Muted = override.MuteNotifications
+	}
+	return info, nil
+}
+
+// IsOffDay reports whether an override marks date as an off day.
+func (s *Scheduler) IsOffDay(date time.Time) (bool, error) {
+	dayID, _, err := s.resolveDay(date)
+	return dayID == -1, err
+}
+
+// DayName returns a human-readable name for a cycle day ID: the weekday name
+// for standard 7-day weeks, "Day N" for custom cycles, and "" for off days.
+func (s *Scheduler) DayName(dayID int) string {
+	if dayID < 0 {
+		return ""
+	}
+	if s.cfg.CycleDays == 7 && s.cfg.AnchorDate == "" {
+		return time.Weekday(dayID % 7).String()
+	}
+	return fmt.Sprintf("Day %d", dayID)
+}
+
 // getCycleDayID calculates the 0-indexed day ID in the cycle for a given date.
 // It respects overrides defined in the configuration.
 func (s *Scheduler) getCycleDayID(date time.Time) (int, error) {
-	// 1. Check for Overrides
-	// Normalize date to YYYY-MM-DD for comparison
-	y, m, d := date.Date()
-	checkDate := time.Date(y, m, d, 0, 0, 0, 0, date.Location())
-
-	for _, o := range s.cfg.Overrides {
-		// Use the same location for comparison
-		oDate := time.Date(o.Date.Year(), o.Date.Month(), o.Date.Day(), 0, 0, 0, 0, date.Location())
-		oEndDate := time.Date(o.EndDate.Year(), o.EndDate.Month(), o.EndDate.Day(), 0, 0, 0, 0, date.Location())
+	dayID, _, err := s.resolveDay(date)
+	return dayID, err
+}
 
-		// Check if checkDate is within [oDate, oEndDate]
-		if (checkDate.Equal(oDate) || checkDate.After(oDate)) && (checkDate.Equal(oEndDate) || checkDate.Before(oEndDate)) {
-			if o.IsOff {
-				return -1, nil // -1 indicates OFF day
-			}
-			return int(o.UseDayID), nil
+// resolveDay calculates the cycle day ID for a date and the override that applied, if any.
+func (s *Scheduler) resolveDay(date time.Time) (int, *config.Override, error) {
+	// 1. Check for Overrides (see config.MatchingOverrides for precedence)
+	if o := s.cfg.EffectiveOverride(date); o != nil {
+		if o.IsOff {
+			return -1, o, nil // -1 indicates OFF day
 		}
+		return int(o.UseDayID), o, nil
 	}
 
 	// 2. Standard Calculation
+	dayID, err := s.baseDayID(date)
+	return dayID, nil, err
+}
+
+// CycleStart returns the first date of the cycle containing date: the
+// Sunday before it in standard weeks, otherwise the last date on day 0
+// counted from anchor_date and anchor_day_id. Overrides don't move it.
+func (s *Scheduler) CycleStart(date time.Time) (time.Time, error) {
+	dayID, err := s.baseDayID(date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	y, m, d := date.Date()
+	return time.Date(y, m, d-dayID, 0, 0, 0, 0, date.Location()), nil
+}
+
+// baseDayID calculates the cycle day ID of a date from the weekday or the
+// anchor date, ignoring overrides.
+func (s *Scheduler) baseDayID(date time.Time) (int, error) {
 	// If standard 7-day cycle and no anchor, use weekday
 	if s.cfg.CycleDays == 7 && s.cfg.AnchorDate == "" {
 		// time.Weekday: Sunday=0, ... Saturday=6
@@ -218,17 +661,17 @@ func (s *Scheduler) getCycleDayID(date time.Time) (int, error) {
 		return 0, fmt.Errorf("anchor_date is required for non-standard cycles")
 	}
 
-	anchor, err := time.Parse("2006-01-02", s.cfg.AnchorDate)
-	if err != nil {
-		return 0, err
+	if s.anchorErr != nil {
+		return 0, s.anchorErr
 	}
+	anchor := s.anchor
 
 	// Normalize to midnight to calculate day difference
 	d1 := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 	// Anchor must be relative to the same timezone location to get correct day diff
 	anchorInLoc := time.Date(anchor.Year(), anchor.Month(), anchor.Day(), 0, 0, 0, 0, date.Location())
 
-	diff := int(d1.Sub(anchorInLoc).Hours() / 24)
+	diff := int(d1.Sub(anchorInLoc).Hours()/24) + int(s.cfg.AnchorDayID)
 
 	// Handle negative difference (date before anchor)
 	mod := diff % s.cfg.CycleDays
@@ -238,32 +681,6 @@ func (s *Scheduler) getCycleDayID(date time.Time) (int, error) {
 	return mod, nil
 }
 
-func (s *Scheduler) getTasksForDay(dayID int) []config.Task {
-	// If dayID is -1 (Off day), return nil
-	if dayID == -1 {
-		return nil
-	}
-	for _, d := range s.cfg.Days {
-		if d.ID == dayID {
-			return d.Tasks
-		}
-	}
-	return nil
-}
-
-// parseTaskTimes converts "HH:MM" strings to time.Time objects on the given date.
-func (s *Scheduler) parseTaskTimes(date time.Time, t config.Task) (time.Time, time.Time, error) {
-	start, err := parseTimeOnDate(date, t.Start)
-	if err != nil {
-		return time.Time{}, time.Time{}, fmt.Errorf("task '%s' start: %w", t.Name, err)
-	}
-	end, err := parseTimeOnDate(date, t.End)
-	if err != nil {
-		return time.Time{}, time.Time{}, fmt.Errorf("task '%s' end: %w", t.Name, err)
-	}
-	return start, end, nil
-}
-
 func parseTimeOnDate(date time.Time, timeStr string) (time.Time, error) {
 	t, err := time.Parse("15:04", timeStr)
 	if err != nil {
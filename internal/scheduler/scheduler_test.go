@@ -1,6 +1,11 @@
 package scheduler
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 	"github.com/Daniel-42-z/sked/internal/config"
@@ -45,6 +50,57 @@ func TestGetCurrentTask(t *testing.T) {
 	}
 }
 
+func TestGetTasksAt(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 1, Tasks: []config.Task{
+				{Name: "Math", Start: "09:00", End: "10:00"},
+				{Name: "/", Start: "10:00", End: "10:30"},
+				{Name: "Art", Start: "10:30", End: "12:00"},
+			}},
+			{ID: 2, Tasks: []config.Task{{Name: "Gym", Start: "09:00", End: "11:00"}}},
+		},
+		Events: []config.Event{{DateStr: "2024-01-02", Task: config.Task{Name: "Dentist", Start: "14:00", End: "15:00"}}},
+	}
+	if err := cfg.ProcessOverrides(); err != nil {
+		t.Fatalf("ProcessOverrides() returned error: %v", err)
+	}
+	sched := New(cfg)
+	at := func(day, h, m int) time.Time { return time.Date(2024, 1, day, h, m, 0, 0, time.UTC) }
+	// Out of order and across dates, with an empty slot, boundaries and a
+	// day without tasks
+	times := []time.Time{
+		at(2, 14, 30), at(1, 9, 0), at(1, 10, 0), at(3, 9, 30), at(1, 11, 59),
+		at(2, 10, 0), at(1, 12, 0), at(1, 8, 59), at(2, 9, 0),
+		at(1, 9, 30).In(time.FixedZone("UTC+1", 3600)),
+	}
+	got, err := sched.GetTasksAt(times)
+	if err != nil {
+		t.Fatalf("GetTasksAt() returned error: %v", err)
+	}
+	if len(got) != len(times) {
+		t.Fatalf("Expected %d answers, got %d", len(times), len(got))
+	}
+	for i, now := range times {
+		want, err := sched.GetCurrentTask(now)
+		if err != nil {
+			t.Fatalf("GetCurrentTask() returned error: %v", err)
+		}
+		switch {
+		case want == nil && got[i] != nil:
+			t.Errorf("%v: expected no task, got %s", now, got[i].Name)
+		case want != nil && got[i] == nil:
+			t.Errorf("%v: expected %s, got none", now, want.Name)
+		case want != nil && (got[i].Name != want.Name || !got[i].StartTime.Equal(want.StartTime)):
+			t.Errorf("%v: expected %s at %v, got %s at %v", now, want.Name, want.StartTime, got[i].Name, got[i].StartTime)
+		}
+	}
+	if got[0] == nil || got[0].Name != "Dentist" {
+		t.Errorf("Expected the dated event at 14:30, got %v", got[0])
+	}
+}
+
 func TestGetNextTask(t *testing.T) {
 	cfg := &config.Config{
 		CycleDays: 7,
@@ -97,6 +153,14 @@ func TestGetNextTask(t *testing.T) {
 	}
 }
 
+func TestGetNextTask_EmptySchedule(t *testing.T) {
+	sched := New(&config.Config{CycleDays: 7, Days: []config.Day{{ID: 1}}})
+	next, err := sched.GetNextTask(time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC))
+	if !errors.Is(err, ErrEmptySchedule) || next != nil {
+		t.Errorf("Expected ErrEmptySchedule, got %v, %v", next, err)
+	}
+}
+
 func TestCycleLogic(t *testing.T) {
 	// 3-day cycle
 	// Anchor: 2024-01-01 (Day 0)
@@ -127,3 +191,589 @@ func TestCycleLogic(t *testing.T) {
 		t.Errorf("expected Day 0 Task, got %v", task)
 	}
 }
+
+func TestAnchorDayID(t *testing.T) {
+	// 2024-01-01 was day 2 of a 3-day cycle, so 2024-01-02 starts a new one
+	cfg := &config.Config{CycleDays: 3, AnchorDate: "2024-01-01", AnchorDayID: 2}
+	sched := New(cfg)
+	for _, tt := range []struct {
+		day  int
+		want int
+	}{{1, 2}, {2, 0}, {4, 2}, {5, 0}} {
+		got, err := sched.getCycleDayID(time.Date(2024, 1, tt.day, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("2024-01-%02d: expected day %d, got %d", tt.day, tt.want, got)
+		}
+	}
+	// Dates before the anchor wrap around
+	if got, _ := sched.getCycleDayID(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)); got != 1 {
+		t.Errorf("2023-12-31: expected day 1, got %d", got)
+	}
+}
+
+func TestCycleStart(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *config.Config
+		date time.Time
+		want time.Time
+	}{
+		// Wednesday, in the week starting Sunday Dec 31
+		{"week", &config.Config{CycleDays: 7}, time.Date(2024, 1, 3, 15, 0, 0, 0, time.UTC), time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{"cycle", &config.Config{CycleDays: 3, AnchorDate: "2024-01-01"}, time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"before_anchor", &config.Config{CycleDays: 3, AnchorDate: "2024-01-01"}, time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 29, 0, 0, 0, 0, time.UTC)},
+		// Overrides don't move the cycle
+		{"override", &config.Config{CycleDays: 3, AnchorDate: "2024-01-01", Overrides: []config.Override{{
+			Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), IsOff: true,
+		}}}, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.cfg).CycleStart(tt.date)
+			if err != nil {
+				t.Fatalf("CycleStart() returned error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetDayBounds(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			// Monday: a single task
+			{ID: 1, Tasks: []config.Task{{Name: "Math", Start: "09:00", End: "10:00"}}},
+			// Tuesday: unordered, with empty slots at both ends
+			{ID: 2, Tasks: []config.Task{
+				{Name: "/", Start: "22:00", End: "23:00"},
+				{Name: "Gym", Start: "17:30", End: "18:30"},
+				{Name: "Standup", Start: "08:30", End: "09:00"},
+				{Name: "/", Start: "07:00", End: "08:00"},
+			}},
+			{ID: 3, Tasks: []config.Task{{Name: "Art", Start: "11:00", End: "12:00"}}},
+		},
+		Overrides: []config.Override{{
+			// Wednesday Jan 3, 2024 is off
+			DateStr: "2024-01-03",
+			IsOff:   true,
+			Date:    time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			EndDate: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+	sched := New(cfg)
+
+	tests := []struct {
+		name        string
+		date        time.Time
+		first, last string
+	}{
+		{name: "single_task", date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), first: "Math", last: "Math"},
+		{name: "ignores_empty_slots", date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), first: "Standup", last: "Gym"},
+		{name: "off_day", date: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{name: "empty", date: time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, last, err := sched.GetDayBounds(tt.date)
+			if err != nil {
+				t.Fatalf("GetDayBounds() returned error: %v", err)
+			}
+			if tt.first == "" {
+				if first != nil || last != nil {
+					t.Errorf("Expected no bounds, got %v, %v", first, last)
+				}
+				return
+			}
+			if first == nil || last == nil || first.Name != tt.first || last.Name != tt.last {
+				t.Errorf("Expected %s..%s, got %v..%v", tt.first, tt.last, first, last)
+			}
+		})
+	}
+}
+
+func TestGetTasksForDay(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 1, Tasks: []config.Task{{Name: "Art", Start: "11:00", End: "12:00"}, {Name: "Math", Start: "09:00", End: "10:00"}}},
+		},
+		Overrides: []config.Override{{
+			// Thursday Jan 4, 2024 follows the Monday schedule
+			DateStr:  "2024-01-04",
+			UseDayID: 1,
+			Date:     time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC),
+			EndDate:  time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+	sched := New(cfg)
+	date := time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	tasks, err := sched.GetTasksForDay(1, date)
+	if err != nil {
+		t.Fatalf("GetTasksForDay() returned error: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].Name != "Math" || tasks[1].Name != "Art" {
+		t.Fatalf("Expected Math, Art, got %+v", tasks)
+	}
+	if want := time.Date(2024, 1, 4, 9, 0, 0, 0, time.UTC); !tasks[0].StartTime.Equal(want) {
+		t.Errorf("Expected Math at %v, got %v", want, tasks[0].StartTime)
+	}
+
+	// The override of the date doesn't apply to another cycle day.
+	tasks, err = sched.GetTasksForDay(4, date)
+	if err != nil || len(tasks) != 0 {
+		t.Errorf("Expected no Thursday tasks, got %+v, %v", tasks, err)
+	}
+
+	if _, err := sched.GetTasksForDay(7, date); err == nil {
+		t.Error("Expected an error for a day outside the cycle")
+	}
+}
+
+func TestGetDayContext(t *testing.T) {
+	sched := New(&config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Math", Start: "09:00", End: "10:00"},
+			{Name: "Art", Start: "11:00", End: "12:00"},
+		}}},
+	})
+	// Monday Jan 1, 2024, between the tasks
+	c, err := sched.GetDayContext(time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetDayContext() returned error: %v", err)
+	}
+	if c.Previous == nil || c.Previous.Name != "Math" || c.Current != nil || c.Next == nil || c.Next.Name != "Art" || c.Day.DayID != 1 {
+		t.Errorf("Unexpected context %+v", c)
+	}
+
+	c, err = New(&config.Config{CycleDays: 7}).GetDayContext(time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC))
+	if err != nil || c.Next != nil {
+		t.Errorf("Expected an empty context for an empty schedule, got %+v, %v", c, err)
+	}
+}
+
+func TestGetNextBoundary(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			// Monday: Lab overlaps the end of Math
+			{ID: 1, Tasks: []config.Task{
+				{Name: "Math", Start: "09:00", End: "10:00"},
+				{Name: "Lab", Start: "09:30", End: "11:00"},
+				{Name: "Art", Start: "13:00", End: "14:00"},
+			}},
+		},
+	}
+	sched := New(cfg)
+	at := func(h, m int) time.Time { return time.Date(2024, 1, 1, h, m, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name string
+		now  time.Time
+		at   time.Time
+		kind BoundaryKind
+		task string
+	}{
+		{name: "next_starts_first", now: at(9, 10), at: at(9, 30), kind: BoundaryStart, task: "Lab"},
+		{name: "current_ends", now: at(10, 30), at: at(11, 0), kind: BoundaryEnd, task: "Lab"},
+		{name: "free", now: at(11, 30), at: at(13, 0), kind: BoundaryStart, task: "Art"},
+		{name: "at_start", now: at(13, 0), at: at(14, 0), kind: BoundaryEnd, task: "Art"},
+		{name: "next_week", now: at(14, 0), at: time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC), kind: BoundaryStart, task: "Math"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, ok, err := sched.GetNextBoundary(tt.now)
+			if err != nil {
+				t.Fatalf("GetNextBoundary() returned error: %v", err)
+			}
+			if !ok || !b.At.Equal(tt.at) || b.Kind != tt.kind || b.Task.Name != tt.task {
+				t.Errorf("Expected %s of %s at %v, got %+v (ok %v)", tt.kind, tt.task, tt.at, b, ok)
+			}
+		})
+	}
+
+	if _, ok := NextBoundary(nil, nil, at(9, 0)); ok {
+		t.Error("Expected no boundary without tasks")
+	}
+}
+
+func TestGetUsage(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		DayWindow: config.DayWindows{"default": "08:00-18:00"},
+		Days: []config.Day{
+			// Monday: overlapping tasks and an empty slot
+			{ID: 1, Tasks: []config.Task{
+				{Name: "Math", Start: "09:00", End: "11:00"},
+				{Name: "Office hours", Start: "10:00", End: "12:00"},
+				{Name: "/", Start: "12:00", End: "13:00"},
+				{Name: "Gym", Start: "13:00", End: "14:00"},
+			}},
+			// Tuesday: tasks partly outside the window
+			{ID: 2, Tasks: []config.Task{
+				{Name: "Run", Start: "07:00", End: "08:30"},
+				{Name: "Reading", Start: "17:00", End: "19:00"},
+			}},
+			{ID: 3, Tasks: []config.Task{{Name: "Art", Start: "11:00", End: "12:00"}}},
+		},
+		Overrides: []config.Override{{
+			// Wednesday Jan 3, 2024 is off
+			DateStr: "2024-01-03",
+			IsOff:   true,
+			Date:    time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			EndDate: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	tests := []struct {
+		name      string
+		window    string
+		date      time.Time
+		scheduled time.Duration
+		windowLen time.Duration
+	}{
+		{name: "overlaps_merged", window: "08:00-18:00", date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), scheduled: 4 * time.Hour, windowLen: 10 * time.Hour},
+		{name: "clipped_to_window", window: "08:00-18:00", date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), scheduled: 90 * time.Minute, windowLen: 10 * time.Hour},
+		{name: "no_window", date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), scheduled: 210 * time.Minute},
+		{name: "off_day", window: "08:00-18:00", date: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), windowLen: 10 * time.Hour},
+		{name: "empty", window: "08:00-18:00", date: time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC), windowLen: 10 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.DayWindow = nil
+			if tt.window != "" {
+				cfg.DayWindow = config.DayWindows{"default": tt.window}
+			}
+			u, err := New(cfg).GetUsage(tt.date)
+			if err != nil {
+				t.Fatalf("GetUsage() returned error: %v", err)
+			}
+			if u.Scheduled != tt.scheduled || u.Window != tt.windowLen {
+				t.Errorf("Expected %v of %v, got %v of %v", tt.scheduled, tt.windowLen, u.Scheduled, u.Window)
+			}
+		})
+	}
+}
+
+func TestGetRemaining(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Math", Start: "09:00", End: "11:00"},
+			{Name: "Office hours", Start: "10:00", End: "12:00"},
+			{Name: "/", Start: "12:00", End: "13:00"},
+			{Name: "Gym", Start: "13:00", End: "14:00"},
+		}}},
+		Overrides: []config.Override{{
+			// Monday Jan 8, 2024 is off
+			DateStr: "2024-01-08",
+			IsOff:   true,
+			Date:    time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+			EndDate: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+	at := func(day, h, m int) time.Time { return time.Date(2024, 1, day, h, m, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name  string
+		now   time.Time
+		tasks int
+		left  time.Duration
+	}{
+		{name: "before_first", now: at(1, 8, 0), tasks: 3, left: 4 * time.Hour},
+		// Only the rest of Math counts, and its overlap with Office hours once
+		{name: "in_progress", now: at(1, 10, 30), tasks: 3, left: 150 * time.Minute},
+		{name: "empty_slot", now: at(1, 12, 30), tasks: 1, left: time.Hour},
+		{name: "all_done", now: at(1, 14, 0), tasks: 0},
+		{name: "off_day", now: at(8, 8, 0), tasks: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := New(cfg).GetRemaining(tt.now)
+			if err != nil {
+				t.Fatalf("GetRemaining() returned error: %v", err)
+			}
+			if r.Tasks != tt.tasks || r.Time != tt.left {
+				t.Errorf("Expected %d tasks (%v), got %d (%v)", tt.tasks, tt.left, r.Tasks, r.Time)
+			}
+		})
+	}
+}
+
+func TestFreeGaps(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2024, 1, 1, h, m, 0, 0, time.UTC) }
+	events := []TaskEvent{
+		{Name: "Gym", StartTime: at(14, 0), EndTime: at(15, 0)},
+		{Name: "Math", StartTime: at(9, 0), EndTime: at(11, 0)},
+		// Ends inside Math, so it doesn't open a gap at 10:30
+		{Name: "Quiz", StartTime: at(10, 0), EndTime: at(10, 30)},
+		{Name: "Lunch", StartTime: at(12, 0), EndTime: at(13, 0)},
+		// Back to back with Lunch
+		{Name: "Call", StartTime: at(13, 0), EndTime: at(13, 50)},
+	}
+
+	got := FreeGaps(events)
+	want := []Gap{{at(11, 0), at(12, 0)}, {at(13, 50), at(14, 0)}}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d gaps, got %d: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) {
+			t.Errorf("Gap %d: Expected %v-%v, got %v-%v", i, want[i].Start, want[i].End, got[i].Start, got[i].End)
+		}
+	}
+	if events[0].Name != "Gym" {
+		t.Error("Expected FreeGaps to leave the events' order alone")
+	}
+	if gaps := FreeGaps(nil); gaps != nil {
+		t.Errorf("Expected no gaps without events, got %v", gaps)
+	}
+}
+
+func TestClipGaps(t *testing.T) {
+	at := func(h, m int) time.Time { return time.Date(2024, 1, 1, h, m, 0, 0, time.UTC) }
+	gaps := []Gap{{at(6, 0), at(9, 0)}, {at(12, 0), at(13, 0)}, {at(20, 0), at(23, 0)}, {at(23, 0), at(23, 30)}}
+
+	got := ClipGaps(gaps, at(8, 0), at(22, 0))
+	want := []Gap{{at(8, 0), at(9, 0)}, {at(12, 0), at(13, 0)}, {at(20, 0), at(22, 0)}}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d gaps, got %v", len(want), got)
+	}
+	for i := range want {
+		if !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) {
+			t.Errorf("Gap %d: Expected %v-%v, got %v-%v", i, want[i].Start, want[i].End, got[i].Start, got[i].End)
+		}
+	}
+	if got := ClipGaps(gaps, time.Time{}, time.Time{}); len(got) != len(gaps) {
+		t.Errorf("Expected gaps unchanged without a window, got %v", got)
+	}
+}
+
+func TestAliases(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 1, Tasks: []config.Task{
+				{Name: "MATH101", Start: "09:00", End: "10:00"},
+				{Name: "/", Start: "10:00", End: "11:00"},
+				{Name: "HIST2B", Start: "11:00", End: "12:00"},
+			}},
+		},
+		// An alias for "/" must not turn the empty slot into a task
+		Aliases: map[string]string{"MATH*": "Maths", "/": "Free"},
+	}
+	sched := New(cfg)
+
+	current, err := sched.GetCurrentTask(time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current == nil || current.Name != "Maths" || current.RawName != "MATH101" {
+		t.Errorf("Expected Maths (MATH101), got %+v", current)
+	}
+
+	next, err := sched.GetNextTask(time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next == nil || next.Name != "HIST2B" || next.RawName != "HIST2B" {
+		t.Errorf("Expected unaliased HIST2B after the empty slot, got %+v", next)
+	}
+
+	current, err = sched.GetCurrentTask(time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current != nil {
+		t.Errorf("Expected no task in the empty slot, got %+v", current)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 1, Tasks: []config.Task{{Name: "Math", Start: "09:00", End: "10:00"}}},
+		},
+		Events: []config.Event{
+			{DateStr: "2024-01-01", Task: config.Task{Name: "Dentist", Start: "08:00", End: "08:30"}},
+			{DateStr: "2024-01-03", Task: config.Task{Name: "Fair", Start: "12:00", End: "13:00"}},
+		},
+		Overrides: []config.Override{{DateStr: "2024-01-03", IsOff: true}},
+	}
+	if err := cfg.ProcessOverrides(); err != nil {
+		t.Fatalf("ProcessOverrides() returned error: %v", err)
+	}
+	sched := New(cfg)
+
+	tests := []struct {
+		date time.Time
+		want []string
+	}{
+		{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), []string{"Dentist", "Math"}},
+		{time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC), []string{"Math"}},
+		// Off days drop their events
+		{time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), nil},
+	}
+	for _, tt := range tests {
+		events, err := sched.GetTasksForDate(tt.date)
+		if err != nil {
+			t.Fatalf("GetTasksForDate() returned error: %v", err)
+		}
+		var got []string
+		for _, e := range events {
+			got = append(got, e.Name)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Expected %v, got %v", tt.date.Format("2006-01-02"), tt.want, got)
+		}
+	}
+	if n := len(cfg.Days[0].Tasks); n != 1 {
+		t.Errorf("Expected the day's tasks to stay untouched, got %d", n)
+	}
+}
+
+func TestMergeAdjacent(t *testing.T) {
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Math", Start: "09:50", End: "10:40"},
+			{Name: "Math", Start: "09:00", End: "09:50"},
+			{Name: "Math", Start: "10:43", End: "11:30"},
+			{Name: "/", Start: "11:30", End: "12:00"},
+			{Name: "/", Start: "12:00", End: "12:30"},
+			// Overlapping, so not merged
+			{Name: "Art", Start: "13:00", End: "14:00"},
+			{Name: "Art", Start: "13:30", End: "14:30"},
+			{Name: "Music", Start: "15:00", End: "16:00"},
+			{Name: "Music", Start: "16:10", End: "17:00"},
+		}}},
+		Events: []config.Event{{Task: config.Task{Name: "Music", Start: "17:00", End: "18:00"}, Date: monday}},
+	}
+	spans := func(date time.Time) []string {
+		events, err := New(cfg).GetTasksForDate(date)
+		if err != nil {
+			t.Fatalf("GetTasksForDate() returned error: %v", err)
+		}
+		var got []string
+		for _, e := range events {
+			got = append(got, fmt.Sprintf("%s %s-%s/%d", e.Name, e.StartTime.Format("15:04"), e.EndTime.Format("15:04"), len(e.Segments)))
+		}
+		return got
+	}
+
+	if got := spans(monday); len(got) != 10 {
+		t.Errorf("Expected no merging by default, got %v", got)
+	}
+	cfg.MergeAdjacent = true
+	want := []string{"Math 09:00-10:40/2", "Math 10:43-11:30/0", "/ 11:30-12:00/0", "/ 12:00-12:30/0", "Art 13:00-14:00/0", "Art 13:30-14:30/0", "Music 15:00-16:00/0", "Music 16:10-18:00/2"}
+	if got := spans(monday); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	cfg.MergeTolerance = "5m"
+	want = []string{"Math 09:00-11:30/3", "/ 11:30-12:00/0", "/ 12:00-12:30/0", "Art 13:00-14:00/0", "Art 13:30-14:30/0", "Music 15:00-16:00/0", "Music 16:10-17:00/0"}
+	if got := spans(monday.AddDate(0, 0, 7)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// Queries see one task across the merged boundaries
+	sched := New(cfg)
+	current, err := sched.GetCurrentTask(time.Date(2024, 1, 1, 10, 41, 0, 0, time.UTC))
+	if err != nil || current == nil {
+		t.Fatalf("Expected a current task, got %v, %v", current, err)
+	}
+	if current.StartTime.Hour() != 9 || current.EndTime.Hour() != 11 {
+		t.Errorf("Expected the merged Math task, got %s-%s", current.StartTime.Format("15:04"), current.EndTime.Format("15:04"))
+	}
+	wantSegments := []Segment{
+		{time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 9, 50, 0, 0, time.UTC)},
+		{time.Date(2024, 1, 1, 9, 50, 0, 0, time.UTC), time.Date(2024, 1, 1, 10, 40, 0, 0, time.UTC)},
+		{time.Date(2024, 1, 1, 10, 43, 0, 0, time.UTC), time.Date(2024, 1, 1, 11, 30, 0, 0, time.UTC)},
+	}
+	if !reflect.DeepEqual(current.Segments, wantSegments) {
+		t.Errorf("Expected segments %v, got %v", wantSegments, current.Segments)
+	}
+	next, err := sched.GetNextTask(time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC))
+	if err != nil || next == nil || next.Name != "Art" {
+		t.Errorf("Expected Art next, got %v, %v", next, err)
+	}
+}
+
+func TestMergeAdjacent_SharedSegments(t *testing.T) {
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cfg := &config.Config{
+		CycleDays:     7,
+		MergeAdjacent: true,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Math", Start: "09:00", End: "10:00"},
+			{Name: "Math", Start: "10:00", End: "11:00"},
+			{Name: "Math", Start: "11:00", End: "11:30"},
+		}}},
+		Events: []config.Event{
+			{Task: config.Task{Name: "Math", Start: "11:30", End: "12:00"}, Date: monday},
+			{Task: config.Task{Name: "Math", Start: "11:30", End: "12:30"}, Date: monday.AddDate(0, 0, 7)},
+		},
+	}
+	s := New(cfg)
+	// Both dates merge their event into the cached merged slot of the day
+	first := s.scheduleOn(monday, 1)
+	s.scheduleOn(monday.AddDate(0, 0, 7), 1)
+	if got := first.slots[0].segments; len(got) != 4 || got[3] != [2]int{11*60 + 30, 12 * 60} {
+		t.Errorf("Expected the first date's segments to stay intact, got %v", got)
+	}
+}
+
+func TestTmpDate_Inline(t *testing.T) {
+	cfg, err := config.ParseInline("07:00-08:00 A; 09:00-10:00 B")
+	if err != nil {
+		t.Fatalf("ParseInline() returned error: %v", err)
+	}
+	s := New(cfg)
+	at := func(h int) time.Time { return cfg.TmpDate.Add(time.Duration(h) * time.Hour) }
+
+	// -n after the last task and -p before the first find nothing rather
+	// than the same tasks a week away
+	if next, err := s.GetNextTask(at(12)); err != nil || next != nil {
+		t.Errorf("Expected no next task after today's last, got %v, %v", next, err)
+	}
+	if prev, err := s.GetPreviousTask(at(6)); err != nil || prev != nil {
+		t.Errorf("Expected no previous task before today's first, got %v, %v", prev, err)
+	}
+	if next, err := s.GetNextTask(at(6)); err != nil || next == nil || next.Name != "A" {
+		t.Errorf("Expected A next, got %v, %v", next, err)
+	}
+	if prev, err := s.GetPreviousTask(at(12)); err != nil || prev == nil || prev.Name != "B" {
+		t.Errorf("Expected B previous, got %v, %v", prev, err)
+	}
+	if tasks, err := s.GetTasksForDate(cfg.TmpDate.AddDate(0, 0, 7)); err != nil || len(tasks) != 0 {
+		t.Errorf("Expected no tasks a week later, got %v, %v", tasks, err)
+	}
+}
+
+func TestTmpDate_PreviousTask(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "t.csv")
+	if err := os.WriteFile(path, []byte("Start,End,Task\n09:00,10:00,Early\n20:00,21:00,Late\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadTmpCSV(path)
+	if err != nil {
+		t.Fatalf("LoadTmpCSV() returned error: %v", err)
+	}
+	s := New(cfg)
+
+	// Before today's first task, last week's Late doesn't count
+	if prev, err := s.GetPreviousTask(cfg.TmpDate.Add(8 * time.Hour)); err != nil || prev != nil {
+		t.Errorf("Expected no previous task, got %v, %v", prev, err)
+	}
+	if prev, err := s.GetPreviousTask(cfg.TmpDate.Add(12 * time.Hour)); err != nil || prev == nil || prev.Name != "Early" {
+		t.Errorf("Expected Early previous, got %v, %v", prev, err)
+	}
+}
@@ -0,0 +1,229 @@
+package scheduler
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+// slot is a task of the config with its display name, icon and times
+// resolved once, when the Scheduler is built.
+type slot struct {
+	task       config.Task
+	name, icon string
+	source     string   // task.Source formatted
+	start, end int      // minutes after midnight
+	err        error    // why the start or end time didn't parse
+	segments   [][2]int // start and end of each task merged into the slot
+}
+
+// newSlot resolves t against the aliases and icons of s.
+func (s *Scheduler) newSlot(t config.Task) slot {
+	sl := slot{task: t, name: s.cfg.DisplayName(t.Name), icon: s.cfg.Icon(t), source: t.Source.String()}
+	var err error
+	if sl.start, err = clock(t.Start); err != nil {
+		sl.err = fmt.Errorf("%s start: %w", t.Describe(), err)
+	} else if sl.end, err = clock(t.End); err != nil {
+		sl.err = fmt.Errorf("%s end: %w", t.Describe(), err)
+	}
+	return sl
+}
+
+// clock parses "HH:MM" into minutes after midnight.
+func clock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// times returns the start and end of the slot on date.
+func (sl *slot) times(date time.Time) (start, end time.Time) {
+	y, m, d := date.Date()
+	start = time.Date(y, m, d, sl.start/60, sl.start%60, 0, 0, date.Location())
+	end = time.Date(y, m, d, sl.end/60, sl.end%60, 0, 0, date.Location())
+	return start, end
+}
+
+// event builds the task instance of the slot between start and end.
+func (sl *slot) event(start, end time.Time) TaskEvent {
+	var segments []Segment
+	if len(sl.segments) > 0 {
+		segments = make([]Segment, len(sl.segments))
+		for i, seg := range sl.segments {
+			segments[i] = Segment{
+				StartTime: start.Add(time.Duration(seg[0]-sl.start) * time.Minute),
+				EndTime:   start.Add(time.Duration(seg[1]-sl.start) * time.Minute),
+			}
+		}
+	}
+	return TaskEvent{
+		Name:      sl.name,
+		RawName:   sl.task.Name,
+		StartTime: start,
+		EndTime:   end,
+		Color:     sl.task.Color,
+		Pomodoro:  sl.task.Pomodoro,
+		Icon:      sl.icon,
+		Tags:      sl.task.Tags,
+		URL:       sl.task.URL,
+		Location:  sl.task.Location,
+		Source:    sl.source,
+		Segments:  segments,
+	}
+}
+
+// daySchedule holds the slots of one day in config order, with the orders
+// the queries walk them in.
+type daySchedule struct {
+	slots   []slot
+	byStart []int // indexes into slots, earliest start first
+	byEnd   []int // indexes into slots, latest end first
+	err     error // the first slot error, reported by every query of the day
+}
+
+// eventsOn returns the task instances of the day on date, earliest start
+// first.
+func (d *daySchedule) eventsOn(date time.Time) ([]TaskEvent, error) {
+	if d.err != nil {
+		return nil, fmt.Errorf("invalid time in config: %w", d.err)
+	}
+	if len(d.slots) == 0 {
+		return nil, nil
+	}
+	events := make([]TaskEvent, 0, len(d.slots))
+	for _, i := range d.byStart {
+		sl := &d.slots[i]
+		start, end := sl.times(date)
+		events = append(events, sl.event(start, end))
+	}
+	return events, nil
+}
+
+// noSlots is the schedule of off days and days without a block.
+var noSlots = &daySchedule{}
+
+// newDaySchedule sorts slots. Ties keep config order.
+func newDaySchedule(slots []slot) *daySchedule {
+	d := &daySchedule{slots: slots, byStart: make([]int, len(slots)), byEnd: make([]int, len(slots))}
+	for i := range slots {
+		d.byStart[i], d.byEnd[i] = i, i
+		if d.err == nil {
+			d.err = slots[i].err
+		}
+	}
+	slices.SortStableFunc(d.byStart, func(a, b int) int { return cmp.Compare(slots[a].start, slots[b].start) })
+	slices.SortStableFunc(d.byEnd, func(a, b int) int { return cmp.Compare(slots[b].end, slots[a].end) })
+	return d
+}
+
+// newDaySchedule sorts slots, first merging adjacent ones if merge_adjacent
+// is on.
+func (s *Scheduler) newDaySchedule(slots []slot) *daySchedule {
+	if s.mergeGap >= 0 {
+		slots = mergeAdjacent(slots, s.mergeGap)
+	}
+	return newDaySchedule(slots)
+}
+
+// mergeAdjacent merges each run of slots with the same name, where every one
+// starts at most gap minutes after the previous one ends, into one slot
+// spanning the run that keeps the details of its first task. Slots only
+// merge with the one starting just before them, and not if they overlap it.
+// The merged slots are in start order; slots are returned as they are if
+// nothing merges or a time didn't parse.
+func mergeAdjacent(slots []slot, gap int) []slot {
+	order := make([]int, len(slots))
+	for i := range slots {
+		if slots[i].err != nil {
+			return slots
+		}
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(slots[a].start, slots[b].start) })
+
+	merged := make([]slot, 0, len(slots))
+	for _, i := range order {
+		sl := slots[i]
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			if d := sl.start - last.end; sl.task.Name == last.task.Name && sl.task.Name != "/" && d >= 0 && d <= gap {
+				if last.segments == nil {
+					last.segments = [][2]int{{last.start, last.end}}
+				} else {
+					// A slot merged before, shared with a cached day schedule
+					last.segments = slices.Clone(last.segments)
+				}
+				last.segments = append(last.segments, [2]int{sl.start, sl.end})
+				last.end, last.task.End = sl.end, sl.task.End
+				continue
+			}
+		}
+		merged = append(merged, sl)
+	}
+	if len(merged) == len(slots) {
+		return slots
+	}
+	return merged
+}
+
+// dateKey identifies a calendar date regardless of location.
+type dateKey struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func keyOf(t time.Time) dateKey {
+	y, m, d := t.Date()
+	return dateKey{y, m, d}
+}
+
+// scheduleOn returns the tasks of cycle day dayID, adjusted by the rules
+// matching date unless an override covers it, followed by the events on
+// date. Only dates with events or rules build a new schedule. Temporary
+// schedules have no tasks outside their TmpDate.
+func (s *Scheduler) scheduleOn(date time.Time, dayID int) *daySchedule {
+	if dayID == -1 || s.tmpDate != (dateKey{}) && keyOf(date) != s.tmpDate {
+		return noSlots
+	}
+	day := s.days[dayID]
+	if day == nil {
+		day = noSlots
+	}
+	var rules []config.Rule
+	if len(s.cfg.Rules) > 0 && s.cfg.EffectiveOverride(date) == nil {
+		rules = s.cfg.MatchingRules(date, dayID)
+	}
+	events := s.events[keyOf(date)]
+	if len(events) == 0 && len(rules) == 0 {
+		return day
+	}
+	slots := slices.Clip(day.slots)
+	if len(rules) > 0 {
+		tasks := config.ApplyRules(rules, s.dayTasks(dayID), s.dayTasks)
+		slots = make([]slot, len(tasks), len(tasks)+len(events))
+		for i, t := range tasks {
+			slots[i] = s.newSlot(t)
+		}
+	}
+	return s.newDaySchedule(append(slots, events...))
+}
+
+// dayTasks returns the tasks of cycle day id as written in the config, or
+// as merged by merge_adjacent.
+func (s *Scheduler) dayTasks(id int) []config.Task {
+	day := s.days[id]
+	if day == nil {
+		return nil
+	}
+	tasks := make([]config.Task, len(day.slots))
+	for i, sl := range day.slots {
+		tasks[i] = sl.task
+	}
+	return tasks
+}
@@ -0,0 +1,87 @@
+// Package sdnotify implements the systemd service notification protocol
+// (sd_notify) without cgo: state lines are sent as datagrams to the socket
+// named by NOTIFY_SOCKET.
+package sdnotify
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Notifier sends state updates to the service manager. A nil Notifier,
+// returned when the process doesn't run under systemd, ignores all calls.
+type Notifier struct {
+	conn *net.UnixConn
+}
+
+// New connects to the socket named by NOTIFY_SOCKET as read through getenv.
+// It returns nil if the variable is unset.
+func New(getenv func(string) string) (*Notifier, error) {
+	path := getenv("NOTIFY_SOCKET")
+	if path == "" {
+		return nil, nil
+	}
+	// Go maps a leading '@' to the abstract namespace, as systemd intends.
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{conn: conn}, nil
+}
+
+// Notify sends the given assignments, e.g. "READY=1" and "STATUS=...", in a
+// single datagram.
+func (n *Notifier) Notify(state ...string) error {
+	if n == nil {
+		return nil
+	}
+	_, err := n.conn.Write([]byte(strings.Join(state, "\n")))
+	return err
+}
+
+// Ready reports that startup is complete, together with an optional status.
+func (n *Notifier) Ready(status string) error {
+	if status == "" {
+		return n.Notify("READY=1")
+	}
+	return n.Notify("READY=1", "STATUS="+status)
+}
+
+// Status updates the free-form status shown by systemctl status.
+func (n *Notifier) Status(status string) error {
+	return n.Notify("STATUS=" + status)
+}
+
+// Stopping reports that the service is shutting down.
+func (n *Notifier) Stopping() error {
+	return n.Notify("STOPPING=1")
+}
+
+// Watchdog tells the service manager the process is still alive.
+func (n *Notifier) Watchdog() error {
+	return n.Notify("WATCHDOG=1")
+}
+
+// Close closes the connection.
+func (n *Notifier) Close() error {
+	if n == nil {
+		return nil
+	}
+	return n.conn.Close()
+}
+
+// WatchdogInterval returns how often the process with the given pid should
+// send Watchdog: half of WATCHDOG_USEC, or 0 if the watchdog is disabled or
+// meant for another process (WATCHDOG_PID).
+func WatchdogInterval(getenv func(string) string, pid int) time.Duration {
+	usec, err := strconv.ParseInt(getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0
+	}
+	if p := getenv("WATCHDOG_PID"); p != "" && p != strconv.Itoa(pid) {
+		return 0
+	}
+	return time.Duration(usec) * time.Microsecond / 2
+}
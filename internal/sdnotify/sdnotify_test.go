@@ -0,0 +1,96 @@
+package sdnotify
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// listen returns a fake service manager socket and a getenv pointing at it.
+func listen(t *testing.T) (*net.UnixConn, func(string) string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notify.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Fatalf("ListenUnixgram() returned error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, func(key string) string {
+		if key == "NOTIFY_SOCKET" {
+			return path
+		}
+		return ""
+	}
+}
+
+func receive(t *testing.T, conn *net.UnixConn) string {
+	t.Helper()
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("Read() returned error: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestNotifier(t *testing.T) {
+	conn, getenv := listen(t)
+	n, err := New(getenv)
+	if err != nil || n == nil {
+		t.Fatalf("New() = %v, %v", n, err)
+	}
+	defer n.Close()
+
+	n.Ready("Current: Math until 10:00")
+	n.Status("No task; next: Art at 13:00")
+	n.Watchdog()
+	n.Ready("")
+	n.Stopping()
+
+	for _, want := range []string{
+		"READY=1\nSTATUS=Current: Math until 10:00",
+		"STATUS=No task; next: Art at 13:00",
+		"WATCHDOG=1",
+		"READY=1",
+		"STOPPING=1",
+	} {
+		if got := receive(t, conn); got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	}
+}
+
+func TestNew_Unset(t *testing.T) {
+	n, err := New(func(string) string { return "" })
+	if n != nil || err != nil {
+		t.Fatalf("Expected nil notifier without NOTIFY_SOCKET, got %v, %v", n, err)
+	}
+	// A nil notifier ignores calls
+	if err := n.Ready("ok"); err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want time.Duration
+	}{
+		{name: "unset", env: map[string]string{}, want: 0},
+		{name: "half", env: map[string]string{"WATCHDOG_USEC": "30000000"}, want: 15 * time.Second},
+		{name: "our_pid", env: map[string]string{"WATCHDOG_USEC": "30000000", "WATCHDOG_PID": "42"}, want: 15 * time.Second},
+		{name: "other_pid", env: map[string]string{"WATCHDOG_USEC": "30000000", "WATCHDOG_PID": "7"}, want: 0},
+		{name: "invalid", env: map[string]string{"WATCHDOG_USEC": "soon"}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WatchdogInterval(func(k string) string { return tt.env[k] }, 42)
+			if got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
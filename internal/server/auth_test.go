@@ -0,0 +1,54 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_Token(t *testing.T) {
+	srv := newTestServer(t)
+	srv.Token = "s3cret"
+
+	tests := []struct {
+		name   string
+		target string
+		header string
+		want   int
+	}{
+		{name: "authorized", target: "/current", header: "Bearer s3cret", want: http.StatusOK},
+		{name: "query_token", target: "/?token=s3cret", want: http.StatusOK},
+		{name: "missing", target: "/current", want: http.StatusUnauthorized},
+		{name: "wrong", target: "/day", header: "Bearer guess", want: http.StatusUnauthorized},
+		{name: "not_bearer", target: "/day", header: "Basic s3cret", want: http.StatusUnauthorized},
+		{name: "healthz_without_token", target: "/healthz", want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("Expected %d, got %d", tt.want, rec.Code)
+			}
+			if rec.Code == http.StatusUnauthorized && rec.Body.Len() != 0 {
+				t.Errorf("Expected no body with 401, got %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestLogRequests_KeepsStatus(t *testing.T) {
+	h := LogRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("Expected %d, got %d", http.StatusTeapot, rec.Code)
+	}
+}
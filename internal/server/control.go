@@ -0,0 +1,44 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Control holds the actions a running watch process offers on its control
+// socket in addition to the read-only endpoints.
+type Control struct {
+	// Reload reloads the config and swaps the scheduler.
+	Reload func() error
+	// NotifyTest sends a test notification through the configured backends.
+	NotifyTest func() error
+}
+
+// ControlHandler returns the endpoints of Handler plus POST /reload and
+// POST /notify-test.
+func (s *Server) ControlHandler(c Control) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", s.Handler())
+	mux.HandleFunc("/reload", handleAction(c.Reload))
+	mux.HandleFunc("/notify-test", handleAction(c.NotifyTest))
+	return mux
+}
+
+func handleAction(action func() error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		if action == nil {
+			writeError(w, http.StatusNotImplemented, fmt.Errorf("%s is not supported", r.URL.Path))
+			return
+		}
+		if err := action(); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func TestControlHandler(t *testing.T) {
+	srv := newTestServer(t)
+	reloads := 0
+	handler := srv.ControlHandler(Control{
+		Reload: func() error {
+			reloads++
+			return nil
+		},
+		NotifyTest: func() error { return errors.New("notify-send not found") },
+	})
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		wantCode int
+		wantBody string
+	}{
+		{name: "reload", method: http.MethodPost, target: "/reload", wantCode: http.StatusOK, wantBody: `"status": "ok"`},
+		{name: "reload_get", method: http.MethodGet, target: "/reload", wantCode: http.StatusMethodNotAllowed},
+		{name: "notify_test_error", method: http.MethodPost, target: "/notify-test", wantCode: http.StatusInternalServerError, wantBody: "notify-send not found"},
+		{name: "read_only_endpoint", method: http.MethodGet, target: "/current", wantCode: http.StatusOK, wantBody: `"name": "Math"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+			if rec.Code != tt.wantCode {
+				t.Errorf("Expected %d, got %d", tt.wantCode, rec.Code)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("Expected body to contain %q, got %s", tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+	if reloads != 1 {
+		t.Errorf("Expected 1 reload, got %d", reloads)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.ControlHandler(Control{}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/reload", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Errorf("Expected 501 without a reload action, got %d", rec.Code)
+	}
+}
+
+func TestControlHandler_ConcurrentReload(t *testing.T) {
+	srv := newTestServer(t)
+	cfg := srv.Scheduler().Config()
+	handler := srv.ControlHandler(Control{
+		Reload: func() error {
+			srv.SetScheduler(scheduler.New(cfg))
+			return nil
+		},
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/reload", nil))
+		}()
+		go func() {
+			defer wg.Done()
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/current", nil))
+			if rec.Code != http.StatusOK {
+				t.Errorf("Expected 200 during reloads, got %d", rec.Code)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if srv.Scheduler().Config() != cfg {
+		t.Errorf("Expected the reloaded scheduler to use the same config")
+	}
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/output"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// pageRefresh is how often, in seconds, the schedule page reloads itself.
+const pageRefresh = 60
+
+//go:embed assets/page.html
+var assets embed.FS
+
+var pageTemplate = template.Must(template.ParseFS(assets, "assets/page.html"))
+
+// pageData is what the schedule page shows.
+type pageData struct {
+	Date    string
+	DayName string
+	IsOff   bool
+	Note    string
+	Tasks   []pageTask
+	Current *pageTask
+	Next    *pageNext
+	Refresh int
+}
+
+// pageTask is a row of the day table.
+type pageTask struct {
+	output.ExtendedTaskEvent
+	Time string // "09:00–10:00" on the configured clock
+}
+
+// pageNext is the next-task countdown.
+type pageNext struct {
+	Name string
+	Time string
+	In   string
+}
+
+// handlePage serves a read-only HTML page with today's schedule.
+func (s *Server) handlePage(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r) {
+		return
+	}
+	data, err := s.pageData(s.Now())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, data); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+func (s *Server) pageData(now time.Time) (pageData, error) {
+	sched := s.Scheduler()
+	cfg := sched.Config()
+	day, err := output.LoadDay(sched, now)
+	if err != nil {
+		return pageData{}, err
+	}
+	current, err := sched.GetCurrentTask(now)
+	if err != nil {
+		return pageData{}, err
+	}
+	next, err := sched.GetNextTask(now)
+	if err != nil && !errors.Is(err, scheduler.ErrEmptySchedule) {
+		return pageData{}, err
+	}
+
+	data := pageData{
+		Date:    now.Format("2006-01-02"),
+		DayName: day.Name,
+		IsOff:   day.Info.IsOff,
+		Note:    day.Info.Note,
+		Refresh: pageRefresh,
+	}
+	for i, t := range output.ExtendTasks(day.Tasks, current, now) {
+		row := pageTask{
+			ExtendedTaskEvent: t,
+			Time:              cfg.Clock.Format(day.Tasks[i].StartTime) + "–" + cfg.Clock.Format(day.Tasks[i].EndTime),
+		}
+		data.Tasks = append(data.Tasks, row)
+	}
+	for i := range data.Tasks {
+		if data.Tasks[i].IsCurrent {
+			data.Current = &data.Tasks[i]
+		}
+	}
+	if next != nil {
+		durations := config.Durations{Style: cfg.DurationStyle}
+		at := cfg.Clock.Format(next.StartTime)
+		if !sameDate(next.StartTime, now) {
+			at = next.StartTime.Format("Mon ") + at
+		}
+		data.Next = &pageNext{Name: next.Name, Time: at, In: durations.Left(next.StartTime.Sub(now))}
+	}
+	return data, nil
+}
+
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandlePage(t *testing.T) {
+	srv := newTestServer(t)
+	rec := get(t, srv, "/")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Errorf("Unexpected Content-Type %q", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"<h1>Monday</h1>",
+		"2024-01-01",
+		`<tr class="current"><td class="time">09:00–10:00</td><td>Math</td></tr>`,
+		// Tuesday is off and Wednesday follows Monday
+		"Next: <strong>Math</strong> at Wed 09:00, in 47h30m",
+		`http-equiv="refresh"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected page to contain %q, got:\n%s", want, body)
+		}
+	}
+
+	// Tuesday Jan 2, 2024 is an off day
+	srv.Now = func() time.Time { return time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC) }
+	body = get(t, srv, "/").Body.String()
+	if !strings.Contains(body, `<div class="banner">Off day</div>`) || !strings.Contains(body, "No tasks today.") {
+		t.Errorf("Expected off-day banner and no tasks, got:\n%s", body)
+	}
+}
+
+func TestHandlePage_UnknownPath(t *testing.T) {
+	if rec := get(t, newTestServer(t), "/nope"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,196 @@
+// Package server exposes the schedule over HTTP as JSON, plus a read-only
+// HTML page of today's schedule at /.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/output"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// maxRangeDays caps how many days a single /range request may cover.
+const maxRangeDays = 366
+
+// Server answers schedule queries over HTTP.
+type Server struct {
+	sched *scheduler.Holder
+
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+	// Token, if set, must accompany every request except /healthz.
+	Token string
+}
+
+// New creates a Server backed by sched.
+func New(sched *scheduler.Scheduler) *Server {
+	return NewShared(scheduler.NewHolder(sched))
+}
+
+// NewShared creates a Server answering from whatever Scheduler h holds, so
+// a reload that swaps it elsewhere also applies to requests.
+func NewShared(h *scheduler.Holder) *Server {
+	return &Server{sched: h, Now: time.Now}
+}
+
+// SetScheduler swaps the scheduler used for subsequent requests, e.g. after a config reload.
+func (s *Server) SetScheduler(sched *scheduler.Scheduler) {
+	s.sched.Store(sched)
+}
+
+// Scheduler returns the scheduler currently in use.
+func (s *Server) Scheduler() *scheduler.Scheduler {
+	return s.sched.Load()
+}
+
+// Handler returns the HTTP handler serving all endpoints.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/{$}", s.handlePage)
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/current", s.handleTask((*scheduler.Scheduler).GetCurrentTask))
+	mux.HandleFunc("/next", s.handleTask((*scheduler.Scheduler).GetNextTask))
+	mux.HandleFunc("/previous", s.handleTask((*scheduler.Scheduler).GetPreviousTask))
+	mux.HandleFunc("/day", s.handleDay)
+	mux.HandleFunc("/range", s.handleRange)
+	if s.Token != "" {
+		return RequireToken(s.Token, mux)
+	}
+	return mux
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleTask(lookup func(*scheduler.Scheduler, time.Time) (*scheduler.TaskEvent, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r) {
+			return
+		}
+		task, err := lookup(s.Scheduler(), s.Now())
+		if errors.Is(err, scheduler.ErrEmptySchedule) {
+			// Nothing is next in a schedule without tasks
+			task, err = nil, nil
+		}
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, output.NewJSONTask(task))
+	}
+}
+
+func (s *Server) handleDay(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r) {
+		return
+	}
+	now := s.Now()
+	date := now
+	if raw := r.URL.Query().Get("date"); raw != "" {
+		var err error
+		date, err = parseDate(raw, now.Location())
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	day, err := s.daySchedule(date, now)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, day)
+}
+
+func (s *Server) handleRange(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r) {
+		return
+	}
+	now := s.Now()
+	q := r.URL.Query()
+	if q.Get("from") == "" || q.Get("to") == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("both 'from' and 'to' are required"))
+		return
+	}
+	from, err := parseDate(q.Get("from"), now.Location())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	to, err := parseDate(q.Get("to"), now.Location())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if to.Before(from) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("'to' cannot be before 'from'"))
+		return
+	}
+
+	days := []output.JSONDay{}
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if len(days) >= maxRangeDays {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("range cannot exceed %d days", maxRangeDays))
+			return
+		}
+		day, err := s.daySchedule(d, now)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		days = append(days, day)
+	}
+	writeJSON(w, http.StatusOK, days)
+}
+
+func (s *Server) daySchedule(date, now time.Time) (output.JSONDay, error) {
+	sched := s.Scheduler()
+	day, err := output.LoadDay(sched, date)
+	if err != nil {
+		return output.JSONDay{}, err
+	}
+	current, err := sched.GetCurrentTask(now)
+	if err != nil {
+		return output.JSONDay{}, err
+	}
+	return output.NewJSONDay(*day, current, now), nil
+}
+
+func parseDate(raw string, loc *time.Location) (time.Time, error) {
+	t, err := time.ParseInLocation("2006-01-02", raw, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s' (expected YYYY-MM-DD)", raw)
+	}
+	return t, nil
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return true
+	}
+	w.Header().Set("Allow", "GET, HEAD")
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
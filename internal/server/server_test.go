@@ -0,0 +1,150 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/output"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 1, Tasks: []config.Task{{Name: "Math", Start: "09:00", End: "10:00"}}},
+			{ID: 2, Tasks: []config.Task{{Name: "History", Start: "11:00", End: "12:00"}}},
+		},
+		Overrides: []config.Override{
+			// Tuesday Jan 2, 2024 -> OFF
+			{DateStr: "2024-01-02", IsOff: true},
+			// Wednesday Jan 3, 2024 -> Use Mon (ID 1)
+			{DateStr: "2024-01-03", UseDayID: 1},
+		},
+	}
+	if err := cfg.ProcessOverrides(); err != nil {
+		t.Fatalf("ProcessOverrides() returned error: %v", err)
+	}
+
+	srv := New(scheduler.New(cfg))
+	// Monday Jan 1, 2024, during Math
+	srv.Now = func() time.Time { return time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC) }
+	return srv
+}
+
+func get(t *testing.T, srv *Server, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestHandleCurrent(t *testing.T) {
+	rec := get(t, newTestServer(t), "/current")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("Unexpected Content-Type %q", ct)
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc == "" {
+		t.Errorf("Expected Cache-Control header to be set")
+	}
+
+	var task output.JSONTask
+	if err := json.Unmarshal(rec.Body.Bytes(), &task); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if task.Name != "Math" {
+		t.Errorf("Expected current task Math, got %q", task.Name)
+	}
+	if task.StartUnix != time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC).Unix() || task.DurationSeconds != 3600 {
+		t.Errorf("Unexpected timestamps in %+v", task)
+	}
+}
+
+func TestHandleDay(t *testing.T) {
+	tests := []struct {
+		name         string
+		date         string
+		wantTasks    []string
+		wantOff      bool
+		wantOverride bool
+		wantDayName  string
+	}{
+		{name: "normal_day", date: "2024-01-01", wantTasks: []string{"Math"}, wantDayName: "Monday"},
+		{name: "off_day", date: "2024-01-02", wantTasks: []string{}, wantOff: true, wantOverride: true},
+		{name: "override_day", date: "2024-01-03", wantTasks: []string{"Math"}, wantOverride: true, wantDayName: "Monday"},
+	}
+
+	srv := newTestServer(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := get(t, srv, "/day?date="+tt.date)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			var day output.JSONDay
+			if err := json.Unmarshal(rec.Body.Bytes(), &day); err != nil {
+				t.Fatalf("Invalid JSON: %v", err)
+			}
+			if day.Date != tt.date {
+				t.Errorf("Expected date %s, got %s", tt.date, day.Date)
+			}
+			if day.IsOff != tt.wantOff || day.OverrideApplied != tt.wantOverride || day.DayName != tt.wantDayName {
+				t.Errorf("Unexpected day metadata: %+v", day)
+			}
+			if tt.wantOff && day.DayID != nil {
+				t.Errorf("Expected null day_id on off day, got %d", *day.DayID)
+			}
+			if day.Tasks == nil {
+				t.Errorf("Expected tasks to be an empty array, not null")
+			}
+			if len(day.Tasks) != len(tt.wantTasks) {
+				t.Fatalf("Expected %d tasks, got %d", len(tt.wantTasks), len(day.Tasks))
+			}
+			for i, name := range tt.wantTasks {
+				if day.Tasks[i].Name != name {
+					t.Errorf("Task %d: expected %s, got %s", i, name, day.Tasks[i].Name)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleRange(t *testing.T) {
+	srv := newTestServer(t)
+
+	rec := get(t, srv, "/range?from=2024-01-01&to=2024-01-03")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var days []output.JSONDay
+	if err := json.Unmarshal(rec.Body.Bytes(), &days); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(days) != 3 {
+		t.Fatalf("Expected 3 days, got %d", len(days))
+	}
+	if len(days[1].Tasks) != 0 {
+		t.Errorf("Expected off day to have no tasks, got %d", len(days[1].Tasks))
+	}
+	if !days[0].Tasks[0].IsCurrent {
+		t.Errorf("Expected Monday's Math to be marked current")
+	}
+
+	if rec := get(t, srv, "/range?from=2024-01-03&to=2024-01-01"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for reversed range, got %d", rec.Code)
+	}
+}
+
+func TestHandleHealthz(t *testing.T) {
+	if rec := get(t, newTestServer(t), "/healthz"); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", rec.Code)
+	}
+}
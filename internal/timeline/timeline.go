@@ -0,0 +1,265 @@
+// Package timeline draws a day of the schedule as a bar in which each task
+// takes up width in proportion to its length, for 'sked timeline'.
+package timeline
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// minWidth is the narrowest bar drawn; narrower terminals get a bar this
+// wide anyway.
+const minWidth = 24
+
+// palette colors tasks without a color of their own, picked by name so a
+// task keeps its color from day to day.
+var palette = []string{"#8ecae6", "#ffb703", "#a7c957", "#f4a261", "#cdb4db", "#90e0ef", "#e9c46a", "#f28482"}
+
+// renderer writes truecolor sequences regardless of what stdout is; the
+// caller decides whether to use color at all.
+var renderer = func() *lipgloss.Renderer {
+	r := lipgloss.NewRenderer(io.Discard)
+	r.SetColorProfile(termenv.TrueColor)
+	return r
+}()
+
+// Options controls how a day is drawn.
+type Options struct {
+	// Width is the number of columns available.
+	Width int
+	// Color draws tasks as truecolor blocks; without it, tasks are ASCII
+	// blocks like [Math    ] and gaps dots.
+	Color bool
+	// From and To bound the bar, usually midnight to midnight or the
+	// day_window.
+	From, To time.Time
+	// Now is marked with a caret if it falls between From and To; zero
+	// marks nothing.
+	Now   time.Time
+	Clock config.Clock
+}
+
+// Horizontal writes the tasks as one bar across the width, with hour labels
+// above it and a caret under the current time.
+func Horizontal(w io.Writer, tasks []scheduler.TaskEvent, opts Options) error {
+	width := max(opts.Width, minWidth)
+	tasks = visible(tasks)
+	lines := []string{
+		axis(opts.From, opts.To, width, opts.Clock),
+		bar(tasks, opts.From, opts.To, width, opts.Color, true),
+	}
+	if col, ok := column(opts.Now, opts.From, opts.To, width); ok {
+		label := "^ " + opts.Clock.Format(opts.Now)
+		if col+len(label) > width {
+			label = opts.Clock.Format(opts.Now) + " ^"
+			col -= len(label) - 1
+		}
+		lines = append(lines, strings.Repeat(" ", max(col, 0))+label)
+	}
+	return writeLines(w, lines)
+}
+
+// Vertical writes one line per hour between From and To, each a bar over
+// that hour. A task's name appears on the line where it starts; the line
+// of the current hour is marked with ">".
+func Vertical(w io.Writer, tasks []scheduler.TaskEvent, opts Options) error {
+	tasks = visible(tasks)
+	label := len(opts.Clock.Format(time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)))
+	width := max(opts.Width-label-3, minWidth)
+
+	var lines []string
+	for from := opts.From; from.Before(opts.To); {
+		to := hourStart(from).Add(time.Hour)
+		if to.After(opts.To) {
+			to = opts.To
+		}
+		marker := " "
+		if !opts.Now.IsZero() && !opts.Now.Before(from) && opts.Now.Before(to) {
+			marker = ">"
+		}
+		hour := fmt.Sprintf("%*s", label, opts.Clock.Format(from))
+		lines = append(lines, marker+hour+" "+bar(tasks, from, to, width, opts.Color, from.Equal(opts.From)))
+		from = to
+	}
+	return writeLines(w, lines)
+}
+
+// hourStart returns the start of the hour t is in, in t's location.
+func hourStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+}
+
+// visible drops empty slots.
+func visible(tasks []scheduler.TaskEvent) []scheduler.TaskEvent {
+	var out []scheduler.TaskEvent
+	for _, t := range tasks {
+		if t.RawName != "/" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// column returns the column of t on a bar from from to to, and whether t is
+// on the bar at all.
+func column(t, from, to time.Time, width int) (int, bool) {
+	if t.IsZero() || t.Before(from) || !t.Before(to) {
+		return 0, false
+	}
+	return int(int64(t.Sub(from)) * int64(width) / int64(to.Sub(from))), true
+}
+
+// axis labels the hours on a bar from from to to, as often as they fit.
+func axis(from, to time.Time, width int, clock config.Clock) string {
+	perHour := float64(width) / to.Sub(from).Hours()
+	labelWidth := len(hourLabel(time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC), clock)) + 1
+	step := 1
+	for _, s := range []int{1, 2, 3, 4, 6, 12, 24} {
+		step = s
+		if perHour*float64(s) >= float64(labelWidth) {
+			break
+		}
+	}
+
+	line := []byte(strings.Repeat(" ", width))
+	for h := hourStart(from); h.Before(to); h = h.Add(time.Hour) {
+		if h.Before(from) || h.Hour()%step != 0 {
+			continue
+		}
+		col, _ := column(h, from, to, width)
+		label := hourLabel(h, clock)
+		if col+len(label) > width {
+			continue
+		}
+		copy(line[col:], label)
+	}
+	return strings.TrimRight(string(line), " ")
+}
+
+// hourLabel is the short label of an hour: "08" on the 24-hour clock, "8a"
+// or "12p" on the 12-hour one.
+func hourLabel(t time.Time, clock config.Clock) string {
+	if clock != config.Clock12 {
+		return fmt.Sprintf("%02d", t.Hour())
+	}
+	h, suffix := t.Hour()%12, "a"
+	if h == 0 {
+		h = 12
+	}
+	if t.Hour() >= 12 {
+		suffix = "p"
+	}
+	return fmt.Sprintf("%d%s", h, suffix)
+}
+
+// bar draws the tasks between from and to across width columns. A column
+// belongs to the first task covering its middle. Names are written into
+// their runs unless the task started before from and labelContinued is
+// unset.
+func bar(tasks []scheduler.TaskEvent, from, to time.Time, width int, color, labelContinued bool) string {
+	span := to.Sub(from)
+	owner := make([]int, width)
+	for c := range owner {
+		mid := from.Add(time.Duration((int64(c)*2 + 1) * int64(span) / int64(width*2)))
+		owner[c] = -1
+		for i, t := range tasks {
+			if !mid.Before(t.StartTime) && mid.Before(t.EndTime) {
+				owner[c] = i
+				break
+			}
+		}
+	}
+
+	var b strings.Builder
+	for start := 0; start < width; {
+		end := start
+		for end < width && owner[end] == owner[start] {
+			end++
+		}
+		n := end - start
+		if owner[start] < 0 {
+			b.WriteString(gap(n, color))
+		} else {
+			t := tasks[owner[start]]
+			name := t.Name
+			if t.StartTime.Before(from) && !labelContinued {
+				name = ""
+			}
+			b.WriteString(block(name, taskColor(t), n, color))
+		}
+		start = end
+	}
+	return b.String()
+}
+
+func gap(n int, color bool) string {
+	if !color {
+		return strings.Repeat(".", n)
+	}
+	return renderer.NewStyle().Faint(true).Render(strings.Repeat("·", n))
+}
+
+// block draws a task run of n columns with name inside, truncated to fit.
+func block(name, bg string, n int, color bool) string {
+	if !color {
+		switch n {
+		case 1:
+			return "#"
+		case 2:
+			return "[]"
+		}
+		return "[" + fit(name, n-2, "~") + "]"
+	}
+	text := fit(name, n, "…")
+	if n > 2 {
+		text = " " + fit(name, n-1, "…")
+	}
+	return renderer.NewStyle().Background(lipgloss.Color(bg)).Foreground(lipgloss.Color("#000000")).Render(text)
+}
+
+// fit truncates s to n columns, ending in ellipsis if it was cut, and pads
+// it with spaces to exactly n columns.
+func fit(s string, n int, ellipsis string) string {
+	if lipgloss.Width(s) > n {
+		var b strings.Builder
+		for _, r := range s {
+			if lipgloss.Width(b.String()+string(r)) > n-1 {
+				break
+			}
+			b.WriteRune(r)
+		}
+		s = b.String()
+		if n > 0 {
+			s += ellipsis
+		}
+	}
+	return s + strings.Repeat(" ", max(n-lipgloss.Width(s), 0))
+}
+
+// taskColor is the task's own color or one from the palette.
+func taskColor(t scheduler.TaskEvent) string {
+	if t.Color != "" {
+		return t.Color
+	}
+	h := fnv.New32a()
+	h.Write([]byte(t.Name))
+	return palette[h.Sum32()%uint32(len(palette))]
+}
+
+func writeLines(w io.Writer, lines []string) error {
+	for _, line := range lines {
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
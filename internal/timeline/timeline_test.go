@@ -0,0 +1,102 @@
+package timeline
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+var updateGolden = flag.Bool("update", false, "regenerate the golden files in testdata")
+
+func testTasks(t *testing.T, day time.Time) []scheduler.TaskEvent {
+	t.Helper()
+	cfg := &config.Config{
+		CycleDays: 7,
+		Days: []config.Day{{ID: 1, Tasks: []config.Task{
+			{Name: "Standup", Start: "09:00", End: "09:15"},
+			{Name: "Deep work on the compiler", Start: "09:30", End: "12:00"},
+			{Name: "/", Start: "12:00", End: "13:00"},
+			{Name: "Lunch", Start: "13:00", End: "14:00", Color: "#2a9d8f"},
+			{Name: "Review", Start: "15:00", End: "16:30"},
+		}}},
+	}
+	tasks, err := scheduler.New(cfg).GetTasksForDate(day)
+	if err != nil {
+		t.Fatalf("GetTasksForDate() returned error: %v", err)
+	}
+	return tasks
+}
+
+func TestGolden(t *testing.T) {
+	// Monday
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tasks := testTasks(t, day)
+	window := Options{From: day.Add(8 * time.Hour), To: day.Add(18 * time.Hour)}
+
+	tests := []struct {
+		name     string
+		vertical bool
+		opts     Options
+	}{
+		{name: "day_ascii", opts: Options{From: day, To: day.AddDate(0, 0, 1)}},
+		{name: "window_ascii", opts: window},
+		{name: "window_color", opts: Options{From: window.From, To: window.To, Color: true}},
+		{name: "window_now", opts: Options{From: window.From, To: window.To, Now: day.Add(10*time.Hour + 20*time.Minute)}},
+		{name: "window_12h", opts: Options{From: window.From, To: window.To, Clock: config.Clock12}},
+		{name: "vertical_ascii", vertical: true, opts: Options{From: window.From, To: window.To, Now: day.Add(10*time.Hour + 20*time.Minute)}},
+		{name: "vertical_color", vertical: true, opts: Options{From: window.From, To: window.To, Color: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Width = 60
+			var buf bytes.Buffer
+			render := Horizontal
+			if tt.vertical {
+				render = Vertical
+			}
+			if err := render(&buf, tasks, tt.opts); err != nil {
+				t.Fatalf("render returned error: %v", err)
+			}
+			got := buf.String()
+
+			path := filepath.Join("testdata", tt.name+".golden")
+			if *updateGolden {
+				if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+					t.Fatalf("Failed to write %s: %v", path, err)
+				}
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read %s (run with -update to create it): %v", path, err)
+			}
+			if got != string(want) {
+				t.Errorf("Output no longer matches %s. If the change is intentional, run 'go test ./internal/timeline -update'.\n%s", path, got)
+			}
+		})
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{s: "Math", n: 6, want: "Math  "},
+		{s: "Mathematics", n: 6, want: "Mathe~"},
+		{s: "Math", n: 4, want: "Math"},
+		{s: "", n: 3, want: "   "},
+	}
+	for _, tt := range tests {
+		if got := fit(tt.s, tt.n, "~"); got != tt.want {
+			t.Errorf("fit(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
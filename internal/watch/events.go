@@ -0,0 +1,90 @@
+package watch
+
+import (
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/output"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// EventType names a watch-mode state change.
+type EventType string
+
+const (
+	// EventInit describes the state at startup: the current task, if any.
+	EventInit         EventType = "init"
+	EventTaskStart    EventType = "task_start"
+	EventTaskEnd      EventType = "task_end"
+	EventDayRollover  EventType = "day_rollover"
+	EventNotification EventType = "notification"
+	// EventStopped is the last event, written when watch mode shuts down.
+	EventStopped EventType = "stopped"
+)
+
+// Event is a single line of the --events stream.
+type Event struct {
+	Type EventType        `json:"type"`
+	Task *output.JSONTask `json:"task"`
+	At   string           `json:"at"`
+	// Date is the schedule date, set on init and day_rollover.
+	Date string `json:"date,omitempty"`
+	// Title and Message are set on notification events.
+	Title   string `json:"title,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// Events translates the decision of one iteration into stream events.
+// prev is the state Step was called with and now the time it was called at.
+// A task ending at midnight is reported before the rollover, a task starting
+// then after it.
+func Events(prev State, d Decision, now time.Time) []Event {
+	at := now.Format(time.RFC3339)
+	if !prev.Started {
+		return append([]Event{{Type: EventInit, Task: output.NewJSONTask(d.Current), At: at, Date: d.EffectiveNow.Format("2006-01-02")}},
+			noticeEvents(d, at)...)
+	}
+
+	var events []Event
+	if d.Transition != nil && d.Transition.Previous != nil {
+		events = append(events, Event{Type: EventTaskEnd, Task: output.NewJSONTask(d.Transition.Previous), At: at})
+	}
+	if !sameDate(prev.LastEffective, d.EffectiveNow) {
+		events = append(events, Event{Type: EventDayRollover, At: at, Date: d.EffectiveNow.Format("2006-01-02")})
+	}
+	if d.Transition != nil && d.Transition.Current != nil {
+		events = append(events, Event{Type: EventTaskStart, Task: output.NewJSONTask(d.Transition.Current), At: at})
+	}
+	return append(events, noticeEvents(d, at)...)
+}
+
+// Stopped returns the final event of the stream, carrying the task that was
+// current in the last iteration.
+func Stopped(state State, now time.Time) Event {
+	return Event{Type: EventStopped, Task: output.NewJSONTask(state.LastCurrent), At: now.Format(time.RFC3339)}
+}
+
+func noticeEvents(d Decision, at string) []Event {
+	var events []Event
+	for _, n := range []struct {
+		notice *Notice
+		task   *scheduler.TaskEvent
+	}{{d.Notice, d.Next}, {d.PhaseNotice, d.Current}} {
+		if n.notice == nil || n.notice.Stale {
+			continue
+		}
+		events = append(events, Event{
+			Type:    EventNotification,
+			Task:    output.NewJSONTask(n.task),
+			At:      at,
+			Title:   n.notice.Notification.Title,
+			Message: n.notice.Notification.Message,
+		})
+	}
+	return events
+}
+
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
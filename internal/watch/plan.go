@@ -0,0 +1,68 @@
+package watch
+
+import (
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/notifier"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// Planned is a notification watch mode would send.
+type Planned struct {
+	// Trigger is when the notification fires. Notifications already due
+	// when planning starts are reported at that time.
+	Trigger      time.Time
+	Offset       time.Duration
+	Notification notifier.Notification
+}
+
+// Plan lists the notifications watch mode would send between from and until,
+// stepping through the same decisions as the real loop without sleeping.
+// Notifications already recorded in state.Notified are skipped.
+func Plan(sched *scheduler.Scheduler, from, until time.Time, state State, settings Settings) ([]Planned, error) {
+	planned := []Planned{}
+	if !settings.Notify {
+		return planned, nil
+	}
+
+	seen := &planState{prior: state.Notified, marked: make(map[string]bool)}
+	state.Notified = seen
+
+	now := from
+	for now.Before(until) {
+		d, next, err := Step(sched, now, state, settings)
+		if err != nil {
+			return nil, err
+		}
+		for _, notice := range d.Notices() {
+			if !notice.Stale {
+				offset := settings.NotifyAhead
+				if notice == d.PhaseNotice {
+					// Phase changes are announced when they happen
+					offset = 0
+				}
+				planned = append(planned, Planned{
+					Trigger:      now,
+					Offset:       offset,
+					Notification: notice.Notification,
+				})
+			}
+			seen.marked[notice.Signature] = true
+		}
+		state = next
+		// Step to the exact wake-up target so triggers are reported without the buffer.
+		now = d.Deadline.Add(-wakeBuffer)
+	}
+	return planned, nil
+}
+
+// planState layers the notifications planned so far over an existing history
+// without modifying it.
+type planState struct {
+	prior  Notified
+	marked map[string]bool
+}
+
+func (p *planState) Seen(sig string) bool {
+	return p.marked[sig] || (p.prior != nil && p.prior.Seen(sig))
+}
@@ -0,0 +1,55 @@
+package watch
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/notifier"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// Simulate replays watch mode over the whole day containing date against a
+// simulated clock, writing every transition and would-be notification to w
+// with its simulated timestamp. wait is called with the simulated time
+// between iterations, letting the caller pace the replay.
+func Simulate(sched *scheduler.Scheduler, date time.Time, settings Settings, w io.Writer, wait func(time.Duration)) error {
+	now := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	end := now.AddDate(0, 0, 1)
+
+	notified := notifier.NewMemoryState()
+	state := State{Notified: notified}
+
+	for now.Before(end) {
+		d, next, err := Step(sched, now, state, settings)
+		if err != nil {
+			return err
+		}
+		if !state.Started || d.Transition != nil {
+			fmt.Fprintf(w, "[%s] current: %s\n", now.Format("15:04:05"), describe(d.Current))
+		}
+		for _, notice := range d.Notices() {
+			if !notice.Stale {
+				n := notice.Notification
+				fmt.Fprintf(w, "[%s] notify: %s: %s\n", now.Format("15:04:05"), n.Title, n.Message)
+			}
+			notified.Mark(notice.Signature, now)
+		}
+		state = next
+
+		if !d.Deadline.Before(end) {
+			break
+		}
+		wait(d.Deadline.Sub(now))
+		now = d.Deadline
+	}
+	fmt.Fprintf(w, "[%s] end of day\n", end.Add(-time.Second).Format("15:04:05"))
+	return nil
+}
+
+func describe(t *scheduler.TaskEvent) string {
+	if t == nil {
+		return "(none)"
+	}
+	return fmt.Sprintf("%s (%s-%s)", t.Name, t.StartTime.Format("15:04"), t.EndTime.Format("15:04"))
+}
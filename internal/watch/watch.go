@@ -0,0 +1,396 @@
+// Package watch holds the decision logic of watch mode: which task is
+// current, when it changed, which notification is due and when to wake up
+// next. It performs no I/O and does not read the clock, so the same logic
+// drives the real watch loop and `sked simulate`.
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/hooks"
+	"github.com/Daniel-42-z/sked/internal/i18n"
+	"github.com/Daniel-42-z/sked/internal/notifier"
+	"github.com/Daniel-42-z/sked/internal/pomodoro"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+const (
+	// wakeBuffer is added to wake-up times so the loop lands in the next state.
+	wakeBuffer = 50 * time.Millisecond
+)
+
+// Settings configure the decision logic.
+type Settings struct {
+	// Lookahead shifts the time used for task lookups: into the future if
+	// positive, into the past if negative. Notifications and wake-ups still
+	// follow the real clock.
+	Lookahead time.Duration
+	// Notify enables notifications, sent NotifyAhead before a task starts.
+	Notify        bool
+	NotifyAhead   time.Duration
+	NotifyOptions notifier.SendOptions
+	// Icons prefixes notification titles with the task's icon.
+	Icons bool
+	// Locale translates notification messages (English if nil), and Clock
+	// formats their times.
+	Locale *i18n.Locale
+	Clock  config.Clock
+	// Durations formats the durations in notification messages.
+	Durations config.Durations
+	// Align rounds wake-ups for schedule boundaries up to the next whole
+	// minute. Notification triggers are not rounded.
+	Align bool
+	// Interval, if positive, caps how long the loop sleeps. With Align the
+	// wake-ups fall on multiples of Interval.
+	Interval time.Duration
+	// Countdown wakes the loop whenever the minutes until the next task
+	// tick down while no task is current, for idle texts with {next_in}.
+	Countdown bool
+}
+
+// Notified reports whether a notification signature was already handled.
+type Notified interface {
+	Seen(sig string) bool
+}
+
+// State is what the loop carries from one iteration to the next.
+type State struct {
+	// LastCurrent and LastPrevious are the current and previous tasks seen
+	// by the previous iteration.
+	LastCurrent  *scheduler.TaskEvent
+	LastPrevious *scheduler.TaskEvent
+	// Started is false until the first iteration has run.
+	Started bool
+	// LastEffective is the EffectiveNow of the previous iteration.
+	LastEffective time.Time
+	// SuspendedAt is the wall time the system was suspended during the last
+	// sleep, or zero if it wasn't.
+	SuspendedAt time.Time
+	// Notified holds the notifications already handled. It may be nil when
+	// notifications are disabled.
+	Notified Notified
+}
+
+// Notice is a notification that became due.
+type Notice struct {
+	Signature    string
+	Notification notifier.Notification
+	// Stale is set when the trigger passed while the system was suspended;
+	// the notice should be recorded but not sent.
+	Stale bool
+}
+
+// Decision is what the loop should do for one iteration.
+type Decision struct {
+	// EffectiveNow is the time used for task lookups (now plus lookahead).
+	EffectiveNow time.Time
+	Current      *scheduler.TaskEvent
+	Next         *scheduler.TaskEvent
+	// Previous is the last task that ended by EffectiveNow.
+	Previous *scheduler.TaskEvent
+	// OffDay is set when EffectiveNow falls on an off day.
+	OffDay bool
+	// Empty is set when the schedule has no tasks at all, so nothing will
+	// ever be current or next.
+	Empty bool
+	// Transition is set when the current task changed since the previous iteration.
+	Transition *hooks.Transition
+	// PreviousChanged is set when the previous task changed since the
+	// previous iteration. It can change without a Transition, e.g. when a
+	// task overlapping the current one ends.
+	PreviousChanged bool
+	// Phase is the pomodoro phase of Current, if it has a pomodoro rhythm.
+	Phase *pomodoro.Phase
+	// Notice is set when a notification for the next task is due.
+	Notice *Notice
+	// PhaseNotice is set when a pomodoro phase of Current has just begun.
+	PhaseNotice *Notice
+	// Deadline is when the loop should run again.
+	Deadline time.Time
+}
+
+// Notices returns the notices that are set.
+func (d Decision) Notices() []*Notice {
+	var notices []*Notice
+	for _, n := range []*Notice{d.Notice, d.PhaseNotice} {
+		if n != nil {
+			notices = append(notices, n)
+		}
+	}
+	return notices
+}
+
+// Step evaluates the schedule at now and returns the decision for this
+// iteration together with the state for the next one.
+func Step(sched *scheduler.Scheduler, now time.Time, state State, settings Settings) (Decision, State, error) {
+	d := Decision{EffectiveNow: now.Add(settings.Lookahead)}
+
+	var err error
+	d.Current, err = sched.GetCurrentTask(d.EffectiveNow)
+	if err != nil {
+		return d, state, fmt.Errorf("getting current task: %w", err)
+	}
+	d.Next, err = sched.GetNextTask(d.EffectiveNow)
+	if errors.Is(err, scheduler.ErrEmptySchedule) {
+		d.Empty, err = true, nil
+	}
+	if err != nil {
+		return d, state, fmt.Errorf("getting next task: %w", err)
+	}
+	d.Previous, err = sched.GetPreviousTask(d.EffectiveNow)
+	if err != nil {
+		return d, state, fmt.Errorf("getting previous task: %w", err)
+	}
+
+	d.OffDay, err = sched.IsOffDay(d.EffectiveNow)
+	if err != nil {
+		return d, state, fmt.Errorf("resolving day: %w", err)
+	}
+
+	if state.Started && !hooks.SameTask(state.LastCurrent, d.Current) {
+		d.Transition = &hooks.Transition{Previous: state.LastCurrent, Current: d.Current, Next: d.Next}
+	}
+	d.PreviousChanged = state.Started && !hooks.SameTask(state.LastPrevious, d.Previous)
+
+	if d.Current != nil {
+		d.Phase = d.Current.PomodoroPhase(d.EffectiveNow)
+	}
+
+	// Notifications are about the tasks as of now, whatever the lookahead
+	// shows: a task's notification triggers at its real start minus
+	// NotifyAhead, and a phase notification when the phase really begins.
+	var wall wallTasks
+	if settings.Notify {
+		wall, err = wallTasksAt(sched, now, d, settings)
+		if err != nil {
+			return d, state, err
+		}
+		if !muted(sched, wall.next) {
+			d.Notice = notice(wall.next, now, state, settings)
+		}
+		if !muted(sched, wall.current) {
+			d.PhaseNotice = phaseNotice(wall.current, wall.phase, state, settings)
+		}
+	}
+
+	d.Deadline = deadline(d.Current, d.Next, d.Phase, wall, now, settings)
+	state.LastCurrent = d.Current
+	state.LastPrevious = d.Previous
+	state.LastEffective = d.EffectiveNow
+	state.Started = true
+	return d, state, nil
+}
+
+// wallTasks are the current and next tasks, and the current pomodoro
+// phase, at the real time rather than the lookahead time.
+type wallTasks struct {
+	current, next *scheduler.TaskEvent
+	phase         *pomodoro.Phase
+}
+
+// wallTasksAt returns the tasks at the real time now, reusing those of d
+// when there is no lookahead.
+func wallTasksAt(sched *scheduler.Scheduler, now time.Time, d Decision, settings Settings) (wallTasks, error) {
+	if settings.Lookahead == 0 {
+		return wallTasks{current: d.Current, next: d.Next, phase: d.Phase}, nil
+	}
+	var r wallTasks
+	var err error
+	r.current, err = sched.GetCurrentTask(now)
+	if err != nil {
+		return r, fmt.Errorf("getting current task: %w", err)
+	}
+	r.next, err = sched.GetNextTask(now)
+	if err != nil && !errors.Is(err, scheduler.ErrEmptySchedule) {
+		return r, fmt.Errorf("getting next task: %w", err)
+	}
+	if r.current != nil {
+		r.phase = r.current.PomodoroPhase(now)
+	}
+	return r, nil
+}
+
+// notice returns the notification for next that is due at now, if any.
+// Triggers are computed from the actual start time, on the real clock.
+func notice(next *scheduler.TaskEvent, now time.Time, state State, settings Settings) *Notice {
+	if next == nil {
+		return nil
+	}
+	trigger := next.StartTime.Add(-settings.NotifyAhead)
+	sig := notifier.Signature(next.Name, next.StartTime, settings.NotifyAhead)
+	if state.Notified != nil && state.Notified.Seen(sig) {
+		return nil
+	}
+
+	if !state.SuspendedAt.IsZero() && trigger.After(state.SuspendedAt) && trigger.Before(now) {
+		// The trigger passed while the system was asleep; don't fire a stale notification.
+		return &Notice{Signature: sig, Stale: true}
+	}
+	if now.Before(trigger) {
+		return nil
+	}
+
+	msg := settings.Locale.T("starts_at", settings.Clock.Format(next.StartTime))
+	if settings.NotifyAhead > 0 {
+		msg = settings.Locale.T("starts_at_ahead", settings.Clock.Format(next.StartTime), settings.Durations.Format(settings.NotifyAhead))
+	}
+	opts := settings.NotifyOptions
+	if settings.NotifyAhead == 0 {
+		// "Starting now" deserves more attention than an advance reminder
+		opts.Urgency = notifier.RaiseUrgency(opts.Urgency)
+	}
+
+	return &Notice{
+		Signature: sig,
+		Notification: notifier.Notification{
+			Title:     title(next, settings),
+			Message:   msg,
+			Options:   opts,
+			TaskName:  next.Name,
+			TaskStart: next.StartTime,
+			TaskEnd:   next.EndTime,
+			TaskURL:   next.URL,
+		},
+	}
+}
+
+// muted reports whether t falls on a date whose override sets
+// mute_notifications.
+func muted(sched *scheduler.Scheduler, t *scheduler.TaskEvent) bool {
+	if t == nil {
+		return false
+	}
+	info, err := sched.GetDayInfo(t.StartTime)
+	return err == nil && info.Muted
+}
+
+// phaseNotice returns the notification for the start of phase p of current.
+// The first focus phase starts with the task itself and is not announced.
+func phaseNotice(current *scheduler.TaskEvent, p *pomodoro.Phase, state State, settings Settings) *Notice {
+	if p == nil || (p.Kind == pomodoro.Focus && p.Index == 1) {
+		return nil
+	}
+	sig := notifier.Signature(fmt.Sprintf("%s|%s %d", current.Name, p.Kind, p.Index), p.Start, 0)
+	if state.Notified != nil && state.Notified.Seen(sig) {
+		return nil
+	}
+	if !state.SuspendedAt.IsZero() && p.Start.After(state.SuspendedAt) {
+		return &Notice{Signature: sig, Stale: true}
+	}
+
+	kind := settings.Locale.T("focus")
+	if p.Kind == pomodoro.Break {
+		kind = settings.Locale.T("break")
+	}
+	return &Notice{
+		Signature: sig,
+		Notification: notifier.Notification{
+			Title:     title(current, settings),
+			Message:   settings.Locale.T("phase", kind, p.Index, p.Total, settings.Clock.Format(p.End)),
+			Options:   settings.NotifyOptions,
+			TaskName:  current.Name,
+			TaskStart: current.StartTime,
+			TaskEnd:   current.EndTime,
+			TaskURL:   current.URL,
+		},
+	}
+}
+
+// title returns the notification title for t.
+func title(t *scheduler.TaskEvent, settings Settings) string {
+	if settings.Icons {
+		return t.Label()
+	}
+	return t.Name
+}
+
+// deadline returns when the loop must wake up next: when the current task
+// ends or its pomodoro countdown changes, when the next one starts, or at
+// midnight, when the day (and possibly its off status) changes, all seen
+// at the lookahead time and shifted back to the real clock; or when a
+// notification for the tasks of wall triggers; never later than
+// settings.Interval from now.
+func deadline(current, next *scheduler.TaskEvent, p *pomodoro.Phase, wall wallTasks, now time.Time, settings Settings) time.Time {
+	eff := now.Add(settings.Lookahead)
+	y, m, d := eff.Date()
+	targets := []time.Time{time.Date(y, m, d+1, 0, 0, 0, 0, eff.Location()).Add(-settings.Lookahead)}
+	if b, ok := scheduler.NextBoundary(current, next, eff); ok {
+		targets = append(targets, b.At.Add(-settings.Lookahead))
+	}
+	if p != nil {
+		// Wake at the phase end, and whenever the minutes left shown in the
+		// output tick down.
+		targets = append(targets, p.End.Add(-settings.Lookahead))
+		if t, ok := minuteTick(p.End, eff); ok {
+			targets = append(targets, t.Add(-settings.Lookahead))
+		}
+	}
+	if settings.Countdown && current == nil && next != nil {
+		if t, ok := minuteTick(next.StartTime, eff); ok {
+			targets = append(targets, t.Add(-settings.Lookahead))
+		}
+	}
+
+	// The next midnight is always ahead, so there is always a target.
+	earliest := targets[0]
+	for _, t := range targets[1:] {
+		if t.After(now) && t.Before(earliest) {
+			earliest = t
+		}
+	}
+	if settings.Align {
+		// No buffer needed: the loop only wakes once the wall clock has
+		// reached the deadline, so it lands on or after the boundary.
+		earliest = ceilMinute(earliest)
+	} else {
+		earliest = earliest.Add(wakeBuffer)
+	}
+
+	if settings.Notify {
+		var triggers []time.Time
+		if wall.next != nil {
+			triggers = append(triggers, wall.next.StartTime.Add(-settings.NotifyAhead))
+		}
+		if wall.phase != nil && settings.Lookahead != 0 {
+			// Without lookahead the phase end is a target already
+			triggers = append(triggers, wall.phase.End)
+		}
+		for _, trigger := range triggers {
+			if trigger.After(now) && trigger.Add(wakeBuffer).Before(earliest) {
+				earliest = trigger.Add(wakeBuffer)
+			}
+		}
+	}
+	if settings.Interval > 0 {
+		limit := now.Add(settings.Interval)
+		if settings.Align {
+			limit = now.Truncate(settings.Interval).Add(settings.Interval)
+		}
+		if limit.Before(earliest) {
+			earliest = limit
+		}
+	}
+	return earliest
+}
+
+// minuteTick returns when the whole minutes left until end, rounded up,
+// next change after now, if that is before end.
+func minuteTick(end, now time.Time) (time.Time, bool) {
+	left := end.Sub(now)
+	whole := left.Truncate(time.Minute)
+	if whole == left {
+		whole -= time.Minute
+	}
+	return end.Add(-whole), whole > 0
+}
+
+// ceilMinute rounds t up to the next whole minute.
+func ceilMinute(t time.Time) time.Time {
+	if r := t.Truncate(time.Minute); r.Before(t) {
+		return r.Add(time.Minute)
+	}
+	return t
+}
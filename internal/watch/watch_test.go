@@ -0,0 +1,539 @@
+package watch
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+	"github.com/Daniel-42-z/sked/internal/i18n"
+	"github.com/Daniel-42-z/sked/internal/notifier"
+	"github.com/Daniel-42-z/sked/internal/scheduler"
+)
+
+// fixtureScheduler returns a Monday with two back-to-back tasks and one after a gap.
+func fixtureScheduler() *scheduler.Scheduler {
+	return scheduler.New(&config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 1, Tasks: []config.Task{
+				{Name: "Math", Start: "09:00", End: "10:00"},
+				{Name: "History", Start: "10:00", End: "11:00"},
+				{Name: "Art", Start: "13:00", End: "14:00"},
+			}},
+		},
+	})
+}
+
+// Monday Jan 1, 2024
+func at(hour, min int) time.Time {
+	return time.Date(2024, 1, 1, hour, min, 0, 0, time.UTC)
+}
+
+func TestStep_Transitions(t *testing.T) {
+	sched := fixtureScheduler()
+	settings := Settings{}
+
+	d, state, err := Step(sched, at(9, 30), State{}, settings)
+	if err != nil {
+		t.Fatalf("Step() returned error: %v", err)
+	}
+	if d.Transition != nil {
+		t.Errorf("Expected no transition on the first iteration")
+	}
+	if d.Current == nil || d.Current.Name != "Math" {
+		t.Fatalf("Expected current Math, got %+v", d.Current)
+	}
+	if want := at(10, 0).Add(wakeBuffer); !d.Deadline.Equal(want) {
+		t.Errorf("Expected deadline %v, got %v", want, d.Deadline)
+	}
+
+	d, state, err = Step(sched, at(10, 0), state, settings)
+	if err != nil {
+		t.Fatalf("Step() returned error: %v", err)
+	}
+	if d.Transition == nil || d.Transition.Previous.Name != "Math" || d.Transition.Current.Name != "History" {
+		t.Errorf("Expected Math -> History transition, got %+v", d.Transition)
+	}
+
+	d, _, err = Step(sched, at(10, 30), state, settings)
+	if err != nil {
+		t.Fatalf("Step() returned error: %v", err)
+	}
+	if d.Transition != nil {
+		t.Errorf("Expected no transition within the same task")
+	}
+}
+
+func TestStep_Previous(t *testing.T) {
+	sched := fixtureScheduler()
+	steps := []struct {
+		at       time.Time
+		previous string
+		changed  bool
+	}{
+		// Art of the Monday before
+		{at: at(9, 30), previous: "Art"},
+		{at: at(10, 0), previous: "Math", changed: true},
+		{at: at(10, 30), previous: "Math"},
+		{at: at(11, 0), previous: "History", changed: true},
+		{at: at(12, 0), previous: "History"},
+	}
+	var state State
+	for _, s := range steps {
+		d, next, err := Step(sched, s.at, state, Settings{})
+		if err != nil {
+			t.Fatalf("Step() returned error: %v", err)
+		}
+		state = next
+		got := ""
+		if d.Previous != nil {
+			got = d.Previous.Name
+		}
+		if got != s.previous || d.PreviousChanged != s.changed {
+			t.Errorf("At %s: Expected previous %q (changed %v), got %q (changed %v)", s.at.Format("15:04"), s.previous, s.changed, got, d.PreviousChanged)
+		}
+	}
+}
+
+func TestStep_Lookahead(t *testing.T) {
+	sched := fixtureScheduler()
+	tests := []struct {
+		name      string
+		lookahead time.Duration
+		current   string
+		deadline  time.Time
+	}{
+		// Three minutes before the Math -> History boundary
+		{name: "zero", current: "Math", deadline: at(10, 0)},
+		{name: "ahead", lookahead: 5 * time.Minute, current: "History", deadline: at(10, 55)},
+		{name: "behind", lookahead: -5 * time.Minute, current: "Math", deadline: at(10, 5)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _, err := Step(sched, at(9, 57), State{}, Settings{Lookahead: tt.lookahead})
+			if err != nil {
+				t.Fatalf("Step() returned error: %v", err)
+			}
+			if !d.EffectiveNow.Equal(at(9, 57).Add(tt.lookahead)) {
+				t.Errorf("Expected effective time %v, got %v", at(9, 57).Add(tt.lookahead), d.EffectiveNow)
+			}
+			if d.Current == nil || d.Current.Name != tt.current {
+				t.Errorf("Expected current %s, got %+v", tt.current, d.Current)
+			}
+			if want := tt.deadline.Add(wakeBuffer); !d.Deadline.Equal(want) {
+				t.Errorf("Expected deadline %v, got %v", want, d.Deadline)
+			}
+		})
+	}
+}
+
+func TestStep_LookaheadNotifications(t *testing.T) {
+	sched := fixtureScheduler()
+	for _, lookahead := range []time.Duration{-10 * time.Minute, 0, 10 * time.Minute} {
+		t.Run(lookahead.String(), func(t *testing.T) {
+			settings := Settings{Lookahead: lookahead, Notify: true, NotifyAhead: 2 * time.Minute}
+			state := State{Notified: notifier.NewMemoryState()}
+
+			// The History notification triggers at 09:58 on the real clock,
+			// whatever the display shows.
+			d, state, err := Step(sched, at(9, 50), state, settings)
+			if err != nil {
+				t.Fatalf("Step() returned error: %v", err)
+			}
+			if d.Notice != nil {
+				t.Errorf("Expected no notice before the trigger, got %+v", d.Notice)
+			}
+			if want := at(9, 58).Add(wakeBuffer); d.Deadline.After(want) {
+				t.Errorf("Expected a deadline by the trigger %v, got %v", want, d.Deadline)
+			}
+
+			d, _, err = Step(sched, at(9, 58), state, settings)
+			if err != nil {
+				t.Fatalf("Step() returned error: %v", err)
+			}
+			if d.Notice == nil || d.Notice.Notification.Title != "History" {
+				t.Errorf("Expected the History notice at the trigger, got %+v", d.Notice)
+			}
+		})
+	}
+}
+
+func TestStep_Countdown(t *testing.T) {
+	sched := fixtureScheduler()
+	// Free between History and Art at 13:00
+	d, _, err := Step(sched, at(11, 30), State{}, Settings{Countdown: true})
+	if err != nil {
+		t.Fatalf("Step() returned error: %v", err)
+	}
+	if want := at(11, 31).Add(wakeBuffer); !d.Deadline.Equal(want) {
+		t.Errorf("Expected a wake-up when the countdown ticks at %v, got %v", want, d.Deadline)
+	}
+
+	d, _, _ = Step(sched, at(11, 30), State{}, Settings{})
+	if want := at(13, 0).Add(wakeBuffer); !d.Deadline.Equal(want) {
+		t.Errorf("Expected deadline %v without a countdown, got %v", want, d.Deadline)
+	}
+
+	// A current task has its own output; no countdown
+	d, _, _ = Step(sched, at(9, 30), State{}, Settings{Countdown: true})
+	if want := at(10, 0).Add(wakeBuffer); !d.Deadline.Equal(want) {
+		t.Errorf("Expected deadline %v during a task, got %v", want, d.Deadline)
+	}
+}
+
+func TestStep_Notifications(t *testing.T) {
+	sched := fixtureScheduler()
+	settings := Settings{Notify: true, NotifyAhead: 5 * time.Minute, NotifyOptions: notifier.SendOptions{Urgency: notifier.UrgencyNormal}}
+	notified := notifier.NewMemoryState()
+	state := State{Notified: notified}
+
+	d, state, _ := Step(sched, at(12, 0), state, settings)
+	if d.Notice != nil {
+		t.Errorf("Expected no notice before the trigger")
+	}
+	if want := at(12, 55).Add(wakeBuffer); !d.Deadline.Equal(want) {
+		t.Errorf("Expected deadline at the notification trigger %v, got %v", want, d.Deadline)
+	}
+
+	d, state, _ = Step(sched, at(12, 55), state, settings)
+	if d.Notice == nil || d.Notice.Stale {
+		t.Fatalf("Expected a notice at the trigger, got %+v", d.Notice)
+	}
+	if n := d.Notice.Notification; n.Title != "Art" || n.Message != "Starts at 13:00 (in 5m)" || n.Options.Urgency != notifier.UrgencyNormal {
+		t.Errorf("Unexpected notification %+v", n)
+	}
+
+	notified.Mark(d.Notice.Signature, at(12, 55))
+	if d, _, _ = Step(sched, at(12, 56), state, settings); d.Notice != nil {
+		t.Errorf("Expected no repeated notice once marked")
+	}
+
+	// Woken from suspend after the trigger passed
+	state = State{Notified: notifier.NewMemoryState(), Started: true, SuspendedAt: at(12, 0)}
+	if d, _, _ = Step(sched, at(12, 58), state, settings); d.Notice == nil || !d.Notice.Stale {
+		t.Errorf("Expected a stale notice after resume, got %+v", d.Notice)
+	}
+}
+
+func TestNotice_StartingNowRaisesUrgency(t *testing.T) {
+	art := &scheduler.TaskEvent{Name: "Art", StartTime: at(13, 0), EndTime: at(14, 0)}
+	settings := Settings{Notify: true, NotifyOptions: notifier.SendOptions{Urgency: notifier.UrgencyNormal}}
+
+	n := notice(art, at(13, 0), State{}, settings)
+	if n == nil {
+		t.Fatalf("Expected a notice at the start time")
+	}
+	if n.Notification.Message != "Starts at 13:00" || n.Notification.Options.Urgency != notifier.UrgencyCritical {
+		t.Errorf("Unexpected notification %+v", n.Notification)
+	}
+}
+
+func TestNotice_Translated(t *testing.T) {
+	art := &scheduler.TaskEvent{Name: "Kunst", StartTime: at(13, 0), EndTime: at(14, 0)}
+	de, _ := i18n.Lookup("de")
+	settings := Settings{Notify: true, NotifyAhead: 5 * time.Minute, Locale: de}
+
+	n := notice(art, at(12, 55), State{}, settings)
+	if n == nil {
+		t.Fatalf("Expected a notice at the trigger time")
+	}
+	if want := "Beginnt um 13:00 (in 5m)"; n.Notification.Message != want {
+		t.Errorf("Expected message %q, got %q", want, n.Notification.Message)
+	}
+}
+
+func TestSimulate_FixtureDay(t *testing.T) {
+	settings := Settings{Notify: true, NotifyAhead: 5 * time.Minute}
+	var buf bytes.Buffer
+	var waited time.Duration
+	err := Simulate(fixtureScheduler(), at(15, 0), settings, &buf, func(d time.Duration) { waited += d })
+	if err != nil {
+		t.Fatalf("Simulate() returned error: %v", err)
+	}
+
+	want := `[00:00:00] current: (none)
+[08:55:00] notify: Math: Starts at 09:00 (in 5m)
+[09:00:00] current: Math (09:00-10:00)
+[09:55:00] notify: History: Starts at 10:00 (in 5m)
+[10:00:00] current: History (10:00-11:00)
+[11:00:00] current: (none)
+[12:55:00] notify: Art: Starts at 13:00 (in 5m)
+[13:00:00] current: Art (13:00-14:00)
+[14:00:00] current: (none)
+[23:59:59] end of day
+`
+	if buf.String() != want {
+		t.Errorf("Unexpected transition log:\n%s\nwant:\n%s", buf.String(), want)
+	}
+	if waited <= 0 || waited > 24*time.Hour {
+		t.Errorf("Expected simulated waits to stay within the day, got %v", waited)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	settings := Settings{Notify: true, NotifyAhead: 5 * time.Minute}
+	history := notifier.NewMemoryState()
+	history.Mark(notifier.Signature("History", at(10, 0), 5*time.Minute), at(9, 55))
+
+	// Math's trigger (08:55) has already passed at 08:58, so it is due immediately.
+	plan, err := Plan(fixtureScheduler(), at(8, 58), at(23, 0), State{Notified: history}, settings)
+	if err != nil {
+		t.Fatalf("Plan() returned error: %v", err)
+	}
+
+	want := []struct {
+		task    string
+		trigger time.Time
+	}{
+		{"Math", at(8, 58)},
+		{"Art", at(12, 55)},
+	}
+	if len(plan) != len(want) {
+		t.Fatalf("Expected %d planned notifications, got %d: %+v", len(want), len(plan), plan)
+	}
+	for i, w := range want {
+		if plan[i].Notification.TaskName != w.task || !plan[i].Trigger.Equal(w.trigger) {
+			t.Errorf("Entry %d: expected %s at %v, got %s at %v", i, w.task, w.trigger, plan[i].Notification.TaskName, plan[i].Trigger)
+		}
+	}
+	if history.Seen(notifier.Signature("Art", at(13, 0), 5*time.Minute)) {
+		t.Errorf("Plan() must not modify the notification history")
+	}
+}
+
+func TestStep_Pomodoro(t *testing.T) {
+	sched := scheduler.New(&config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 1, Tasks: []config.Task{{Name: "Deep work", Start: "09:00", End: "12:00", Pomodoro: "25m/5m"}}},
+		},
+	})
+	settings := Settings{Notify: true}
+	notified := notifier.NewMemoryState()
+	state := State{Notified: notified}
+
+	d, state, err := Step(sched, at(9, 0), state, settings)
+	if err != nil {
+		t.Fatalf("Step() returned error: %v", err)
+	}
+	if d.Phase == nil || d.Phase.Kind != "focus" || d.Phase.Index != 1 || d.Phase.Total != 6 {
+		t.Fatalf("Expected focus 1/6, got %+v", d.Phase)
+	}
+	if d.PhaseNotice != nil {
+		t.Errorf("Expected the first focus phase not to be announced")
+	}
+	// The countdown ticks every minute
+	if want := at(9, 1).Add(wakeBuffer); !d.Deadline.Equal(want) {
+		t.Errorf("Expected deadline %v, got %v", want, d.Deadline)
+	}
+
+	d, _, err = Step(sched, at(9, 25), state, settings)
+	if err != nil {
+		t.Fatalf("Step() returned error: %v", err)
+	}
+	if d.PhaseNotice == nil || d.PhaseNotice.Notification.Message != "Break 1/6 until 09:30" {
+		t.Fatalf("Expected a break notice, got %+v", d.PhaseNotice)
+	}
+	notified.Mark(d.PhaseNotice.Signature, at(9, 25))
+
+	if d, _, _ = Step(sched, at(9, 26), state, settings); d.PhaseNotice != nil {
+		t.Errorf("Expected the break notice only once")
+	}
+}
+
+func TestSimulate_Pomodoro(t *testing.T) {
+	sched := scheduler.New(&config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 1, Tasks: []config.Task{{Name: "Deep work", Start: "09:00", End: "10:10", Pomodoro: "25m/5m"}}},
+		},
+	})
+	var buf bytes.Buffer
+	if err := Simulate(sched, at(0, 0), Settings{Notify: true}, &buf, func(time.Duration) {}); err != nil {
+		t.Fatalf("Simulate() returned error: %v", err)
+	}
+
+	want := `[00:00:00] current: (none)
+[09:00:00] current: Deep work (09:00-10:10)
+[09:25:00] notify: Deep work: Break 1/3 until 09:30
+[09:30:00] notify: Deep work: Focus 2/3 until 09:55
+[09:55:00] notify: Deep work: Break 2/3 until 10:00
+[10:00:00] notify: Deep work: Focus 3/3 until 10:10
+[10:10:00] current: (none)
+[23:59:59] end of day
+`
+	if buf.String() != want {
+		t.Errorf("Unexpected transition log:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestStep_OffDaySleepsUntilMidnight(t *testing.T) {
+	sched := scheduler.New(&config.Config{
+		CycleDays: 7,
+		Days:      []config.Day{{ID: 1, Tasks: []config.Task{{Name: "Math", Start: "09:00", End: "10:00"}}}},
+		Overrides: []config.Override{{Date: at(0, 0), EndDate: at(0, 0), IsOff: true}},
+	})
+
+	d, _, err := Step(sched, at(9, 30), State{}, Settings{})
+	if err != nil {
+		t.Fatalf("Step() returned error: %v", err)
+	}
+	if !d.OffDay || d.Current != nil {
+		t.Fatalf("Expected an off day without a current task, got %+v", d)
+	}
+	if want := at(24, 0).Add(wakeBuffer); !d.Deadline.Equal(want) {
+		t.Errorf("Expected deadline %v, got %v", want, d.Deadline)
+	}
+}
+
+func TestStep_EmptySchedule(t *testing.T) {
+	sched := scheduler.New(&config.Config{CycleDays: 7})
+
+	d, _, err := Step(sched, at(9, 30), State{}, Settings{})
+	if err != nil {
+		t.Fatalf("Step() returned error: %v", err)
+	}
+	if !d.Empty || d.Current != nil || d.Next != nil {
+		t.Fatalf("Expected an empty schedule without tasks, got %+v", d)
+	}
+	if want := at(24, 0).Add(wakeBuffer); !d.Deadline.Equal(want) {
+		t.Errorf("Expected deadline %v, got %v", want, d.Deadline)
+	}
+
+	d, _, err = Step(fixtureScheduler(), at(9, 30), State{}, Settings{})
+	if err != nil || d.Empty {
+		t.Errorf("Expected a non-empty schedule, got %+v, %v", d, err)
+	}
+}
+
+func TestEvents_Sequence(t *testing.T) {
+	sched := scheduler.New(&config.Config{
+		CycleDays: 7,
+		Days: []config.Day{
+			{ID: 1, Tasks: []config.Task{
+				{Name: "Math", Start: "09:00", End: "10:00"},
+				{Name: "History", Start: "10:00", End: "11:00"},
+				{Name: "Night", Start: "23:00", End: "23:59"},
+			}},
+			{ID: 2, Tasks: []config.Task{{Name: "Early", Start: "00:00", End: "01:00"}}},
+		},
+	})
+	settings := Settings{Notify: true, NotifyAhead: 5 * time.Minute}
+	notified := notifier.NewMemoryState()
+	state := State{Notified: notified}
+
+	var got []string
+	end := time.Date(2024, 1, 2, 0, 30, 0, 0, time.UTC)
+	for now := at(9, 30); now.Before(end); {
+		d, next, err := Step(sched, now, state, settings)
+		if err != nil {
+			t.Fatalf("Step() returned error: %v", err)
+		}
+		for _, e := range Events(state, d, now) {
+			line := fmt.Sprintf("%s %s", now.Format("15:04"), e.Type)
+			if e.Task != nil {
+				line += " " + e.Task.Name
+			}
+			if e.Date != "" {
+				line += " " + e.Date
+			}
+			got = append(got, line)
+		}
+		for _, n := range d.Notices() {
+			notified.Mark(n.Signature, now)
+		}
+		state = next
+		now = d.Deadline
+	}
+
+	want := []string{
+		"09:30 init Math 2024-01-01",
+		"09:55 notification History",
+		"10:00 task_end Math",
+		"10:00 task_start History",
+		"11:00 task_end History",
+		"22:55 notification Night",
+		"23:00 task_start Night",
+		"23:55 notification Early",
+		"23:59 task_end Night",
+		"00:00 day_rollover 2024-01-02",
+		"00:00 task_start Early",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected events:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestStopped(t *testing.T) {
+	sched := fixtureScheduler()
+	_, state, err := Step(sched, at(9, 30), State{}, Settings{})
+	if err != nil {
+		t.Fatalf("Step() returned error: %v", err)
+	}
+	e := Stopped(state, at(9, 45))
+	if e.Type != EventStopped || e.At != at(9, 45).Format(time.RFC3339) {
+		t.Errorf("Expected stopped event at 09:45, got %+v", e)
+	}
+	if e.Task == nil || e.Task.Name != state.LastCurrent.Name {
+		t.Errorf("Expected last current task, got %+v", e.Task)
+	}
+}
+
+func TestDeadline_AlignAndInterval(t *testing.T) {
+	sched := fixtureScheduler()
+	sec := func(h, m, s int) time.Time { return time.Date(2024, 1, 1, h, m, s, 0, time.UTC) }
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		settings Settings
+		want     time.Time
+	}{
+		{name: "default", now: at(9, 30), want: at(10, 0).Add(wakeBuffer)},
+		{name: "align_without_buffer", now: at(9, 30), settings: Settings{Align: true}, want: at(10, 0)},
+		// Math ends at 10:00, i.e. 09:59:30 with the lookahead
+		{name: "align_rounds_up", now: at(9, 30), settings: Settings{Align: true, Lookahead: 30 * time.Second}, want: at(10, 0)},
+		// The 12:58:30 notification trigger keeps its precision
+		{name: "align_keeps_trigger", now: at(12, 0), settings: Settings{Align: true, Notify: true, NotifyAhead: 90 * time.Second}, want: sec(12, 58, 30).Add(wakeBuffer)},
+		{name: "interval", now: sec(9, 30, 10), settings: Settings{Interval: 30 * time.Second}, want: sec(9, 30, 40)},
+		{name: "aligned_interval", now: sec(9, 30, 10), settings: Settings{Align: true, Interval: 30 * time.Second}, want: sec(9, 30, 30)},
+		{name: "boundary_before_interval", now: sec(9, 59, 50), settings: Settings{Align: true, Interval: 30 * time.Second}, want: at(10, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _, err := Step(sched, tt.now, State{Notified: notifier.NewMemoryState()}, tt.settings)
+			if err != nil {
+				t.Fatalf("Step() returned error: %v", err)
+			}
+			if !d.Deadline.Equal(tt.want) {
+				t.Errorf("Expected deadline %v, got %v", tt.want, d.Deadline)
+			}
+		})
+	}
+}
+
+func TestStep_MutedOverride(t *testing.T) {
+	sched := scheduler.New(&config.Config{
+		CycleDays: 7,
+		Days:      []config.Day{{ID: 5, Tasks: []config.Task{{Name: "Math", Start: "13:00", End: "14:00"}}}},
+		Overrides: []config.Override{{Date: at(0, 0), EndDate: at(0, 0), UseDayID: 5, Note: "Conference", MuteNotifications: true}},
+	})
+	settings := Settings{Notify: true, NotifyAhead: 5 * time.Minute}
+
+	d, _, err := Step(sched, at(12, 55), State{Notified: notifier.NewMemoryState()}, settings)
+	if err != nil {
+		t.Fatalf("Step() returned error: %v", err)
+	}
+	if d.Next == nil || d.Next.Name != "Math" {
+		t.Fatalf("Expected Math next on the overridden day, got %+v", d.Next)
+	}
+	if d.Notice != nil {
+		t.Errorf("Expected no notice on a muted day, got %+v", d.Notice)
+	}
+	if want := at(13, 0).Add(wakeBuffer); !d.Deadline.Equal(want) {
+		t.Errorf("Expected deadline %v, got %v", want, d.Deadline)
+	}
+}
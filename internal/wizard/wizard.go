@@ -0,0 +1,326 @@
+// Package wizard implements the interactive `sked init` setup.
+package wizard
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+// Prompter asks questions on a line-oriented input.
+type Prompter struct {
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+// NewPrompter creates a Prompter reading answers from r and writing prompts to w.
+func NewPrompter(r io.Reader, w io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewScanner(r), out: w}
+}
+
+// Ask prints question and returns the answer, or def if the answer is empty.
+// Invalid answers are reported and the question is asked again.
+func (p *Prompter) Ask(question, def string, validate func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", question)
+		}
+		if !p.in.Scan() {
+			if err := p.in.Err(); err != nil {
+				return "", err
+			}
+			return "", io.ErrUnexpectedEOF
+		}
+		answer := strings.TrimSpace(p.in.Text())
+		if answer == "" {
+			answer = def
+		}
+		if validate == nil {
+			return answer, nil
+		}
+		if err := validate(answer); err != nil {
+			fmt.Fprintf(p.out, "  %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// Confirm asks a yes/no question; an empty answer selects def.
+func (p *Prompter) Confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	answer, err := p.Ask(question+" ("+hint+")", "", func(s string) error {
+		switch strings.ToLower(s) {
+		case "", "y", "yes", "n", "no":
+			return nil
+		}
+		return fmt.Errorf("please answer y or n")
+	})
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(answer) {
+	case "y", "yes":
+		return true, nil
+	case "n", "no":
+		return false, nil
+	}
+	return def, nil
+}
+
+// Answers holds everything the wizard collected.
+type Answers struct {
+	Format     string // "csv" or "toml"
+	CycleDays  int
+	AnchorDate string
+	WorkStart  string
+	WorkEnd    string
+	FirstDay   int // day ID the scaffolded tasks belong to
+	Tasks      []config.Task
+}
+
+// Interview asks the setup questions. now provides the default anchor date.
+func Interview(p *Prompter, now time.Time) (*Answers, error) {
+	a := &Answers{CycleDays: 7}
+	var err error
+
+	a.Format, err = p.Ask("Schedule format (csv for a simple weekly grid, toml for custom cycles)", "csv", oneOf("csv", "toml"))
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Format == "toml" {
+		days, err := p.Ask("Days in your cycle (7 for a normal week)", "7", positiveInt)
+		if err != nil {
+			return nil, err
+		}
+		a.CycleDays, _ = strconv.Atoi(days)
+	}
+	if a.CycleDays != 7 {
+		a.AnchorDate, err = p.Ask("Date of the first day of the cycle (YYYY-MM-DD)", now.Format("2006-01-02"), validDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	a.WorkStart, err = p.Ask("Working hours start (HH:MM)", "09:00", validTime)
+	if err != nil {
+		return nil, err
+	}
+	a.WorkEnd, err = p.Ask("Working hours end (HH:MM)", "17:00", after(a.WorkStart))
+	if err != nil {
+		return nil, err
+	}
+
+	scaffold, err := p.Confirm("Add tasks for a first day now?", false)
+	if err != nil || !scaffold {
+		return a, err
+	}
+
+	if a.CycleDays == 7 {
+		day, err := p.Ask("Which weekday", "Monday", validWeekday)
+		if err != nil {
+			return nil, err
+		}
+		a.FirstDay, _ = config.ParseDayName(day)
+	}
+
+	start := a.WorkStart
+	for {
+		name, err := p.Ask("Task name (empty to finish)", "", nil)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			break
+		}
+		if strings.ContainsAny(name, ",\"") {
+			fmt.Fprintln(p.out, "  task names cannot contain commas or quotes")
+			continue
+		}
+		start, err = p.Ask("  Start (HH:MM)", start, validTime)
+		if err != nil {
+			return nil, err
+		}
+		end, err := p.Ask("  End (HH:MM)", addHour(start), after(start))
+		if err != nil {
+			return nil, err
+		}
+		a.Tasks = append(a.Tasks, config.Task{Name: name, Start: start, End: end})
+		start = end
+	}
+	return a, nil
+}
+
+// File is a file the wizard writes.
+type File struct {
+	Path    string
+	Content string
+}
+
+// Files renders the config at configPath (and its CSV, for the csv format).
+func (a *Answers) Files(configPath string) []File {
+	if a.Format == "csv" {
+		csvPath := filepath.Join(filepath.Dir(configPath), "schedule.csv")
+		return []File{
+			{Path: configPath, Content: fmt.Sprintf("# Generated by 'sked init'. See sample_config.toml for all options.\ncsv_path = %q\n", filepath.Base(csvPath))},
+			{Path: csvPath, Content: a.renderCSV()},
+		}
+	}
+	return []File{{Path: configPath, Content: a.renderTOML()}}
+}
+
+// Write writes files, refusing to overwrite existing ones unless force is set.
+func Write(files []File, force bool) error {
+	if !force {
+		for _, f := range files {
+			if _, err := os.Stat(f.Path); err == nil {
+				return fmt.Errorf("%s already exists (use --force to overwrite)", f.Path)
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	for _, f := range files {
+		if err := os.MkdirAll(filepath.Dir(f.Path), 0o755); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := config.WriteFile(f.Path, []byte(f.Content)); err != nil {
+			return fmt.Errorf("failed to write %s: %w", f.Path, err)
+		}
+	}
+	return nil
+}
+
+var weekdayColumns = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// renderCSV writes one row per scaffolded task, or hourly empty slots across
+// the working hours if there are none.
+func (a *Answers) renderCSV() string {
+	var b strings.Builder
+	b.WriteString("Start,End," + strings.Join(weekdayColumns, ",") + "\n")
+
+	tasks := a.Tasks
+	if len(tasks) == 0 {
+		for start := a.WorkStart; start < a.WorkEnd; start = addHour(start) {
+			end := min(addHour(start), a.WorkEnd)
+			tasks = append(tasks, config.Task{Start: start, End: end})
+			if end == "23:59" {
+				break
+			}
+		}
+	}
+
+	for _, t := range tasks {
+		cells := make([]string, len(weekdayColumns))
+		// Columns run Monday..Sunday; day IDs run Sunday(0)..Saturday(6).
+		cells[(a.FirstDay+6)%7] = t.Name
+		fmt.Fprintf(&b, "%s,%s,%s\n", t.Start, t.End, strings.Join(cells, ","))
+	}
+	return b.String()
+}
+
+func (a *Answers) renderTOML() string {
+	var b strings.Builder
+	b.WriteString("# Generated by 'sked init'. See sample_config.toml for all options.\n\n")
+	fmt.Fprintf(&b, "config_version = %d\n", config.CurrentVersion)
+	fmt.Fprintf(&b, "cycle_days = %d\n", a.CycleDays)
+	if a.AnchorDate != "" {
+		b.WriteString("# Day 0 of the cycle.\n")
+		fmt.Fprintf(&b, "anchor_date = %q\n", a.AnchorDate)
+	}
+	fmt.Fprintf(&b, "\n# Working hours: %s-%s\n", a.WorkStart, a.WorkEnd)
+
+	if len(a.Tasks) == 0 {
+		b.WriteString("#\n# [[day]]\n#   id = 1\n#   tasks = [\n")
+		fmt.Fprintf(&b, "#     { name = \"First task\", start = %q, end = %q },\n", a.WorkStart, addHour(a.WorkStart))
+		b.WriteString("#   ]\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "\n[[day]]\n  id = %d\n  tasks = [\n", a.FirstDay)
+	for _, t := range a.Tasks {
+		fmt.Fprintf(&b, "    { name = %q, start = %q, end = %q },\n", t.Name, t.Start, t.End)
+	}
+	b.WriteString("  ]\n")
+	return b.String()
+}
+
+func oneOf(options ...string) func(string) error {
+	return func(s string) error {
+		for _, o := range options {
+			if s == o {
+				return nil
+			}
+		}
+		return fmt.Errorf("please answer one of: %s", strings.Join(options, ", "))
+	}
+}
+
+func positiveInt(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return fmt.Errorf("please enter a positive number")
+	}
+	return nil
+}
+
+func validDate(s string) error {
+	if _, err := time.Parse("2006-01-02", s); err != nil {
+		return fmt.Errorf("please enter a date as YYYY-MM-DD")
+	}
+	return nil
+}
+
+func validTime(s string) error {
+	if _, err := time.Parse("15:04", s); err != nil || len(s) != 5 {
+		return fmt.Errorf("please enter a time as HH:MM (24-hour)")
+	}
+	return nil
+}
+
+func validWeekday(s string) error {
+	if id, err := config.ParseDayName(s); err != nil || id < 0 || id > 6 {
+		return fmt.Errorf("please enter a weekday such as Monday or Tue")
+	}
+	return nil
+}
+
+// after validates an HH:MM time later than start.
+func after(start string) func(string) error {
+	return func(s string) error {
+		if err := validTime(s); err != nil {
+			return err
+		}
+		if s <= start {
+			return fmt.Errorf("must be after %s", start)
+		}
+		return nil
+	}
+}
+
+// addHour returns the HH:MM time one hour after t, capped at 23:59.
+func addHour(t string) string {
+	parsed, err := time.Parse("15:04", t)
+	if err != nil {
+		return t
+	}
+	next := parsed.Add(time.Hour)
+	if next.Day() != parsed.Day() {
+		return "23:59"
+	}
+	return next.Format("15:04")
+}
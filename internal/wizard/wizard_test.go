@@ -0,0 +1,122 @@
+package wizard
+
+import (
+	"bytes"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Daniel-42-z/sked/internal/config"
+)
+
+func interview(t *testing.T, input string) (*Answers, string) {
+	t.Helper()
+	var out bytes.Buffer
+	p := NewPrompter(strings.NewReader(input), &out)
+	a, err := Interview(p, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Interview() returned error: %v\n%s", err, out.String())
+	}
+	return a, out.String()
+}
+
+func TestInterview_CSVWithTasks(t *testing.T) {
+	input := strings.Join([]string{
+		"",      // format: csv
+		"",      // work start 09:00
+		"",      // work end 17:00
+		"y",     // scaffold
+		"tue",   // weekday
+		"Math",  // task
+		"",      // start 09:00
+		"",      // end 10:00
+		"Art",   // task
+		"",      // start 10:00
+		"10:00", // invalid end, re-asked
+		"11:30", // end
+		"",      // finish
+	}, "\n") + "\n"
+
+	a, out := interview(t, input)
+	if !strings.Contains(out, "must be after 10:00") {
+		t.Errorf("Expected invalid end time to be reported, got:\n%s", out)
+	}
+	if a.Format != "csv" || a.FirstDay != 2 || len(a.Tasks) != 2 {
+		t.Fatalf("Unexpected answers: %+v", a)
+	}
+	if !reflect.DeepEqual(a.Tasks[1], config.Task{Name: "Art", Start: "10:00", End: "11:30"}) {
+		t.Errorf("Unexpected second task: %+v", a.Tasks[1])
+	}
+
+	path := filepath.Join(t.TempDir(), "sked", "config.toml")
+	files := a.Files(path)
+	if err := Write(files, false); err != nil {
+		t.Fatalf("Write() returned error: %v", err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Generated config does not load: %v", err)
+	}
+	if len(cfg.Days) != 1 || cfg.Days[0].ID != 2 || len(cfg.Days[0].Tasks) != 2 {
+		t.Errorf("Unexpected loaded days: %+v", cfg.Days)
+	}
+
+	if err := Write(files, false); err == nil {
+		t.Errorf("Expected Write() to refuse overwriting without force")
+	}
+	if err := Write(files, true); err != nil {
+		t.Errorf("Expected Write() with force to succeed, got %v", err)
+	}
+}
+
+func TestInterview_TOMLCycle(t *testing.T) {
+	input := strings.Join([]string{
+		"yaml",       // invalid format, re-asked
+		"toml",       // format
+		"0",          // invalid cycle, re-asked
+		"6",          // cycle days
+		"2024-13-01", // invalid date, re-asked
+		"",           // anchor 2024-01-01
+		"08:00",      // work start
+		"07:00",      // invalid work end, re-asked
+		"15:00",      // work end
+		"n",          // no scaffold
+	}, "\n") + "\n"
+
+	a, _ := interview(t, input)
+	if a.Format != "toml" || a.CycleDays != 6 || a.AnchorDate != "2024-01-01" || a.WorkStart != "08:00" || a.WorkEnd != "15:00" {
+		t.Fatalf("Unexpected answers: %+v", a)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := Write(a.Files(path), false); err != nil {
+		t.Fatalf("Write() returned error: %v", err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Generated config does not load: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Generated config is invalid: %v", err)
+	}
+}
+
+func TestInterview_EOF(t *testing.T) {
+	p := NewPrompter(strings.NewReader("csv\n"), &bytes.Buffer{})
+	if _, err := Interview(p, time.Now()); err == nil {
+		t.Errorf("Expected error on truncated input")
+	}
+}
+
+func TestRenderCSV_HourlySlots(t *testing.T) {
+	a := &Answers{Format: "csv", CycleDays: 7, WorkStart: "09:00", WorkEnd: "11:30"}
+	want := "Start,End,Mon,Tue,Wed,Thu,Fri,Sat,Sun\n" +
+		"09:00,10:00,,,,,,,\n" +
+		"10:00,11:00,,,,,,,\n" +
+		"11:00,11:30,,,,,,,\n"
+	if got := a.renderCSV(); got != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+}
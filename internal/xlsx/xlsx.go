@@ -0,0 +1,291 @@
+// Package xlsx reads the cell values of a worksheet from an Office Open XML
+// (.xlsx) workbook. It understands shared and inline strings, numbers,
+// booleans and merged cells, and ignores styles and formulas.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Cell is the value of a cell.
+type Cell struct {
+	Value    string  // text, or the number as written in the file
+	Number   float64 // the value of a numeric cell
+	IsNumber bool
+}
+
+// Sheet holds the cells of a worksheet as rows of columns, both starting
+// at 0. Cells inside a merged range carry the value of its top-left cell.
+type Sheet struct {
+	Name string
+	Rows [][]Cell
+}
+
+// Cell returns the cell at row and col, or an empty cell outside the sheet.
+func (s *Sheet) Cell(row, col int) Cell {
+	if row < 0 || row >= len(s.Rows) || col < 0 || col >= len(s.Rows[row]) {
+		return Cell{}
+	}
+	return s.Rows[row][col]
+}
+
+// CellName returns the A1-style reference of a cell, e.g. "C7" for row 6,
+// column 2.
+func CellName(row, col int) string {
+	var letters []byte
+	for col++; col > 0; col = (col - 1) / 26 {
+		letters = append([]byte{byte('A' + (col-1)%26)}, letters...)
+	}
+	return string(letters) + strconv.Itoa(row+1)
+}
+
+// ParseCellName parses an A1-style reference into its row and column.
+func ParseCellName(name string) (row, col int, err error) {
+	i := 0
+	for i < len(name) && name[i] >= 'A' && name[i] <= 'Z' {
+		col = col*26 + int(name[i]-'A'+1)
+		i++
+	}
+	n, err := strconv.Atoi(name[i:])
+	if i == 0 || err != nil || n < 1 {
+		return 0, 0, fmt.Errorf("invalid cell reference '%s'", name)
+	}
+	return n - 1, col - 1, nil
+}
+
+// ReadSheet reads the worksheet called name from the workbook at path, or
+// the first one if name is empty.
+func ReadSheet(path, name string) (*Sheet, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	target, sheetName, err := findSheet(files, name)
+	if err != nil {
+		return nil, err
+	}
+	var strs []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if strs, err = readSharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+	f, ok := files[target]
+	if !ok {
+		return nil, fmt.Errorf("worksheet '%s' is missing from the workbook", sheetName)
+	}
+	s, err := readWorksheet(f, strs)
+	if err != nil {
+		return nil, fmt.Errorf("worksheet '%s': %w", sheetName, err)
+	}
+	s.Name = sheetName
+	return s, nil
+}
+
+type workbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relationships struct {
+	Rels []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// findSheet returns the zip entry of the worksheet called name (the first
+// one if empty) and its name.
+func findSheet(files map[string]*zip.File, name string) (string, string, error) {
+	var wb workbook
+	if err := decodeFile(files, "xl/workbook.xml", &wb); err != nil {
+		return "", "", err
+	}
+	var rels relationships
+	if err := decodeFile(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return "", "", err
+	}
+	if len(wb.Sheets) == 0 {
+		return "", "", fmt.Errorf("workbook has no sheets")
+	}
+	var names []string
+	for _, s := range wb.Sheets {
+		names = append(names, s.Name)
+		if name != "" && s.Name != name {
+			continue
+		}
+		for _, r := range rels.Rels {
+			if r.ID != s.RID {
+				continue
+			}
+			if strings.HasPrefix(r.Target, "/") {
+				return strings.TrimPrefix(r.Target, "/"), s.Name, nil
+			}
+			return path.Join("xl", r.Target), s.Name, nil
+		}
+		return "", "", fmt.Errorf("worksheet '%s' has no relationship", s.Name)
+	}
+	return "", "", fmt.Errorf("no sheet named '%s' (sheets: %s)", name, strings.Join(names, ", "))
+}
+
+func decodeFile(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%s is missing from the workbook", name)
+	}
+	return decode(f, v)
+}
+
+func decode(f *zip.File, v any) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if err := xml.NewDecoder(r).Decode(v); err != nil && err != io.EOF {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// richText is a shared or inline string: plain text or runs of formatted
+// text. Phonetic runs are ignored.
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t richText) String() string {
+	s := t.T
+	for _, r := range t.Runs {
+		s += r.T
+	}
+	return s
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	var sst struct {
+		Items []richText `xml:"si"`
+	}
+	if err := decode(f, &sst); err != nil {
+		return nil, err
+	}
+	strs := make([]string, len(sst.Items))
+	for i, it := range sst.Items {
+		strs[i] = it.String()
+	}
+	return strs, nil
+}
+
+type worksheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string    `xml:"r,attr"`
+			Type   string    `xml:"t,attr"`
+			V      string    `xml:"v"`
+			Inline *richText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+	Merges []struct {
+		Ref string `xml:"ref,attr"`
+	} `xml:"mergeCells>mergeCell"`
+}
+
+func readWorksheet(f *zip.File, strs []string) (*Sheet, error) {
+	var ws worksheet
+	if err := decode(f, &ws); err != nil {
+		return nil, err
+	}
+	s := &Sheet{}
+	for _, row := range ws.Rows {
+		for _, c := range row.Cells {
+			r, col, err := ParseCellName(c.Ref)
+			if err != nil {
+				return nil, err
+			}
+			cell, err := cellValue(c.Type, c.V, c.Inline, strs)
+			if err != nil {
+				return nil, fmt.Errorf("cell %s: %w", c.Ref, err)
+			}
+			s.set(r, col, cell)
+		}
+	}
+	for _, m := range ws.Merges {
+		from, to, ok := strings.Cut(m.Ref, ":")
+		if !ok {
+			continue
+		}
+		r1, c1, err := ParseCellName(from)
+		if err != nil {
+			return nil, err
+		}
+		r2, c2, err := ParseCellName(to)
+		if err != nil {
+			return nil, err
+		}
+		v := s.Cell(r1, c1)
+		for r := r1; r <= r2; r++ {
+			for c := c1; c <= c2; c++ {
+				s.set(r, c, v)
+			}
+		}
+	}
+	return s, nil
+}
+
+func cellValue(typ, v string, inline *richText, strs []string) (Cell, error) {
+	switch typ {
+	case "s":
+		i, err := strconv.Atoi(v)
+		if err != nil || i < 0 || i >= len(strs) {
+			return Cell{}, fmt.Errorf("invalid shared string index '%s'", v)
+		}
+		return Cell{Value: strs[i]}, nil
+	case "inlineStr":
+		if inline == nil {
+			return Cell{}, nil
+		}
+		return Cell{Value: inline.String()}, nil
+	case "str", "e":
+		return Cell{Value: v}, nil
+	case "b":
+		if v == "1" {
+			return Cell{Value: "TRUE"}, nil
+		}
+		return Cell{Value: "FALSE"}, nil
+	}
+	if v == "" {
+		return Cell{}, nil
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return Cell{}, fmt.Errorf("invalid number '%s'", v)
+	}
+	return Cell{Value: v, Number: n, IsNumber: true}, nil
+}
+
+func (s *Sheet) set(row, col int, c Cell) {
+	for len(s.Rows) <= row {
+		s.Rows = append(s.Rows, nil)
+	}
+	for len(s.Rows[row]) <= col {
+		s.Rows[row] = append(s.Rows[row], Cell{})
+	}
+	s.Rows[row][col] = c
+}
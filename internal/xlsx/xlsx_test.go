@@ -0,0 +1,32 @@
+package xlsx
+
+import "testing"
+
+func TestCellName(t *testing.T) {
+	tests := []struct {
+		row, col int
+		want     string
+	}{
+		{0, 0, "A1"},
+		{6, 2, "C7"},
+		{9, 25, "Z10"},
+		{0, 26, "AA1"},
+		{99, 701, "ZZ100"},
+		{0, 702, "AAA1"},
+	}
+	for _, tt := range tests {
+		got := CellName(tt.row, tt.col)
+		if got != tt.want {
+			t.Errorf("CellName(%d, %d): Expected %s, got %s", tt.row, tt.col, tt.want, got)
+		}
+		row, col, err := ParseCellName(got)
+		if err != nil || row != tt.row || col != tt.col {
+			t.Errorf("ParseCellName(%s): Expected %d, %d, got %d, %d (%v)", got, tt.row, tt.col, row, col, err)
+		}
+	}
+	for _, bad := range []string{"", "A", "7", "A0", "a1"} {
+		if _, _, err := ParseCellName(bad); err == nil {
+			t.Errorf("ParseCellName(%q): Expected an error, got nil", bad)
+		}
+	}
+}